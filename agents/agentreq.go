@@ -69,6 +69,7 @@ type AgentRequest struct {
 	CGRRequest *config.NavigableMap
 	CGRReply   *config.NavigableMap
 	Reply      *config.NavigableMap
+	Answer     config.DataProvider // answer received when proxying the request
 	tenant,
 	timezone string
 	filterS *engine.FilterS
@@ -99,6 +100,11 @@ func (ar *AgentRequest) FieldAsInterface(fldPath []string) (val interface{}, err
 		return ar.CGRReply.FieldAsInterface(fldPath[1:])
 	case utils.MetaRep:
 		return ar.Reply.FieldAsInterface(fldPath[1:])
+	case utils.MetaAns:
+		if ar.Answer == nil {
+			return nil, utils.ErrNotFound
+		}
+		return ar.Answer.FieldAsInterface(fldPath[1:])
 	}
 }
 
@@ -19,11 +19,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
@@ -31,6 +34,7 @@ import (
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/rpcclient"
 	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
 	"github.com/fiorix/go-diameter/diam/datatype"
 	"github.com/fiorix/go-diameter/diam/sm"
 )
@@ -40,7 +44,9 @@ func NewDiameterAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 	if sS != nil && reflect.ValueOf(sS).IsNil() {
 		sS = nil
 	}
-	da := &DiameterAgent{cgrCfg: cgrCfg, filterS: filterS, sS: sS}
+	da := &DiameterAgent{cgrCfg: cgrCfg, filterS: filterS, sS: sS,
+		peerStats: make(map[string]*DiamPeerStats),
+		pending:   make(map[uint32]chan *diam.Message)}
 	dictsPath := cgrCfg.DiameterAgentCfg().DictionariesPath
 	if len(dictsPath) != 0 {
 		if err := loadDictionaries(dictsPath, utils.DiameterAgent); err != nil {
@@ -50,6 +56,11 @@ func NewDiameterAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 	msgTemplates := da.cgrCfg.DiameterAgentCfg().Templates
 	// Inflate *template field types
 	for _, procsr := range da.cgrCfg.DiameterAgentCfg().RequestProcessors {
+		if tpls, err := config.InflateTemplates(procsr.ProxyFields, msgTemplates); err != nil {
+			return nil, err
+		} else if tpls != nil {
+			procsr.ProxyFields = tpls
+		}
 		if tpls, err := config.InflateTemplates(procsr.RequestFields, msgTemplates); err != nil {
 			return nil, err
 		} else if tpls != nil {
@@ -61,6 +72,13 @@ func NewDiameterAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 			procsr.ReplyFields = tpls
 		}
 	}
+	if len(da.cgrCfg.DiameterAgentCfg().Peers) != 0 {
+		da.peers = make(map[string]*diamPeer)
+		settings := da.smSettings()
+		for _, peerCfg := range da.cgrCfg.DiameterAgentCfg().Peers {
+			da.peers[peerCfg.ID] = newDiamPeer(peerCfg, settings)
+		}
+	}
 	return da, nil
 }
 
@@ -70,16 +88,36 @@ type DiameterAgent struct {
 	sS       rpcclient.RpcClientConnection // Connection towards CGR-SessionS component
 	aReqs    int
 	aReqsLck sync.RWMutex
+	peers    map[string]*diamPeer // upstream peers used when proxying requests
+
+	peerStats    map[string]*DiamPeerStats // request statistics, indexed on Origin-Host
+	peerStatsLck sync.RWMutex
+
+	pending    map[uint32]chan *diam.Message // requests sent to the clients waiting for answer (ie: RAR), indexed on Hop-by-Hop ID
+	pendingLck sync.Mutex
 }
 
 // ListenAndServe is called when DiameterAgent is started, usually from within cmd/cgr-engine
 func (da *DiameterAgent) ListenAndServe() error {
-	return diam.ListenAndServeNetwork(da.cgrCfg.DiameterAgentCfg().ListenNet, da.cgrCfg.DiameterAgentCfg().Listen, da.handlers(), nil)
+	if !da.cgrCfg.DiameterAgentCfg().TLS {
+		return diam.ListenAndServeNetwork(da.cgrCfg.DiameterAgentCfg().ListenNet, da.cgrCfg.DiameterAgentCfg().Listen, da.handlers(), nil)
+	}
+	tlsCfg, err := utils.LoadTLSConfig(da.cgrCfg.TlsCfg().ServerCerificate,
+		da.cgrCfg.TlsCfg().ServerKey, da.cgrCfg.TlsCfg().CaCertificate,
+		da.cgrCfg.TlsCfg().ServerPolicy, da.cgrCfg.TlsCfg().ServerName)
+	if err != nil {
+		return err
+	}
+	l, err := tls.Listen(da.cgrCfg.DiameterAgentCfg().ListenNet, da.cgrCfg.DiameterAgentCfg().Listen, &tlsCfg)
+	if err != nil {
+		return err
+	}
+	return (&diam.Server{Handler: da.handlers()}).Serve(l)
 }
 
-// Creates the message handlers
-func (da *DiameterAgent) handlers() diam.Handler {
-	settings := &sm.Settings{
+// smSettings builds the settings of the diameter state machines
+func (da *DiameterAgent) smSettings() (settings *sm.Settings) {
+	settings = &sm.Settings{
 		OriginHost:       datatype.DiameterIdentity(da.cgrCfg.DiameterAgentCfg().OriginHost),
 		OriginRealm:      datatype.DiameterIdentity(da.cgrCfg.DiameterAgentCfg().OriginRealm),
 		VendorID:         datatype.Unsigned32(da.cgrCfg.DiameterAgentCfg().VendorId),
@@ -108,8 +146,12 @@ func (da *DiameterAgent) handlers() diam.Handler {
 	for i, host := range hosts {
 		settings.HostIPAddresses[i] = datatype.Address(host)
 	}
+	return
+}
 
-	dSM := sm.New(settings)
+// Creates the message handlers
+func (da *DiameterAgent) handlers() diam.Handler {
+	dSM := sm.New(da.smSettings())
 
 	dSM.HandleFunc("ALL", da.handleMessage) // route all commands to one dispatcher
 	go func() {
@@ -117,11 +159,39 @@ func (da *DiameterAgent) handlers() diam.Handler {
 			utils.Logger.Err(fmt.Sprintf("<%s> sm error: %v", utils.DiameterAgent, err))
 		}
 	}()
-	return dSM
+	if len(da.cgrCfg.DiameterAgentCfg().AllowedPeers) == 0 {
+		return dSM
+	}
+	return diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+		if m.Header.CommandCode == diam.CapabilitiesExchange &&
+			m.Header.CommandFlags&diam.RequestFlag != 0 &&
+			!da.authorizeCER(c, m) { // validate the peer before the state machine answers the CER
+			return
+		}
+		dSM.ServeDIAM(c, m)
+	})
 }
 
 // handleALL is the handler of all messages coming in via Diameter
 func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
+	if m.Header.CommandFlags&diam.RequestFlag == 0 { // answer to one of our requests (ie: ASA, RAA)
+		da.pendingLck.Lock()
+		ansChan, has := da.pending[m.Header.HopByHopID]
+		da.pendingLck.Unlock()
+		if !has {
+			utils.Logger.Info(
+				fmt.Sprintf("<%s> received answer %s from %s",
+					utils.DiameterAgent, m, c.RemoteAddr()))
+			return
+		}
+		select {
+		case ansChan <- m:
+		default: // duplicate answer, first one wins
+		}
+		return
+	}
+	var answered bool
+	defer func() { da.countPeerRequest(diamOriginHost(m), answered) }()
 	dApp, err := m.Dictionary().App(m.Header.ApplicationID)
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> decoding app: %d, err: %s",
@@ -160,8 +230,9 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 		writeOnConn(c, diamBareErr(m, diam.CommandUnsupported))
 		return
 	}
-	// cache message for ASR
-	if da.cgrCfg.DiameterAgentCfg().ASRTemplate != "" {
+	// cache message for ASR and RAR
+	if da.cgrCfg.DiameterAgentCfg().ASRTemplate != "" ||
+		da.cgrCfg.DiameterAgentCfg().RARTemplate != "" {
 		sessID, err := diamDP.FieldAsString([]string{"Session-Id"})
 		if err != nil {
 			utils.Logger.Warning(
@@ -193,17 +264,21 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 	}
 	rply := config.NewNavigableMap(nil) // share it among different processors
 	var processed bool
+	var pxyAns *diam.Message // answer received from upstream peer
 	for _, reqProcessor := range da.cgrCfg.DiameterAgentCfg().RequestProcessors {
 		var lclProcessed bool
-		lclProcessed, err = da.processRequest(reqProcessor,
-			newAgentRequest(
-				diamDP, reqVars, rply,
-				reqProcessor.Tenant, da.cgrCfg.GeneralCfg().DefaultTenant,
-				utils.FirstNonEmpty(reqProcessor.Timezone,
-					da.cgrCfg.GeneralCfg().DefaultTimezone),
-				da.filterS))
+		agReq := newAgentRequest(
+			diamDP, reqVars, rply,
+			reqProcessor.Tenant, da.cgrCfg.GeneralCfg().DefaultTenant,
+			utils.FirstNonEmpty(reqProcessor.Timezone,
+				da.cgrCfg.GeneralCfg().DefaultTimezone),
+			da.filterS)
+		lclProcessed, err = da.processRequest(reqProcessor, agReq)
 		if lclProcessed {
 			processed = lclProcessed
+			if ansDP, canCast := agReq.Answer.(*diameterDP); canCast {
+				pxyAns = ansDP.m
+			}
 		}
 		if err != nil ||
 			(lclProcessed && !reqProcessor.ContinueOnSuccess) {
@@ -223,6 +298,10 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 		writeOnConn(c, diamErr)
 		return
 	}
+	if pxyAns != nil && len(rply.Values()) == 0 { // no reply_fields, relay the upstream answer
+		answered = writeOnConn(c, diamRelayedAnswer(m, pxyAns)) == nil
+		return
+	}
 	a, err := diamAnswer(m, 0, false,
 		rply, da.cgrCfg.GeneralCfg().DefaultTimezone)
 	if err != nil {
@@ -231,7 +310,7 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 				utils.DiameterAgent, err.Error(), m))
 
 	}
-	writeOnConn(c, a)
+	answered = writeOnConn(c, a) == nil
 }
 
 func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
@@ -240,6 +319,13 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 		reqProcessor.Filters, agReq); err != nil || !pass {
 		return pass, err
 	}
+	if reqProcessor.Flags.HasKey(utils.MetaProxy) { // answer is available to the fields via *ans prefix
+		var pxyAns *diam.Message
+		if pxyAns, err = da.proxyRequest(reqProcessor, agReq); err != nil {
+			return
+		}
+		agReq.Answer = newDADataProvider(nil, pxyAns)
+	}
 	if agReq.CGRRequest, err = agReq.AsNavigableMap(reqProcessor.RequestFields); err != nil {
 		return
 	}
@@ -368,6 +454,46 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 	return true, nil
 }
 
+// proxyRequest forwards the request towards the upstream peer of the processor and returns its answer
+func (da *DiameterAgent) proxyRequest(reqProcessor *config.DARequestProcessor,
+	agReq *AgentRequest) (a *diam.Message, err error) {
+	peer, has := da.peers[reqProcessor.ProxyPeer]
+	if !has {
+		return nil, fmt.Errorf("unknown proxy peer: <%s>", reqProcessor.ProxyPeer)
+	}
+	diamDP, canCast := agReq.Request.(*diameterDP)
+	if !canCast {
+		return nil, errors.New("cannot proxy non diameter request")
+	}
+	m := diamDP.m
+	fwd := diam.NewRequest(m.Header.CommandCode, m.Header.ApplicationID, m.Dictionary())
+	fwd.Header.CommandFlags = m.Header.CommandFlags
+	fwd.Header.EndToEndID = m.Header.EndToEndID // Hop-by-Hop ID is changed by relays, End-to-End ID not
+	if len(reqProcessor.ProxyFields) == 0 {
+		for _, reqAVP := range m.AVP {
+			fwd.AddAVP(reqAVP)
+		}
+	} else {
+		var nM *config.NavigableMap
+		if nM, err = agReq.AsNavigableMap(reqProcessor.ProxyFields); err != nil {
+			return
+		}
+		if err = updateDiamMsgFromNavMap(fwd, nM, agReq.timezone); err != nil {
+			return
+		}
+	}
+	if _, err = fwd.NewAVP(avp.RouteRecord, avp.Mbit, 0,
+		datatype.DiameterIdentity(da.cgrCfg.DiameterAgentCfg().OriginHost)); err != nil {
+		return
+	}
+	if reqProcessor.Flags.HasKey(utils.MetaLog) {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> LOG, processorID: %s, proxying to peer: <%s> diameter message: %s",
+				utils.DiameterAgent, reqProcessor.ID, reqProcessor.ProxyPeer, fwd))
+	}
+	return peer.send(fwd)
+}
+
 // rpcclient.RpcClientConnection interface
 func (da *DiameterAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
 	return utils.RPCCall(da, serviceMethod, args, reply)
@@ -416,6 +542,85 @@ func (da *DiameterAgent) V1DisconnectSession(args utils.AttrDisconnectSession, r
 	return
 }
 
+// V1ReAuthorize sends a Re-Auth-Request towards the client of the session, built out of rar_template
+func (da *DiameterAgent) V1ReAuthorize(args utils.AttrReAuthorizeSession, reply *string) (err error) {
+	if da.cgrCfg.DiameterAgentCfg().RARTemplate == "" {
+		return utils.ErrNotImplemented
+	}
+	ssID, has := args.EventStart[utils.OriginID]
+	if !has {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> cannot re-authorize session, missing OriginID in event: %s",
+				utils.DiameterAgent, utils.ToJSON(args.EventStart)))
+		return utils.ErrMandatoryIeMissing
+	}
+	msg, has := engine.Cache.Get(utils.CacheDiameterMessages, ssID.(string))
+	if !has {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot retrieve message from cache with OriginID: <%s>",
+				utils.DiameterAgent, ssID))
+		return utils.ErrMandatoryIeMissing
+	}
+	dmd := msg.(*diamMsgData)
+	aReq := newAgentRequest(
+		newDADataProvider(dmd.c, dmd.m),
+		dmd.vars, nil, nil,
+		da.cgrCfg.GeneralCfg().DefaultTenant,
+		da.cgrCfg.GeneralCfg().DefaultTimezone, da.filterS)
+	nM, err := aReq.AsNavigableMap(da.cgrCfg.DiameterAgentCfg().Templates[da.cgrCfg.DiameterAgentCfg().RARTemplate])
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot re-authorize session with OriginID: <%s>, err: %s",
+				utils.DiameterAgent, ssID, err.Error()))
+		return utils.ErrServerError
+	}
+	m := diam.NewRequest(diam.ReAuth, dmd.m.Header.ApplicationID, dmd.m.Dictionary())
+	if err = updateDiamMsgFromNavMap(m, nM, da.cgrCfg.GeneralCfg().DefaultTimezone); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot re-authorize session with OriginID: <%s>, err: %s",
+				utils.DiameterAgent, ssID, err.Error()))
+		return utils.ErrServerError
+	}
+	raa, err := da.sendRequest(dmd.c, m)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot re-authorize session with OriginID: <%s>, err: %s",
+				utils.DiameterAgent, ssID, err.Error()))
+		return
+	}
+	if err = diamResultCodeErr(raa); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> session with OriginID: <%s> not re-authorized, err: %s",
+				utils.DiameterAgent, ssID, err.Error()))
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// sendRequest writes the request towards the client and waits for its answer
+func (da *DiameterAgent) sendRequest(c diam.Conn, m *diam.Message) (a *diam.Message, err error) {
+	hbhID := m.Header.HopByHopID
+	ansChan := make(chan *diam.Message, 1)
+	da.pendingLck.Lock()
+	da.pending[hbhID] = ansChan
+	da.pendingLck.Unlock()
+	defer func() {
+		da.pendingLck.Lock()
+		delete(da.pending, hbhID)
+		da.pendingLck.Unlock()
+	}()
+	if err = writeOnConn(c, m); err != nil {
+		return nil, utils.ErrServerError
+	}
+	select {
+	case a = <-ansChan:
+	case <-time.After(da.cgrCfg.GeneralCfg().ReplyTimeout):
+		err = utils.ErrReplyTimeout
+	}
+	return
+}
+
 // V1GetActiveSessionIDs is part of the sessions.SessionSClient
 func (da *DiameterAgent) V1GetActiveSessionIDs(ignParam string,
 	sessionIDs *[]*sessions.SessionID) error {
@@ -20,9 +20,69 @@ package agents
 import (
 	"testing"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/sessions"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
+	"github.com/fiorix/go-diameter/diam/datatype"
 )
 
 func TestDAsSessionSClientIface(t *testing.T) {
 	_ = sessions.SessionSClient(new(DiameterAgent))
 }
+
+func TestDAV1ReAuthorize(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	da := &DiameterAgent{cgrCfg: cfg}
+	var rply string
+	if err := da.V1ReAuthorize(utils.AttrReAuthorizeSession{
+		EventStart: map[string]interface{}{utils.OriginID: "sess1"}},
+		&rply); err != utils.ErrNotImplemented {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotImplemented, err)
+	}
+	cfg.DiameterAgentCfg().RARTemplate = utils.MetaRAR
+	if err := da.V1ReAuthorize(utils.AttrReAuthorizeSession{
+		EventStart: map[string]interface{}{utils.Account: "1001"}},
+		&rply); err != utils.ErrMandatoryIeMissing {
+		t.Errorf("expecting: %v, received: %v", utils.ErrMandatoryIeMissing, err)
+	}
+	// session not cached
+	if err := da.V1ReAuthorize(utils.AttrReAuthorizeSession{
+		EventStart: map[string]interface{}{utils.OriginID: "sessNotCached"}},
+		&rply); err != utils.ErrMandatoryIeMissing {
+		t.Errorf("expecting: %v, received: %v", utils.ErrMandatoryIeMissing, err)
+	}
+}
+
+func TestDAHandleRAA(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	da := &DiameterAgent{cgrCfg: cfg, pending: make(map[uint32]chan *diam.Message)}
+	rar := diam.NewRequest(diam.ReAuth, 4, nil)
+	ansChan := make(chan *diam.Message, 1)
+	da.pending[rar.Header.HopByHopID] = ansChan
+	raa := rar.Answer(diam.UnableToComply)
+	da.handleMessage(nil, raa)
+	select {
+	case a := <-ansChan:
+		if err := diamResultCodeErr(a); err == nil {
+			t.Error("expecting error for non-success Result-Code")
+		}
+	default:
+		t.Error("answer not dispatched to the pending request")
+	}
+	if err := diamResultCodeErr(rar.Answer(diam.Success)); err != nil {
+		t.Error(err)
+	}
+	expRes := newDiamAnswer(diam.NewRequest(diam.ReAuth, 4, nil), 0)
+	expRes.NewAVP(avp.ExperimentalResult, avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.ExperimentalResultCode, avp.Mbit, 0, datatype.Unsigned32(5030)),
+		}})
+	if err := diamResultCodeErr(expRes); err == nil {
+		t.Error("expecting error for non-success Experimental-Result-Code")
+	}
+	if err := diamResultCodeErr(diam.NewRequest(diam.ReAuth, 4, nil)); err == nil {
+		t.Error("expecting error for missing Result-Code")
+	}
+}
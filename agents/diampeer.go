@@ -0,0 +1,159 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
+	"github.com/fiorix/go-diameter/diam/datatype"
+	"github.com/fiorix/go-diameter/diam/sm"
+)
+
+// newDiamPeer constructs a diamPeer, the connection is dialed on first request
+func newDiamPeer(cfg *config.DiameterPeerCfg, settings *sm.Settings) (dp *diamPeer) {
+	dp = &diamPeer{
+		cfg:     cfg,
+		dSM:     sm.New(settings),
+		pending: make(map[uint32]chan *diam.Message),
+	}
+	dp.dSM.HandleFunc("ALL", dp.handleMessage) // answers are dispatched based on Hop-by-Hop ID
+	go func() {
+		for err := range dp.dSM.ErrorReports() {
+			utils.Logger.Err(fmt.Sprintf("<%s> peer: <%s> sm error: %v",
+				utils.DiameterAgent, dp.cfg.ID, err))
+		}
+	}()
+	return
+}
+
+// diamPeer is an upstream Diameter peer (ie: OCS) where requests are proxied to
+type diamPeer struct {
+	cfg     *config.DiameterPeerCfg
+	dSM     *sm.StateMachine
+	connMux sync.Mutex
+	conn    diam.Conn
+	pendMux sync.Mutex
+	pending map[uint32]chan *diam.Message // requests waiting for answer, indexed on Hop-by-Hop ID
+}
+
+// connect returns the active connection towards the peer, dialing it if not connected
+// CER/CEA and the DWR/DWA watchdog are handled by the state machine of the client
+func (dp *diamPeer) connect() (conn diam.Conn, err error) {
+	dp.connMux.Lock()
+	defer dp.connMux.Unlock()
+	if dp.conn != nil {
+		return dp.conn, nil
+	}
+	cli := &sm.Client{
+		Handler:            dp.dSM,
+		MaxRetransmits:     3,
+		RetransmitInterval: time.Second,
+		EnableWatchdog:     true,
+		WatchdogInterval:   dp.cfg.WatchdogInterval,
+		AuthApplicationID: []*diam.AVP{
+			// Advertise support for credit control application
+			diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4)), // RFC 4006
+		},
+	}
+	if conn, err = cli.DialNetwork(dp.cfg.Transport, dp.cfg.Address); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> failed connecting to peer: <%s> at: <%s>, err: %s",
+				utils.DiameterAgent, dp.cfg.ID, dp.cfg.Address, err.Error()))
+		return
+	}
+	dp.conn = conn
+	if cn, canCast := conn.(diam.CloseNotifier); canCast {
+		go func() { // reconnect on next request once the connection is lost (ie: watchdog failure)
+			<-cn.CloseNotify()
+			dp.disconnect(conn)
+		}()
+	}
+	return
+}
+
+// disconnect closes the connection so it will be redialed on next request
+func (dp *diamPeer) disconnect(conn diam.Conn) {
+	dp.connMux.Lock()
+	if dp.conn == conn {
+		dp.conn = nil
+		conn.Close()
+	}
+	dp.connMux.Unlock()
+}
+
+// send writes the request towards the peer and waits for its answer
+func (dp *diamPeer) send(m *diam.Message) (a *diam.Message, err error) {
+	var conn diam.Conn
+	if conn, err = dp.connect(); err != nil {
+		return
+	}
+	hbhID := m.Header.HopByHopID
+	ansChan := make(chan *diam.Message, 1)
+	dp.pendMux.Lock()
+	dp.pending[hbhID] = ansChan
+	dp.pendMux.Unlock()
+	defer func() {
+		dp.pendMux.Lock()
+		delete(dp.pending, hbhID)
+		dp.pendMux.Unlock()
+	}()
+	if _, err = m.WriteTo(conn); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> failed writing message to peer: <%s>, err: %s, msg: %s",
+				utils.DiameterAgent, dp.cfg.ID, err.Error(), m))
+		dp.disconnect(conn)
+		return
+	}
+	select {
+	case a = <-ansChan:
+	case <-time.After(dp.cfg.ReplyTimeout):
+		err = utils.ErrReplyTimeout
+	}
+	return
+}
+
+// handleMessage dispatches the answers received from the peer towards the waiting requests
+func (dp *diamPeer) handleMessage(c diam.Conn, m *diam.Message) {
+	if m.Header.CommandFlags&diam.RequestFlag != 0 {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> unsupported request received from peer: <%s>, msg: %s",
+				utils.DiameterAgent, dp.cfg.ID, m))
+		writeOnConn(c, diamBareErr(m, diam.CommandUnsupported))
+		return
+	}
+	dp.pendMux.Lock()
+	ansChan, has := dp.pending[m.Header.HopByHopID]
+	dp.pendMux.Unlock()
+	if !has {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> ignoring unexpected answer from peer: <%s>, msg: %s",
+				utils.DiameterAgent, dp.cfg.ID, m))
+		return
+	}
+	select {
+	case ansChan <- m:
+	default: // duplicate answer, first one wins
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
+	"github.com/fiorix/go-diameter/diam/datatype"
+	"github.com/fiorix/go-diameter/diam/sm"
+)
+
+// startOCSStandIn starts an in-process Diameter server answering CCRs with 300s granted
+func startOCSStandIn(t *testing.T) net.Listener {
+	ocsSM := sm.New(&sm.Settings{
+		OriginHost:       datatype.DiameterIdentity("OCS-StandIn"),
+		OriginRealm:      datatype.DiameterIdentity("ocs.org"),
+		VendorID:         datatype.Unsigned32(0),
+		ProductName:      datatype.UTF8String("StandIn"),
+		FirmwareRevision: datatype.Unsigned32(1),
+		HostIPAddresses:  []datatype.Address{datatype.Address("127.0.0.1")},
+	})
+	ocsSM.HandleFunc("ALL", func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		if sessID, err := m.FindAVP(avp.SessionID, 0); err == nil {
+			a.AddAVP(sessID)
+		}
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("OCS-StandIn"))
+		a.NewAVP(avp.GrantedServiceUnit, avp.Mbit, 0, &diam.GroupedAVP{
+			AVP: []*diam.AVP{
+				diam.NewAVP(avp.CCTime, avp.Mbit, 0, datatype.Unsigned32(300)),
+			}})
+		a.WriteTo(c)
+	})
+	ln, err := net.Listen(utils.TCP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go (&diam.Server{Handler: ocsSM}).Serve(ln)
+	return ln
+}
+
+func TestDiamPeerProxy(t *testing.T) {
+	ln := startOCSStandIn(t)
+	defer ln.Close()
+	peer := newDiamPeer(
+		&config.DiameterPeerCfg{
+			ID:               "OCS",
+			Address:          ln.Addr().String(),
+			Transport:        utils.TCP,
+			ReplyTimeout:     2 * time.Second,
+			WatchdogInterval: 30 * time.Second,
+		},
+		&sm.Settings{
+			OriginHost:       datatype.DiameterIdentity("CGR-DA"),
+			OriginRealm:      datatype.DiameterIdentity("cgrates.org"),
+			VendorID:         datatype.Unsigned32(0),
+			ProductName:      datatype.UTF8String("CGRateS"),
+			FirmwareRevision: datatype.Unsigned32(utils.DIAMETER_FIRMWARE_REVISION),
+			HostIPAddresses:  []datatype.Address{datatype.Address("127.0.0.1")},
+		})
+	m := diam.NewRequest(diam.CreditControl, 4, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("proxied;1449573472;00001"))
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.CCRequestNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+	pxyAns, err := peer.send(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pxyAns.Header.HopByHopID != m.Header.HopByHopID {
+		t.Errorf("expecting HopByHopID: %d, received: %d",
+			m.Header.HopByHopID, pxyAns.Header.HopByHopID)
+	}
+	agReq := newAgentRequest(newDADataProvider(nil, m), nil, nil, nil,
+		"cgrates.org", "", nil)
+	if _, err := agReq.FieldAsString([]string{utils.MetaAns, "Result-Code"}); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	agReq.Answer = newDADataProvider(nil, pxyAns)
+	if rc, err := agReq.FieldAsString([]string{utils.MetaAns, "Result-Code"}); err != nil {
+		t.Error(err)
+	} else if rc != "2001" {
+		t.Errorf("expecting: 2001, received: <%s>", rc)
+	}
+	if ccTime, err := agReq.FieldAsString([]string{utils.MetaAns,
+		"Granted-Service-Unit", "CC-Time"}); err != nil {
+		t.Error(err)
+	} else if ccTime != "300" {
+		t.Errorf("expecting: 300, received: <%s>", ccTime)
+	}
+	// relayed answer keeps the upstream AVPs and the HopByHopID of the original request
+	req := diam.NewRequest(diam.CreditControl, 4, nil)
+	a := diamRelayedAnswer(req, pxyAns)
+	if a.Header.HopByHopID != req.Header.HopByHopID {
+		t.Errorf("expecting HopByHopID: %d, received: %d",
+			req.Header.HopByHopID, a.Header.HopByHopID)
+	}
+	if oh, err := newDADataProvider(nil, a).FieldAsString([]string{"Origin-Host"}); err != nil {
+		t.Error(err)
+	} else if oh != "OCS-StandIn" {
+		t.Errorf("expecting: OCS-StandIn, received: <%s>", oh)
+	}
+}
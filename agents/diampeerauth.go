@@ -0,0 +1,124 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
+	"github.com/fiorix/go-diameter/diam/datatype"
+)
+
+// DiamPeerStats holds the request statistics of one Diameter peer, indexed on Origin-Host
+type DiamPeerStats struct {
+	Requests    int       // requests received from the peer
+	Answered    int       // requests answered successfully
+	Errors      int       // requests answered with error
+	Rejected    int       // CERs rejected with DIAMETER_UNKNOWN_PEER
+	LastRequest time.Time // time of the last request received
+}
+
+// diamOriginHost returns the Origin-Host of the message, empty if not present
+func diamOriginHost(m *diam.Message) (originHost string) {
+	ohAVP, err := m.FindAVP(avp.OriginHost, 0)
+	if err != nil {
+		return
+	}
+	originHost, _ = diamAVPAsString(ohAVP)
+	return
+}
+
+// peerAllowed checks the peer against the allowed peers table, empty table allows all
+func (da *DiameterAgent) peerAllowed(originHost, originRealm string) bool {
+	if len(da.cgrCfg.DiameterAgentCfg().AllowedPeers) == 0 {
+		return true
+	}
+	for _, allowedPeer := range da.cgrCfg.DiameterAgentCfg().AllowedPeers {
+		if allowedPeer.Allows(originHost, originRealm) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeCER validates the peer sending the CER, answering with DIAMETER_UNKNOWN_PEER if not allowed
+func (da *DiameterAgent) authorizeCER(c diam.Conn, m *diam.Message) (allowed bool) {
+	diamDP := newDADataProvider(c, m)
+	originHost, _ := diamDP.FieldAsString([]string{"Origin-Host"})
+	originRealm, _ := diamDP.FieldAsString([]string{"Origin-Realm"})
+	if allowed = da.peerAllowed(originHost, originRealm); allowed {
+		return
+	}
+	utils.Logger.Warning(
+		fmt.Sprintf("<%s> rejecting unknown peer with Origin-Host: <%s>, Origin-Realm: <%s> from %s",
+			utils.DiameterAgent, originHost, originRealm, c.RemoteAddr()))
+	da.peerStatsLck.Lock()
+	da.peerStat(originHost).Rejected++
+	da.peerStatsLck.Unlock()
+	a := diamBareErr(m, diam.UnknownPeer)
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0,
+		datatype.DiameterIdentity(da.cgrCfg.DiameterAgentCfg().OriginHost))
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0,
+		datatype.DiameterIdentity(da.cgrCfg.DiameterAgentCfg().OriginRealm))
+	writeOnConn(c, a)
+	c.Close()
+	return
+}
+
+// peerStat returns the statistics of the peer, creating them if not present
+// should be called under peerStatsLck
+func (da *DiameterAgent) peerStat(originHost string) (ps *DiamPeerStats) {
+	var has bool
+	if ps, has = da.peerStats[originHost]; !has {
+		ps = new(DiamPeerStats)
+		da.peerStats[originHost] = ps
+	}
+	return
+}
+
+// countPeerRequest updates the statistics of the peer with one request
+func (da *DiameterAgent) countPeerRequest(originHost string, answered bool) {
+	da.peerStatsLck.Lock()
+	ps := da.peerStat(originHost)
+	ps.Requests++
+	if answered {
+		ps.Answered++
+	} else {
+		ps.Errors++
+	}
+	ps.LastRequest = time.Now()
+	da.peerStatsLck.Unlock()
+}
+
+// V1GetPeerStats returns the request statistics of the peers, indexed on Origin-Host
+func (da *DiameterAgent) V1GetPeerStats(ignParam string,
+	reply *map[string]*DiamPeerStats) error {
+	da.peerStatsLck.RLock()
+	stats := make(map[string]*DiamPeerStats, len(da.peerStats))
+	for originHost, ps := range da.peerStats {
+		psClone := *ps
+		stats[originHost] = &psClone
+	}
+	da.peerStatsLck.RUnlock()
+	*reply = stats
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
+	"github.com/fiorix/go-diameter/diam/datatype"
+)
+
+func TestDAPeerAllowed(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	da := &DiameterAgent{cgrCfg: cfg, peerStats: make(map[string]*DiamPeerStats)}
+	if !da.peerAllowed("pcef1", "roaming.org") {
+		t.Error("peer should be allowed with empty allowed_peers")
+	}
+	cfg.DiameterAgentCfg().AllowedPeers = []*config.DiameterAllowedPeerCfg{
+		{OriginHost: "pcef1", OriginRealm: "roaming.org"},
+		{OriginRealm: "partner.org"},
+	}
+	if !da.peerAllowed("pcef1", "roaming.org") {
+		t.Error("pcef1@roaming.org should be allowed")
+	}
+	if da.peerAllowed("pcef2", "roaming.org") {
+		t.Error("pcef2@roaming.org should not be allowed")
+	}
+	if !da.peerAllowed("any", "partner.org") {
+		t.Error("any@partner.org should be allowed")
+	}
+	if da.peerAllowed("pcef1", "unknown.org") {
+		t.Error("pcef1@unknown.org should not be allowed")
+	}
+}
+
+func TestDAPeerStats(t *testing.T) {
+	da := &DiameterAgent{peerStats: make(map[string]*DiamPeerStats)}
+	m := diam.NewRequest(diam.CreditControl, 4, nil)
+	if oh := diamOriginHost(m); oh != "" {
+		t.Errorf("expecting empty Origin-Host, received: <%s>", oh)
+	}
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("pcef1"))
+	oh := diamOriginHost(m)
+	if oh != "pcef1" {
+		t.Errorf("expecting: pcef1, received: <%s>", oh)
+	}
+	da.countPeerRequest(oh, true)
+	da.countPeerRequest(oh, true)
+	da.countPeerRequest(oh, false)
+	var stats map[string]*DiamPeerStats
+	if err := da.V1GetPeerStats("", &stats); err != nil {
+		t.Error(err)
+	} else if ps, has := stats["pcef1"]; !has {
+		t.Errorf("no stats for pcef1: %+v", stats)
+	} else if ps.Requests != 3 || ps.Answered != 2 || ps.Errors != 1 ||
+		ps.LastRequest.IsZero() {
+		t.Errorf("unexpected stats: %+v", ps)
+	}
+	// returned stats are a copy
+	stats["pcef1"].Requests = 0
+	if da.peerStats["pcef1"].Requests != 3 {
+		t.Errorf("stats modified from outside: %+v", da.peerStats["pcef1"])
+	}
+}
@@ -19,8 +19,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
@@ -31,12 +34,22 @@ import (
 
 // NewHttpAgent will construct a HTTPAgent
 func NewHTTPAgent(sessionS rpcclient.RpcClientConnection,
-	filterS *engine.FilterS, dfltTenant, reqPayload, rplyPayload string,
-	reqProcessors []*config.HttpAgntProcCfg) *HTTPAgent {
-	return &HTTPAgent{sessionS: sessionS, filterS: filterS,
+	filterS *engine.FilterS, dfltTenant string,
+	agntCfg *config.HttpAgentCfg) *HTTPAgent {
+	ha := &HTTPAgent{sessionS: sessionS, filterS: filterS,
 		dfltTenant: dfltTenant,
-		reqPayload: reqPayload, rplyPayload: rplyPayload,
-		reqProcessors: reqProcessors}
+		reqPayload: agntCfg.RequestPayload, rplyPayload: agntCfg.ReplyPayload,
+		reqProcessors: agntCfg.RequestProcessors,
+		sessionKey:    agntCfg.SessionKey,
+		idleTimeout:   agntCfg.SessionIdleTimeout,
+		disconnectURL: agntCfg.DisconnectURL,
+		sessions:      make(map[string]*haSession)}
+	if ha.disconnectURL != "" {
+		ha.httpPoster = engine.NewHTTPPoster(
+			config.CgrConfig().GeneralCfg().HttpSkipTlsVerify,
+			config.CgrConfig().GeneralCfg().ReplyTimeout)
+	}
+	return ha
 }
 
 // HTTPAgent is a handler for HTTP requests
@@ -47,6 +60,20 @@ type HTTPAgent struct {
 	reqPayload,
 	rplyPayload string
 	reqProcessors []*config.HttpAgntProcCfg
+	sessionKey    config.RSRParsers // correlates the requests of the same session
+	idleTimeout   time.Duration
+	disconnectURL string
+	httpPoster    *engine.HTTPPoster
+	sessions      map[string]*haSession // active sessions indexed on session key
+	ssMux         sync.Mutex            // protects sessions
+}
+
+// haSession is a session initiated via HTTPAgent
+type haSession struct {
+	cgrEv    *utils.CGREvent // last event sent to SessionS, used on termination
+	flags    utils.StringMap // flags of the processor sending the event
+	lastUsed time.Time
+	idleTmr  *time.Timer
 }
 
 // ServeHTTP implements http.Handler interface
@@ -81,6 +108,12 @@ func (ha *HTTPAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 					utils.HTTPAgent, err.Error()))
 			return
 		}
+		if err = writeReplyHeaders(w, agReq.Reply); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s writing headers out of %s",
+					utils.HTTPAgent, err.Error(), utils.ToJSON(agReq.Reply)))
+			return
+		}
 		if err = encdr.Encode(agReq.Reply); err != nil {
 			utils.Logger.Warning(
 				fmt.Sprintf("<%s> error: %s encoding out %s",
@@ -115,6 +148,14 @@ func (ha *HTTPAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
 			break
 		}
 	}
+	var ssKey string // empty for stateless processing
+	if len(ha.sessionKey) != 0 &&
+		utils.IsSliceMember([]string{utils.MetaInitiate,
+			utils.MetaUpdate, utils.MetaTerminate}, reqType) {
+		if ssKey, err = ha.sessionKey.ParseDataProvider(agReq, utils.NestingSep); err != nil {
+			return
+		}
+	}
 	if reqProcessor.Flags.HasKey(utils.MetaLog) {
 		utils.Logger.Info(
 			fmt.Sprintf("<%s> LOG, processorID: %s, http message: %s",
@@ -154,6 +195,9 @@ func (ha *HTTPAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
 		var initReply sessions.V1InitSessionReply
 		err = ha.sessionS.Call(utils.SessionSv1InitiateSession,
 			initArgs, &initReply)
+		if err == nil {
+			ha.refreshSession(ssKey, cgrEv, reqProcessor.Flags)
+		}
 		if agReq.CGRReply, err = NewCGRReply(&initReply, err); err != nil {
 			return
 		}
@@ -164,6 +208,9 @@ func (ha *HTTPAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
 		var updateReply sessions.V1UpdateSessionReply
 		err = ha.sessionS.Call(utils.SessionSv1UpdateSession,
 			updateArgs, &updateReply)
+		if err == nil {
+			ha.refreshSession(ssKey, cgrEv, reqProcessor.Flags)
+		}
 		if agReq.CGRReply, err = NewCGRReply(&updateReply, err); err != nil {
 			return
 		}
@@ -176,6 +223,9 @@ func (ha *HTTPAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
 		var tRply string
 		err = ha.sessionS.Call(utils.SessionSv1TerminateSession,
 			terminateArgs, &tRply)
+		if err == nil {
+			ha.removeSession(ssKey)
+		}
 		if agReq.CGRReply, err = NewCGRReply(nil, err); err != nil {
 			return
 		}
@@ -226,3 +276,135 @@ func (ha *HTTPAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
 	}
 	return true, nil
 }
+
+// refreshSession stores the last event of the session, restarting its idle timer
+func (ha *HTTPAgent) refreshSession(ssKey string, cgrEv *utils.CGREvent, flags utils.StringMap) {
+	if ssKey == "" {
+		return
+	}
+	ha.ssMux.Lock()
+	defer ha.ssMux.Unlock()
+	if ss, has := ha.sessions[ssKey]; has {
+		ss.cgrEv = cgrEv
+		ss.flags = flags
+		ss.lastUsed = time.Now()
+		if ss.idleTmr != nil {
+			ss.idleTmr.Reset(ha.idleTimeout)
+		}
+		return
+	}
+	ss := &haSession{cgrEv: cgrEv, flags: flags, lastUsed: time.Now()}
+	if ha.idleTimeout > 0 {
+		ss.idleTmr = time.AfterFunc(ha.idleTimeout,
+			func() { ha.terminateIdleSession(ssKey, ss) })
+	}
+	ha.sessions[ssKey] = ss
+}
+
+// removeSession stops tracking the session
+func (ha *HTTPAgent) removeSession(ssKey string) {
+	if ssKey == "" {
+		return
+	}
+	ha.ssMux.Lock()
+	defer ha.ssMux.Unlock()
+	ss, has := ha.sessions[ssKey]
+	if !has {
+		return
+	}
+	if ss.idleTmr != nil {
+		ss.idleTmr.Stop()
+	}
+	delete(ha.sessions, ssKey)
+}
+
+// terminateIdleSession terminates via SessionS the session not receiving requests within idle timeout
+// the usage is not known so the session is terminated with the one already debited
+func (ha *HTTPAgent) terminateIdleSession(ssKey string, ss *haSession) {
+	ha.ssMux.Lock()
+	if ha.sessions[ssKey] != ss ||
+		time.Since(ss.lastUsed) < ha.idleTimeout { // refreshed in the meantime
+		ha.ssMux.Unlock()
+		return
+	}
+	delete(ha.sessions, ssKey)
+	ha.ssMux.Unlock()
+	ev := make(map[string]interface{})
+	for fldName, fldVal := range ss.cgrEv.Event {
+		if fldName == utils.Usage || fldName == utils.LastUsed {
+			continue
+		}
+		ev[fldName] = fldVal
+	}
+	terminateArgs := sessions.NewV1TerminateSessionArgs(
+		ss.flags.HasKey(utils.MetaAccounts),
+		ss.flags.HasKey(utils.MetaResources),
+		ss.flags.HasKey(utils.MetaThresholds),
+		ss.flags.HasKey(utils.MetaStats),
+		utils.CGREvent{Tenant: ss.cgrEv.Tenant,
+			ID: utils.UUIDSha1Prefix(), Event: ev})
+	var tRply string
+	if err := ha.sessionS.Call(utils.SessionSv1TerminateSession,
+		terminateArgs, &tRply); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s terminating idle session with key: <%s>",
+				utils.HTTPAgent, err.Error(), ssKey))
+	}
+}
+
+// haDisconnectEvent is posted towards disconnect_url when SessionS disconnects a session
+type haDisconnectEvent struct {
+	SessionKey string
+	EventStart map[string]interface{}
+	Reason     string
+}
+
+// rpcclient.RpcClientConnection interface
+func (ha *HTTPAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.RPCCall(ha, serviceMethod, args, reply)
+}
+
+// V1DisconnectSession is part of the sessions.SessionSClient
+// the disconnect is posted towards the disconnect_url, the session not being tracked further
+func (ha *HTTPAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) (err error) {
+	if ha.disconnectURL == "" {
+		return utils.ErrNotImplemented
+	}
+	originID, has := args.EventStart[utils.OriginID]
+	if !has {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> cannot disconnect session, missing OriginID in event: %s",
+				utils.HTTPAgent, utils.ToJSON(args.EventStart)))
+		return utils.ErrMandatoryIeMissing
+	}
+	var ssKey string
+	ha.ssMux.Lock()
+	for key, ss := range ha.sessions {
+		if ss.cgrEv.Event[utils.OriginID] == originID {
+			ssKey = key
+			break
+		}
+	}
+	ha.ssMux.Unlock()
+	ha.removeSession(ssKey) // SessionS terminated it already
+	body, err := json.Marshal(&haDisconnectEvent{SessionKey: ssKey,
+		EventStart: args.EventStart, Reason: args.Reason})
+	if err != nil {
+		return
+	}
+	if _, err = ha.httpPoster.Post(ha.disconnectURL, utils.CONTENT_JSON, body,
+		config.CgrConfig().GeneralCfg().PosterAttempts, utils.META_NONE); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot disconnect session with OriginID: <%s>, err: %s",
+				utils.HTTPAgent, originID, err.Error()))
+		return utils.ErrServerError
+	}
+	*reply = utils.OK
+	return
+}
+
+// V1GetActiveSessionIDs is part of the sessions.SessionSClient
+func (ha *HTTPAgent) V1GetActiveSessionIDs(ignParam string,
+	sessionIDs *[]*sessions.SessionID) error {
+	return utils.ErrNotImplemented
+}
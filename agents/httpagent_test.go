@@ -0,0 +1,163 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/sessions"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testHASessionS initiates all sessions, passing the terminate events to the channel
+type testHASessionS struct {
+	tEvs chan *utils.CGREvent
+}
+
+func (sS *testHASessionS) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	switch serviceMethod {
+	case utils.SessionSv1InitiateSession:
+		maxUsage := time.Minute
+		reply.(*sessions.V1InitSessionReply).MaxUsage = &maxUsage
+	case utils.SessionSv1TerminateSession:
+		sS.tEvs <- &args.(*sessions.V1TerminateSessionArgs).CGREvent
+		*reply.(*string) = utils.OK
+	default:
+		return utils.ErrNotImplemented
+	}
+	return nil
+}
+
+func TestHTTPAgentSessions(t *testing.T) {
+	cfg, err := config.NewCGRConfigFromJsonStringWithDefaults(`{
+"http_agent": [
+	{
+		"id": "metering",
+		"url": "/metering",
+		"request_payload":	"*url",
+		"reply_payload":	"*json",
+		"session_key": "~*req.session",
+		"session_idle_timeout": "50ms",
+		"request_processors": [
+			{
+				"id": "init",
+				"filters": ["*string:*req.request_type:init"],
+				"flags": ["*initiate", "*accounts"],
+				"request_fields":[
+					{"tag": "OriginID", "field_id": "OriginID", "type": "*composed",
+						"value": "~*req.session", "mandatory": true},
+					{"tag": "Account", "field_id": "Account", "type": "*composed",
+						"value": "~*req.account", "mandatory": true},
+					{"tag": "Usage", "field_id": "Usage", "type": "*constant", "value": "1m"},
+				],
+				"reply_fields":[
+					{"tag": "MaxUsage", "field_id": "MaxUsage", "type": "*composed",
+						"value": "~*cgrep.MaxUsage"},
+				],
+			},
+		],
+	},
+],
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := engine.NewMapStorage()
+	dm := engine.NewDataManager(data)
+	sS := &testHASessionS{tEvs: make(chan *utils.CGREvent, 1)}
+	ha := NewHTTPAgent(sS, engine.NewFilterS(cfg, nil, dm),
+		"cgrates.org", cfg.HttpAgentCfg()[0])
+	w := httptest.NewRecorder()
+	ha.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/metering?request_type=init&session=s1&account=1001", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("received code: %d", w.Code)
+	}
+	ha.ssMux.Lock()
+	_, has := ha.sessions["s1"]
+	ha.ssMux.Unlock()
+	if !has {
+		t.Fatal("session s1 not tracked")
+	}
+	select {
+	case tEv := <-sS.tEvs:
+		if tEv.Event[utils.OriginID] != "s1" || tEv.Event[utils.Account] != "1001" {
+			t.Errorf("received terminate event: %s", utils.ToJSON(tEv))
+		} else if _, has := tEv.Event[utils.Usage]; has {
+			t.Errorf("unexpected usage in terminate event: %s", utils.ToJSON(tEv))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("idle session not terminated")
+	}
+	ha.ssMux.Lock()
+	_, has = ha.sessions["s1"]
+	ha.ssMux.Unlock()
+	if has {
+		t.Error("session s1 still tracked after termination")
+	}
+}
+
+func TestHTTPAgentV1DisconnectSession(t *testing.T) {
+	disconnects := make(chan *haDisconnectEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var dEv haDisconnectEvent
+		if err := json.Unmarshal(body, &dEv); err != nil {
+			t.Error(err)
+		}
+		disconnects <- &dEv
+	}))
+	defer srv.Close()
+	ha := NewHTTPAgent(nil, nil, "cgrates.org", &config.HttpAgentCfg{
+		SessionKey:    config.NewRSRParsersMustCompile("~*req.session", true, utils.INFIELD_SEP),
+		DisconnectURL: srv.URL})
+	ha.refreshSession("s1", &utils.CGREvent{Tenant: "cgrates.org",
+		Event: map[string]interface{}{utils.OriginID: "origin1"}}, nil)
+	var reply string
+	if err := ha.V1DisconnectSession(utils.AttrDisconnectSession{
+		EventStart: map[string]interface{}{utils.OriginID: "origin1"},
+		Reason:     "FORCED_DISCONNECT"}, &reply); err != nil {
+		t.Fatal(err)
+	} else if reply != utils.OK {
+		t.Errorf("received reply: %s", reply)
+	}
+	select {
+	case dEv := <-disconnects:
+		if dEv.SessionKey != "s1" || dEv.Reason != "FORCED_DISCONNECT" ||
+			dEv.EventStart[utils.OriginID] != "origin1" {
+			t.Errorf("received: %s", utils.ToJSON(dEv))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("disconnect not posted")
+	}
+	if len(ha.sessions) != 0 {
+		t.Errorf("sessions still tracked: %s", utils.ToJSON(ha.sessions))
+	}
+	if err := ha.V1DisconnectSession(utils.AttrDisconnectSession{
+		EventStart: map[string]interface{}{}}, &reply); err != utils.ErrMandatoryIeMissing {
+		t.Errorf("expecting: %v, received: %v", utils.ErrMandatoryIeMissing, err)
+	}
+}
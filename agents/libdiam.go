@@ -476,6 +476,16 @@ func diamAnswer(m *diam.Message, resCode uint32, errFlag bool,
 	return
 }
 
+// diamRelayedAnswer builds the answer to m out of the one received from the upstream peer
+func diamRelayedAnswer(m, pxyAns *diam.Message) (a *diam.Message) {
+	a = newDiamAnswer(m, 0)
+	a.Header.CommandFlags = pxyAns.Header.CommandFlags
+	for _, ansAVP := range pxyAns.AVP {
+		a.AddAVP(ansAVP)
+	}
+	return
+}
+
 // negDiamAnswer is used to return the negative answer we need previous to
 func diamErr(m *diam.Message, resCode uint32,
 	reqVars map[string]interface{},
@@ -527,6 +537,30 @@ func newDiamAnswer(m *diam.Message, resCode uint32) *diam.Message {
 	return nm
 }
 
+// diamResultCodeErr returns an error when the Result-Code (or Experimental-Result-Code) of the answer is not a success
+func diamResultCodeErr(m *diam.Message) error {
+	for _, path := range [][]interface{}{
+		{"Result-Code"},
+		{"Experimental-Result", "Experimental-Result-Code"}} {
+		avps, err := m.FindAVPsWithPath(path, dict.UndefinedVendorID)
+		if err != nil {
+			return err
+		}
+		if len(avps) == 0 {
+			continue
+		}
+		resCode, canCast := avps[0].Data.(datatype.Unsigned32)
+		if !canCast {
+			return fmt.Errorf("unexpected Result-Code: %v", avps[0].Data)
+		}
+		if resCode < 2000 || resCode >= 3000 { // 2xxx are the success codes
+			return fmt.Errorf("answered with Result-Code: %d", resCode)
+		}
+		return nil
+	}
+	return errors.New("missing Result-Code")
+}
+
 // diamMessageData is cached when data is needed (ie. )
 type diamMsgData struct {
 	c    diam.Conn
@@ -685,3 +685,35 @@ func TestUpdateDiamMsgFromNavMap4(t *testing.T) {
 		t.Errorf("Expected %s, recived %s", utils.ToJSON(eMessage), utils.ToJSON(m2))
 	}
 }
+
+func TestDiamAnswerGxChargingRuleInstall(t *testing.T) {
+	if err := loadDictionaries("../data/diameter/dict/gx", utils.DiameterAgent); err != nil {
+		t.Fatal(err)
+	}
+	m := diam.NewRequest(diam.CreditControl, 16777238, nil) // Gx
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("pcef1;1449573472;00001"))
+	m.NewAVP("CC-Request-Type", avp.Mbit, 0, datatype.Enumerated(1))
+	nM := config.NewNavigableMap(nil)
+	for _, itm := range []*config.NMItem{
+		{Path: []string{"Session-Id"}, Data: "pcef1;1449573472;00001"},
+		{Path: []string{"Result-Code"}, Data: "2001"},
+		{Path: []string{"Charging-Rule-Install", "Charging-Rule-Name"}, Data: "rule_gold"},
+	} {
+		nM.Set(itm.Path, []*config.NMItem{itm}, true, true)
+	}
+	a, err := diamAnswer(m, 0, false, nM, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aDP := newDADataProvider(nil, a)
+	if rc, err := aDP.FieldAsString([]string{"Result-Code"}); err != nil {
+		t.Error(err)
+	} else if rc != "2001" {
+		t.Errorf("expecting: 2001, received: <%s>", rc)
+	}
+	if ruleName, err := aDP.FieldAsString([]string{"Charging-Rule-Install", "Charging-Rule-Name"}); err != nil {
+		t.Error(err)
+	} else if ruleName != "rule_gold" {
+		t.Errorf("expecting: rule_gold, received: <%s>", ruleName)
+	}
+}
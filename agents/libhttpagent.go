@@ -19,6 +19,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -43,8 +44,23 @@ func newHADataProvider(reqPayload string,
 		return newHTTPUrlDP(req)
 	case utils.MetaXml:
 		return newHTTPXmlDP(req)
+	case utils.MetaJSON:
+		return newHTTPJSONDP(req)
+	case utils.MetaForm:
+		return newHTTPFormDP(req)
+	}
+}
 
+// httpHeaderField returns the value of the request header in fldPath, prefixed by *hdr
+func httpHeaderField(hdr http.Header, fldPath []string) (data interface{}, err error) {
+	if len(fldPath) != 2 {
+		return nil, utils.ErrNotFound
+	}
+	vals, has := hdr[http.CanonicalHeaderKey(fldPath[1])]
+	if !has || len(vals) == 0 {
+		return nil, utils.ErrNotFound
 	}
+	return vals[0], nil
 }
 
 func newHTTPUrlDP(req *http.Request) (dP config.DataProvider, err error) {
@@ -68,6 +84,9 @@ func (hU *httpUrlDP) String() string {
 
 // FieldAsInterface is part of engine.DataProvider interface
 func (hU *httpUrlDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) != 0 && fldPath[0] == utils.MetaHdr {
+		return httpHeaderField(hU.req.Header, fldPath)
+	}
 	if len(fldPath) != 1 {
 		return nil, utils.ErrNotFound
 	}
@@ -116,7 +135,8 @@ func newHTTPXmlDP(req *http.Request) (dP config.DataProvider, err error) {
 	if err != nil {
 		return nil, err
 	}
-	dP = &httpXmlDP{xmlDoc: doc, cache: config.NewNavigableMap(nil), addr: req.RemoteAddr}
+	dP = &httpXmlDP{xmlDoc: doc, cache: config.NewNavigableMap(nil),
+		addr: req.RemoteAddr, hdr: req.Header}
 	return
 }
 
@@ -126,6 +146,7 @@ type httpXmlDP struct {
 	cache  *config.NavigableMap
 	xmlDoc *xmlquery.Node
 	addr   string
+	hdr    http.Header
 }
 
 // String is part of engine.DataProvider interface
@@ -140,6 +161,9 @@ func (hU *httpXmlDP) FieldAsInterface(fldPath []string) (data interface{}, err e
 	if len(fldPath) == 0 {
 		return nil, fmt.Errorf("Empty path")
 	}
+	if fldPath[0] == utils.MetaHdr {
+		return httpHeaderField(hU.hdr, fldPath)
+	}
 	if data, err = hU.cache.FieldAsInterface(fldPath); err == nil ||
 		err != utils.ErrNotFound { // item found in cache
 		return
@@ -201,6 +225,172 @@ func (hU *httpXmlDP) RemoteHost() net.Addr {
 	return utils.NewNetAddr("TCP", hU.addr)
 }
 
+func newHTTPJSONDP(req *http.Request) (dP config.DataProvider, err error) {
+	var body interface{}
+	dec := json.NewDecoder(req.Body)
+	dec.UseNumber() // keep the numbers as received
+	if err = dec.Decode(&body); err != nil {
+		return nil, err
+	}
+	dP = &httpJSONDP{req: req, body: body}
+	return
+}
+
+// httpJSONDP implements engine.DataProvider, serving as json data decoder
+// the body is decoded once, the fields being navigated directly within it
+type httpJSONDP struct {
+	req  *http.Request
+	body interface{}
+}
+
+// String is part of engine.DataProvider interface
+func (hJ *httpJSONDP) String() string {
+	return utils.ToJSON(hJ.body)
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+// the elements of the arrays are selected by index, ie: items[0].id
+func (hJ *httpJSONDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) == 0 {
+		return nil, fmt.Errorf("Empty path")
+	}
+	if fldPath[0] == utils.MetaHdr {
+		return httpHeaderField(hJ.req.Header, fldPath)
+	}
+	return jsonFieldAsInterface(hJ.body, fldPath)
+}
+
+// jsonFieldAsInterface navigates the decoded json data following fldPath
+func jsonFieldAsInterface(data interface{}, fldPath []string) (interface{}, error) {
+	for _, spath := range fldPath {
+		idx := -1
+		if sIdx := strings.Index(spath, utils.IdxStart); sIdx != -1 &&
+			strings.HasSuffix(spath, utils.IdxEnd) {
+			var err error
+			if idx, err = strconv.Atoi(spath[sIdx+1 : len(spath)-1]); err != nil {
+				return nil, err
+			}
+			spath = spath[:sIdx]
+		}
+		if spath != "" { // arrays can be selected directly, ie: [0]
+			mp, canCast := data.(map[string]interface{})
+			if !canCast {
+				return nil, utils.ErrNotFound
+			}
+			var has bool
+			if data, has = mp[spath]; !has {
+				return nil, utils.ErrNotFound
+			}
+		}
+		if idx != -1 {
+			sl, canCast := data.([]interface{})
+			if !canCast || idx < 0 || idx >= len(sl) {
+				return nil, utils.ErrNotFound
+			}
+			data = sl[idx]
+		}
+	}
+	return data, nil
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (hJ *httpJSONDP) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	valIface, err = hJ.FieldAsInterface(fldPath)
+	if err != nil {
+		return
+	}
+	data, err = utils.IfaceAsString(valIface)
+	return
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (hJ *httpJSONDP) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
+// RemoteHost is part of engine.DataProvider interface
+func (hJ *httpJSONDP) RemoteHost() net.Addr {
+	return utils.NewNetAddr("TCP", hJ.req.RemoteAddr)
+}
+
+func newHTTPFormDP(req *http.Request) (dP config.DataProvider, err error) {
+	if err = req.ParseForm(); err != nil {
+		return nil, err
+	}
+	dP = &httpFormDP{req: req}
+	return
+}
+
+// httpFormDP implements engine.DataProvider, serving as form-urlencoded data decoder
+// nested fields are encoded with brackets, ie: the path data.object.id is read out of data[object][id]
+type httpFormDP struct {
+	req *http.Request
+}
+
+// String is part of engine.DataProvider interface
+func (hF *httpFormDP) String() string {
+	return hF.req.Form.Encode()
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+// repeated fields are selected by index on the last path element, ie: phone[1]
+func (hF *httpFormDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) == 0 {
+		return nil, fmt.Errorf("Empty path")
+	}
+	if fldPath[0] == utils.MetaHdr {
+		return httpHeaderField(hF.req.Header, fldPath)
+	}
+	key := fldPath[0]
+	for _, spath := range fldPath[1:] {
+		key += utils.IdxStart + spath + utils.IdxEnd
+	}
+	if vals, has := hF.req.Form[key]; has && len(vals) != 0 {
+		return vals[0], nil
+	}
+	sIdx := strings.LastIndex(key, utils.IdxStart)
+	if sIdx == -1 || !strings.HasSuffix(key, utils.IdxEnd) {
+		return nil, utils.ErrNotFound
+	}
+	idx, err := strconv.Atoi(key[sIdx+1 : len(key)-1])
+	if err != nil {
+		return nil, utils.ErrNotFound
+	}
+	key = key[:sIdx]
+	vals, has := hF.req.Form[key]
+	if !has {
+		vals = hF.req.Form[key+utils.IdxStart+utils.IdxEnd] // ie: phone[]
+	}
+	if idx < 0 || idx >= len(vals) {
+		return nil, utils.ErrNotFound
+	}
+	return vals[idx], nil
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (hF *httpFormDP) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	valIface, err = hF.FieldAsInterface(fldPath)
+	if err != nil {
+		return
+	}
+	data, err = utils.IfaceAsString(valIface)
+	return
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (hF *httpFormDP) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
+// RemoteHost is part of engine.DataProvider interface
+func (hF *httpFormDP) RemoteHost() net.Addr {
+	return utils.NewNetAddr("TCP", hF.req.RemoteAddr)
+}
+
 // httpAgentReplyEncoder will encode  []*engine.NMElement
 // and write content to http writer
 type httpAgentReplyEncoder interface {
@@ -215,9 +405,41 @@ func newHAReplyEncoder(encType string,
 		return nil, fmt.Errorf("unsupported encoder type <%s>", encType)
 	case utils.MetaXml:
 		return newHAXMLEncoder(w)
+	case utils.MetaJSON:
+		return newHAJSONEncoder(w)
 	}
 }
 
+// writeReplyHeaders sets the reply fields prefixed by *hdr as HTTP headers, removing them out of the reply
+func writeReplyHeaders(w http.ResponseWriter, nM *config.NavigableMap) (err error) {
+	var hdrs interface{}
+	if hdrs, err = nM.FieldAsInterface([]string{utils.MetaHdr}); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	hdrMp, canCast := hdrs.(map[string]interface{})
+	if !canCast {
+		return fmt.Errorf("cannot cast headers: %s", utils.ToJSON(hdrs))
+	}
+	for hdrName, val := range hdrMp {
+		nmItms, isNMItems := val.([]*config.NMItem)
+		if !isNMItems {
+			return fmt.Errorf("value: %+v is not []*NMItem", val)
+		}
+		for _, nmItm := range nmItms {
+			hdrVal, err := utils.IfaceAsString(nmItm.Data)
+			if err != nil {
+				return err
+			}
+			w.Header().Add(hdrName, hdrVal)
+		}
+	}
+	nM.Remove([]string{utils.MetaHdr})
+	return
+}
+
 func newHAXMLEncoder(w http.ResponseWriter) (xE httpAgentReplyEncoder, err error) {
 	return &haXMLEncoder{w: w}, nil
 }
@@ -245,3 +467,31 @@ func (xE *haXMLEncoder) Encode(nM *config.NavigableMap) (err error) {
 	_, err = xE.w.Write(xmlOut)
 	return
 }
+
+func newHAJSONEncoder(w http.ResponseWriter) (jE httpAgentReplyEncoder, err error) {
+	return &haJSONEncoder{w: w}, nil
+}
+
+type haJSONEncoder struct {
+	w http.ResponseWriter
+}
+
+// Encode implements httpAgentReplyEncoder
+func (jE *haJSONEncoder) Encode(nM *config.NavigableMap) (err error) {
+	var mp map[string]interface{}
+	if mp, err = nM.AsJSONMap(); err != nil {
+		return
+	}
+	if len(mp) == 0 {
+		return
+	}
+	var jsnOut []byte
+	if jsnOut, err = json.Marshal(mp); err != nil {
+		return
+	}
+	if jE.w.Header().Get("Content-Type") == "" {
+		jE.w.Header().Set("Content-Type", "application/json")
+	}
+	_, err = jE.w.Write(jsnOut)
+	return
+}
@@ -22,9 +22,12 @@ import (
 	"bufio"
 	"bytes"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
-	//"github.com/cgrates/cgrates/utils"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestHttpUrlDPFieldAsInterface(t *testing.T) {
@@ -191,3 +194,107 @@ func TestHttpXmlDPFieldAsInterface2(t *testing.T) {
 		t.Errorf("expecting: 0.0225, received: <%s>", data)
 	}
 }
+
+func TestHttpJSONDPFieldAsInterface(t *testing.T) {
+	body := `{
+	"event": "sms.delivered",
+	"data": {
+		"id": "msg_7f2a",
+		"to": "+4986517174963",
+		"parts": 2,
+		"price": {"amount": 0.045, "currency": "EUR"},
+		"segments": [{"id": 1, "status": "ok"}, {"id": 2, "status": "failed"}]
+	}
+}`
+	req, err := http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		t.Error(err)
+	}
+	req.Header.Set("X-Api-Key", "secret")
+	dP, err := newHTTPJSONDP(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, eVal := range map[string]string{
+		"event":                      "sms.delivered",
+		"data.to":                    "+4986517174963",
+		"data.parts":                 "2",
+		"data.price.amount":          "0.045",
+		"data.segments[1].status":    "failed",
+		utils.MetaHdr + ".x-api-key": "secret",
+	} {
+		if data, err := dP.FieldAsString(strings.Split(path, utils.NestingSep)); err != nil {
+			t.Errorf("path: %s, err: %v", path, err)
+		} else if data != eVal {
+			t.Errorf("path: %s, expecting: %s, received: <%s>", path, eVal, data)
+		}
+	}
+	for _, path := range []string{"data.from", "data.segments[2].status", "event.id",
+		utils.MetaHdr + ".Content-Type"} {
+		if _, err := dP.FieldAsString(strings.Split(path, utils.NestingSep)); err != utils.ErrNotFound {
+			t.Errorf("path: %s, expecting: %v, received: %v", path, utils.ErrNotFound, err)
+		}
+	}
+}
+
+func TestHttpFormDPFieldAsInterface(t *testing.T) {
+	body := "type=charge.succeeded&data[object][id]=ch_1&data[object][amount]=2000&phone[]=1001&phone[]=1002"
+	req, err := http.NewRequest("POST", "http://localhost:8080/?source=gateway", strings.NewReader(body))
+	if err != nil {
+		t.Error(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	dP, err := newHTTPFormDP(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, eVal := range map[string]string{
+		"type":                          "charge.succeeded",
+		"source":                        "gateway",
+		"data.object.id":                "ch_1",
+		"data.object.amount":            "2000",
+		"phone[1]":                      "1002",
+		utils.MetaHdr + ".Content-Type": "application/x-www-form-urlencoded",
+	} {
+		if data, err := dP.FieldAsString(strings.Split(path, utils.NestingSep)); err != nil {
+			t.Errorf("path: %s, err: %v", path, err)
+		} else if data != eVal {
+			t.Errorf("path: %s, expecting: %s, received: <%s>", path, eVal, data)
+		}
+	}
+	for _, path := range []string{"data.object.currency", "phone[2]"} {
+		if _, err := dP.FieldAsString(strings.Split(path, utils.NestingSep)); err != utils.ErrNotFound {
+			t.Errorf("path: %s, expecting: %v, received: %v", path, utils.ErrNotFound, err)
+		}
+	}
+}
+
+func TestHAJSONEncoder(t *testing.T) {
+	nM := config.NewNavigableMap(nil)
+	nM.Set([]string{utils.MetaHdr, "X-Request-Id"}, []*config.NMItem{
+		&config.NMItem{Path: []string{utils.MetaHdr, "X-Request-Id"}, Data: "req1"}}, false, true)
+	nM.Set([]string{"result", "status"}, []*config.NMItem{
+		&config.NMItem{Path: []string{"result", "status"}, Data: "OK"}}, false, true)
+	nM.Set([]string{"result", "max_usage"}, []*config.NMItem{
+		&config.NMItem{Path: []string{"result", "max_usage"}, Data: "120"}}, false, true)
+	w := httptest.NewRecorder()
+	if err := writeReplyHeaders(w, nM); err != nil {
+		t.Fatal(err)
+	}
+	encdr, err := newHAReplyEncoder(utils.MetaJSON, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := encdr.Encode(nM); err != nil {
+		t.Fatal(err)
+	}
+	if hdr := w.Header().Get("X-Request-Id"); hdr != "req1" {
+		t.Errorf("unexpected header: <%s>", hdr)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("unexpected content type: <%s>", ct)
+	}
+	if eOut := `{"result":{"max_usage":"120","status":"OK"}}`; w.Body.String() != eOut {
+		t.Errorf("expecting: %s, received: %s", eOut, w.Body.String())
+	}
+}
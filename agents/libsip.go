@@ -0,0 +1,409 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	sipVersion    = "SIP/2.0"
+	sipCRLF       = "\r\n"
+	sipINVITE     = "INVITE"
+	sipACK        = "ACK"
+	sipOPTIONS    = "OPTIONS"
+	sipMaxUDPSz   = 65535
+	sipMaxBodyLen = 65535 // same as a message fitting one datagram
+)
+
+// sipCompactHdrs translates the compact form of the header names (RFC 3261 7.3.3)
+var sipCompactHdrs = map[string]string{
+	"i": "Call-ID",
+	"m": "Contact",
+	"e": "Content-Encoding",
+	"l": "Content-Length",
+	"c": "Content-Type",
+	"f": "From",
+	"s": "Subject",
+	"k": "Supported",
+	"t": "To",
+	"v": "Via",
+}
+
+// sipReasons are the default reason phrases of the status codes sent out
+var sipReasons = map[int]string{
+	100: "Trying",
+	200: "OK",
+	300: "Multiple Choices",
+	301: "Moved Permanently",
+	302: "Moved Temporarily",
+	400: "Bad Request",
+	401: "Unauthorized",
+	402: "Payment Required",
+	403: "Forbidden",
+	404: "Not Found",
+	480: "Temporarily Unavailable",
+	486: "Busy Here",
+	487: "Request Terminated",
+	500: "Server Internal Error",
+	501: "Not Implemented",
+	503: "Service Unavailable",
+	603: "Decline",
+	604: "Does Not Exist Anywhere",
+}
+
+// sipHeader is one header line of a SIP message
+type sipHeader struct {
+	Name  string
+	Value string
+}
+
+// sipMessage is a SIP request or response (RFC 3261)
+// headers are kept in the order received since the order of Via headers matters
+type sipMessage struct {
+	Method     string // empty for responses
+	RequestURI string
+	StatusCode int
+	Reason     string
+	Headers    []*sipHeader
+	Body       []byte
+}
+
+// Header returns the value of the first header with the name specified, compact forms included
+func (m *sipMessage) Header(name string) string {
+	for _, hdr := range m.Headers {
+		if strings.EqualFold(hdr.Name, name) {
+			return hdr.Value
+		}
+	}
+	return ""
+}
+
+// HeaderValues returns the values of all headers with the name specified, in order
+func (m *sipMessage) HeaderValues(name string) (vals []string) {
+	for _, hdr := range m.Headers {
+		if strings.EqualFold(hdr.Name, name) {
+			vals = append(vals, hdr.Value)
+		}
+	}
+	return
+}
+
+// AddHeader appends a header to the message
+func (m *sipMessage) AddHeader(name, value string) {
+	m.Headers = append(m.Headers, &sipHeader{Name: name, Value: value})
+}
+
+// Bytes encodes the message in wire format, Content-Length being computed out of the body
+func (m *sipMessage) Bytes() []byte {
+	var buf bytes.Buffer
+	if m.Method != "" {
+		fmt.Fprintf(&buf, "%s %s %s%s", m.Method, m.RequestURI, sipVersion, sipCRLF)
+	} else {
+		fmt.Fprintf(&buf, "%s %d %s%s", sipVersion, m.StatusCode, m.Reason, sipCRLF)
+	}
+	for _, hdr := range m.Headers {
+		if strings.EqualFold(hdr.Name, "Content-Length") {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s: %s%s", hdr.Name, hdr.Value, sipCRLF)
+	}
+	fmt.Fprintf(&buf, "Content-Length: %d%s%s", len(m.Body), sipCRLF, sipCRLF)
+	buf.Write(m.Body)
+	return buf.Bytes()
+}
+
+// String implements fmt.Stringer
+func (m *sipMessage) String() string {
+	return string(m.Bytes())
+}
+
+// parseSIPMessage decodes a SIP message out of its wire format
+// the body is the content after the empty line, Content-Length is only considered on streams
+func parseSIPMessage(b []byte) (m *sipMessage, err error) {
+	hdrPart, body := b, []byte(nil)
+	if idx := bytes.Index(b, []byte(sipCRLF+sipCRLF)); idx != -1 {
+		hdrPart, body = b[:idx], b[idx+4:]
+	} else if idx := bytes.Index(b, []byte("\n\n")); idx != -1 {
+		hdrPart, body = b[:idx], b[idx+2:]
+	}
+	lines := strings.Split(strings.TrimLeft(string(hdrPart), sipCRLF), "\n")
+	startLine := strings.TrimRight(lines[0], "\r")
+	splt := strings.SplitN(startLine, " ", 3)
+	if len(splt) != 3 {
+		return nil, fmt.Errorf("malformed start line: <%s>", startLine)
+	}
+	m = new(sipMessage)
+	if splt[0] == sipVersion { // response
+		if m.StatusCode, err = strconv.Atoi(splt[1]); err != nil {
+			return nil, fmt.Errorf("malformed status code: <%s>", splt[1])
+		}
+		m.Reason = splt[2]
+	} else {
+		if splt[2] != sipVersion {
+			return nil, fmt.Errorf("unsupported version: <%s>", splt[2])
+		}
+		m.Method, m.RequestURI = splt[0], splt[1]
+	}
+	for _, line := range lines[1:] {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' { // folded header, continuation of the previous one
+			if len(m.Headers) == 0 {
+				return nil, fmt.Errorf("malformed header line: <%s>", line)
+			}
+			m.Headers[len(m.Headers)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		sepIdx := strings.Index(line, utils.InInFieldSep)
+		if sepIdx == -1 {
+			return nil, fmt.Errorf("malformed header line: <%s>", line)
+		}
+		name := strings.TrimSpace(line[:sepIdx])
+		if longName, isCompact := sipCompactHdrs[strings.ToLower(name)]; isCompact {
+			name = longName
+		}
+		m.AddHeader(name, strings.TrimSpace(line[sepIdx+1:]))
+	}
+	if len(body) != 0 {
+		m.Body = make([]byte, len(body))
+		copy(m.Body, body)
+	}
+	return
+}
+
+// readSIPMessage reads one SIP message out of a stream (ie: TCP), using Content-Length for the body
+func readSIPMessage(r *bufio.Reader) (m *sipMessage, err error) {
+	var hdrPart bytes.Buffer
+	for {
+		var line string
+		if line, err = r.ReadString('\n'); err != nil {
+			return
+		}
+		if strings.TrimSpace(line) == "" {
+			if hdrPart.Len() == 0 { // keepalive in-between messages
+				continue
+			}
+			break
+		}
+		hdrPart.WriteString(line)
+	}
+	if m, err = parseSIPMessage(hdrPart.Bytes()); err != nil {
+		return
+	}
+	if cLen := m.Header("Content-Length"); cLen != "" {
+		var bodyLen int
+		if bodyLen, err = strconv.Atoi(cLen); err != nil {
+			return nil, fmt.Errorf("malformed Content-Length: <%s>", cLen)
+		}
+		if bodyLen < 0 || bodyLen > sipMaxBodyLen {
+			return nil, fmt.Errorf("invalid Content-Length: %d", bodyLen)
+		}
+		m.Body = make([]byte, bodyLen)
+		if _, err = io.ReadFull(r, m.Body); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// newSIPResponse builds a response to the request, copying the headers needed to match the transaction
+func newSIPResponse(req *sipMessage, code int, reason string) (rpl *sipMessage) {
+	if reason == "" {
+		if reason = sipReasons[code]; reason == "" {
+			reason = "Unknown"
+		}
+	}
+	rpl = &sipMessage{StatusCode: code, Reason: reason}
+	for _, via := range req.HeaderValues("Via") {
+		rpl.AddHeader("Via", via)
+	}
+	rpl.AddHeader("From", req.Header("From"))
+	to := req.Header("To")
+	if code > 100 && !strings.Contains(to, ";tag=") {
+		to += ";tag=" + utils.UUIDSha1Prefix()
+	}
+	rpl.AddHeader("To", to)
+	rpl.AddHeader("Call-ID", req.Header("Call-ID"))
+	rpl.AddHeader("CSeq", req.Header("CSeq"))
+	return
+}
+
+// sipResponseFromNavMap builds the response to the request out of the reply fields
+// *sipReplyCode and *sipReplyReason control the status line, the other fields become headers
+func sipResponseFromNavMap(req *sipMessage, nM *config.NavigableMap) (rpl *sipMessage, err error) {
+	var mp map[string]interface{}
+	if mp, err = nM.AsJSONMap(); err != nil {
+		return
+	}
+	codeIface, has := mp[MetaSIPReplyCode]
+	if !has {
+		return nil, fmt.Errorf("missing reply field: <%s>", MetaSIPReplyCode)
+	}
+	var codeStr string
+	if codeStr, err = utils.IfaceAsString(codeIface); err != nil {
+		return
+	}
+	code, err := strconv.Atoi(codeStr)
+	if err != nil || code < 200 || code > 699 {
+		return nil, fmt.Errorf("invalid final status code: <%s>", codeStr)
+	}
+	var reason string
+	if reasonIface, has := mp[MetaSIPReplyReason]; has {
+		if reason, err = utils.IfaceAsString(reasonIface); err != nil {
+			return
+		}
+	}
+	rpl = newSIPResponse(req, code, reason)
+	hdrNames := make([]string, 0, len(mp))
+	for hdrName := range mp {
+		if hdrName == MetaSIPReplyCode || hdrName == MetaSIPReplyReason {
+			continue
+		}
+		hdrNames = append(hdrNames, hdrName)
+	}
+	sort.Strings(hdrNames)
+	for _, hdrName := range hdrNames {
+		vals, isSlice := mp[hdrName].([]interface{})
+		if !isSlice {
+			vals = []interface{}{mp[hdrName]}
+		}
+		for _, val := range vals {
+			if _, isMap := val.(map[string]interface{}); isMap {
+				return nil, fmt.Errorf("cannot encode nested reply field: <%s>", hdrName)
+			}
+			var hdrVal string
+			if hdrVal, err = utils.IfaceAsString(val); err != nil {
+				return
+			}
+			rpl.AddHeader(hdrName, hdrVal)
+		}
+	}
+	return
+}
+
+// sipURIUser returns the user part out of a SIP URI, ie: <sip:1002@cgrates.org;user=phone> will return 1002
+func sipURIUser(uri string) string {
+	if idx := strings.Index(uri, "<"); idx != -1 {
+		uri = uri[idx+1:]
+	}
+	if idx := strings.Index(uri, ":"); idx != -1 {
+		uri = uri[idx+1:]
+	}
+	if idx := strings.Index(uri, "@"); idx != -1 {
+		return uri[:idx]
+	}
+	return ""
+}
+
+// sipContacts builds the Contact header value out of the sorted suppliers, in order of preference
+// SupplierParameters is used as host (or full URI if starting with sip:), defaulting to SupplierID
+func sipContacts(user string, spls *engine.SortedSuppliers) (contacts string, err error) {
+	if spls == nil || len(spls.SortedSuppliers) == 0 {
+		return "", errors.New("no suppliers")
+	}
+	cnts := make([]string, len(spls.SortedSuppliers))
+	for i, spl := range spls.SortedSuppliers {
+		uri := utils.FirstNonEmpty(spl.SupplierParameters, spl.SupplierID)
+		if !strings.HasPrefix(uri, "sip:") && !strings.HasPrefix(uri, "sips:") {
+			if user != "" {
+				uri = user + "@" + uri
+			}
+			uri = "sip:" + uri
+		}
+		cnts[i] = fmt.Sprintf("<%s>;q=%s", uri,
+			strconv.FormatFloat(float64(len(cnts)-i)/float64(len(cnts)), 'f', 3, 64))
+	}
+	return strings.Join(cnts, ", "), nil
+}
+
+// newSIPDataProvider constructs a DataProvider out of a SIP request
+func newSIPDataProvider(req *sipMessage, remoteAddr net.Addr) config.DataProvider {
+	return &sipDP{req: req, remoteAddr: remoteAddr, cache: config.NewNavigableMap(nil)}
+}
+
+// sipDP implements engine.DataProvider, serving as sipMessage decoder
+// decoded data is only searched once and cached
+type sipDP struct {
+	req        *sipMessage
+	remoteAddr net.Addr
+	cache      *config.NavigableMap
+}
+
+// String is part of engine.DataProvider interface
+func (sP *sipDP) String() string {
+	return sP.req.String()
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+// the path is the header name, the first header being considered when multiple with the same name
+func (sP *sipDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) != 1 {
+		return nil, utils.ErrNotFound
+	}
+	if data, err = sP.cache.FieldAsInterface(fldPath); err == nil ||
+		err != utils.ErrNotFound { // item found in cache
+		return
+	}
+	hdrName := fldPath[0]
+	if longName, isCompact := sipCompactHdrs[strings.ToLower(hdrName)]; isCompact {
+		hdrName = longName
+	}
+	vals := sP.req.HeaderValues(hdrName)
+	if len(vals) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	data = vals[0]
+	sP.cache.Set(fldPath, data, false, false)
+	return
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (sP *sipDP) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	if valIface, err = sP.FieldAsInterface(fldPath); err != nil {
+		return
+	}
+	return utils.IfaceAsString(valIface)
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (sP *sipDP) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
+// RemoteHost is part of engine.DataProvider interface
+func (sP *sipDP) RemoteHost() net.Addr {
+	return utils.NewNetAddr(sP.remoteAddr.Network(), sP.remoteAddr.String())
+}
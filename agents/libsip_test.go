@@ -0,0 +1,216 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+var sipInvite = "INVITE sip:1002@cgrates.org SIP/2.0\r\n" +
+	"Via: SIP/2.0/UDP 10.0.0.10:5060;branch=z9hG4bK776asdhds\r\n" +
+	"v: SIP/2.0/UDP 10.0.0.1:5060;branch=z9hG4bK776asdhdr\r\n" +
+	"Max-Forwards: 70\r\n" +
+	"To: <sip:1002@cgrates.org>\r\n" +
+	"f: \"1001\" <sip:1001@cgrates.org>;tag=1928301774\r\n" +
+	"i: a84b4c76e66710@pc33.cgrates.org\r\n" +
+	"CSeq: 314159 INVITE\r\n" +
+	"Subject: folded\r\n" +
+	" header\r\n" +
+	"Content-Type: application/sdp\r\n" +
+	"Content-Length: 4\r\n" +
+	"\r\n" +
+	"v=0\n"
+
+func TestParseSIPMessage(t *testing.T) {
+	m, err := parseSIPMessage([]byte(sipInvite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Method != sipINVITE || m.RequestURI != "sip:1002@cgrates.org" {
+		t.Errorf("received method: <%s>, uri: <%s>", m.Method, m.RequestURI)
+	}
+	eVias := []string{
+		"SIP/2.0/UDP 10.0.0.10:5060;branch=z9hG4bK776asdhds",
+		"SIP/2.0/UDP 10.0.0.1:5060;branch=z9hG4bK776asdhdr"}
+	if vias := m.HeaderValues("via"); !reflect.DeepEqual(eVias, vias) {
+		t.Errorf("expecting: %+v, received: %+v", eVias, vias)
+	}
+	if callID := m.Header("Call-ID"); callID != "a84b4c76e66710@pc33.cgrates.org" {
+		t.Errorf("received Call-ID: <%s>", callID)
+	}
+	if subject := m.Header("Subject"); subject != "folded header" {
+		t.Errorf("received Subject: <%s>", subject)
+	}
+	if string(m.Body) != "v=0\n" {
+		t.Errorf("received body: <%s>", m.Body)
+	}
+	if _, err := parseSIPMessage([]byte("INVITE sip:1002@cgrates.org\r\n\r\n")); err == nil {
+		t.Error("expecting error on malformed start line")
+	}
+	if _, err := parseSIPMessage([]byte("INVITE sip:1002@cgrates.org SIP/2.0\r\nTo\r\n\r\n")); err == nil {
+		t.Error("expecting error on malformed header")
+	}
+	rpl, err := parseSIPMessage([]byte("SIP/2.0 302 Moved Temporarily\r\nContent-Length: 0\r\n\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpl.Method != "" || rpl.StatusCode != 302 || rpl.Reason != "Moved Temporarily" {
+		t.Errorf("received: %+v", rpl)
+	}
+}
+
+func TestReadSIPMessage(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\r\n" + sipInvite + sipInvite))
+	for i := 0; i < 2; i++ {
+		if m, err := readSIPMessage(r); err != nil {
+			t.Fatal(err)
+		} else if string(m.Body) != "v=0\n" {
+			t.Errorf("received body: <%s>", m.Body)
+		}
+	}
+	if _, err := readSIPMessage(r); err == nil {
+		t.Error("expecting error at the end of stream")
+	}
+	for _, cLen := range []string{"-1", "1000000000"} {
+		msg := strings.Replace(sipInvite, "Content-Length: 4", "Content-Length: "+cLen, 1)
+		if _, err := readSIPMessage(bufio.NewReader(strings.NewReader(msg))); err == nil {
+			t.Errorf("expecting error on Content-Length: %s", cLen)
+		}
+	}
+}
+
+func TestNewSIPResponse(t *testing.T) {
+	m, _ := parseSIPMessage([]byte(sipInvite))
+	trying := newSIPResponse(m, 100, "")
+	if trying.Reason != "Trying" {
+		t.Errorf("received reason: <%s>", trying.Reason)
+	}
+	if to := trying.Header("To"); to != "<sip:1002@cgrates.org>" {
+		t.Errorf("received To: <%s>", to)
+	}
+	rpl := newSIPResponse(m, 403, "")
+	if !strings.HasPrefix(rpl.Header("To"), "<sip:1002@cgrates.org>;tag=") {
+		t.Errorf("received To: <%s>", rpl.Header("To"))
+	}
+	if len(rpl.HeaderValues("Via")) != 2 {
+		t.Errorf("received Vias: %+v", rpl.HeaderValues("Via"))
+	}
+	b := rpl.Bytes()
+	if !bytes.HasPrefix(b, []byte("SIP/2.0 403 Forbidden\r\n")) ||
+		!bytes.HasSuffix(b, []byte("Content-Length: 0\r\n\r\n")) {
+		t.Errorf("received: <%s>", b)
+	}
+	if decoded, err := parseSIPMessage(b); err != nil {
+		t.Error(err)
+	} else if decoded.Header("CSeq") != "314159 INVITE" {
+		t.Errorf("received CSeq: <%s>", decoded.Header("CSeq"))
+	}
+}
+
+func TestSIPResponseFromNavMap(t *testing.T) {
+	m, _ := parseSIPMessage([]byte(sipInvite))
+	nM := config.NewNavigableMap(nil)
+	if _, err := sipResponseFromNavMap(m, nM); err == nil {
+		t.Error("expecting error on missing reply code")
+	}
+	nM.Set([]string{MetaSIPReplyCode}, []*config.NMItem{
+		{Path: []string{MetaSIPReplyCode}, Data: "302"}}, false, true)
+	nM.Set([]string{"Contact"}, []*config.NMItem{
+		{Path: []string{"Contact"}, Data: "<sip:1002@gw1>;q=1.000"},
+		{Path: []string{"Contact"}, Data: "<sip:1002@gw2>;q=0.500"}}, false, true)
+	rpl, err := sipResponseFromNavMap(m, nM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpl.StatusCode != 302 || rpl.Reason != "Moved Temporarily" {
+		t.Errorf("received: %d %s", rpl.StatusCode, rpl.Reason)
+	}
+	eCnts := []string{"<sip:1002@gw1>;q=1.000", "<sip:1002@gw2>;q=0.500"}
+	if cnts := rpl.HeaderValues("Contact"); !reflect.DeepEqual(eCnts, cnts) {
+		t.Errorf("expecting: %+v, received: %+v", eCnts, cnts)
+	}
+	nM.Set([]string{MetaSIPReplyCode}, []*config.NMItem{
+		{Path: []string{MetaSIPReplyCode}, Data: "100"}}, false, false)
+	if _, err := sipResponseFromNavMap(m, nM); err == nil {
+		t.Error("expecting error on provisional reply code")
+	}
+}
+
+func TestSIPURIUser(t *testing.T) {
+	for uri, eUser := range map[string]string{
+		"sip:1002@cgrates.org":                         "1002",
+		"\"1001\" <sip:1001@cgrates.org>;tag=1928":     "1001",
+		"<sips:+4986517174963@cgrates.org;user=phone>": "+4986517174963",
+		"sip:cgrates.org":                              "",
+	} {
+		if user := sipURIUser(uri); user != eUser {
+			t.Errorf("uri: <%s>, expecting: <%s>, received: <%s>", uri, eUser, user)
+		}
+	}
+}
+
+func TestSIPContacts(t *testing.T) {
+	if _, err := sipContacts("1002", nil); err == nil {
+		t.Error("expecting error on no suppliers")
+	}
+	spls := &engine.SortedSuppliers{
+		SortedSuppliers: []*engine.SortedSupplier{
+			{SupplierID: "gw1.cgrates.org"},
+			{SupplierID: "supplier2", SupplierParameters: "10.0.0.2:5060"},
+			{SupplierID: "supplier3", SupplierParameters: "sip:+49@gw3.cgrates.org"},
+			{SupplierID: "supplier4", SupplierParameters: "gw4.cgrates.org"},
+		},
+	}
+	eCnts := "<sip:1002@gw1.cgrates.org>;q=1.000, <sip:1002@10.0.0.2:5060>;q=0.750, " +
+		"<sip:+49@gw3.cgrates.org>;q=0.500, <sip:1002@gw4.cgrates.org>;q=0.250"
+	if cnts, err := sipContacts("1002", spls); err != nil {
+		t.Error(err)
+	} else if cnts != eCnts {
+		t.Errorf("expecting: <%s>, received: <%s>", eCnts, cnts)
+	}
+}
+
+func TestSIPDataProvider(t *testing.T) {
+	m, _ := parseSIPMessage([]byte(sipInvite))
+	dP := newSIPDataProvider(m, utils.NewNetAddr(utils.UDP, "10.0.0.1:5060"))
+	if from, err := dP.FieldAsString([]string{"From"}); err != nil {
+		t.Error(err)
+	} else if from != "\"1001\" <sip:1001@cgrates.org>;tag=1928301774" {
+		t.Errorf("received From: <%s>", from)
+	}
+	if callID, err := dP.FieldAsString([]string{"i"}); err != nil {
+		t.Error(err)
+	} else if callID != "a84b4c76e66710@pc33.cgrates.org" {
+		t.Errorf("received Call-ID: <%s>", callID)
+	}
+	if _, err := dP.FieldAsString([]string{"Authorization"}); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if rh := dP.RemoteHost().String(); rh != "10.0.0.1" {
+		t.Errorf("received remote host: <%s>", rh)
+	}
+}
@@ -0,0 +1,496 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SMPP v3.4 command IDs
+const (
+	smppGenericNack     uint32 = 0x80000000
+	smppBindReceiver    uint32 = 0x00000001
+	smppBindTransmitter uint32 = 0x00000002
+	smppSubmitSM        uint32 = 0x00000004
+	smppDeliverSM       uint32 = 0x00000005
+	smppUnbind          uint32 = 0x00000006
+	smppBindTransceiver uint32 = 0x00000009
+	smppEnquireLink     uint32 = 0x00000015
+	smppRespMask        uint32 = 0x80000000
+)
+
+// SMPP v3.4 command_status values
+const (
+	smppStatusOK        uint32 = 0x00000000 // ESME_ROK
+	smppStatusInvCmdLen uint32 = 0x00000002 // ESME_RINVCMDLEN
+	smppStatusInvCmdID  uint32 = 0x00000003 // ESME_RINVCMDID
+	smppStatusInvBndSts uint32 = 0x00000004 // ESME_RINVBNDSTS
+	smppStatusAlyBnd    uint32 = 0x00000005 // ESME_RALYBND
+	smppStatusSysErr    uint32 = 0x00000008 // ESME_RSYSERR
+	smppStatusInvPaswd  uint32 = 0x0000000E // ESME_RINVPASWD
+	smppStatusInvSysID  uint32 = 0x0000000F // ESME_RINVSYSID
+)
+
+// optional parameters (TLVs) we are interested in
+const (
+	smppTagUserMessageReference uint16 = 0x0204
+	smppTagSarMsgRefNum         uint16 = 0x020C
+	smppTagSarTotalSegments     uint16 = 0x020E
+	smppTagSarSegmentSeqnum     uint16 = 0x020F
+	smppTagMessagePayload       uint16 = 0x0424
+)
+
+const (
+	smppHeaderLen        = 16
+	smppMaxPDULen        = 70000 // enough for a message_payload of 64K
+	smppInterfaceVersion = 0x34
+	smppESMClassUDHI     = 0x40
+	smppDataCodingLatin1 = 0x03
+	smppDataCodingUCS2   = 0x08
+)
+
+// smppCmdNames is used to expose the command in *vars
+var smppCmdNames = map[uint32]string{
+	smppSubmitSM:  "submit_sm",
+	smppDeliverSM: "deliver_sm",
+}
+
+// smppPDU is one SMPP protocol data unit
+type smppPDU struct {
+	CommandID uint32
+	Status    uint32
+	Sequence  uint32
+	Body      []byte
+}
+
+// Bytes encodes the PDU for the wire
+func (pdu *smppPDU) Bytes() []byte {
+	b := make([]byte, smppHeaderLen+len(pdu.Body))
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)))
+	binary.BigEndian.PutUint32(b[4:8], pdu.CommandID)
+	binary.BigEndian.PutUint32(b[8:12], pdu.Status)
+	binary.BigEndian.PutUint32(b[12:16], pdu.Sequence)
+	copy(b[smppHeaderLen:], pdu.Body)
+	return b
+}
+
+// String is used in logs
+func (pdu *smppPDU) String() string {
+	return fmt.Sprintf("command_id: 0x%08X, command_status: 0x%08X, sequence_number: %d, body: %x",
+		pdu.CommandID, pdu.Status, pdu.Sequence, pdu.Body)
+}
+
+// newSMPPResponse builds the response for the request PDU
+func newSMPPResponse(req *smppPDU, status uint32, body []byte) *smppPDU {
+	return &smppPDU{CommandID: req.CommandID | smppRespMask,
+		Status: status, Sequence: req.Sequence, Body: body}
+}
+
+// readSMPPPDU reads one PDU out of the stream
+func readSMPPPDU(r io.Reader) (pdu *smppPDU, err error) {
+	hdr := make([]byte, smppHeaderLen)
+	if _, err = io.ReadFull(r, hdr); err != nil {
+		return
+	}
+	pduLen := binary.BigEndian.Uint32(hdr[0:4])
+	if pduLen < smppHeaderLen || pduLen > smppMaxPDULen {
+		return nil, fmt.Errorf("invalid command_length: %d", pduLen)
+	}
+	pdu = &smppPDU{
+		CommandID: binary.BigEndian.Uint32(hdr[4:8]),
+		Status:    binary.BigEndian.Uint32(hdr[8:12]),
+		Sequence:  binary.BigEndian.Uint32(hdr[12:16]),
+		Body:      make([]byte, pduLen-smppHeaderLen),
+	}
+	if _, err = io.ReadFull(r, pdu.Body); err != nil {
+		return nil, err
+	}
+	return
+}
+
+var errSMPPShortBody = errors.New("unexpected end of PDU body")
+
+// smppReader decodes the mandatory parameters out of a PDU body
+type smppReader struct {
+	b   []byte
+	pos int
+}
+
+func (sr *smppReader) readByte() (c byte, err error) {
+	if sr.pos >= len(sr.b) {
+		return 0, errSMPPShortBody
+	}
+	c = sr.b[sr.pos]
+	sr.pos++
+	return
+}
+
+func (sr *smppReader) readCString() (s string, err error) {
+	idx := bytes.IndexByte(sr.b[sr.pos:], 0)
+	if idx == -1 {
+		return "", errSMPPShortBody
+	}
+	s = string(sr.b[sr.pos : sr.pos+idx])
+	sr.pos += idx + 1
+	return
+}
+
+func (sr *smppReader) readBytes(n int) (b []byte, err error) {
+	if sr.pos+n > len(sr.b) {
+		return nil, errSMPPShortBody
+	}
+	b = sr.b[sr.pos : sr.pos+n]
+	sr.pos += n
+	return
+}
+
+// smppCStrings encodes the values as C-Octet Strings
+func smppCStrings(vals ...string) []byte {
+	var buf bytes.Buffer
+	for _, val := range vals {
+		buf.WriteString(val)
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+// smppBind holds the parameters of the bind_* operations
+type smppBind struct {
+	SystemID   string
+	Password   string
+	SystemType string
+}
+
+// decodeSMPPBind decodes the body of a bind_* PDU
+func decodeSMPPBind(body []byte) (bnd *smppBind, err error) {
+	sr := &smppReader{b: body}
+	bnd = new(smppBind)
+	if bnd.SystemID, err = sr.readCString(); err != nil {
+		return nil, err
+	}
+	if bnd.Password, err = sr.readCString(); err != nil {
+		return nil, err
+	}
+	if bnd.SystemType, err = sr.readCString(); err != nil {
+		return nil, err
+	}
+	return // interface_version, addr_ton, addr_npi and address_range are not used
+}
+
+// Bytes encodes the bind_* body
+func (bnd *smppBind) Bytes() []byte {
+	b := smppCStrings(bnd.SystemID, bnd.Password, bnd.SystemType)
+	b = append(b, smppInterfaceVersion, 0, 0) // interface_version, addr_ton, addr_npi
+	return append(b, 0)                       // address_range
+}
+
+// smppSM is the short message out of submit_sm and deliver_sm PDUs
+type smppSM struct {
+	ServiceType          string
+	SourceAddrTON        byte
+	SourceAddrNPI        byte
+	SourceAddr           string
+	DestAddrTON          byte
+	DestAddrNPI          byte
+	DestinationAddr      string
+	ESMClass             byte
+	ProtocolID           byte
+	PriorityFlag         byte
+	ScheduleDeliveryTime string
+	ValidityPeriod       string
+	RegisteredDelivery   byte
+	ReplaceIfPresentFlag byte
+	DataCoding           byte
+	SMDefaultMsgID       byte
+	ShortMessage         []byte
+	TLVs                 map[uint16][]byte
+}
+
+// decodeSMPPSM decodes the body of submit_sm and deliver_sm PDUs
+func decodeSMPPSM(body []byte) (sm *smppSM, err error) {
+	sr := &smppReader{b: body}
+	sm = &smppSM{TLVs: make(map[uint16][]byte)}
+	for _, fld := range []interface{}{
+		&sm.ServiceType,
+		&sm.SourceAddrTON, &sm.SourceAddrNPI, &sm.SourceAddr,
+		&sm.DestAddrTON, &sm.DestAddrNPI, &sm.DestinationAddr,
+		&sm.ESMClass, &sm.ProtocolID, &sm.PriorityFlag,
+		&sm.ScheduleDeliveryTime, &sm.ValidityPeriod,
+		&sm.RegisteredDelivery, &sm.ReplaceIfPresentFlag,
+		&sm.DataCoding, &sm.SMDefaultMsgID} {
+		switch f := fld.(type) {
+		case *string:
+			*f, err = sr.readCString()
+		case *byte:
+			*f, err = sr.readByte()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	var smLen byte
+	if smLen, err = sr.readByte(); err != nil {
+		return nil, err
+	}
+	if sm.ShortMessage, err = sr.readBytes(int(smLen)); err != nil {
+		return nil, err
+	}
+	for sr.pos < len(sr.b) { // optional parameters
+		var tl []byte
+		if tl, err = sr.readBytes(4); err != nil {
+			return nil, err
+		}
+		var val []byte
+		if val, err = sr.readBytes(int(binary.BigEndian.Uint16(tl[2:4]))); err != nil {
+			return nil, err
+		}
+		sm.TLVs[binary.BigEndian.Uint16(tl[0:2])] = val
+	}
+	return
+}
+
+// payload returns the message out of short_message or message_payload TLV
+func (sm *smppSM) payload() []byte {
+	if len(sm.ShortMessage) == 0 {
+		return sm.TLVs[smppTagMessagePayload]
+	}
+	return sm.ShortMessage
+}
+
+// userData returns the message payload without the User Data Header
+func (sm *smppSM) userData() []byte {
+	pld := sm.payload()
+	if sm.ESMClass&smppESMClassUDHI == 0 || len(pld) == 0 {
+		return pld
+	}
+	udhLen := int(pld[0]) + 1
+	if udhLen > len(pld) {
+		return nil
+	}
+	return pld[udhLen:]
+}
+
+// isSegment returns true if the PDU is carrying only one part of a concatenated message
+func (sm *smppSM) isSegment() bool {
+	if sm.ESMClass&smppESMClassUDHI != 0 {
+		return true
+	}
+	_, hasSAR := sm.TLVs[smppTagSarTotalSegments]
+	return hasSAR
+}
+
+// Text decodes the user data based on data_coding
+func (sm *smppSM) Text() string {
+	ud := sm.userData()
+	switch sm.DataCoding {
+	case smppDataCodingUCS2:
+		u16s := make([]uint16, len(ud)/2)
+		for i := range u16s {
+			u16s[i] = binary.BigEndian.Uint16(ud[2*i:])
+		}
+		return string(utf16.Decode(u16s))
+	case smppDataCodingLatin1:
+		rns := make([]rune, len(ud))
+		for i, c := range ud {
+			rns[i] = rune(c)
+		}
+		return string(rns)
+	}
+	return string(ud)
+}
+
+// MessageLength returns the number of characters in the message
+func (sm *smppSM) MessageLength() int {
+	if sm.DataCoding == smppDataCodingUCS2 {
+		return len(sm.userData()) / 2
+	}
+	return len(sm.userData())
+}
+
+// smppSegmentLimits returns the characters fitting a single SMS
+// and each part of a concatenated one, based on data_coding
+func smppSegmentLimits(dataCoding byte) (single, multi int) {
+	switch dataCoding {
+	case smppDataCodingUCS2:
+		return 70, 67
+	case 0x02, 0x04, smppDataCodingLatin1: // 8-bit
+		return 140, 134
+	}
+	return 160, 153 // GSM 7-bit default alphabet
+}
+
+// Segments returns the number of SMS the message is charged as
+func (sm *smppSM) Segments() int {
+	if sm.isSegment() { // concatenated by the ESME, each PDU is one part
+		return 1
+	}
+	msgLen := sm.MessageLength()
+	single, multi := smppSegmentLimits(sm.DataCoding)
+	if msgLen <= single {
+		return 1
+	}
+	return (msgLen + multi - 1) / multi
+}
+
+// newSMPPDataProvider constructs a DataProvider
+func newSMPPDataProvider(sm *smppSM, remoteAddr net.Addr) config.DataProvider {
+	return &smppDP{sm: sm, remoteAddr: remoteAddr, cache: config.NewNavigableMap(nil)}
+}
+
+// smppDP implements engine.DataProvider, serving as smppSM decoder
+// decoded data is only searched once and cached
+type smppDP struct {
+	sm         *smppSM
+	remoteAddr net.Addr
+	cache      *config.NavigableMap
+}
+
+// String is part of engine.DataProvider interface
+func (sP *smppDP) String() string {
+	return utils.ToIJSON(sP.sm)
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+// the path is the name of the SMPP parameter, plus a few derived ones:
+// short_message (decoded text), message_length and segments
+func (sP *smppDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) != 1 {
+		return nil, utils.ErrNotFound
+	}
+	if data, err = sP.cache.FieldAsInterface(fldPath); err == nil ||
+		err != utils.ErrNotFound { // item found in cache
+		return
+	}
+	err = nil // cancel previous err
+	switch fldPath[0] {
+	default:
+		return nil, utils.ErrNotFound
+	case "service_type":
+		data = sP.sm.ServiceType
+	case "source_addr_ton":
+		data = int(sP.sm.SourceAddrTON)
+	case "source_addr_npi":
+		data = int(sP.sm.SourceAddrNPI)
+	case "source_addr":
+		data = sP.sm.SourceAddr
+	case "dest_addr_ton":
+		data = int(sP.sm.DestAddrTON)
+	case "dest_addr_npi":
+		data = int(sP.sm.DestAddrNPI)
+	case "destination_addr":
+		data = sP.sm.DestinationAddr
+	case "esm_class":
+		data = int(sP.sm.ESMClass)
+	case "protocol_id":
+		data = int(sP.sm.ProtocolID)
+	case "priority_flag":
+		data = int(sP.sm.PriorityFlag)
+	case "schedule_delivery_time":
+		data = sP.sm.ScheduleDeliveryTime
+	case "validity_period":
+		data = sP.sm.ValidityPeriod
+	case "registered_delivery":
+		data = int(sP.sm.RegisteredDelivery)
+	case "data_coding":
+		data = int(sP.sm.DataCoding)
+	case "short_message":
+		data = sP.sm.Text()
+	case "message_length":
+		data = sP.sm.MessageLength()
+	case "segments":
+		data = sP.sm.Segments()
+	case "user_message_reference", "sar_msg_ref_num":
+		tag := smppTagUserMessageReference
+		if fldPath[0] == "sar_msg_ref_num" {
+			tag = smppTagSarMsgRefNum
+		}
+		val, has := sP.sm.TLVs[tag]
+		if !has || len(val) != 2 {
+			return nil, utils.ErrNotFound
+		}
+		data = int(binary.BigEndian.Uint16(val))
+	case "sar_total_segments", "sar_segment_seqnum":
+		tag := smppTagSarTotalSegments
+		if fldPath[0] == "sar_segment_seqnum" {
+			tag = smppTagSarSegmentSeqnum
+		}
+		val, has := sP.sm.TLVs[tag]
+		if !has || len(val) != 1 {
+			return nil, utils.ErrNotFound
+		}
+		data = int(val[0])
+	}
+	sP.cache.Set(fldPath, data, false, false)
+	return
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (sP *smppDP) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	if valIface, err = sP.FieldAsInterface(fldPath); err != nil {
+		return
+	}
+	return utils.IfaceAsString(valIface)
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (sP *smppDP) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
+// RemoteHost is part of engine.DataProvider interface
+func (sP *smppDP) RemoteHost() net.Addr {
+	return utils.NewNetAddr(sP.remoteAddr.Network(), sP.remoteAddr.String())
+}
+
+// smppStatusFromNavMap extracts the command_status and message_id out of the reply fields
+func smppStatusFromNavMap(nM *config.NavigableMap) (status uint32, msgID string, err error) {
+	var mp map[string]interface{}
+	if mp, err = nM.AsJSONMap(); err != nil {
+		return
+	}
+	if statusIface, has := mp[MetaSMPPCommandStatus]; has {
+		var statusStr string
+		if statusStr, err = utils.IfaceAsString(statusIface); err != nil {
+			return
+		}
+		var st uint64
+		if st, err = strconv.ParseUint(statusStr, 0, 32); err != nil {
+			return 0, "", fmt.Errorf("invalid command_status: <%s>", statusStr)
+		}
+		status = uint32(st)
+	}
+	if msgIDIface, has := mp[MetaSMPPMessageID]; has {
+		if msgID, err = utils.IfaceAsString(msgIDIface); err != nil {
+			return
+		}
+	}
+	return
+}
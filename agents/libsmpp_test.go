@@ -0,0 +1,192 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testSMPPSMBody encodes a submit_sm/deliver_sm body
+func testSMPPSMBody(src, dst string, esmClass, dataCoding byte, msg, tlvs []byte) []byte {
+	b := smppCStrings("")                  // service_type
+	b = append(b, 1, 1)                    // source_addr_ton, source_addr_npi
+	b = append(b, smppCStrings(src)...)    // source_addr
+	b = append(b, 1, 1)                    // dest_addr_ton, dest_addr_npi
+	b = append(b, smppCStrings(dst)...)    // destination_addr
+	b = append(b, esmClass, 0, 0)          // esm_class, protocol_id, priority_flag
+	b = append(b, smppCStrings("", "")...) // schedule_delivery_time, validity_period
+	b = append(b, 1, 0, dataCoding, 0)     // registered_delivery, replace_if_present_flag, data_coding, sm_default_msg_id
+	b = append(b, byte(len(msg)))          // sm_length
+	b = append(b, msg...)                  // short_message
+	return append(b, tlvs...)
+}
+
+func TestSMPPPDUBytes(t *testing.T) {
+	pdu := &smppPDU{CommandID: smppEnquireLink, Sequence: 7}
+	eBytes := []byte{0, 0, 0, 16, 0, 0, 0, 0x15, 0, 0, 0, 0, 0, 0, 0, 7}
+	if b := pdu.Bytes(); !bytes.Equal(eBytes, b) {
+		t.Errorf("expecting: %x, received: %x", eBytes, b)
+	}
+	bnd := &smppBind{SystemID: "cgr", Password: "pass", SystemType: "OCS"}
+	pdu = &smppPDU{CommandID: smppBindTransceiver, Sequence: 1, Body: bnd.Bytes()}
+	if rcv, err := readSMPPPDU(bytes.NewReader(pdu.Bytes())); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(pdu, rcv) {
+		t.Errorf("expecting: %s, received: %s", pdu, rcv)
+	} else if rcvBnd, err := decodeSMPPBind(rcv.Body); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(bnd, rcvBnd) {
+		t.Errorf("expecting: %+v, received: %+v", bnd, rcvBnd)
+	}
+	if _, err := readSMPPPDU(bytes.NewReader([]byte{0, 0, 0, 8, 0, 0, 0, 0x15,
+		0, 0, 0, 0, 0, 0, 0, 7})); err == nil {
+		t.Error("expecting error on invalid command_length")
+	}
+	if rpl := newSMPPResponse(pdu, smppStatusInvPaswd, nil); rpl.CommandID != 0x80000009 ||
+		rpl.Sequence != 1 || rpl.Status != smppStatusInvPaswd {
+		t.Errorf("received: %s", rpl)
+	}
+}
+
+func TestSMPPDecodeSM(t *testing.T) {
+	sarTLVs := []byte{0x02, 0x0C, 0, 2, 0, 42, // sar_msg_ref_num
+		0x02, 0x0E, 0, 1, 3, // sar_total_segments
+		0x02, 0x0F, 0, 1, 2} // sar_segment_seqnum
+	sm, err := decodeSMPPSM(testSMPPSMBody("1001", "1002", 0, 0,
+		[]byte("Hello"), sarTLVs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.SourceAddr != "1001" || sm.DestinationAddr != "1002" ||
+		sm.RegisteredDelivery != 1 || sm.Text() != "Hello" {
+		t.Errorf("received: %s", utils.ToJSON(sm))
+	}
+	if len(sm.TLVs) != 3 || !bytes.Equal(sm.TLVs[smppTagSarTotalSegments], []byte{3}) {
+		t.Errorf("received TLVs: %+v", sm.TLVs)
+	}
+	if _, err := decodeSMPPSM(testSMPPSMBody("1001", "1002", 0, 0,
+		[]byte("Hello"), []byte{0x02, 0x0E, 0, 1})); err != errSMPPShortBody {
+		t.Errorf("expecting: %v, received: %v", errSMPPShortBody, err)
+	}
+	// UCS2 with User Data Header
+	msg := []byte{0x05, 0x00, 0x03, 0x2A, 0x02, 0x01, // concatenation UDH
+		0x04, 0x1F, 0x04, 0x40, 0x04, 0x38, 0x00, 0x21} // "При!"
+	if sm, err = decodeSMPPSM(testSMPPSMBody("1001", "1002", smppESMClassUDHI,
+		smppDataCodingUCS2, msg, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if txt := sm.Text(); txt != "При!" {
+		t.Errorf("received text: <%s>", txt)
+	}
+	if msgLen := sm.MessageLength(); msgLen != 4 {
+		t.Errorf("received message_length: %d", msgLen)
+	}
+}
+
+func TestSMPPSegments(t *testing.T) {
+	testCases := []struct {
+		dataCoding byte
+		msgLen     int
+		segments   int
+	}{
+		{0, 0, 1},
+		{0, 160, 1},
+		{0, 161, 2},
+		{0, 306, 2},
+		{0, 307, 3},
+		{0x04, 140, 1},
+		{0x04, 141, 2},
+		{smppDataCodingUCS2, 140, 1},
+		{smppDataCodingUCS2, 142, 2},
+		{smppDataCodingUCS2, 404, 4},
+	}
+	for _, tc := range testCases {
+		sm := &smppSM{DataCoding: tc.dataCoding,
+			TLVs: map[uint16][]byte{ // long messages are sent within message_payload
+				smppTagMessagePayload: bytes.Repeat([]byte("a"), tc.msgLen)}}
+		if segs := sm.Segments(); segs != tc.segments {
+			t.Errorf("data_coding: %d, length: %d, expecting: %d segments, received: %d",
+				tc.dataCoding, tc.msgLen, tc.segments, segs)
+		}
+	}
+	// concatenated by the ESME, each part is charged on its own
+	sm := &smppSM{ESMClass: smppESMClassUDHI,
+		ShortMessage: append([]byte{0x05, 0x00, 0x03, 0x2A, 0x02, 0x01},
+			bytes.Repeat([]byte("a"), 153)...)}
+	if segs := sm.Segments(); segs != 1 {
+		t.Errorf("received segments: %d", segs)
+	}
+}
+
+func TestSMPPDataProvider(t *testing.T) {
+	sm, err := decodeSMPPSM(testSMPPSMBody("1001", "1002", 0, 0,
+		[]byte(strings.Repeat("a", 200)), []byte{0x02, 0x04, 0, 2, 0, 9}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dP := newSMPPDataProvider(sm, &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 2775})
+	for fld, eVal := range map[string]interface{}{
+		"source_addr":            "1001",
+		"destination_addr":       "1002",
+		"dest_addr_ton":          1,
+		"data_coding":            0,
+		"message_length":         200,
+		"segments":               2,
+		"user_message_reference": 9,
+	} {
+		if val, err := dP.FieldAsInterface([]string{fld}); err != nil {
+			t.Errorf("field: %s, error: %v", fld, err)
+		} else if !reflect.DeepEqual(eVal, val) {
+			t.Errorf("field: %s, expecting: %v, received: %v", fld, eVal, val)
+		}
+	}
+	if _, err := dP.FieldAsInterface([]string{"sar_total_segments"}); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if rHost := dP.RemoteHost().String(); rHost != "10.0.0.1" {
+		t.Errorf("received remote host: <%s>", rHost)
+	}
+}
+
+func TestSMPPStatusFromNavMap(t *testing.T) {
+	nM := config.NewNavigableMap(nil)
+	if status, msgID, err := smppStatusFromNavMap(nM); err != nil {
+		t.Error(err)
+	} else if status != smppStatusOK || msgID != "" {
+		t.Errorf("received status: 0x%X, message_id: <%s>", status, msgID)
+	}
+	nM.Set([]string{MetaSMPPCommandStatus}, "0x45", false, true)
+	nM.Set([]string{MetaSMPPMessageID}, "msg1", false, true)
+	if status, msgID, err := smppStatusFromNavMap(nM); err != nil {
+		t.Error(err)
+	} else if status != 0x45 || msgID != "msg1" {
+		t.Errorf("received status: 0x%X, message_id: <%s>", status, msgID)
+	}
+	nM.Set([]string{MetaSMPPCommandStatus}, "ESME_RSYSERR", false, true)
+	if _, _, err := smppStatusFromNavMap(nM); err == nil {
+		t.Error("expecting error on invalid command_status")
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/sessions"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+const (
+	MetaSIPMethod      = "*sipMethod"
+	MetaSIPRequestURI  = "*sipRequestURI"
+	MetaSIPContacts    = "*sipContacts"
+	MetaSIPReplyCode   = "*sipReplyCode"
+	MetaSIPReplyReason = "*sipReplyReason"
+)
+
+// NewSIPAgent will construct a SIPAgent
+func NewSIPAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
+	sS rpcclient.RpcClientConnection) (*SIPAgent, error) {
+	if sS != nil && reflect.ValueOf(sS).IsNil() {
+		sS = nil
+	}
+	sa := &SIPAgent{cgrCfg: cgrCfg, filterS: filterS, sS: sS}
+	msgTemplates := sa.cgrCfg.SIPAgentCfg().Templates
+	// Inflate *template field types
+	for _, procsr := range sa.cgrCfg.SIPAgentCfg().RequestProcessors {
+		if tpls, err := config.InflateTemplates(procsr.RequestFields, msgTemplates); err != nil {
+			return nil, err
+		} else if tpls != nil {
+			procsr.RequestFields = tpls
+		}
+		if tpls, err := config.InflateTemplates(procsr.ReplyFields, msgTemplates); err != nil {
+			return nil, err
+		} else if tpls != nil {
+			procsr.ReplyFields = tpls
+		}
+	}
+	return sa, nil
+}
+
+// SIPAgent is a SIP redirect server, authorizing the INVITEs via SessionS
+// and answering with the sorted suppliers as contacts
+type SIPAgent struct {
+	cgrCfg  *config.CGRConfig
+	filterS *engine.FilterS
+	sS      rpcclient.RpcClientConnection // Connection towards CGR-SessionS component
+}
+
+// ListenAndServe is called when SIPAgent is started, usually from within cmd/cgr-engine
+func (sa *SIPAgent) ListenAndServe() (err error) {
+	utils.Logger.Info(
+		fmt.Sprintf("<%s> start listening on <%s://%s>",
+			utils.SIPAgent, sa.cgrCfg.SIPAgentCfg().ListenNet, sa.cgrCfg.SIPAgentCfg().Listen))
+	if sa.cgrCfg.SIPAgentCfg().ListenNet == utils.TCP {
+		var l net.Listener
+		if l, err = net.Listen(utils.TCP, sa.cgrCfg.SIPAgentCfg().Listen); err != nil {
+			return
+		}
+		return sa.serveTCP(l)
+	}
+	var pc net.PacketConn
+	if pc, err = net.ListenPacket(utils.UDP, sa.cgrCfg.SIPAgentCfg().Listen); err != nil {
+		return
+	}
+	return sa.serveUDP(pc)
+}
+
+// serveUDP reads the requests out of the packet connection, each datagram holding one message
+func (sa *SIPAgent) serveUDP(pc net.PacketConn) (err error) {
+	buf := make([]byte, sipMaxUDPSz)
+	for {
+		var n int
+		var addr net.Addr
+		if n, addr, err = pc.ReadFrom(buf); err != nil {
+			return
+		}
+		if len(strings.TrimSpace(string(buf[:n]))) == 0 { // keepalive
+			continue
+		}
+		msg, err := parseSIPMessage(buf[:n])
+		if err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s decoding message from %s",
+					utils.SIPAgent, err.Error(), addr))
+			continue
+		}
+		go sa.handleMessage(msg, addr, func(rpl *sipMessage) (err error) {
+			_, err = pc.WriteTo(rpl.Bytes(), addr)
+			return
+		})
+	}
+}
+
+// serveTCP accepts connections, reading the requests out of each stream
+func (sa *SIPAgent) serveTCP(l net.Listener) (err error) {
+	for {
+		var conn net.Conn
+		if conn, err = l.Accept(); err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for {
+				msg, err := readSIPMessage(r)
+				if err != nil {
+					if err != io.EOF {
+						utils.Logger.Warning(
+							fmt.Sprintf("<%s> error: %s reading message from %s",
+								utils.SIPAgent, err.Error(), conn.RemoteAddr()))
+					}
+					return
+				}
+				sa.handleMessage(msg, conn.RemoteAddr(), func(rpl *sipMessage) (err error) {
+					_, err = conn.Write(rpl.Bytes())
+					return
+				})
+			}
+		}(conn)
+	}
+}
+
+// handleMessage answers one SIP request
+func (sa *SIPAgent) handleMessage(msg *sipMessage, remoteAddr net.Addr,
+	write func(*sipMessage) error) {
+	if msg.Method == "" || // responses are not expected by a redirect server
+		msg.Method == sipACK { // ACK for our final response, nothing to answer
+		return
+	}
+	if msg.Method == sipINVITE { // stop the retransmissions while authorizing
+		if err := write(newSIPResponse(msg, 100, "")); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s writing reply to %s",
+					utils.SIPAgent, err.Error(), remoteAddr))
+			return
+		}
+	}
+	if err := write(sa.processMessage(msg, remoteAddr)); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s writing reply to %s",
+				utils.SIPAgent, err.Error(), remoteAddr))
+	}
+}
+
+// processMessage passes the request through the request processors, returning the response
+func (sa *SIPAgent) processMessage(msg *sipMessage, remoteAddr net.Addr) (rpl *sipMessage) {
+	sDP := newSIPDataProvider(msg, remoteAddr)
+	reqVars := map[string]interface{}{
+		MetaSIPMethod:     msg.Method,
+		MetaSIPRequestURI: msg.RequestURI,
+	}
+	rply := config.NewNavigableMap(nil) // share it among different processors
+	var processed bool
+	var err error
+	for _, reqProcessor := range sa.cgrCfg.SIPAgentCfg().RequestProcessors {
+		var lclProcessed bool
+		agReq := newAgentRequest(
+			sDP, reqVars, rply,
+			reqProcessor.Tenant, sa.cgrCfg.GeneralCfg().DefaultTenant,
+			utils.FirstNonEmpty(reqProcessor.Timezone,
+				sa.cgrCfg.GeneralCfg().DefaultTimezone),
+			sa.filterS)
+		if lclProcessed, err = sa.processRequest(reqProcessor, agReq); lclProcessed {
+			processed = lclProcessed
+		}
+		if err != nil ||
+			(lclProcessed && !reqProcessor.ContinueOnSuccess) {
+			break
+		}
+	}
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s processing message: %s",
+				utils.SIPAgent, err.Error(), msg))
+		return newSIPResponse(msg, 503, "")
+	} else if !processed {
+		if msg.Method == sipOPTIONS { // keepalive from the proxies
+			return newSIPResponse(msg, 200, "")
+		}
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> no request processor enabled, ignoring message %s from %s",
+				utils.SIPAgent, msg, remoteAddr))
+		return newSIPResponse(msg, 503, "")
+	}
+	if rpl, err = sipResponseFromNavMap(msg, rply); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s building reply out of: %s",
+				utils.SIPAgent, err.Error(), rply))
+		return newSIPResponse(msg, 503, "")
+	}
+	return
+}
+
+// processRequest represents one processor processing the request
+func (sa *SIPAgent) processRequest(reqProcessor *config.SIPReqProcessor,
+	agReq *AgentRequest) (processed bool, err error) {
+	if pass, err := sa.filterS.Pass(agReq.tenant,
+		reqProcessor.Filters, agReq); err != nil || !pass {
+		return pass, err
+	}
+	if agReq.CGRRequest, err = agReq.AsNavigableMap(reqProcessor.RequestFields); err != nil {
+		return
+	}
+	cgrEv := agReq.CGRRequest.AsCGREvent(agReq.tenant, utils.NestingSep)
+	var reqType string
+	for _, typ := range []string{
+		utils.MetaDryRun, utils.MetaAuth,
+		utils.MetaEvent, utils.MetaCDRs,
+		utils.META_NONE} {
+		if reqProcessor.Flags.HasKey(typ) { // request type is identified through flags
+			reqType = typ
+			break
+		}
+	}
+	if reqProcessor.Flags.HasKey(utils.MetaLog) {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> LOG, processorID: %s, SIP message: %s",
+				utils.SIPAgent, reqProcessor.ID, agReq.Request.String()))
+	}
+	switch reqType {
+	default:
+		return false, fmt.Errorf("unknown request type: <%s>", reqType)
+	case utils.META_NONE: // do nothing on CGRateS side
+	case utils.MetaDryRun:
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> DRY_RUN, processorID: %s, CGREvent: %s",
+				utils.SIPAgent, reqProcessor.ID, utils.ToJSON(cgrEv)))
+	case utils.MetaAuth:
+		authArgs := sessions.NewV1AuthorizeArgs(
+			reqProcessor.Flags.HasKey(utils.MetaAttributes),
+			reqProcessor.Flags.HasKey(utils.MetaResources),
+			reqProcessor.Flags.HasKey(utils.MetaAccounts),
+			reqProcessor.Flags.HasKey(utils.MetaThresholds),
+			reqProcessor.Flags.HasKey(utils.MetaStats),
+			reqProcessor.Flags.HasKey(utils.MetaSuppliers),
+			reqProcessor.Flags.HasKey(utils.MetaSuppliersIgnoreErrors),
+			reqProcessor.Flags.HasKey(utils.MetaSuppliersEventCost),
+			*cgrEv)
+		var authReply sessions.V1AuthorizeReply
+		err = sa.sS.Call(utils.SessionSv1AuthorizeEvent,
+			authArgs, &authReply)
+		if err == nil && authReply.Suppliers != nil { // contacts to redirect to, available via *vars
+			ruri, _ := agReq.Vars.FieldAsString([]string{MetaSIPRequestURI})
+			if cnts, errCnts := sipContacts(sipURIUser(ruri), authReply.Suppliers); errCnts == nil {
+				agReq.Vars.Set([]string{MetaSIPContacts}, cnts, false, true)
+			}
+		}
+		if agReq.CGRReply, err = NewCGRReply(&authReply, err); err != nil {
+			return
+		}
+	case utils.MetaEvent:
+		evArgs := sessions.NewV1ProcessEventArgs(
+			reqProcessor.Flags.HasKey(utils.MetaResources),
+			reqProcessor.Flags.HasKey(utils.MetaAccounts),
+			reqProcessor.Flags.HasKey(utils.MetaAttributes),
+			reqProcessor.Flags.HasKey(utils.MetaThresholds),
+			reqProcessor.Flags.HasKey(utils.MetaStats),
+			*cgrEv)
+		var eventRply sessions.V1ProcessEventReply
+		err = sa.sS.Call(utils.SessionSv1ProcessEvent,
+			evArgs, &eventRply)
+		if utils.ErrHasPrefix(err, utils.RalsErrorPrfx) {
+			cgrEv.Event[utils.Usage] = 0 // avoid further debits
+		} else if eventRply.MaxUsage != nil {
+			cgrEv.Event[utils.Usage] = *eventRply.MaxUsage // make sure the CDR reflects the debit
+		}
+		if agReq.CGRReply, err = NewCGRReply(&eventRply, err); err != nil {
+			return
+		}
+	case utils.MetaCDRs: // allow CDR processing
+	}
+	// separate request so we can capture the Event also here
+	if reqProcessor.Flags.HasKey(utils.MetaCDRs) &&
+		!reqProcessor.Flags.HasKey(utils.MetaDryRun) {
+		var rplyCDRs string
+		if err = sa.sS.Call(utils.SessionSv1ProcessCDR,
+			cgrEv, &rplyCDRs); err != nil {
+			agReq.CGRReply.Set([]string{utils.Error}, err.Error(), false, false)
+		}
+	}
+	if nM, err := agReq.AsNavigableMap(reqProcessor.ReplyFields); err != nil {
+		return false, err
+	} else {
+		agReq.Reply.Merge(nM)
+	}
+	if reqProcessor.Flags.HasKey(utils.MetaLog) {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> LOG, SIP reply: %s",
+				utils.SIPAgent, agReq.Reply))
+	}
+	if reqType == utils.MetaDryRun {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> DRY_RUN, SIP reply: %s",
+				utils.SIPAgent, agReq.Reply))
+	}
+	return true, nil
+}
@@ -0,0 +1,148 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/sessions"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testSIPSessionS authorizes account 1001 towards two suppliers, rejecting the others
+type testSIPSessionS struct{}
+
+func (*testSIPSessionS) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if serviceMethod != utils.SessionSv1AuthorizeEvent {
+		return utils.ErrNotImplemented
+	}
+	authArgs := args.(*sessions.V1AuthorizeArgs)
+	if authArgs.CGREvent.Event[utils.Account] != "1001" {
+		return utils.ErrAccountNotFound
+	}
+	reply.(*sessions.V1AuthorizeReply).Suppliers = &engine.SortedSuppliers{
+		SortedSuppliers: []*engine.SortedSupplier{
+			{SupplierID: "supplier1", SupplierParameters: "gw1.cgrates.org"},
+			{SupplierID: "supplier2", SupplierParameters: "gw2.cgrates.org"},
+		},
+	}
+	return nil
+}
+
+func TestSIPAgentRedirect(t *testing.T) {
+	cfg, err := config.NewCGRConfigFromJsonStringWithDefaults(`{
+"sip_agent": {
+	"request_processors": [
+		{
+			"id": "redirect",
+			"filters": ["*string:*vars.*sipMethod:INVITE"],
+			"flags": ["*auth", "*suppliers"],
+			"request_fields":[
+				{"tag": "ToR", "field_id": "ToR", "type": "*constant", "value": "*voice"},
+				{"tag": "OriginID", "field_id": "OriginID", "type": "*composed",
+					"value": "~*req.Call-ID", "mandatory": true},
+				{"tag": "Account", "field_id": "Account", "type": "*composed",
+					"value": "~*req.From:s/^.*<sip:([0-9]+)@.*$/${1}/", "mandatory": true},
+				{"tag": "Destination", "field_id": "Destination", "type": "*composed",
+					"value": "~*vars.*sipRequestURI:s/^sip:([0-9]+)@.*$/${1}/", "mandatory": true},
+			],
+			"reply_fields":[
+				{"tag": "Forbidden", "filters": ["*rsr::~*cgrep.Error(!^$)"],
+					"field_id": "*sipReplyCode", "type": "*constant", "value": "403", "blocker": true},
+				{"tag": "Redirect", "type": "*template", "value": "*302"},
+			],
+		},
+	],
+},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := engine.NewMapStorage()
+	dm := engine.NewDataManager(data)
+	sa, err := NewSIPAgent(cfg, engine.NewFilterS(cfg, nil, dm), new(testSIPSessionS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc, err := net.ListenPacket(utils.UDP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	go sa.serveUDP(pc)
+	clnt, err := net.Dial(utils.UDP, pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clnt.Close()
+	readReply := func() *sipMessage {
+		buf := make([]byte, sipMaxUDPSz)
+		clnt.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, err := clnt.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rpl, err := parseSIPMessage(buf[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rpl
+	}
+	if _, err := clnt.Write([]byte(sipInvite)); err != nil {
+		t.Fatal(err)
+	}
+	if rpl := readReply(); rpl.StatusCode != 100 {
+		t.Errorf("expecting 100 Trying, received: %s", rpl)
+	}
+	rpl := readReply()
+	if rpl.StatusCode != 302 {
+		t.Fatalf("expecting 302 Moved Temporarily, received: %s", rpl)
+	}
+	eCnts := "<sip:1002@gw1.cgrates.org>;q=1.000, <sip:1002@gw2.cgrates.org>;q=0.500"
+	if cnts := rpl.Header("Contact"); cnts != eCnts {
+		t.Errorf("expecting: <%s>, received: <%s>", eCnts, cnts)
+	}
+	if callID := rpl.Header("Call-ID"); callID != "a84b4c76e66710@pc33.cgrates.org" {
+		t.Errorf("received Call-ID: <%s>", callID)
+	}
+	// unknown account is rejected
+	if _, err := clnt.Write([]byte(strings.Replace(sipInvite,
+		"sip:1001@cgrates.org", "sip:1003@cgrates.org", 1))); err != nil {
+		t.Fatal(err)
+	}
+	if rpl := readReply(); rpl.StatusCode != 100 {
+		t.Errorf("expecting 100 Trying, received: %s", rpl)
+	}
+	if rpl := readReply(); rpl.StatusCode != 403 {
+		t.Errorf("expecting 403 Forbidden, received: %s", rpl)
+	}
+	// keepalives not matching any processor are answered with 200 OK
+	if _, err := clnt.Write([]byte(strings.Replace(strings.Replace(sipInvite,
+		"INVITE sip:", "OPTIONS sip:", 1), "314159 INVITE", "314159 OPTIONS", 1))); err != nil {
+		t.Fatal(err)
+	}
+	if rpl := readReply(); rpl.StatusCode != 200 {
+		t.Errorf("expecting 200 OK, received: %s", rpl)
+	}
+}
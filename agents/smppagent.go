@@ -0,0 +1,492 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/sessions"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+const (
+	MetaSMPPCmd           = "*smppCmd"
+	MetaSMPPSystemID      = "*smppSystemID"
+	MetaSMPPCommandStatus = "*smppCommandStatus"
+	MetaSMPPMessageID     = "*smppMessageID"
+	smppReconnectDelay    = 5 * time.Second
+)
+
+// NewSMPPAgent will construct a SMPPAgent
+func NewSMPPAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
+	sS rpcclient.RpcClientConnection) (*SMPPAgent, error) {
+	if sS != nil && reflect.ValueOf(sS).IsNil() {
+		sS = nil
+	}
+	sa := &SMPPAgent{cgrCfg: cgrCfg, filterS: filterS, sS: sS}
+	msgTemplates := sa.cgrCfg.SMPPAgentCfg().Templates
+	// Inflate *template field types
+	for _, procsr := range sa.cgrCfg.SMPPAgentCfg().RequestProcessors {
+		if tpls, err := config.InflateTemplates(procsr.RequestFields, msgTemplates); err != nil {
+			return nil, err
+		} else if tpls != nil {
+			procsr.RequestFields = tpls
+		}
+		if tpls, err := config.InflateTemplates(procsr.ReplyFields, msgTemplates); err != nil {
+			return nil, err
+		} else if tpls != nil {
+			procsr.ReplyFields = tpls
+		}
+	}
+	return sa, nil
+}
+
+// SMPPAgent charges the short messages received via SMPP, acting as SMSC towards the ESMEs
+// binding to it and/or as ESME receiver towards an SMSC
+type SMPPAgent struct {
+	cgrCfg  *config.CGRConfig
+	filterS *engine.FilterS
+	sS      rpcclient.RpcClientConnection // Connection towards CGR-SessionS component
+}
+
+// ListenAndServe is called when SMPPAgent is started, usually from within cmd/cgr-engine
+func (sa *SMPPAgent) ListenAndServe() (err error) {
+	errChan := make(chan error, 1)
+	if sa.cgrCfg.SMPPAgentCfg().Listen != "" {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> start listening on <%s>",
+				utils.SMPPAgent, sa.cgrCfg.SMPPAgentCfg().Listen))
+		var l net.Listener
+		if l, err = net.Listen(utils.TCP, sa.cgrCfg.SMPPAgentCfg().Listen); err != nil {
+			return
+		}
+		go func() { errChan <- sa.serve(l) }()
+	}
+	if sa.cgrCfg.SMPPAgentCfg().SMSCAddress != "" {
+		go sa.connectSMSC()
+	}
+	return <-errChan
+}
+
+// serve accepts the ESME connections
+func (sa *SMPPAgent) serve(l net.Listener) (err error) {
+	for {
+		var conn net.Conn
+		if conn, err = l.Accept(); err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			sa.serveSession(&smppSession{conn: conn})
+		}(conn)
+	}
+}
+
+// connectSMSC keeps a bind_receiver session towards the SMSC, reconnecting on errors
+func (sa *SMPPAgent) connectSMSC() {
+	for {
+		if err := sa.bindSMSC(); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s on session with SMSC <%s>, reconnecting in %s",
+					utils.SMPPAgent, err.Error(), sa.cgrCfg.SMPPAgentCfg().SMSCAddress,
+					smppReconnectDelay))
+		}
+		time.Sleep(smppReconnectDelay)
+	}
+}
+
+// bindSMSC connects and binds as receiver to the SMSC, serving the session until closed
+func (sa *SMPPAgent) bindSMSC() (err error) {
+	var conn net.Conn
+	if conn, err = net.Dial(utils.TCP, sa.cgrCfg.SMPPAgentCfg().SMSCAddress); err != nil {
+		return
+	}
+	defer conn.Close()
+	ss := &smppSession{conn: conn, isESME: true}
+	r := bufio.NewReader(conn)
+	bnd := &smppBind{
+		SystemID:   sa.cgrCfg.SMPPAgentCfg().SystemID,
+		Password:   sa.cgrCfg.SMPPAgentCfg().Password,
+		SystemType: sa.cgrCfg.SMPPAgentCfg().SystemType}
+	if err = ss.writePDU(&smppPDU{CommandID: smppBindReceiver,
+		Sequence: ss.nextSequence(), Body: bnd.Bytes()}); err != nil {
+		return
+	}
+	var rpl *smppPDU
+	if rpl, err = readSMPPPDU(r); err != nil {
+		return
+	}
+	if rpl.CommandID != smppBindReceiver|smppRespMask {
+		return fmt.Errorf("unexpected bind reply: %s", rpl)
+	}
+	if rpl.Status != smppStatusOK {
+		return fmt.Errorf("bind failed with command_status: 0x%08X", rpl.Status)
+	}
+	ss.bindCmd = smppBindReceiver
+	ss.systemID = bnd.SystemID
+	utils.Logger.Info(
+		fmt.Sprintf("<%s> bound as receiver to SMSC <%s>",
+			utils.SMPPAgent, sa.cgrCfg.SMPPAgentCfg().SMSCAddress))
+	stopEnquire := make(chan struct{})
+	defer close(stopEnquire)
+	go func() { // keep the session alive
+		tckr := time.NewTicker(sa.cgrCfg.SMPPAgentCfg().EnquireLinkInterval)
+		defer tckr.Stop()
+		for {
+			select {
+			case <-stopEnquire:
+				return
+			case <-tckr.C:
+				if err := ss.writePDU(&smppPDU{CommandID: smppEnquireLink,
+					Sequence: ss.nextSequence()}); err != nil {
+					conn.Close() // unblocks the reads
+					return
+				}
+			}
+		}
+	}()
+	return sa.readSession(ss, r)
+}
+
+// smppSession is one SMPP session, either accepted from an ESME or opened towards a SMSC
+type smppSession struct {
+	conn     net.Conn
+	isESME   bool   // we are the ESME within the session
+	bindCmd  uint32 // command used to bind, 0 if not bound yet
+	systemID string
+	sequence uint32
+	wLk      sync.Mutex // concurrent writes
+}
+
+// writePDU sends one PDU over the session
+func (ss *smppSession) writePDU(pdu *smppPDU) (err error) {
+	ss.wLk.Lock()
+	_, err = ss.conn.Write(pdu.Bytes())
+	ss.wLk.Unlock()
+	return
+}
+
+// nextSequence returns the sequence_number for the PDUs originated by us
+func (ss *smppSession) nextSequence() uint32 {
+	return atomic.AddUint32(&ss.sequence, 1)
+}
+
+// serveSession reads the PDUs out of an ESME connection
+func (sa *SMPPAgent) serveSession(ss *smppSession) {
+	if err := sa.readSession(ss, bufio.NewReader(ss.conn)); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s on session with %s",
+				utils.SMPPAgent, err.Error(), ss.conn.RemoteAddr()))
+	}
+}
+
+// readSession reads the PDUs out of the session, answering them
+// returns nil when the session is terminated via unbind or closed by remote
+func (sa *SMPPAgent) readSession(ss *smppSession, r *bufio.Reader) (err error) {
+	for {
+		var pdu *smppPDU
+		if pdu, err = readSMPPPDU(r); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+		if pdu.CommandID&smppRespMask != 0 { // responses to our enquire_link, nothing to do
+			continue
+		}
+		var rpl *smppPDU
+		switch pdu.CommandID {
+		default:
+			rpl = &smppPDU{CommandID: smppGenericNack,
+				Status: smppStatusInvCmdID, Sequence: pdu.Sequence}
+		case smppBindReceiver, smppBindTransmitter, smppBindTransceiver:
+			if ss.isESME {
+				rpl = &smppPDU{CommandID: smppGenericNack,
+					Status: smppStatusInvCmdID, Sequence: pdu.Sequence}
+				break
+			}
+			rpl = sa.bindESME(ss, pdu)
+		case smppEnquireLink:
+			rpl = newSMPPResponse(pdu, smppStatusOK, nil)
+		case smppUnbind:
+			return ss.writePDU(newSMPPResponse(pdu, smppStatusOK, nil))
+		case smppSubmitSM, smppDeliverSM:
+			if ss.bindCmd == 0 ||
+				(pdu.CommandID == smppSubmitSM && (ss.isESME || ss.bindCmd == smppBindReceiver)) ||
+				(pdu.CommandID == smppDeliverSM && !ss.isESME) {
+				rpl = newSMPPResponse(pdu, smppStatusInvBndSts, nil)
+				break
+			}
+			go func(pdu *smppPDU) { // do not block the session while charging
+				if err := ss.writePDU(sa.processPDU(ss, pdu)); err != nil {
+					utils.Logger.Warning(
+						fmt.Sprintf("<%s> error: %s writing reply to %s",
+							utils.SMPPAgent, err.Error(), ss.conn.RemoteAddr()))
+				}
+			}(pdu)
+			continue
+		}
+		if err = ss.writePDU(rpl); err != nil {
+			return
+		}
+	}
+}
+
+// bindESME checks the credentials of an ESME binding to us, returning the bind response
+func (sa *SMPPAgent) bindESME(ss *smppSession, pdu *smppPDU) *smppPDU {
+	if ss.bindCmd != 0 {
+		return newSMPPResponse(pdu, smppStatusAlyBnd, nil)
+	}
+	bnd, err := decodeSMPPBind(pdu.Body)
+	if err != nil {
+		return newSMPPResponse(pdu, smppStatusInvCmdLen, nil)
+	}
+	if sa.cgrCfg.SMPPAgentCfg().SystemID != "" &&
+		bnd.SystemID != sa.cgrCfg.SMPPAgentCfg().SystemID {
+		return newSMPPResponse(pdu, smppStatusInvSysID, nil)
+	}
+	if sa.cgrCfg.SMPPAgentCfg().Password != "" &&
+		bnd.Password != sa.cgrCfg.SMPPAgentCfg().Password {
+		return newSMPPResponse(pdu, smppStatusInvPaswd, nil)
+	}
+	ss.bindCmd = pdu.CommandID
+	ss.systemID = bnd.SystemID
+	return newSMPPResponse(pdu, smppStatusOK,
+		smppCStrings(sa.cgrCfg.SMPPAgentCfg().SystemID))
+}
+
+// processPDU passes the short message through the request processors, returning the response
+func (sa *SMPPAgent) processPDU(ss *smppSession, pdu *smppPDU) (rpl *smppPDU) {
+	status, msgID := sa.processMessage(ss, pdu)
+	var body []byte
+	if pdu.CommandID == smppSubmitSM {
+		if status == smppStatusOK {
+			body = smppCStrings(msgID)
+		}
+	} else { // deliver_sm_resp has message_id unused
+		body = smppCStrings("")
+	}
+	return newSMPPResponse(pdu, status, body)
+}
+
+// processMessage returns the command_status and message_id out of request processors
+// the message_id is generated upfront, available in *vars and can be overwritten via reply fields
+func (sa *SMPPAgent) processMessage(ss *smppSession, pdu *smppPDU) (status uint32, msgID string) {
+	sm, err := decodeSMPPSM(pdu.Body)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s decoding PDU: %s from %s",
+				utils.SMPPAgent, err.Error(), pdu, ss.conn.RemoteAddr()))
+		return smppStatusInvCmdLen, ""
+	}
+	sDP := newSMPPDataProvider(sm, ss.conn.RemoteAddr())
+	msgID = utils.UUIDSha1Prefix()
+	reqVars := map[string]interface{}{
+		MetaSMPPCmd:       smppCmdNames[pdu.CommandID],
+		MetaSMPPSystemID:  ss.systemID,
+		MetaSMPPMessageID: msgID,
+	}
+	rply := config.NewNavigableMap(nil) // share it among different processors
+	var processed bool
+	for _, reqProcessor := range sa.cgrCfg.SMPPAgentCfg().RequestProcessors {
+		var lclProcessed bool
+		agReq := newAgentRequest(
+			sDP, reqVars, rply,
+			reqProcessor.Tenant, sa.cgrCfg.GeneralCfg().DefaultTenant,
+			utils.FirstNonEmpty(reqProcessor.Timezone,
+				sa.cgrCfg.GeneralCfg().DefaultTimezone),
+			sa.filterS)
+		if lclProcessed, err = sa.processRequest(reqProcessor, agReq); lclProcessed {
+			processed = lclProcessed
+		}
+		if err != nil ||
+			(lclProcessed && !reqProcessor.ContinueOnSuccess) {
+			break
+		}
+	}
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s processing message: %s",
+				utils.SMPPAgent, err.Error(), sDP))
+		return smppStatusSysErr, ""
+	} else if !processed {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> no request processor enabled, ignoring message %s from %s",
+				utils.SMPPAgent, sDP, ss.conn.RemoteAddr()))
+		return smppStatusSysErr, ""
+	}
+	var rplyMsgID string
+	if status, rplyMsgID, err = smppStatusFromNavMap(rply); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s building reply out of: %s",
+				utils.SMPPAgent, err.Error(), rply))
+		return smppStatusSysErr, ""
+	}
+	return status, utils.FirstNonEmpty(rplyMsgID, msgID)
+}
+
+// authorizeMessages makes sure that all the messages (*sms usage) in the event can be debited
+func (sa *SMPPAgent) authorizeMessages(reqProcessor *config.SMPPReqProcessor,
+	cgrEv *utils.CGREvent) (err error) {
+	usage, err := cgrEv.FieldAsDuration(utils.Usage)
+	if err != nil { // no usage to authorize, SessionS will complain if needed
+		return nil
+	}
+	authArgs := sessions.NewV1AuthorizeArgs(
+		reqProcessor.Flags.HasKey(utils.MetaAttributes),
+		false, true, false, false, false, false, false, *cgrEv)
+	var authReply sessions.V1AuthorizeReply
+	if err = sa.sS.Call(utils.SessionSv1AuthorizeEvent,
+		authArgs, &authReply); err != nil {
+		return
+	}
+	if authReply.MaxUsage == nil || *authReply.MaxUsage < usage {
+		return utils.ErrInsufficientCredit
+	}
+	return
+}
+
+// processRequest represents one processor processing the request
+func (sa *SMPPAgent) processRequest(reqProcessor *config.SMPPReqProcessor,
+	agReq *AgentRequest) (processed bool, err error) {
+	if pass, err := sa.filterS.Pass(agReq.tenant,
+		reqProcessor.Filters, agReq); err != nil || !pass {
+		return pass, err
+	}
+	if agReq.CGRRequest, err = agReq.AsNavigableMap(reqProcessor.RequestFields); err != nil {
+		return
+	}
+	cgrEv := agReq.CGRRequest.AsCGREvent(agReq.tenant, utils.NestingSep)
+	var reqType string
+	for _, typ := range []string{
+		utils.MetaDryRun, utils.MetaAuth,
+		utils.MetaEvent, utils.MetaCDRs,
+		utils.META_NONE} {
+		if reqProcessor.Flags.HasKey(typ) { // request type is identified through flags
+			reqType = typ
+			break
+		}
+	}
+	if reqProcessor.Flags.HasKey(utils.MetaLog) {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> LOG, processorID: %s, SMPP message: %s",
+				utils.SMPPAgent, reqProcessor.ID, agReq.Request.String()))
+	}
+	switch reqType {
+	default:
+		return false, fmt.Errorf("unknown request type: <%s>", reqType)
+	case utils.META_NONE: // do nothing on CGRateS side
+	case utils.MetaDryRun:
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> DRY_RUN, processorID: %s, CGREvent: %s",
+				utils.SMPPAgent, reqProcessor.ID, utils.ToJSON(cgrEv)))
+	case utils.MetaAuth:
+		authArgs := sessions.NewV1AuthorizeArgs(
+			reqProcessor.Flags.HasKey(utils.MetaAttributes),
+			reqProcessor.Flags.HasKey(utils.MetaResources),
+			reqProcessor.Flags.HasKey(utils.MetaAccounts),
+			reqProcessor.Flags.HasKey(utils.MetaThresholds),
+			reqProcessor.Flags.HasKey(utils.MetaStats),
+			reqProcessor.Flags.HasKey(utils.MetaSuppliers),
+			reqProcessor.Flags.HasKey(utils.MetaSuppliersIgnoreErrors),
+			reqProcessor.Flags.HasKey(utils.MetaSuppliersEventCost),
+			*cgrEv)
+		var authReply sessions.V1AuthorizeReply
+		err = sa.sS.Call(utils.SessionSv1AuthorizeEvent,
+			authArgs, &authReply)
+		if agReq.CGRReply, err = NewCGRReply(&authReply, err); err != nil {
+			return
+		}
+	case utils.MetaEvent:
+		var eventRply sessions.V1ProcessEventReply
+		if reqProcessor.Flags.HasKey(utils.MetaAccounts) {
+			// a partial debit would charge messages which are not delivered
+			err = sa.authorizeMessages(reqProcessor, cgrEv)
+		}
+		if err == nil {
+			evArgs := sessions.NewV1ProcessEventArgs(
+				reqProcessor.Flags.HasKey(utils.MetaResources),
+				reqProcessor.Flags.HasKey(utils.MetaAccounts),
+				reqProcessor.Flags.HasKey(utils.MetaAttributes),
+				reqProcessor.Flags.HasKey(utils.MetaThresholds),
+				reqProcessor.Flags.HasKey(utils.MetaStats),
+				*cgrEv)
+			err = sa.sS.Call(utils.SessionSv1ProcessEvent,
+				evArgs, &eventRply)
+		}
+		var partialDebit bool // credit consumed since authorization, only part of the messages debited
+		if err == nil && eventRply.MaxUsage != nil &&
+			reqProcessor.Flags.HasKey(utils.MetaAccounts) {
+			// the *sms usage is the number of messages
+			if usage, errUsage := cgrEv.FieldAsDuration(utils.Usage); errUsage == nil &&
+				*eventRply.MaxUsage < usage {
+				partialDebit = true
+				err = utils.ErrInsufficientCredit
+			}
+		}
+		if partialDebit {
+			cgrEv.Event[utils.Usage] = *eventRply.MaxUsage // the message is rejected, the CDR reflects the partial debit
+		} else if utils.ErrHasPrefix(err, utils.RalsErrorPrfx) ||
+			(err != nil && strings.Contains(err.Error(), utils.ErrInsufficientCredit.Error())) {
+			cgrEv.Event[utils.Usage] = 0 // the message is rejected, avoid further debits
+		} else if eventRply.MaxUsage != nil {
+			cgrEv.Event[utils.Usage] = *eventRply.MaxUsage // make sure the CDR reflects the debit
+		}
+		if agReq.CGRReply, err = NewCGRReply(&eventRply, err); err != nil {
+			return
+		}
+	case utils.MetaCDRs: // allow CDR processing
+	}
+	// separate request so we can capture the Event also here
+	if reqProcessor.Flags.HasKey(utils.MetaCDRs) &&
+		!reqProcessor.Flags.HasKey(utils.MetaDryRun) {
+		var rplyCDRs string
+		if err = sa.sS.Call(utils.SessionSv1ProcessCDR,
+			cgrEv, &rplyCDRs); err != nil {
+			agReq.CGRReply.Set([]string{utils.Error}, err.Error(), false, false)
+		}
+	}
+	if nM, err := agReq.AsNavigableMap(reqProcessor.ReplyFields); err != nil {
+		return false, err
+	} else {
+		agReq.Reply.Merge(nM)
+	}
+	if reqProcessor.Flags.HasKey(utils.MetaLog) {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> LOG, SMPP reply: %s",
+				utils.SMPPAgent, agReq.Reply))
+	}
+	if reqType == utils.MetaDryRun {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> DRY_RUN, SMPP reply: %s",
+				utils.SMPPAgent, agReq.Reply))
+	}
+	return true, nil
+}
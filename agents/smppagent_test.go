@@ -0,0 +1,261 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/sessions"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testSMPPSessionS has credit for all the messages of account 1001 and for one message of the others
+// account 1003 is authorized for all the messages, the credit being consumed until the debit
+type testSMPPSessionS struct {
+	sync.Mutex
+	debits    map[string]time.Duration // debited messages, indexed on account
+	cdrUsages map[string]time.Duration // Usage of the CDRs, indexed on account
+}
+
+func newTestSMPPSessionS() *testSMPPSessionS {
+	return &testSMPPSessionS{
+		debits:    make(map[string]time.Duration),
+		cdrUsages: make(map[string]time.Duration),
+	}
+}
+
+func (sS *testSMPPSessionS) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	sS.Lock()
+	defer sS.Unlock()
+	switch serviceMethod {
+	case utils.SessionSv1AuthorizeEvent:
+		ev := args.(*sessions.V1AuthorizeArgs).CGREvent
+		usage, err := ev.FieldAsDuration(utils.Usage)
+		if err != nil {
+			return err
+		}
+		if acnt := ev.Event[utils.Account]; acnt != "1001" && acnt != "1003" {
+			usage = time.Duration(1)
+		}
+		reply.(*sessions.V1AuthorizeReply).MaxUsage = &usage
+	case utils.SessionSv1ProcessEvent:
+		ev := args.(*sessions.V1ProcessEventArgs).CGREvent
+		usage, err := ev.FieldAsDuration(utils.Usage)
+		if err != nil {
+			return err
+		}
+		if ev.Event[utils.Account] != "1001" {
+			usage = time.Duration(1)
+		}
+		sS.debits[ev.Event[utils.Account].(string)] += usage
+		reply.(*sessions.V1ProcessEventReply).MaxUsage = &usage
+	case utils.SessionSv1ProcessCDR:
+		ev := args.(*utils.CGREvent)
+		usage, err := ev.FieldAsDuration(utils.Usage)
+		if err != nil {
+			return err
+		}
+		sS.cdrUsages[ev.Event[utils.Account].(string)] = usage
+		*reply.(*string) = utils.OK
+	default:
+		return utils.ErrNotImplemented
+	}
+	return nil
+}
+
+// testSMPPAgentClient starts the SMPPAgent and returns the function sending PDUs towards it
+func testSMPPAgentClient(t *testing.T, cfgJSON string,
+	sS *testSMPPSessionS) (sendPDU func(*smppPDU) *smppPDU, closeClnt func()) {
+	cfg, err := config.NewCGRConfigFromJsonStringWithDefaults(cfgJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := engine.NewMapStorage()
+	dm := engine.NewDataManager(data)
+	sa, err := NewSMPPAgent(cfg, engine.NewFilterS(cfg, nil, dm), sS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen(utils.TCP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go sa.serve(l)
+	clnt, err := net.Dial(utils.TCP, l.Addr().String())
+	if err != nil {
+		l.Close()
+		t.Fatal(err)
+	}
+	sendPDU = func(pdu *smppPDU) *smppPDU {
+		if _, err := clnt.Write(pdu.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		clnt.SetReadDeadline(time.Now().Add(2 * time.Second))
+		rpl, err := readSMPPPDU(clnt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rpl.Sequence != pdu.Sequence {
+			t.Errorf("expecting sequence: %d, received: %s", pdu.Sequence, rpl)
+		}
+		return rpl
+	}
+	return sendPDU, func() {
+		clnt.Close()
+		l.Close()
+	}
+}
+
+func TestSMPPAgentSubmitSM(t *testing.T) {
+	sendPDU, closeClnt := testSMPPAgentClient(t, `{
+"smpp_agent": {
+	"password": "secret",
+	"request_processors": [
+		{
+			"id": "sms",
+			"filters": ["*string:*vars.*smppCmd:submit_sm"],
+			"flags": ["*event", "*accounts"],
+			"request_fields":[
+				{"tag": "ToR", "field_id": "ToR", "type": "*constant", "value": "*sms"},
+				{"tag": "OriginID", "field_id": "OriginID", "type": "*composed",
+					"value": "~*vars.*smppMessageID", "mandatory": true},
+				{"tag": "Account", "field_id": "Account", "type": "*composed",
+					"value": "~*req.source_addr", "mandatory": true},
+				{"tag": "Destination", "field_id": "Destination", "type": "*composed",
+					"value": "~*req.destination_addr", "mandatory": true},
+				{"tag": "Usage", "field_id": "Usage", "type": "*composed",
+					"value": "~*req.segments", "mandatory": true},
+			],
+			"reply_fields":[
+				{"tag": "CommandStatus", "type": "*template", "value": "*smpp_status"},
+			],
+		},
+	],
+},
+}`, newTestSMPPSessionS())
+	defer closeClnt()
+	sm := testSMPPSMBody("1001", "1002", 0, 0, []byte("Hello"), nil)
+	if rpl := sendPDU(&smppPDU{CommandID: smppSubmitSM,
+		Sequence: 1, Body: sm}); rpl.Status != smppStatusInvBndSts {
+		t.Errorf("expecting not bound, received: %s", rpl)
+	}
+	bnd := &smppBind{SystemID: "CGRateS", Password: "wrong"}
+	if rpl := sendPDU(&smppPDU{CommandID: smppBindTransmitter,
+		Sequence: 2, Body: bnd.Bytes()}); rpl.Status != smppStatusInvPaswd {
+		t.Errorf("expecting invalid password, received: %s", rpl)
+	}
+	bnd.Password = "secret"
+	if rpl := sendPDU(&smppPDU{CommandID: smppBindTransmitter,
+		Sequence: 3, Body: bnd.Bytes()}); rpl.Status != smppStatusOK ||
+		rpl.CommandID != smppBindTransmitter|smppRespMask {
+		t.Errorf("expecting bound, received: %s", rpl)
+	}
+	if rpl := sendPDU(&smppPDU{CommandID: smppEnquireLink,
+		Sequence: 4}); rpl.Status != smppStatusOK {
+		t.Errorf("received: %s", rpl)
+	}
+	rpl := sendPDU(&smppPDU{CommandID: smppSubmitSM, Sequence: 5, Body: sm})
+	if rpl.CommandID != smppSubmitSM|smppRespMask || rpl.Status != smppStatusOK {
+		t.Errorf("expecting submit_sm_resp with ESME_ROK, received: %s", rpl)
+	} else if len(rpl.Body) < 2 || rpl.Body[len(rpl.Body)-1] != 0 {
+		t.Errorf("expecting message_id, received: %s", rpl)
+	}
+	// account 1002 has credit for one message out of two
+	sm = testSMPPSMBody("1002", "1001", 0, 0, make([]byte, 200), nil)
+	if rpl := sendPDU(&smppPDU{CommandID: smppSubmitSM,
+		Sequence: 6, Body: sm}); rpl.Status != 0x45 || len(rpl.Body) != 0 {
+		t.Errorf("expecting ESME_RSUBMITFAIL, received: %s", rpl)
+	}
+	if rpl := sendPDU(&smppPDU{CommandID: smppDeliverSM,
+		Sequence: 7, Body: sm}); rpl.Status != smppStatusInvBndSts {
+		t.Errorf("expecting invalid bind status, received: %s", rpl)
+	}
+	if rpl := sendPDU(&smppPDU{CommandID: 0x00000103,
+		Sequence: 8}); rpl.CommandID != smppGenericNack || rpl.Status != smppStatusInvCmdID {
+		t.Errorf("expecting generic_nack, received: %s", rpl)
+	}
+	if rpl := sendPDU(&smppPDU{CommandID: smppUnbind,
+		Sequence: 9}); rpl.CommandID != smppUnbind|smppRespMask || rpl.Status != smppStatusOK {
+		t.Errorf("expecting unbind_resp, received: %s", rpl)
+	}
+}
+
+func TestSMPPAgentInsufficientCredit(t *testing.T) {
+	sS := newTestSMPPSessionS()
+	sendPDU, closeClnt := testSMPPAgentClient(t, `{
+"smpp_agent": {
+	"request_processors": [
+		{
+			"id": "sms",
+			"filters": ["*string:*vars.*smppCmd:submit_sm"],
+			"flags": ["*event", "*accounts", "*cdrs"],
+			"request_fields":[
+				{"tag": "ToR", "field_id": "ToR", "type": "*constant", "value": "*sms"},
+				{"tag": "OriginID", "field_id": "OriginID", "type": "*composed",
+					"value": "~*vars.*smppMessageID", "mandatory": true},
+				{"tag": "Account", "field_id": "Account", "type": "*composed",
+					"value": "~*req.source_addr", "mandatory": true},
+				{"tag": "Destination", "field_id": "Destination", "type": "*composed",
+					"value": "~*req.destination_addr", "mandatory": true},
+				{"tag": "Usage", "field_id": "Usage", "type": "*composed",
+					"value": "~*req.segments", "mandatory": true},
+			],
+			"reply_fields":[
+				{"tag": "CommandStatus", "type": "*template", "value": "*smpp_status"},
+			],
+		},
+	],
+},
+}`, sS)
+	defer closeClnt()
+	bnd := &smppBind{SystemID: "CGRateS"}
+	if rpl := sendPDU(&smppPDU{CommandID: smppBindTransmitter,
+		Sequence: 1, Body: bnd.Bytes()}); rpl.Status != smppStatusOK {
+		t.Fatalf("expecting bound, received: %s", rpl)
+	}
+	// two segments, credit for all of them
+	if rpl := sendPDU(&smppPDU{CommandID: smppSubmitSM, Sequence: 2,
+		Body: testSMPPSMBody("1001", "1002", 0, 0, make([]byte, 200), nil)}); rpl.Status != smppStatusOK {
+		t.Errorf("expecting ESME_ROK, received: %s", rpl)
+	}
+	// MaxUsage lower than the segments on authorization, nothing debited
+	if rpl := sendPDU(&smppPDU{CommandID: smppSubmitSM, Sequence: 3,
+		Body: testSMPPSMBody("1002", "1001", 0, 0, make([]byte, 200), nil)}); rpl.Status != 0x45 {
+		t.Errorf("expecting ESME_RSUBMITFAIL, received: %s", rpl)
+	}
+	// MaxUsage lower than the segments on debit, the CDR is charging the partial debit
+	if rpl := sendPDU(&smppPDU{CommandID: smppSubmitSM, Sequence: 4,
+		Body: testSMPPSMBody("1003", "1001", 0, 0, make([]byte, 200), nil)}); rpl.Status != 0x45 {
+		t.Errorf("expecting ESME_RSUBMITFAIL, received: %s", rpl)
+	}
+	sS.Lock()
+	defer sS.Unlock()
+	if eDebits := map[string]time.Duration{"1001": 2, "1003": 1}; !reflect.DeepEqual(eDebits, sS.debits) {
+		t.Errorf("expecting debits: %v, received: %v", eDebits, sS.debits)
+	}
+	if eUsages := map[string]time.Duration{"1001": 2, "1002": 0, "1003": 1}; !reflect.DeepEqual(eUsages, sS.cdrUsages) {
+		t.Errorf("expecting CDR usages: %v, received: %v", eUsages, sS.cdrUsages)
+	}
+}
@@ -187,6 +187,7 @@ func (self *ApierV1) SetAccount(attr utils.AttrSetAccount, reply *string) (err e
 				ID: accID,
 			}
 		}
+		ub.JournalChanges(utils.ApierV1SetAccount)
 		if attr.ActionPlanId != "" {
 			_, err := guardian.Guardian.Guard(func() (interface{}, error) {
 				acntAPids, err := self.DataManager.DataDB().GetAccountActionPlans(accID, false, utils.NonTransactional)
@@ -273,10 +274,16 @@ func (self *ApierV1) SetAccount(attr utils.AttrSetAccount, reply *string) (err e
 		if attr.Disabled != nil {
 			ub.Disabled = *attr.Disabled
 		}
+		if attr.CreditLimit != nil {
+			if err := ub.SetCreditLimit(*attr.CreditLimit); err != nil {
+				return 0, err
+			}
+		}
 		// All prepared, save account
 		if err := self.DataManager.DataDB().SetAccount(ub); err != nil {
 			return 0, err
 		}
+		ub.StoreJournal()
 		return 0, nil
 	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
 	if err != nil {
@@ -402,26 +409,8 @@ func (self *ApierV1) GetAccount(attr *utils.AttrGetAccount, reply *interface{})
 	return nil
 }
 
-type AttrAddBalance struct {
-	Tenant         string
-	Account        string
-	BalanceUuid    *string
-	BalanceId      *string
-	BalanceType    string
-	Directions     *string
-	Value          float64
-	ExpiryTime     *string
-	RatingSubject  *string
-	Categories     *string
-	DestinationIds *string
-	TimingIds      *string
-	Weight         *float64
-	SharedGroups   *string
-	Overwrite      bool // When true it will reset if the balance is already there
-	Blocker        *bool
-	Disabled       *bool
-	Cdrlog         *bool
-}
+// AttrAddBalance are the attributes of AddBalance and DebitBalance, shared with the dispatchers
+type AttrAddBalance = utils.AttrAddBalance
 
 func (self *ApierV1) AddBalance(attr *AttrAddBalance, reply *string) error {
 	return self.modifyBalance(engine.TOPUP, attr, reply)
@@ -638,3 +627,93 @@ func (self *ApierV1) RemoveBalances(attr *utils.AttrSetBalance, reply *string) e
 	*reply = OK
 	return nil
 }
+
+// GetBalanceHistory returns the balance movements of an account, as recorded when rals.balance_history is enabled
+func (self *ApierV1) GetBalanceHistory(attr utils.AttrGetBalanceHistory, reply *[]*engine.BalanceMovement) error {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	fltr, err := attr.AsBalanceHistoryFilter(self.Config.GeneralCfg().DefaultTimezone)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	mvs, err := self.CdrDb.GetBalanceMovements(fltr)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = mvs
+	return nil
+}
+
+// ReserveBalance holds value out of the account balances until captured, released or expired, replying with the reservation ID
+func (self *ApierV1) ReserveBalance(attr utils.AttrReserveBalance, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "BalanceType", "Value"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	var ttl time.Duration
+	if attr.TTL != "" {
+		if ttl, err = utils.ParseDurationWithNanosecs(attr.TTL); err != nil {
+			return utils.NewErrServerError(err)
+		}
+	}
+	if attr.ReservationID == "" {
+		attr.ReservationID = utils.GenUUID()
+	}
+	fltr := &engine.BalanceFilter{
+		ID:   attr.BalanceID,
+		Type: utils.StringPointer(attr.BalanceType),
+	}
+	if attr.DestinationIDs != nil {
+		fltr.DestinationIDs = utils.StringMapPointer(utils.ParseStringMap(*attr.DestinationIDs))
+	}
+	if attr.Categories != nil {
+		fltr.Categories = utils.StringMapPointer(utils.ParseStringMap(*attr.Categories))
+	}
+	if _, err = engine.ReserveBalance(utils.AccountKey(attr.Tenant, attr.Account),
+		attr.ReservationID, fltr, attr.Value, ttl); err != nil {
+		if err != utils.ErrNotFound && err != utils.ErrExists &&
+			err != utils.ErrInsufficientCredit && err != utils.ErrInvalidValue {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = attr.ReservationID
+	return
+}
+
+// CaptureReservation debits the account out of a reservation, replying with the value captured
+func (self *ApierV1) CaptureReservation(attr utils.AttrReservation, reply *float64) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "ReservationID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	captured, err := engine.CaptureReservation(utils.AccountKey(attr.Tenant, attr.Account),
+		attr.ReservationID, attr.Value)
+	if err != nil {
+		if err != utils.ErrNotFound && err != utils.ErrInsufficientCredit &&
+			err != utils.ErrInvalidValue {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = captured
+	return
+}
+
+// ReleaseReservation gives back the reserved value to the account balances
+func (self *ApierV1) ReleaseReservation(attr utils.AttrReservation, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "ReservationID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err = engine.ReleaseReservation(utils.AccountKey(attr.Tenant, attr.Account),
+		attr.ReservationID); err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
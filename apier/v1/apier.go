@@ -127,6 +127,34 @@ func (apier *ApierV1) GetSharedGroup(sgId string, reply *engine.SharedGroup) err
 	return nil
 }
 
+type AttrSetSharedGroupCreditLimit struct {
+	ID          string
+	CreditLimit float64 // 0 removes the limit
+}
+
+// SetSharedGroupCreditLimit limits how far the default balances sharing the group may go negative
+func (apier *ApierV1) SetSharedGroupCreditLimit(attr AttrSetSharedGroupCreditLimit, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"ID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if attr.CreditLimit < 0 {
+		return fmt.Errorf("negative credit limit: %v", attr.CreditLimit)
+	}
+	sg, err := apier.DataManager.GetSharedGroup(attr.ID, true, utils.NonTransactional)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	sg.CreditLimit = attr.CreditLimit
+	if err = apier.DataManager.SetSharedGroup(sg, utils.NonTransactional); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*reply = OK
+	return
+}
+
 func (self *ApierV1) SetDestination(attrs utils.AttrSetDestination, reply *string) (err error) {
 	if missing := utils.MissingStructFields(&attrs, []string{"Id", "Prefixes"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
@@ -263,6 +291,8 @@ type AttrLoadTpFromStorDb struct {
 	FlushDb  bool // Flush dataDB before loading
 	DryRun   bool // Only simulate, no write
 	Validate bool // Run structural checks
+	// ActivationTime stages the load, to be switched live by the Scheduler at this time <*midnight|$time>
+	ActivationTime string
 }
 
 // Loads complete data in a TP from storDb
@@ -285,6 +315,18 @@ func (self *ApierV1) LoadTariffPlanFromStorDb(attrs AttrLoadTpFromStorDb, reply
 		*reply = OK
 		return nil // Mission complete, no errors
 	}
+	if attrs.ActivationTime != "" {
+		loadID, err := self.stageLoad(dbReader, attrs.ActivationTime)
+		if err != nil {
+			return err
+		}
+		*reply = loadID
+		return nil
+	}
+	if err := dbReader.SaveLoadVersion(utils.GenUUID(),
+		self.Config.GeneralCfg().LoadHistorySize); err != nil {
+		return utils.NewErrServerError(err)
+	}
 	if err := dbReader.WriteToDatabase(attrs.FlushDb, false, false); err != nil {
 		return utils.NewErrServerError(err)
 	}
@@ -1104,40 +1146,48 @@ func (v1 *ApierV1) GetCacheKeys(args utils.ArgsCacheKeys, reply *utils.ArgsCache
 	return
 }
 
-func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder, reply *string) error {
-	if len(attrs.FolderPath) == 0 {
-		return fmt.Errorf("%s:%s", utils.ErrMandatoryIeMissing.Error(), "FolderPath")
+// newTpReaderFromFolder returns the TpReader for the .csv files in folderPath
+func (self *ApierV1) newTpReaderFromFolder(folderPath string) (*engine.TpReader, error) {
+	if len(folderPath) == 0 {
+		return nil, fmt.Errorf("%s:%s", utils.ErrMandatoryIeMissing.Error(), "FolderPath")
 	}
-	if fi, err := os.Stat(attrs.FolderPath); err != nil {
+	if fi, err := os.Stat(folderPath); err != nil {
 		if strings.HasSuffix(err.Error(), "no such file or directory") {
-			return utils.ErrInvalidPath
+			return nil, utils.ErrInvalidPath
 		}
-		return utils.NewErrServerError(err)
+		return nil, utils.NewErrServerError(err)
 	} else if !fi.IsDir() {
-		return utils.ErrInvalidPath
+		return nil, utils.ErrInvalidPath
 	}
-	loader := engine.NewTpReader(self.DataManager.DataDB(),
+	return engine.NewTpReader(self.DataManager.DataDB(),
 		engine.NewFileCSVStorage(utils.CSV_SEP,
-			path.Join(attrs.FolderPath, utils.DESTINATIONS_CSV),
-			path.Join(attrs.FolderPath, utils.TIMINGS_CSV),
-			path.Join(attrs.FolderPath, utils.RATES_CSV),
-			path.Join(attrs.FolderPath, utils.DESTINATION_RATES_CSV),
-			path.Join(attrs.FolderPath, utils.RATING_PLANS_CSV),
-			path.Join(attrs.FolderPath, utils.RATING_PROFILES_CSV),
-			path.Join(attrs.FolderPath, utils.SHARED_GROUPS_CSV),
-			path.Join(attrs.FolderPath, utils.ACTIONS_CSV),
-			path.Join(attrs.FolderPath, utils.ACTION_PLANS_CSV),
-			path.Join(attrs.FolderPath, utils.ACTION_TRIGGERS_CSV),
-			path.Join(attrs.FolderPath, utils.ACCOUNT_ACTIONS_CSV),
-			path.Join(attrs.FolderPath, utils.ResourcesCsv),
-			path.Join(attrs.FolderPath, utils.StatsCsv),
-			path.Join(attrs.FolderPath, utils.ThresholdsCsv),
-			path.Join(attrs.FolderPath, utils.FiltersCsv),
-			path.Join(attrs.FolderPath, utils.SuppliersCsv),
-			path.Join(attrs.FolderPath, utils.AttributesCsv),
-			path.Join(attrs.FolderPath, utils.ChargersCsv),
-			path.Join(attrs.FolderPath, utils.DispatchersCsv),
-		), "", self.Config.GeneralCfg().DefaultTimezone)
+			path.Join(folderPath, utils.DESTINATIONS_CSV),
+			path.Join(folderPath, utils.TIMINGS_CSV),
+			path.Join(folderPath, utils.RATES_CSV),
+			path.Join(folderPath, utils.DESTINATION_RATES_CSV),
+			path.Join(folderPath, utils.RATING_PLANS_CSV),
+			path.Join(folderPath, utils.RATING_PROFILES_CSV),
+			path.Join(folderPath, utils.SHARED_GROUPS_CSV),
+			path.Join(folderPath, utils.ACTIONS_CSV),
+			path.Join(folderPath, utils.ACTION_PLANS_CSV),
+			path.Join(folderPath, utils.ACTION_TRIGGERS_CSV),
+			path.Join(folderPath, utils.ACCOUNT_ACTIONS_CSV),
+			path.Join(folderPath, utils.ResourcesCsv),
+			path.Join(folderPath, utils.StatsCsv),
+			path.Join(folderPath, utils.ThresholdsCsv),
+			path.Join(folderPath, utils.FiltersCsv),
+			path.Join(folderPath, utils.SuppliersCsv),
+			path.Join(folderPath, utils.AttributesCsv),
+			path.Join(folderPath, utils.ChargersCsv),
+			path.Join(folderPath, utils.DispatchersCsv),
+		), "", self.Config.GeneralCfg().DefaultTimezone), nil
+}
+
+func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder, reply *string) error {
+	loader, err := self.newTpReaderFromFolder(attrs.FolderPath)
+	if err != nil {
+		return err
+	}
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
 	}
@@ -1152,6 +1202,18 @@ func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 		}
 	}
 
+	if attrs.ActivationTime != "" {
+		loadID, err := self.stageLoad(loader, attrs.ActivationTime)
+		if err != nil {
+			return err
+		}
+		*reply = loadID
+		return nil
+	}
+	if err := loader.SaveLoadVersion(utils.GenUUID(),
+		self.Config.GeneralCfg().LoadHistorySize); err != nil {
+		return utils.NewErrServerError(err)
+	}
 	if err := loader.WriteToDatabase(attrs.FlushDb, false, false); err != nil {
 		return utils.NewErrServerError(err)
 	}
@@ -1183,6 +1245,112 @@ func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 	return nil
 }
 
+// DiffTariffPlanFromFolder reports the changes LoadTariffPlanFromFolder would do in DataDB, without writing anything
+func (self *ApierV1) DiffTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder, reply *engine.LoadDiff) error {
+	loader, err := self.newTpReaderFromFolder(attrs.FolderPath)
+	if err != nil {
+		return err
+	}
+	if err := loader.LoadAll(); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	ld, err := loader.DiffWithDatabase(false)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*reply = ld
+	return nil
+}
+
+type AttrRollbackLoad struct {
+	LoadID string // as listed by GetLoadHistory
+}
+
+// RollbackLoad restores the DataDB objects replaced by a tariff plan load and removes the ones it created
+func (self *ApierV1) RollbackLoad(attrs AttrRollbackLoad, reply *string) error {
+	if missing := utils.MissingStructFields(&attrs, []string{"LoadID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	lv, err := self.DataManager.RollbackLoad(attrs.LoadID)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	if len(lv.IDs(utils.ACTION_PLAN_PREFIX)) != 0 {
+		sched := self.ServManager.GetScheduler()
+		if sched != nil {
+			utils.Logger.Info("ApierV1.RollbackLoad, reloading scheduler.")
+			sched.Reload()
+		}
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// stageLoad keeps the loaded tariff plan aside until actTime, returning the ID of the staged load
+func (self *ApierV1) stageLoad(tpr *engine.TpReader, actTime string) (loadID string, err error) {
+	var aTime time.Time
+	if aTime, err = utils.ParseTimeDetectLayout(actTime,
+		self.Config.GeneralCfg().DefaultTimezone); err != nil {
+		return "", utils.NewErrServerError(err)
+	}
+	loadID = utils.GenUUID()
+	if err = tpr.StageLoad(loadID, aTime); err != nil {
+		return "", utils.NewErrServerError(err)
+	}
+	tpr.Init()
+	if sched := self.ServManager.GetScheduler(); sched != nil {
+		utils.Logger.Info(fmt.Sprintf("ApierV1, staged load <%s>, reloading scheduler.", loadID))
+		sched.Reload()
+	}
+	return
+}
+
+type AttrStagedLoad struct {
+	LoadID string // as returned when staging the load
+}
+
+// ActivateStagedLoad switches a staged load live without waiting for its activation time
+func (self *ApierV1) ActivateStagedLoad(attrs AttrStagedLoad, reply *string) error {
+	if missing := utils.MissingStructFields(&attrs, []string{"LoadID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := self.DataManager.ActivateStagedLoad(attrs.LoadID,
+		self.Config.GeneralCfg().LoadHistorySize); err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	if sched := self.ServManager.GetScheduler(); sched != nil {
+		utils.Logger.Info("ApierV1.ActivateStagedLoad, reloading scheduler.")
+		sched.Reload()
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// RemoveStagedLoad discards a staged load before its activation
+func (self *ApierV1) RemoveStagedLoad(attrs AttrStagedLoad, reply *string) error {
+	if missing := utils.MissingStructFields(&attrs, []string{"LoadID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := self.DataManager.RemoveStagedLoad(attrs.LoadID); err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	if sched := self.ServManager.GetScheduler(); sched != nil {
+		utils.Logger.Info("ApierV1.RemoveStagedLoad, reloading scheduler.")
+		sched.Reload()
+	}
+	*reply = utils.OK
+	return nil
+}
+
 type AttrRemoveRatingProfile struct {
 	Direction string
 	Tenant    string
@@ -1448,3 +1616,13 @@ func (v1 *ApierV1) ReplayFailedPosts(args ArgsReplyFailedPosts, reply *string) (
 	*reply = utils.OK
 	return nil
 }
+
+// GetEventBusMetrics returns the back-pressure metrics of the asynchronous connections, indexed on subscriber
+func (v1 *ApierV1) GetEventBusMetrics(ign string, reply *map[string]*engine.EventBusMetrics) error {
+	mtrcs, err := engine.GetEventBusMetrics()
+	if err != nil {
+		return err
+	}
+	*reply = mtrcs
+	return nil
+}
@@ -61,15 +61,18 @@ func (apierV1 *ApierV1) SetAttributeProfile(alsPrf *engine.AttributeProfile, rep
 	}
 	if len(alsPrf.Attributes) != 0 {
 		for _, attr := range alsPrf.Attributes {
+			if attr.Type == utils.MetaRemove {
+				continue // no Substitute needed
+			}
 			for _, sub := range attr.Substitute {
 				if sub.Rules == "" {
 					return utils.NewErrMandatoryIeMissing("Rules")
 				}
-				if err := sub.Compile(); err != nil {
-					return utils.NewErrServerError(err)
-				}
 			}
 		}
+		if err := alsPrf.Compile(); err != nil { // checks the types and compiles the substitutes
+			return utils.NewErrServerError(err)
+		}
 	}
 
 	if err := apierV1.DataManager.SetAttributeProfile(alsPrf, true); err != nil {
@@ -0,0 +1,50 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/agents"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewDiameterAgentV1(da *agents.DiameterAgent) *DiameterAgentV1 {
+	return &DiameterAgentV1{da: da}
+}
+
+// Exports RPC from DiameterAgent
+type DiameterAgentV1 struct {
+	da *agents.DiameterAgent
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (dAv1 *DiameterAgentV1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(dAv1, serviceMethod, args, reply)
+}
+
+// GetPeerStats returns the request statistics of the Diameter peers, indexed on Origin-Host
+func (dAv1 *DiameterAgentV1) GetPeerStats(ign string,
+	reply *map[string]*agents.DiamPeerStats) error {
+	return dAv1.da.V1GetPeerStats(ign, reply)
+}
+
+func (dAv1 *DiameterAgentV1) Ping(ign *utils.CGREvent, reply *string) error {
+	*reply = utils.Pong
+	return nil
+}
@@ -19,10 +19,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package v1
 
 import (
+	"time"
+
 	"github.com/cgrates/cgrates/dispatchers"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/sessions"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/ltcache"
 )
 
 // GetDispatcherProfile returns a Dispatcher Profile
@@ -84,6 +87,27 @@ func (apierV1 *ApierV1) RemoveDispatcherProfile(arg *utils.TenantID, reply *stri
 	return nil
 }
 
+func NewDispatcherSv1(dps *dispatchers.DispatcherService) *DispatcherSv1 {
+	return &DispatcherSv1{dS: dps}
+}
+
+// Exports RPC from DispatcherS
+type DispatcherSv1 struct {
+	dS *dispatchers.DispatcherService
+}
+
+// Ping return pong if the service is active
+func (dS *DispatcherSv1) Ping(ign *utils.CGREvent, reply *string) error {
+	*reply = utils.Pong
+	return nil
+}
+
+// GetAPIKeyCounters returns the request counters of an API key, indexed on method
+func (dS *DispatcherSv1) GetAPIKeyCounters(apiKey string,
+	reply *map[string]*dispatchers.APIKeyCounter) error {
+	return dS.dS.GetAPIKeyCounters(apiKey, reply)
+}
+
 func NewDispatcherThresholdSv1(dps *dispatchers.DispatcherService) *DispatcherThresholdSv1 {
 	return &DispatcherThresholdSv1{dS: dps}
 }
@@ -343,6 +367,11 @@ func (dS *DispatcherSessionSv1) ForceDisconnect(args *dispatchers.FilterSessionW
 	return dS.dS.SessionSv1ForceDisconnect(args, reply)
 }
 
+func (dS *DispatcherSessionSv1) ReAuthorize(args *dispatchers.FilterSessionWithApiKey,
+	reply *string) (err error) {
+	return dS.dS.SessionSv1ReAuthorize(args, reply)
+}
+
 func (dS *DispatcherSessionSv1) GetPassiveSessions(args *dispatchers.FilterSessionWithApiKey,
 	reply *[]*sessions.ActiveSession) (err error) {
 	return dS.dS.SessionSv1GetPassiveSessions(args, reply)
@@ -362,3 +391,191 @@ func (dS *DispatcherSessionSv1) SetPassiveSession(args *dispatchers.SessionWithA
 	reply *string) (err error) {
 	return dS.dS.SessionSv1SetPassiveSession(args, reply)
 }
+
+func NewDispatcherCDRsV1(dps *dispatchers.DispatcherService) *DispatcherCDRsV1 {
+	return &DispatcherCDRsV1{dS: dps}
+}
+
+// Exports RPC from CDRsV1
+type DispatcherCDRsV1 struct {
+	dS *dispatchers.DispatcherService
+}
+
+// ProcessCDR implements CDRsV1ProcessCDR
+func (dS *DispatcherCDRsV1) ProcessCDR(args *dispatchers.CDRWithApiKey,
+	reply *string) error {
+	return dS.dS.CDRsV1ProcessCDR(args, reply)
+}
+
+func NewDispatcherCDRsV2(dps *dispatchers.DispatcherService) *DispatcherCDRsV2 {
+	return &DispatcherCDRsV2{dS: dps}
+}
+
+// Exports RPC from CDRsV2
+type DispatcherCDRsV2 struct {
+	dS *dispatchers.DispatcherService
+}
+
+// ProcessCDR implements CDRsV2ProcessCDR
+func (dS *DispatcherCDRsV2) ProcessCDR(args *dispatchers.ArgV2ProcessCDRWithApiKey,
+	reply *string) error {
+	return dS.dS.CDRsV2ProcessCDR(args, reply)
+}
+
+func NewDispatcherResponder(dps *dispatchers.DispatcherService) *DispatcherResponder {
+	return &DispatcherResponder{dR: dps}
+}
+
+// Exports RPC from Responder
+type DispatcherResponder struct {
+	dR *dispatchers.DispatcherService
+}
+
+// GetCost implements ResponderGetCost
+func (dR *DispatcherResponder) GetCost(args *dispatchers.CallDescriptorWithApiKey,
+	reply *engine.CallCost) error {
+	return dR.dR.ResponderGetCost(args, reply)
+}
+
+// Debit implements ResponderDebit
+func (dR *DispatcherResponder) Debit(args *dispatchers.CallDescriptorWithApiKey,
+	reply *engine.CallCost) error {
+	return dR.dR.ResponderDebit(args, reply)
+}
+
+// MaxDebit implements ResponderMaxDebit
+func (dR *DispatcherResponder) MaxDebit(args *dispatchers.CallDescriptorWithApiKey,
+	reply *engine.CallCost) error {
+	return dR.dR.ResponderMaxDebit(args, reply)
+}
+
+func NewDispatcherCacheSv1(dps *dispatchers.DispatcherService) *DispatcherCacheSv1 {
+	return &DispatcherCacheSv1{dS: dps}
+}
+
+// Exports RPC from CacheSv1
+type DispatcherCacheSv1 struct {
+	dS *dispatchers.DispatcherService
+}
+
+// Ping implements CacheSv1Ping
+func (dS *DispatcherCacheSv1) Ping(args *dispatchers.CGREvWithApiKey, reply *string) error {
+	return dS.dS.CacheSv1Ping(args, reply)
+}
+
+// GetItemIDs implements CacheSv1GetItemIDs
+func (dS *DispatcherCacheSv1) GetItemIDs(args *dispatchers.ArgsGetCacheItemIDsWithApiKey,
+	reply *[]string) error {
+	return dS.dS.CacheSv1GetItemIDs(args, reply)
+}
+
+// HasItem implements CacheSv1HasItem
+func (dS *DispatcherCacheSv1) HasItem(args *dispatchers.ArgsGetCacheItemWithApiKey,
+	reply *bool) error {
+	return dS.dS.CacheSv1HasItem(args, reply)
+}
+
+// GetItemExpiryTime implements CacheSv1GetItemExpiryTime
+func (dS *DispatcherCacheSv1) GetItemExpiryTime(args *dispatchers.ArgsGetCacheItemWithApiKey,
+	reply *time.Time) error {
+	return dS.dS.CacheSv1GetItemExpiryTime(args, reply)
+}
+
+// RemoveItem implements CacheSv1RemoveItem, broadcasted to all the engines in the profile
+func (dS *DispatcherCacheSv1) RemoveItem(args *dispatchers.ArgsGetCacheItemWithApiKey,
+	reply *string) error {
+	return dS.dS.CacheSv1RemoveItem(args, reply)
+}
+
+// Clear implements CacheSv1Clear, broadcasted to all the engines in the profile
+func (dS *DispatcherCacheSv1) Clear(args *dispatchers.AttrCacheIDsWithApiKey,
+	reply *string) error {
+	return dS.dS.CacheSv1Clear(args, reply)
+}
+
+// GetCacheStats implements CacheSv1GetCacheStats
+func (dS *DispatcherCacheSv1) GetCacheStats(args *dispatchers.AttrCacheIDsWithApiKey,
+	reply *map[string]*ltcache.CacheStats) error {
+	return dS.dS.CacheSv1GetCacheStats(args, reply)
+}
+
+// PrecacheStatus implements CacheSv1PrecacheStatus
+func (dS *DispatcherCacheSv1) PrecacheStatus(args *dispatchers.AttrCacheIDsWithApiKey,
+	reply *map[string]string) error {
+	return dS.dS.CacheSv1PrecacheStatus(args, reply)
+}
+
+// HasGroup implements CacheSv1HasGroup
+func (dS *DispatcherCacheSv1) HasGroup(args *dispatchers.ArgsGetGroupWithApiKey,
+	reply *bool) error {
+	return dS.dS.CacheSv1HasGroup(args, reply)
+}
+
+// GetGroupItemIDs implements CacheSv1GetGroupItemIDs
+func (dS *DispatcherCacheSv1) GetGroupItemIDs(args *dispatchers.ArgsGetGroupWithApiKey,
+	reply *[]string) error {
+	return dS.dS.CacheSv1GetGroupItemIDs(args, reply)
+}
+
+// RemoveGroup implements CacheSv1RemoveGroup, broadcasted to all the engines in the profile
+func (dS *DispatcherCacheSv1) RemoveGroup(args *dispatchers.ArgsGetGroupWithApiKey,
+	reply *string) error {
+	return dS.dS.CacheSv1RemoveGroup(args, reply)
+}
+
+func NewDispatcherApierV1(dps *dispatchers.DispatcherService) *DispatcherApierV1 {
+	return &DispatcherApierV1{dA: dps}
+}
+
+// Exports RPC from ApierV1
+type DispatcherApierV1 struct {
+	dA *dispatchers.DispatcherService
+}
+
+// GetAccount implements ApierV1GetAccount
+func (dA *DispatcherApierV1) GetAccount(args *dispatchers.AttrGetAccountWithApiKey,
+	reply *interface{}) error {
+	return dA.dA.ApierV1GetAccount(args, reply)
+}
+
+// SetAccount implements ApierV1SetAccount
+func (dA *DispatcherApierV1) SetAccount(args *dispatchers.AttrSetAccountWithApiKey,
+	reply *string) error {
+	return dA.dA.ApierV1SetAccount(args, reply)
+}
+
+// RemoveAccount implements ApierV1RemoveAccount
+func (dA *DispatcherApierV1) RemoveAccount(args *dispatchers.AttrRemoveAccountWithApiKey,
+	reply *string) error {
+	return dA.dA.ApierV1RemoveAccount(args, reply)
+}
+
+// AddBalance implements ApierV1AddBalance
+func (dA *DispatcherApierV1) AddBalance(args *dispatchers.AttrAddBalanceWithApiKey,
+	reply *string) error {
+	return dA.dA.ApierV1AddBalance(args, reply)
+}
+
+// DebitBalance implements ApierV1DebitBalance
+func (dA *DispatcherApierV1) DebitBalance(args *dispatchers.AttrAddBalanceWithApiKey,
+	reply *string) error {
+	return dA.dA.ApierV1DebitBalance(args, reply)
+}
+
+// SetBalance implements ApierV1SetBalance
+func (dA *DispatcherApierV1) SetBalance(args *dispatchers.AttrSetBalanceWithApiKey,
+	reply *string) error {
+	return dA.dA.ApierV1SetBalance(args, reply)
+}
+
+// RemoveBalances implements ApierV1RemoveBalances
+func (dA *DispatcherApierV1) RemoveBalances(args *dispatchers.AttrSetBalanceWithApiKey,
+	reply *string) error {
+	return dA.dA.ApierV1RemoveBalances(args, reply)
+}
+
+// ReloadCache implements ApierV1ReloadCache, broadcasted to all the engines in the profile
+func (dA *DispatcherApierV1) ReloadCache(args *dispatchers.AttrReloadCacheWithApiKey,
+	reply *string) error {
+	return dA.dA.ApierV1ReloadCache(args, reply)
+}
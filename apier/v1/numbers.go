@@ -0,0 +1,118 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// GetPortedNumbers returns the ported numbers of a prefix
+func (apierV1 *ApierV1) GetPortedNumbers(arg utils.TenantID, reply *engine.PortedNumbers) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if pn, err := apierV1.DataManager.GetPortedNumbers(arg.Tenant, arg.ID, true, true, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	} else {
+		*reply = *pn
+	}
+	return nil
+}
+
+// GetPortedNumbersIDs returns the prefixes with ported numbers registered for a tenant
+func (apierV1 *ApierV1) GetPortedNumbersIDs(tenant string, pnIDs *[]string) error {
+	prfx := utils.PortedNumbersPrefix + tenant + ":"
+	keys, err := apierV1.DataManager.DataDB().GetKeysForPrefix(prfx)
+	if err != nil {
+		return err
+	}
+	retIDs := make([]string, len(keys))
+	for i, key := range keys {
+		retIDs[i] = key[len(prfx):]
+	}
+	*pnIDs = retIDs
+	return nil
+}
+
+// SetPortedNumbers adds/updates the ported numbers of a prefix
+func (apierV1 *ApierV1) SetPortedNumbers(pn *engine.PortedNumbers, reply *string) error {
+	if missing := utils.MissingStructFields(pn, []string{"Tenant", "ID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := pn.Validate(); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	if err := apierV1.DataManager.SetPortedNumbers(pn); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// RemovePortedNumbers removes the ported numbers of a prefix
+func (apierV1 *ApierV1) RemovePortedNumbers(arg utils.TenantID, reply *string) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := apierV1.DataManager.RemovePortedNumbers(arg.Tenant,
+		arg.ID, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
+func NewNumberSv1(nS *engine.NumberService) *NumberSv1 {
+	return &NumberSv1{nS: nS}
+}
+
+// Exports RPC from NumberS
+type NumberSv1 struct {
+	nS *engine.NumberService
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (nSv1 *NumberSv1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(nSv1, serviceMethod, args, reply)
+}
+
+func (nSv1 *NumberSv1) Ping(ign *utils.CGREvent, reply *string) error {
+	*reply = utils.Pong
+	return nil
+}
+
+// ProcessEvent normalizes the numbers within the event and routes the ported ones
+func (nSv1 *NumberSv1) ProcessEvent(args *utils.CGREvent,
+	reply *engine.NumberSProcessEventReply) error {
+	return nSv1.nS.V1ProcessEvent(args, reply)
+}
+
+// LookupNumber returns the E.164 format of a number together with its routing number
+func (nSv1 *NumberSv1) LookupNumber(args *engine.ArgsLookupNumber,
+	reply *engine.NumberLookup) error {
+	return nSv1.nS.V1LookupNumber(args, reply)
+}
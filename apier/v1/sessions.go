@@ -91,6 +91,11 @@ func (ssv1 *SessionSv1) ForceDisconnect(args map[string]string,
 	return ssv1.Ss.BiRPCv1ForceDisconnect(nil, args, rply)
 }
 
+func (ssv1 *SessionSv1) ReAuthorize(args map[string]string,
+	rply *string) error {
+	return ssv1.Ss.BiRPCv1ReAuthorize(nil, args, rply)
+}
+
 func (ssv1 *SessionSv1) GetPassiveSessions(args map[string]string,
 	rply *[]*sessions.ActiveSession) error {
 	return ssv1.Ss.BiRPCv1GetPassiveSessions(nil, args, rply)
@@ -43,6 +43,7 @@ func (ssv1 *SessionSv1) Handlers() map[string]interface{} {
 		utils.SessionSv1ProcessEvent:              ssv1.BiRPCv1ProcessEvent,
 
 		utils.SessionSv1ForceDisconnect:            ssv1.BiRPCv1ForceDisconnect,
+		utils.SessionSv1ReAuthorize:                ssv1.BiRPCv1ReAuthorize,
 		utils.SessionSv1RegisterInternalBiJSONConn: ssv1.BiRPCv1RegisterInternalBiJSONConn,
 		utils.SessionSv1Ping:                       ssv1.BiRPCPing,
 
@@ -120,6 +121,11 @@ func (ssv1 *SessionSv1) BiRPCv1ForceDisconnect(clnt *rpc2.Client, args map[strin
 	return ssv1.Ss.BiRPCv1ForceDisconnect(clnt, args, rply)
 }
 
+func (ssv1 *SessionSv1) BiRPCv1ReAuthorize(clnt *rpc2.Client, args map[string]string,
+	rply *string) error {
+	return ssv1.Ss.BiRPCv1ReAuthorize(clnt, args, rply)
+}
+
 func (ssv1 *SessionSv1) BiRPCv1RegisterInternalBiJSONConn(clnt *rpc2.Client, args string,
 	rply *string) error {
 	return ssv1.Ss.BiRPCv1RegisterInternalBiJSONConn(clnt, args, rply)
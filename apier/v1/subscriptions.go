@@ -0,0 +1,148 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// GetSubscriptionProduct returns a SubscriptionProduct
+func (self *ApierV1) GetSubscriptionProduct(arg utils.TenantID, reply *engine.SubscriptionProduct) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	prdct, err := self.DataManager.GetSubscriptionProduct(arg.Tenant, arg.ID)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = *prdct
+	return nil
+}
+
+// SetSubscriptionProduct adds or updates a SubscriptionProduct, the subscribers being charged with the new fee from their next billing cycle
+func (self *ApierV1) SetSubscriptionProduct(prdct *engine.SubscriptionProduct, reply *string) error {
+	if missing := utils.MissingStructFields(prdct, []string{"Tenant", "ID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if prdct.BundleActionsID != "" {
+		if _, err := self.DataManager.GetActions(prdct.BundleActionsID, false, utils.NonTransactional); err != nil {
+			return utils.NewErrServerError(err)
+		}
+	}
+	if err := self.DataManager.SetSubscriptionProduct(prdct); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// RemoveSubscriptionProduct removes a SubscriptionProduct
+func (self *ApierV1) RemoveSubscriptionProduct(arg utils.TenantID, reply *string) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := self.DataManager.RemoveSubscriptionProduct(arg.Tenant, arg.ID); err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// Subscribe ties the account to a product, charging the fee prorated to the rest of the billing cycle
+func (self *ApierV1) Subscribe(attr utils.AttrSubscription, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "ProductID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	sTime, err := self.subscriptionTime(attr.Time)
+	if err != nil {
+		return
+	}
+	if err = engine.Subscribe(utils.AccountKey(attr.Tenant, attr.Account),
+		attr.ProductID, attr.AnchorDay, sTime); err != nil {
+		if err != utils.ErrNotFound && err != utils.ErrExists {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// CancelSubscription removes the subscription of the account to a product, crediting the unused part of the billing cycle
+func (self *ApierV1) CancelSubscription(attr utils.AttrSubscription, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "ProductID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	cTime, err := self.subscriptionTime(attr.Time)
+	if err != nil {
+		return
+	}
+	if err = engine.CancelSubscription(utils.AccountKey(attr.Tenant, attr.Account),
+		attr.ProductID, cTime); err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// ChangeSubscription moves the account to another product, the unused part of the billing cycle
+// being credited for the old product and charged for the new one
+func (self *ApierV1) ChangeSubscription(attr utils.AttrSubscription, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account",
+		"ProductID", "NewProductID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	cTime, err := self.subscriptionTime(attr.Time)
+	if err != nil {
+		return
+	}
+	if err = engine.ChangeSubscription(utils.AccountKey(attr.Tenant, attr.Account),
+		attr.ProductID, attr.NewProductID, cTime); err != nil {
+		if err != utils.ErrNotFound && err != utils.ErrExists {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// subscriptionTime parses the time of a subscription change, defaulting to now
+func (self *ApierV1) subscriptionTime(tmStr string) (tm time.Time, err error) {
+	if tmStr == "" {
+		return time.Now(), nil
+	}
+	if tm, err = utils.ParseTimeDetectLayout(tmStr,
+		self.Config.GeneralCfg().DefaultTimezone); err != nil {
+		err = utils.NewErrServerError(err)
+	}
+	return
+}
@@ -71,6 +71,7 @@ func (self *ApierV1) AddAccountActionTriggers(attr AttrAddAccountActionTriggers,
 		} else {
 			return 0, err
 		}
+		account.JournalChanges(utils.ApierV1AddAccountActionTriggers)
 		if attr.ActionTriggerIDs != nil {
 			if attr.ActionTriggerOverwrite {
 				account.ActionTriggers = make(engine.ActionTriggers, 0)
@@ -97,7 +98,11 @@ func (self *ApierV1) AddAccountActionTriggers(attr AttrAddAccountActionTriggers,
 			}
 		}
 		account.InitCounters()
-		return 0, self.DataManager.DataDB().SetAccount(account)
+		if err := self.DataManager.DataDB().SetAccount(account); err != nil {
+			return 0, err
+		}
+		account.StoreJournal()
+		return 0, nil
 	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
 	if err != nil {
 		*reply = err.Error()
@@ -606,9 +611,13 @@ func (self *ApierV1) AddTriggeredAction(attr AttrAddActionTrigger, reply *string
 		if err != nil {
 			return 0, err
 		}
+		acnt.JournalChanges(utils.ApierV1AddTriggeredAction)
 		acnt.ActionTriggers = append(acnt.ActionTriggers, at)
-
-		return 0, self.DataManager.DataDB().SetAccount(acnt)
+		if err := self.DataManager.DataDB().SetAccount(acnt); err != nil {
+			return 0, err
+		}
+		acnt.StoreJournal()
+		return 0, nil
 	}, config.CgrConfig().GeneralCfg().LockingTimeout, acntID)
 	if err != nil {
 		return err
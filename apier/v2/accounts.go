@@ -95,6 +95,7 @@ type AttrSetAccount struct {
 	ActionTriggerIDs       *[]string
 	ActionTriggerOverwrite bool
 	AllowNegative          *bool
+	CreditLimit            *float64
 	Disabled               *bool
 	ReloadScheduler        bool
 }
@@ -228,6 +229,11 @@ func (self *ApierV2) SetAccount(attr AttrSetAccount, reply *string) error {
 		if attr.Disabled != nil {
 			ub.Disabled = *attr.Disabled
 		}
+		if attr.CreditLimit != nil {
+			if err := ub.SetCreditLimit(*attr.CreditLimit); err != nil {
+				return 0, err
+			}
+		}
 		// All prepared, save account
 		return 0, self.DataManager.DataDB().SetAccount(ub)
 	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
@@ -133,6 +133,28 @@ func (self *ApierV2) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 		}
 	}
 
+	if attrs.ActivationTime != "" {
+		actTime, err := utils.ParseTimeDetectLayout(attrs.ActivationTime,
+			self.Config.GeneralCfg().DefaultTimezone)
+		if err != nil {
+			return utils.NewErrServerError(err)
+		}
+		loadID := utils.GenUUID()
+		if err := loader.StageLoad(loadID, actTime); err != nil {
+			return utils.NewErrServerError(err)
+		}
+		loader.Init()
+		if sched := self.ServManager.GetScheduler(); sched != nil {
+			utils.Logger.Info("ApierV2.LoadTariffPlanFromFolder, staged load, reloading scheduler.")
+			sched.Reload()
+		}
+		*reply = utils.LoadInstance{LoadID: loadID, LoadTime: actTime}
+		return nil
+	}
+	if err := loader.SaveLoadVersion(utils.GenUUID(),
+		self.Config.GeneralCfg().LoadHistorySize); err != nil {
+		return utils.NewErrServerError(err)
+	}
 	if err := loader.WriteToDatabase(attrs.FlushDb, false, false); err != nil {
 		return utils.NewErrServerError(err)
 	}
@@ -335,7 +335,7 @@ func startAsteriskAgent(internalSMGChan chan rpcclient.RpcClientConnection, exit
 }
 
 func startDiameterAgent(internalSsChan chan rpcclient.RpcClientConnection,
-	exitChan chan bool, filterSChan chan *engine.FilterS) {
+	server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
 	var err error
 	utils.Logger.Info("Starting CGRateS DiameterAgent service")
 	filterS := <-filterSChan
@@ -386,6 +386,7 @@ func startDiameterAgent(internalSsChan chan rpcclient.RpcClientConnection,
 			return
 		}
 	}
+	server.RpcRegister(v1.NewDiameterAgentV1(da))
 	if err = da.ListenAndServe(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> error: %s!", err))
 	}
@@ -425,6 +426,74 @@ func startRadiusAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitCh
 	exitChan <- true
 }
 
+func startSIPAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitChan chan bool,
+	filterSChan chan *engine.FilterS) {
+	filterS := <-filterSChan
+	filterSChan <- filterS
+	utils.Logger.Info("Starting CGRateS SIPAgent service")
+	var err error
+	var sS rpcclient.RpcClientConnection
+	if len(cfg.SIPAgentCfg().SessionSConns) != 0 {
+		sS, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			cfg.SIPAgentCfg().SessionSConns, internalSMGChan,
+			cfg.GeneralCfg().InternalTtl)
+		if err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> Could not connect to %s: %s",
+				utils.SIPAgent, utils.SessionS, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
+	sa, err := agents.NewSIPAgent(cfg, filterS, sS)
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s>", utils.SIPAgent, err.Error()))
+		exitChan <- true
+		return
+	}
+	if err = sa.ListenAndServe(); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s>", utils.SIPAgent, err.Error()))
+	}
+	exitChan <- true
+}
+
+func startSMPPAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitChan chan bool,
+	filterSChan chan *engine.FilterS) {
+	filterS := <-filterSChan
+	filterSChan <- filterS
+	utils.Logger.Info("Starting CGRateS SMPPAgent service")
+	var err error
+	var sS rpcclient.RpcClientConnection
+	if len(cfg.SMPPAgentCfg().SessionSConns) != 0 {
+		sS, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			cfg.SMPPAgentCfg().SessionSConns, internalSMGChan,
+			cfg.GeneralCfg().InternalTtl)
+		if err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> Could not connect to %s: %s",
+				utils.SMPPAgent, utils.SessionS, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
+	sa, err := agents.NewSMPPAgent(cfg, filterS, sS)
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s>", utils.SMPPAgent, err.Error()))
+		exitChan <- true
+		return
+	}
+	if err = sa.ListenAndServe(); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s>", utils.SMPPAgent, err.Error()))
+	}
+	exitChan <- true
+}
+
 func startFsAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitChan chan bool) {
 	var err error
 	var sS rpcclient.RpcClientConnection
@@ -476,8 +545,16 @@ func startHTTPAgent(internalSMGChan chan rpcclient.RpcClientConnection,
 	utils.Logger.Info("Starting HTTP agent")
 	var err error
 	for _, agntCfg := range cfg.HttpAgentCfg() {
-		var sSConn *rpcclient.RpcClientPool
-		if len(agntCfg.SessionSConns) != 0 {
+		var sSConn rpcclient.RpcClientConnection
+		var sSInternal bool
+		if len(agntCfg.SessionSConns) != 0 &&
+			agntCfg.DisconnectURL != "" && // disconnects need bidirectional connection
+			agntCfg.SessionSConns[0].Address == utils.MetaInternal {
+			sSInternal = true
+			sSIntConn := <-internalSMGChan
+			internalSMGChan <- sSIntConn
+			sSConn = utils.NewBiRPCInternalClient(sSIntConn.(*sessions.SessionS))
+		} else if len(agntCfg.SessionSConns) != 0 {
 			sSConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
 				cfg.TlsCfg().ClientKey,
 				cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
@@ -492,9 +569,19 @@ func startHTTPAgent(internalSMGChan chan rpcclient.RpcClientConnection,
 				return
 			}
 		}
-		server.RegisterHttpHandler(agntCfg.Url,
-			agents.NewHTTPAgent(sSConn, filterS, dfltTenant, agntCfg.RequestPayload,
-				agntCfg.ReplyPayload, agntCfg.RequestProcessors))
+		ha := agents.NewHTTPAgent(sSConn, filterS, dfltTenant, agntCfg)
+		if sSInternal { // bidirectional client backwards connection
+			sSConn.(*utils.BiRPCInternalClient).SetClientConn(ha)
+			var rply string
+			if err := sSConn.Call(utils.SessionSv1RegisterInternalBiJSONConn,
+				utils.EmptyString, &rply); err != nil {
+				utils.Logger.Crit(fmt.Sprintf("<%s> could not connect to %s, error: %s",
+					utils.HTTPAgent, utils.SessionS, err.Error()))
+				exitChan <- true
+				return
+			}
+		}
+		server.RegisterHttpHandler(agntCfg.Url, ha)
 	}
 }
 
@@ -610,16 +697,33 @@ func startScheduler(internalSchedulerChan chan *scheduler.Scheduler, cacheDoneCh
 
 // startAttributeService fires up the AttributeS
 func startAttributeService(internalAttributeSChan chan rpcclient.RpcClientConnection,
-	cacheS *engine.CacheS, cfg *config.CGRConfig, dm *engine.DataManager,
+	cacheS *engine.CacheS, internalNumberSChan chan rpcclient.RpcClientConnection,
+	cfg *config.CGRConfig, dm *engine.DataManager,
 	server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
 	filterS := <-filterSChan
 	filterSChan <- filterS
 	<-cacheS.GetPrecacheChannel(utils.CacheAttributeProfiles)
-
+	var numberSConn *rpcclient.RpcClientPool
+	var err error
+	if len(cfg.AttributeSCfg().NumberSConns) != 0 { // NumberS connection init
+		numberSConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			cfg.AttributeSCfg().NumberSConns, internalNumberSChan,
+			cfg.GeneralCfg().InternalTtl)
+		if err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> Could not connect to %s: %s",
+				utils.AttributeS, utils.NumberS, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
 	aS, err := engine.NewAttributeService(dm, filterS,
 		cfg.AttributeSCfg().StringIndexedFields,
 		cfg.AttributeSCfg().PrefixIndexedFields,
-		cfg.AttributeSCfg().ProcessRuns)
+		cfg.AttributeSCfg().ProcessRuns, numberSConn)
 	if err != nil {
 		utils.Logger.Crit(
 			fmt.Sprintf("<%s> Could not init, error: %s",
@@ -690,6 +794,28 @@ func startChargerService(internalChargerSChan chan rpcclient.RpcClientConnection
 	internalChargerSChan <- cSv1
 }
 
+// startNumberService fires up the NumberS
+func startNumberService(internalNumberSChan chan rpcclient.RpcClientConnection,
+	cfg *config.CGRConfig, dm *engine.DataManager,
+	server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
+	filterS := <-filterSChan
+	filterSChan <- filterS
+	nS := engine.NewNumberService(dm, filterS, cfg.NumberSCfg())
+	go func() {
+		if err := nS.ListenAndServe(exitChan); err != nil {
+			utils.Logger.Crit(
+				fmt.Sprintf("<%s> Error: %s listening for packets",
+					utils.NumberS, err.Error()))
+		}
+		nS.Shutdown()
+		exitChan <- true
+		return
+	}()
+	nSv1 := v1.NewNumberSv1(nS)
+	server.RpcRegister(nSv1)
+	internalNumberSChan <- nSv1
+}
+
 func startResourceService(internalRsChan chan rpcclient.RpcClientConnection, cacheS *engine.CacheS,
 	internalThresholdSChan chan rpcclient.RpcClientConnection, cfg *config.CGRConfig,
 	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
@@ -887,11 +1013,12 @@ func startFilterService(filterSChan chan *engine.FilterS, cacheS *engine.CacheS,
 
 // loaderService will start and register APIs for LoaderService if enabled
 func loaderService(cacheS *engine.CacheS, cfg *config.CGRConfig,
-	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
+	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS,
+	srvManager *servmanager.ServiceManager) {
 	filterS := <-filterSChan
 	filterSChan <- filterS
 	ldrS := loaders.NewLoaderService(dm, cfg.LoaderCfg(),
-		cfg.GeneralCfg().DefaultTimezone, filterS)
+		cfg.GeneralCfg().DefaultTimezone, filterS, srvManager)
 	if !ldrS.Enabled() {
 		return
 	}
@@ -901,7 +1028,7 @@ func loaderService(cacheS *engine.CacheS, cfg *config.CGRConfig,
 
 // startDispatcherService fires up the DispatcherS
 func startDispatcherService(internalDispatcherSChan chan *dispatchers.DispatcherService,
-	intAttrSChan chan rpcclient.RpcClientConnection,
+	intAttrSChan, intThdSChan chan rpcclient.RpcClientConnection,
 	cfg *config.CGRConfig,
 	cacheS *engine.CacheS, filterSChan chan *engine.FilterS,
 	dm *engine.DataManager, server *utils.Server, exitChan chan bool) {
@@ -925,6 +1052,22 @@ func startDispatcherService(internalDispatcherSChan chan *dispatchers.Dispatcher
 			return
 		}
 	}
+	var thdSConn *rpcclient.RpcClientPool
+	if len(cfg.DispatcherSCfg().ThresholdSConns) != 0 { // ThresholdS connection init
+		thdSConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			cfg.DispatcherSCfg().ThresholdSConns, intThdSChan,
+			cfg.GeneralCfg().InternalTtl)
+		if err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> Could not connect to %s: %s",
+				utils.DispatcherS, utils.ThresholdS, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
 	conns := make(map[string]*rpcclient.RpcClientPool)
 	for connID, haPoolCfg := range cfg.DispatcherSCfg().Conns {
 		var connPool *rpcclient.RpcClientPool
@@ -944,7 +1087,7 @@ func startDispatcherService(internalDispatcherSChan chan *dispatchers.Dispatcher
 		conns[connID] = connPool
 	}
 
-	dspS, err := dispatchers.NewDispatcherService(dm, cfg, fltrS, attrSConn, conns)
+	dspS, err := dispatchers.NewDispatcherService(dm, cfg, fltrS, attrSConn, thdSConn, conns)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<%s> Could not init, error: %s", utils.DispatcherS, err.Error()))
 		exitChan <- true
@@ -959,6 +1102,9 @@ func startDispatcherService(internalDispatcherSChan chan *dispatchers.Dispatcher
 		return
 	}()
 
+	server.RpcRegisterName(utils.DispatcherSv1,
+		v1.NewDispatcherSv1(dspS))
+
 	server.RpcRegisterName(utils.ThresholdSv1,
 		v1.NewDispatcherThresholdSv1(dspS))
 
@@ -980,6 +1126,21 @@ func startDispatcherService(internalDispatcherSChan chan *dispatchers.Dispatcher
 	server.RpcRegisterName(utils.ChargerSv1,
 		v1.NewDispatcherChargerSv1(dspS))
 
+	server.RpcRegisterName(utils.CDRsV1,
+		v1.NewDispatcherCDRsV1(dspS))
+
+	server.RpcRegisterName(utils.CDRsV2,
+		v1.NewDispatcherCDRsV2(dspS))
+
+	server.RpcRegisterName(utils.Responder,
+		v1.NewDispatcherResponder(dspS))
+
+	server.RpcRegisterName(utils.CacheSv1,
+		v1.NewDispatcherCacheSv1(dspS))
+
+	server.RpcRegisterName(utils.ApierV1,
+		v1.NewDispatcherApierV1(dspS))
+
 	internalDispatcherSChan <- dspS
 }
 
@@ -1010,7 +1171,7 @@ func startAnalyzerService(internalAnalyzerSChan chan rpcclient.RpcClientConnecti
 func startRpc(server *utils.Server, internalRaterChan,
 	internalCdrSChan, internalRsChan, internalStatSChan,
 	internalAttrSChan, internalChargerSChan, internalThdSChan, internalSuplSChan,
-	internalSMGChan, internalAnalyzerSChan chan rpcclient.RpcClientConnection,
+	internalSMGChan, internalAnalyzerSChan, internalNumberSChan chan rpcclient.RpcClientConnection,
 	internalDispatcherSChan chan *dispatchers.DispatcherService, exitChan chan bool) {
 	select { // Any of the rpc methods will unlock listening to rpc requests
 	case resp := <-internalRaterChan:
@@ -1035,6 +1196,8 @@ func startRpc(server *utils.Server, internalRaterChan,
 		internalDispatcherSChan <- dispatcherS
 	case analyzerS := <-internalAnalyzerSChan:
 		internalAnalyzerSChan <- analyzerS
+	case numberS := <-internalNumberSChan:
+		internalNumberSChan <- numberS
 	}
 
 	go server.ServeJSON(cfg.ListenCfg().RPCJSONListen)
@@ -1138,6 +1301,22 @@ func schedCDRsConns(internalCDRSChan chan rpcclient.RpcClientConnection, exitCha
 	engine.SetSchedCdrsConns(cdrsConn)
 }
 
+func schedSessionSConns(internalSMGChan chan rpcclient.RpcClientConnection, exitChan chan bool) {
+	sSConn, err := engine.NewRPCPool(rpcclient.POOL_FIRST,
+		cfg.TlsCfg().ClientKey,
+		cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+		cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+		cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+		cfg.SchedulerCfg().SessionSConns, internalSMGChan,
+		cfg.GeneralCfg().InternalTtl)
+	if err != nil {
+		utils.Logger.Crit(fmt.Sprintf("<%s> Could not connect to SessionS: %s", utils.SchedulerS, err.Error()))
+		exitChan <- true
+		return
+	}
+	engine.SetSchedSessionSConns(sSConn)
+}
+
 func memProfFile(memProfPath string) bool {
 	f, err := os.Create(memProfPath)
 	if err != nil {
@@ -1295,6 +1474,13 @@ func main() {
 	// Done initing DBs
 	engine.SetRoundingDecimals(cfg.GeneralCfg().RoundingDecimals)
 	engine.SetRpSubjectPrefixMatching(cfg.RalsCfg().RpSubjectPrefixMatching)
+	engine.SetBalanceHistory(cfg.RalsCfg().BalanceHistory)
+	eventBus, err := engine.NewEventBus(cfg.EventBusCfg().QueueSize,
+		cfg.EventBusCfg().OverflowStrategy, cfg.EventBusCfg().PersistDir)
+	if err != nil {
+		log.Fatalf("<%s> error: %s", utils.EventBus, err.Error())
+	}
+	engine.SetEventBus(eventBus)
 	stopHandled := false
 
 	// Rpc/http server
@@ -1321,6 +1507,7 @@ func main() {
 	internalSMGChan := make(chan rpcclient.RpcClientConnection, 1)
 	internalAttributeSChan := make(chan rpcclient.RpcClientConnection, 1)
 	internalChargerSChan := make(chan rpcclient.RpcClientConnection, 1)
+	internalNumberSChan := make(chan rpcclient.RpcClientConnection, 1)
 	internalRsChan := make(chan rpcclient.RpcClientConnection, 1)
 	internalStatSChan := make(chan rpcclient.RpcClientConnection, 1)
 	internalThresholdSChan := make(chan rpcclient.RpcClientConnection, 1)
@@ -1357,6 +1544,11 @@ func main() {
 		go schedCDRsConns(internalCdrSChan, exitChan)
 	}
 
+	// Create connection to SessionS and share it in engine(used for *reauthorize_session action)
+	if len(cfg.SchedulerCfg().SessionSConns) != 0 {
+		go schedSessionSConns(internalSMGChan, exitChan)
+	}
+
 	// Start CDRC components if necessary
 	go startCdrcs(internalCdrSChan, internalRaterChan, exitChan, filterSChan)
 
@@ -1382,13 +1574,21 @@ func main() {
 	}
 
 	if cfg.DiameterAgentCfg().Enabled {
-		go startDiameterAgent(internalSMGChan, exitChan, filterSChan)
+		go startDiameterAgent(internalSMGChan, server, exitChan, filterSChan)
 	}
 
 	if cfg.RadiusAgentCfg().Enabled {
 		go startRadiusAgent(internalSMGChan, exitChan, filterSChan)
 	}
 
+	if cfg.SIPAgentCfg().Enabled {
+		go startSIPAgent(internalSMGChan, exitChan, filterSChan)
+	}
+
+	if cfg.SMPPAgentCfg().Enabled {
+		go startSMPPAgent(internalSMGChan, exitChan, filterSChan)
+	}
+
 	if len(cfg.HttpAgentCfg()) != 0 {
 		go startHTTPAgent(internalSMGChan, exitChan, server, filterSChan,
 			cfg.GeneralCfg().DefaultTenant)
@@ -1397,9 +1597,13 @@ func main() {
 	// Start FilterS
 	go startFilterService(filterSChan, cacheS, internalStatSChan, cfg, dm, exitChan)
 
+	if cfg.NumberSCfg().Enabled {
+		go startNumberService(internalNumberSChan,
+			cfg, dm, server, exitChan, filterSChan)
+	}
 	if cfg.AttributeSCfg().Enabled {
 		go startAttributeService(internalAttributeSChan, cacheS,
-			cfg, dm, server, exitChan, filterSChan)
+			internalNumberSChan, cfg, dm, server, exitChan, filterSChan)
 	}
 	if cfg.ChargerSCfg().Enabled {
 		go startChargerService(internalChargerSChan, cacheS,
@@ -1429,7 +1633,7 @@ func main() {
 	}
 	if cfg.DispatcherSCfg().Enabled {
 		go startDispatcherService(internalDispatcherSChan,
-			internalAttributeSChan, cfg, cacheS, filterSChan,
+			internalAttributeSChan, internalThresholdSChan, cfg, cacheS, filterSChan,
 			dm, server, exitChan)
 	}
 
@@ -1437,16 +1641,22 @@ func main() {
 		go startAnalyzerService(internalAnalyzerSChan, server, exitChan)
 	}
 
-	go loaderService(cacheS, cfg, dm, server, exitChan, filterSChan)
+	go loaderService(cacheS, cfg, dm, server, exitChan, filterSChan, srvManager)
 
 	// Serve rpc connections
 	go startRpc(server, internalRaterChan, internalCdrSChan,
 		internalRsChan, internalStatSChan,
 		internalAttributeSChan, internalChargerSChan, internalThresholdSChan,
 		internalSupplierSChan, internalSMGChan, internalAnalyzerSChan,
-		internalDispatcherSChan, exitChan)
+		internalNumberSChan, internalDispatcherSChan, exitChan)
 	<-exitChan
 
+	if err := eventBus.Shutdown(); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> error: %s on shutdown", utils.EventBus, err.Error()))
+	}
+	engine.ShutdownBalanceExpiryNotifier()
+	// export the account changes still queued
+	engine.ShutdownAccountExporter()
 	if *cpuProfDir != "" { // wait to end cpuProfiling
 		cpuProfChanStop <- struct{}{}
 		<-cpuProfChanDone
@@ -117,10 +117,22 @@ func startRater(internalRaterChan chan rpcclient.RpcClientConnection, cacheS *en
 		FilterS: filterS}
 	if thdS != nil {
 		engine.SetThresholdS(thdS) // temporary architectural fix until we will have separate AccountS
+		// one engine sharing the data_db is enough to scan it for expiring balances
+		if cfg.RalsCfg().ExpiryScanInterval > 0 {
+			expNtf := engine.NewBalanceExpiryNotifier(dm, cfg.RalsCfg().ExpiryScanInterval,
+				cfg.RalsCfg().ExpiryNotifyBefore)
+			go expNtf.ListenAndServe()
+			engine.SetBalanceExpiryNotifier(expNtf)
+		}
 	}
 	if stats != nil {
 		engine.SetStatS(stats)
 	}
+	if cfg.AccountExporterCfg().Enabled {
+		accExp := engine.NewAccountExporter(cfg, filterS)
+		go accExp.ListenAndServe()
+		engine.SetAccountExporter(accExp)
+	}
 
 	apierRpcV2 := &v2.ApierV2{
 		ApierV1: *apierRpcV1}
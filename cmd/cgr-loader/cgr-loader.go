@@ -55,7 +55,7 @@ var (
 		"The name of redis sentinel")
 
 	storDBType = flag.String("stordb_type", dfltCfg.StorDbCfg().StorDBType,
-		"The type of the storDb database <*mysql|*postgres|*mongo>")
+		"The type of the storDb database <*mysql|*postgres|*sqlite|*mongo>")
 	storDBHost = flag.String("stordb_host", dfltCfg.StorDbCfg().StorDBHost,
 		"The storDb host to connect to.")
 	storDBPort = flag.String("stordb_port", dfltCfg.StorDbCfg().StorDBPort,
@@ -79,6 +79,10 @@ var (
 		"Enable detailed verbose logging output")
 	dryRun = flag.Bool("dry_run", false,
 		"When true will not save loaded data to dataDb but just parse it for consistency and errors.")
+	diff = flag.Bool("diff", false,
+		"When true will not save loaded data to dataDb but print the changes compared with the data in dataDb.")
+	diffJSON = flag.Bool("diff_json", false,
+		"Print the -diff report as JSON")
 	fieldSep = flag.String("field_sep", ",",
 		`Separator for csv file (by default "," is used)`)
 
@@ -94,6 +98,8 @@ var (
 	disableReverse = flag.Bool("disable_reverse_mappings", false, "Will disable reverse mappings rebuilding")
 	flushStorDB    = flag.Bool("flush_stordb", false, "Remove tariff plan data for id from the database")
 	remove         = flag.Bool("remove", false, "Will remove instead of adding data from DB")
+	activationTime = flag.String("activation_time", "",
+		"Stage the tariff plan and let the Scheduler switch it live at this time <*midnight|$time>")
 
 	err    error
 	dm     *engine.DataManager
@@ -247,7 +253,7 @@ func main() {
 		defer storDb.Close()
 	}
 
-	if !*dryRun {
+	if !*dryRun && !*diff {
 		//tpid_remove
 		if *toStorDB { // Import files from a directory into storDb
 			if ldrCfg.LoaderCgrCfg().TpID == "" {
@@ -306,6 +312,19 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if *diff { // Report what the load would change, not saving it
+		ld, err := tpReader.DiffWithDatabase(*remove)
+		if err != nil {
+			log.Fatal("Could not compare with database: ", err)
+		}
+		if *diffJSON {
+			fmt.Println(utils.ToIJSON(ld))
+		} else {
+			fmt.Println(ld.AsText())
+		}
+		return
+	}
+
 	if *dryRun { // We were just asked to parse the data, not saving it
 		return
 	}
@@ -324,7 +343,34 @@ func main() {
 		log.Print("WARNING: automatic cache reloading is disabled!")
 	}
 
+	if *activationTime != "" && !*remove {
+		actTime, err := utils.ParseTimeDetectLayout(*activationTime, ldrCfg.GeneralCfg().DefaultTimezone)
+		if err != nil {
+			log.Fatal("Could not parse the activation time: ", err)
+		}
+		loadID := utils.GenUUID()
+		if err := tpReader.StageLoad(loadID, actTime); err != nil {
+			log.Fatal("Could not stage the load: ", err)
+		}
+		if *verbose {
+			log.Printf("Staged load ID: %s, activating at: %s", loadID, actTime)
+		}
+		if cacheS != nil {
+			var reply string
+			if err = cacheS.Call(utils.ApierV1ReloadScheduler, "", &reply); err != nil {
+				log.Printf("WARNING: Got error on scheduler reload: %s\n", err.Error())
+			}
+		}
+		return
+	}
 	if !*remove {
+		loadID := utils.GenUUID()
+		if err := tpReader.SaveLoadVersion(loadID, ldrCfg.GeneralCfg().LoadHistorySize); err != nil {
+			log.Fatal("Could not save the load version: ", err)
+		}
+		if *verbose && ldrCfg.GeneralCfg().LoadHistorySize != 0 {
+			log.Print("Load ID: ", loadID)
+		}
 		// write maps to database
 		if err := tpReader.WriteToDatabase(*flush, *verbose, *disableReverse); err != nil {
 			log.Fatal("Could not write to database: ", err)
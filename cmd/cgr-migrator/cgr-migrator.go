@@ -39,7 +39,7 @@ var (
 		"Configuration directory path.")
 
 	migrate = flag.String("migrate", "", "fire up automatic migration "+
-		"\n <*set_versions|*cost_details|*accounts|*actions|*action_triggers|*action_plans|*shared_groups|*stordb|*datadb>")
+		"\n <*set_versions|*cost_details|*accounts|*actions|*action_triggers|*action_plans|*shared_groups|*tp_attributes|*stordb|*datadb>")
 	version = flag.Bool("version", false, "prints the application version")
 
 	inDataDBType = flag.String("datadb_type", dfltCfg.DataDbCfg().DataDbType,
@@ -77,7 +77,7 @@ var (
 		"the name of redis sentinel")
 
 	inStorDBType = flag.String("stordb_type", dfltCfg.StorDbCfg().StorDBType,
-		"the type of the StorDB Database <*mysql|*postgres|*mongo>")
+		"the type of the StorDB Database <*mysql|*postgres|*sqlite|*mongo>")
 	inStorDBHost = flag.String("stordb_host", dfltCfg.StorDbCfg().StorDBHost,
 		"the StorDB host")
 	inStorDBPort = flag.String("stordb_port", dfltCfg.StorDbCfg().StorDBPort,
@@ -90,7 +90,7 @@ var (
 		"the StorDB password")
 
 	outStorDBType = flag.String("out_stordb_type", utils.MetaStorDB,
-		"output StorDB type for move mode <*mysql|*postgres|*mongo>")
+		"output StorDB type for move mode <*mysql|*postgres|*sqlite|*mongo>")
 	outStorDBHost = flag.String("out_stordb_host", utils.MetaStorDB,
 		"output StorDB host")
 	outStorDBPort = flag.String("out_stordb_port", utils.MetaStorDB,
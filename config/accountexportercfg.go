@@ -0,0 +1,53 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+// AccountExporterCfg is the configuration of the change data capture feed of the accounts
+type AccountExporterCfg struct {
+	Enabled    bool
+	Filters    []string
+	ExportPath string
+	Transport  string
+	Attempts   int
+}
+
+func (ae *AccountExporterCfg) loadFromJsonCfg(jsnCfg *AccountExporterJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Enabled != nil {
+		ae.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Filters != nil {
+		ae.Filters = make([]string, len(*jsnCfg.Filters))
+		for i, fltr := range *jsnCfg.Filters {
+			ae.Filters[i] = fltr
+		}
+	}
+	if jsnCfg.Export_path != nil {
+		ae.ExportPath = *jsnCfg.Export_path
+	}
+	if jsnCfg.Transport != nil {
+		ae.Transport = *jsnCfg.Transport
+	}
+	if jsnCfg.Attempts != nil {
+		ae.Attempts = *jsnCfg.Attempts
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ type AttributeSCfg struct {
 	StringIndexedFields *[]string
 	PrefixIndexedFields *[]string
 	ProcessRuns         int
+	NumberSConns        []*HaPoolConfig
 }
 
 func (alS *AttributeSCfg) loadFromJsonCfg(jsnCfg *AttributeSJsonCfg) (err error) {
@@ -50,5 +51,12 @@ func (alS *AttributeSCfg) loadFromJsonCfg(jsnCfg *AttributeSJsonCfg) (err error)
 	if jsnCfg.Process_runs != nil {
 		alS.ProcessRuns = *jsnCfg.Process_runs
 	}
+	if jsnCfg.Numbers_conns != nil {
+		alS.NumberSConns = make([]*HaPoolConfig, len(*jsnCfg.Numbers_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Numbers_conns {
+			alS.NumberSConns[idx] = NewDfltHaPoolConfig()
+			alS.NumberSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
 	return
 }
@@ -53,6 +53,11 @@ func NewDbDefaults() DbDefaults {
 			"DbPort": "5432",
 			"DbPass": "CGRateS.org",
 		},
+		utils.SQLITE: map[string]string{
+			"DbName": "/var/lib/cgrates/stordb.sqlite",
+			"DbPort": "",
+			"DbPass": "",
+		},
 		utils.MONGO: map[string]string{
 			"DbName": "cgrates",
 			"DbPort": "27017",
@@ -145,14 +150,19 @@ func NewDefaultCGRConfig() (*CGRConfig, error) {
 	cfg.CdreProfiles = make(map[string]*CdreCfg)
 	cfg.CdrcProfiles = make(map[string][]*CdrcCfg)
 	cfg.analyzerSCfg = new(AnalyzerSCfg)
+	cfg.eventBusCfg = new(EventBusCfg)
+	cfg.accountExporterCfg = new(AccountExporterCfg)
 	cfg.sessionSCfg = new(SessionSCfg)
 	cfg.fsAgentCfg = new(FsAgentCfg)
 	cfg.kamAgentCfg = new(KamAgentCfg)
 	cfg.asteriskAgentCfg = new(AsteriskAgentCfg)
 	cfg.diameterAgentCfg = new(DiameterAgentCfg)
 	cfg.radiusAgentCfg = new(RadiusAgentCfg)
+	cfg.sipAgentCfg = new(SIPAgentCfg)
+	cfg.smppAgentCfg = new(SMPPAgentCfg)
 	cfg.attributeSCfg = new(AttributeSCfg)
 	cfg.chargerSCfg = new(ChargerSCfg)
+	cfg.numberSCfg = new(NumberSCfg)
 	cfg.resourceSCfg = new(ResourceSConfig)
 	cfg.statsCfg = new(StatSCfg)
 	cfg.thresholdSCfg = new(ThresholdSCfg)
@@ -280,36 +290,41 @@ type CGRConfig struct {
 
 	ConfigReloads map[string]chan struct{} // Signals to specific entities that a config reload should occur
 
-	generalCfg       *GeneralCfg       // General config
-	dataDbCfg        *DataDbCfg        // Database config
-	storDbCfg        *StorDbCfg        // StroreDb config
-	tlsCfg           *TlsCfg           // TLS config
-	cacheCfg         CacheCfg          // Cache config
-	listenCfg        *ListenCfg        // Listen config
-	httpCfg          *HTTPCfg          // HTTP config
-	filterSCfg       *FilterSCfg       // FilterS config
-	ralsCfg          *RalsCfg          // Rals config
-	schedulerCfg     *SchedulerCfg     // Scheduler config
-	cdrsCfg          *CdrsCfg          // Cdrs config
-	sessionSCfg      *SessionSCfg      // SessionS config
-	fsAgentCfg       *FsAgentCfg       // FreeSWITCHAgent config
-	kamAgentCfg      *KamAgentCfg      // KamailioAgent config
-	asteriskAgentCfg *AsteriskAgentCfg // AsteriskAgent config
-	diameterAgentCfg *DiameterAgentCfg // DiameterAgent config
-	radiusAgentCfg   *RadiusAgentCfg   // RadiusAgent config
-	attributeSCfg    *AttributeSCfg    // AttributeS config
-	chargerSCfg      *ChargerSCfg      // ChargerS config
-	resourceSCfg     *ResourceSConfig  // ResourceS config
-	statsCfg         *StatSCfg         // StatS config
-	thresholdSCfg    *ThresholdSCfg    // ThresholdS config
-	supplierSCfg     *SupplierSCfg     // SupplierS config
-	sureTaxCfg       *SureTaxCfg       // SureTax config
-	dispatcherSCfg   *DispatcherSCfg   // DispatcherS config
-	loaderCgrCfg     *LoaderCgrCfg     // LoaderCgr config
-	migratorCgrCfg   *MigratorCgrCfg   // MigratorCgr config
-	mailerCfg        *MailerCfg        // Mailer config
-	analyzerSCfg     *AnalyzerSCfg     // AnalyzerS config
-	SmOsipsConfig    *SmOsipsConfig    // SMOpenSIPS Configuration
+	generalCfg         *GeneralCfg         // General config
+	dataDbCfg          *DataDbCfg          // Database config
+	storDbCfg          *StorDbCfg          // StroreDb config
+	tlsCfg             *TlsCfg             // TLS config
+	cacheCfg           CacheCfg            // Cache config
+	listenCfg          *ListenCfg          // Listen config
+	httpCfg            *HTTPCfg            // HTTP config
+	filterSCfg         *FilterSCfg         // FilterS config
+	ralsCfg            *RalsCfg            // Rals config
+	schedulerCfg       *SchedulerCfg       // Scheduler config
+	cdrsCfg            *CdrsCfg            // Cdrs config
+	sessionSCfg        *SessionSCfg        // SessionS config
+	fsAgentCfg         *FsAgentCfg         // FreeSWITCHAgent config
+	kamAgentCfg        *KamAgentCfg        // KamailioAgent config
+	asteriskAgentCfg   *AsteriskAgentCfg   // AsteriskAgent config
+	diameterAgentCfg   *DiameterAgentCfg   // DiameterAgent config
+	radiusAgentCfg     *RadiusAgentCfg     // RadiusAgent config
+	sipAgentCfg        *SIPAgentCfg        // SIPAgent config
+	smppAgentCfg       *SMPPAgentCfg       // SMPPAgent config
+	attributeSCfg      *AttributeSCfg      // AttributeS config
+	chargerSCfg        *ChargerSCfg        // ChargerS config
+	numberSCfg         *NumberSCfg         // NumberS config
+	resourceSCfg       *ResourceSConfig    // ResourceS config
+	statsCfg           *StatSCfg           // StatS config
+	thresholdSCfg      *ThresholdSCfg      // ThresholdS config
+	supplierSCfg       *SupplierSCfg       // SupplierS config
+	sureTaxCfg         *SureTaxCfg         // SureTax config
+	dispatcherSCfg     *DispatcherSCfg     // DispatcherS config
+	loaderCgrCfg       *LoaderCgrCfg       // LoaderCgr config
+	migratorCgrCfg     *MigratorCgrCfg     // MigratorCgr config
+	mailerCfg          *MailerCfg          // Mailer config
+	analyzerSCfg       *AnalyzerSCfg       // AnalyzerS config
+	eventBusCfg        *EventBusCfg        // EventBus config
+	accountExporterCfg *AccountExporterCfg // AccountExporter config
+	SmOsipsConfig      *SmOsipsConfig      // SMOpenSIPS Configuration
 }
 
 func (self *CGRConfig) checkConfigSanity() error {
@@ -329,6 +344,9 @@ func (self *CGRConfig) checkConfigSanity() error {
 				}
 			}
 		}
+		if self.ralsCfg.ExpiryScanInterval > 0 && len(self.ralsCfg.RALsThresholdSConns) == 0 {
+			return errors.New("RALs expiry notifications require thresholds_conns.")
+		}
 	}
 	// CDRServer checks
 	if self.cdrsCfg.CDRSEnabled {
@@ -569,6 +587,34 @@ func (self *CGRConfig) checkConfigSanity() error {
 				}
 			}
 		}
+		if self.diameterAgentCfg.TLS {
+			if self.diameterAgentCfg.ListenNet != utils.TCP {
+				return fmt.Errorf("<%s> TLS not supported over <%s> transport",
+					utils.DiameterAgent, self.diameterAgentCfg.ListenNet)
+			}
+			if self.tlsCfg.ServerCerificate == "" || self.tlsCfg.ServerKey == "" {
+				return fmt.Errorf("<%s> TLS enabled but no server certificate defined",
+					utils.DiameterAgent)
+			}
+		}
+		peerIDs := make(utils.StringMap)
+		for _, peer := range self.diameterAgentCfg.Peers {
+			if peer.Address == "" {
+				return fmt.Errorf("<%s> missing address for peer: <%s>",
+					utils.DiameterAgent, peer.ID)
+			}
+			if !utils.IsSliceMember([]string{utils.TCP, utils.SCTP}, peer.Transport) {
+				return fmt.Errorf("<%s> unsupported transport <%s> for peer: <%s>",
+					utils.DiameterAgent, peer.Transport, peer.ID)
+			}
+			peerIDs[peer.ID] = true
+		}
+		for _, reqProc := range self.diameterAgentCfg.RequestProcessors {
+			if reqProc.Flags.HasKey(utils.MetaProxy) && !peerIDs.HasKey(reqProc.ProxyPeer) {
+				return fmt.Errorf("<%s> unknown proxy_peer <%s> in request processor: <%s>",
+					utils.DiameterAgent, reqProc.ProxyPeer, reqProc.ID)
+			}
+		}
 	}
 	if self.radiusAgentCfg.Enabled && !self.sessionSCfg.Enabled {
 		for _, raSMGConn := range self.radiusAgentCfg.SessionSConns {
@@ -581,23 +627,86 @@ func (self *CGRConfig) checkConfigSanity() error {
 	for _, httpAgentCfg := range self.httpAgentCfg {
 		// httpAgent checks
 		for _, sSConn := range httpAgentCfg.SessionSConns {
-			if sSConn.Address == utils.MetaInternal && self.sessionSCfg.Enabled {
+			if sSConn.Address == utils.MetaInternal && !self.sessionSCfg.Enabled {
 				return errors.New("SessionS not enabled but referenced by HttpAgent component")
 			}
 		}
-		if !utils.IsSliceMember([]string{utils.MetaUrl, utils.MetaXml}, httpAgentCfg.RequestPayload) {
+		if !utils.IsSliceMember([]string{utils.MetaUrl, utils.MetaXml,
+			utils.MetaJSON, utils.MetaForm}, httpAgentCfg.RequestPayload) {
 			return fmt.Errorf("<%s> unsupported request payload %s",
 				utils.HTTPAgent, httpAgentCfg.RequestPayload)
 		}
-		if !utils.IsSliceMember([]string{utils.MetaXml}, httpAgentCfg.ReplyPayload) {
+		if !utils.IsSliceMember([]string{utils.MetaXml, utils.MetaJSON}, httpAgentCfg.ReplyPayload) {
 			return fmt.Errorf("<%s> unsupported reply payload %s",
 				utils.HTTPAgent, httpAgentCfg.ReplyPayload)
 		}
+		if len(httpAgentCfg.SessionKey) == 0 &&
+			(httpAgentCfg.SessionIdleTimeout != 0 || httpAgentCfg.DisconnectURL != "") {
+			return fmt.Errorf("<%s> session_key needs to be defined for stateful sessions of agent <%s>",
+				utils.HTTPAgent, httpAgentCfg.ID)
+		}
+		if httpAgentCfg.DisconnectURL != "" &&
+			(len(httpAgentCfg.SessionSConns) == 0 ||
+				httpAgentCfg.SessionSConns[0].Address != utils.MetaInternal) {
+			return fmt.Errorf("<%s> disconnect_url of agent <%s> needs %s sessions_conns",
+				utils.HTTPAgent, httpAgentCfg.ID, utils.MetaInternal)
+		}
+	}
+	// SIPAgent checks
+	if self.sipAgentCfg.Enabled {
+		if !self.sessionSCfg.Enabled {
+			for _, sSConn := range self.sipAgentCfg.SessionSConns {
+				if sSConn.Address == utils.MetaInternal {
+					return fmt.Errorf("%s not enabled but referenced by %s component",
+						utils.SessionS, utils.SIPAgent)
+				}
+			}
+		}
+		if !utils.IsSliceMember([]string{utils.UDP, utils.TCP}, self.sipAgentCfg.ListenNet) {
+			return fmt.Errorf("<%s> unsupported listen_net <%s>",
+				utils.SIPAgent, self.sipAgentCfg.ListenNet)
+		}
+	}
+	// SMPPAgent checks
+	if self.smppAgentCfg.Enabled {
+		if !self.sessionSCfg.Enabled {
+			for _, sSConn := range self.smppAgentCfg.SessionSConns {
+				if sSConn.Address == utils.MetaInternal {
+					return fmt.Errorf("%s not enabled but referenced by %s component",
+						utils.SessionS, utils.SMPPAgent)
+				}
+			}
+		}
+		if self.smppAgentCfg.Listen == "" && self.smppAgentCfg.SMSCAddress == "" {
+			return fmt.Errorf("<%s> one of listen or smsc_address needs to be defined",
+				utils.SMPPAgent)
+		}
+		if self.smppAgentCfg.SMSCAddress != "" && self.smppAgentCfg.EnquireLinkInterval <= 0 {
+			return fmt.Errorf("<%s> enquire_link_interval needs to be bigger than 0",
+				utils.SMPPAgent)
+		}
 	}
 	if self.attributeSCfg.Enabled {
 		if self.attributeSCfg.ProcessRuns < 1 {
 			return fmt.Errorf("<%s> process_runs needs to be bigger than 0", utils.AttributeS)
 		}
+		if !self.numberSCfg.Enabled {
+			for _, connCfg := range self.attributeSCfg.NumberSConns {
+				if connCfg.Address == utils.MetaInternal {
+					return fmt.Errorf("%s not enabled but requested by %s component.",
+						utils.NumberS, utils.AttributeS)
+				}
+			}
+		}
+	}
+	// NumberS checks
+	if self.numberSCfg.Enabled {
+		for _, dp := range self.numberSCfg.DialPlans {
+			if _, err := strconv.ParseUint(dp.CountryCode, 10, 16); err != nil {
+				return fmt.Errorf("<%s> dial plan <%s> needs a numeric country_code, have: <%s>",
+					utils.NumberS, dp.ID, dp.CountryCode)
+			}
+		}
 	}
 	if self.chargerSCfg.Enabled {
 		for _, connCfg := range self.chargerSCfg.AttributeSConns {
@@ -659,6 +768,11 @@ func (self *CGRConfig) checkConfigSanity() error {
 		if self.attributeSCfg.Enabled {
 			return fmt.Errorf("<%s> cannot start in tandem with <%s>", utils.DispatcherS, utils.AttributeS)
 		}
+		for _, connCfg := range self.dispatcherSCfg.ThresholdSConns {
+			if connCfg.Address == utils.MetaInternal && !self.thresholdSCfg.Enabled {
+				return fmt.Errorf("<%s> %s not enabled but requested", utils.DispatcherS, utils.ThresholdS)
+			}
+		}
 		if len(self.dispatcherSCfg.Conns) == 0 {
 			return fmt.Errorf("<%s> no connections defined", utils.DispatcherS)
 		}
@@ -680,6 +794,33 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	if !self.sessionSCfg.Enabled {
+		for _, connCfg := range self.schedulerCfg.SessionSConns {
+			if connCfg.Address == utils.MetaInternal {
+				return errors.New("SessionS not enabled but requested by Scheduler")
+			}
+		}
+	}
+	// EventBus checks
+	if !utils.IsSliceMember([]string{utils.MetaBlock, utils.MetaDrop}, self.eventBusCfg.OverflowStrategy) {
+		return fmt.Errorf("<%s> unsupported overflow_strategy: <%s>", utils.EventBus, self.eventBusCfg.OverflowStrategy)
+	}
+	if self.eventBusCfg.QueueSize <= 0 {
+		return fmt.Errorf("<%s> queue_size needs to be positive", utils.EventBus)
+	}
+	// AccountExporter checks
+	if self.accountExporterCfg.Enabled {
+		if !self.ralsCfg.RALsEnabled {
+			return fmt.Errorf("<%s> RALs not enabled but requested by %s component.", utils.RALService, utils.AccountExporter)
+		}
+		if self.accountExporterCfg.ExportPath == "" {
+			return fmt.Errorf("<%s> empty export_path", utils.AccountExporter)
+		}
+		if !utils.IsSliceMember([]string{utils.META_HTTP_POST, utils.MetaHTTPjsonMap, utils.MetaAMQPjsonMap,
+			utils.MetaSQSjsonMap, utils.MetaAWSjsonMap}, self.accountExporterCfg.Transport) {
+			return fmt.Errorf("<%s> unsupported transport: <%s>", utils.AccountExporter, self.accountExporterCfg.Transport)
+		}
+	}
 	return nil
 }
 
@@ -832,6 +973,22 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		return err
 	}
 
+	jsnSIPAgntCfg, err := jsnCfg.SIPAgentJsonCfg()
+	if err != nil {
+		return err
+	}
+	if err := self.sipAgentCfg.loadFromJsonCfg(jsnSIPAgntCfg, self.generalCfg.RsrSepatarot); err != nil {
+		return err
+	}
+
+	jsnSMPPAgntCfg, err := jsnCfg.SMPPAgentJsonCfg()
+	if err != nil {
+		return err
+	}
+	if err := self.smppAgentCfg.loadFromJsonCfg(jsnSMPPAgntCfg, self.generalCfg.RsrSepatarot); err != nil {
+		return err
+	}
+
 	jsnAttributeSCfg, err := jsnCfg.AttributeServJsonCfg()
 	if err != nil {
 		return err
@@ -840,6 +997,14 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		return err
 	}
 
+	jsnNumberSCfg, err := jsnCfg.NumberServJsonCfg()
+	if err != nil {
+		return err
+	}
+	if err := self.numberSCfg.loadFromJsonCfg(jsnNumberSCfg); err != nil {
+		return err
+	}
+
 	jsnChargerSCfg, err := jsnCfg.ChargerServJsonCfg()
 	if err != nil {
 		return err
@@ -941,6 +1106,22 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		return err
 	}
 
+	jsnEventBusCfg, err := jsnCfg.EventBusCfgJson()
+	if err != nil {
+		return err
+	}
+	if err := self.eventBusCfg.loadFromJsonCfg(jsnEventBusCfg); err != nil {
+		return err
+	}
+
+	jsnAccountExporterCfg, err := jsnCfg.AccountExporterJsonCfg()
+	if err != nil {
+		return err
+	}
+	if err := self.accountExporterCfg.loadFromJsonCfg(jsnAccountExporterCfg); err != nil {
+		return err
+	}
+
 	if jsnCdreCfg != nil {
 		for profileName, jsnCdre1Cfg := range jsnCdreCfg {
 			if _, hasProfile := self.CdreProfiles[profileName]; !hasProfile { // New profile, create before loading from json
@@ -1026,6 +1207,14 @@ func (self *CGRConfig) RadiusAgentCfg() *RadiusAgentCfg {
 	return self.radiusAgentCfg
 }
 
+func (self *CGRConfig) SIPAgentCfg() *SIPAgentCfg {
+	return self.sipAgentCfg
+}
+
+func (self *CGRConfig) SMPPAgentCfg() *SMPPAgentCfg {
+	return self.smppAgentCfg
+}
+
 func (cfg *CGRConfig) AttributeSCfg() *AttributeSCfg {
 	return cfg.attributeSCfg
 }
@@ -1034,6 +1223,10 @@ func (cfg *CGRConfig) ChargerSCfg() *ChargerSCfg {
 	return cfg.chargerSCfg
 }
 
+func (cfg *CGRConfig) NumberSCfg() *NumberSCfg {
+	return cfg.numberSCfg
+}
+
 // ToDo: fix locking here
 func (self *CGRConfig) ResourceSCfg() *ResourceSConfig {
 	return self.resourceSCfg
@@ -1140,3 +1333,11 @@ func (cfg *CGRConfig) MailerCfg() *MailerCfg {
 func (cfg *CGRConfig) AnalyzerSCfg() *AnalyzerSCfg {
 	return cfg.analyzerSCfg
 }
+
+func (cfg *CGRConfig) EventBusCfg() *EventBusCfg {
+	return cfg.eventBusCfg
+}
+
+func (cfg *CGRConfig) AccountExporterCfg() *AccountExporterCfg {
+	return cfg.accountExporterCfg
+}
@@ -48,6 +48,7 @@ const CGRATES_CFG_JSON = `
 	"response_cache_ttl": "0s",								// the life span of a cached response
 	"internal_ttl": "2m",									// maximum duration to wait for internal connections before giving up
 	"locking_timeout": "0",									// timeout internal locks to avoid deadlocks
+	"load_history_size": 10,								// number of tariff plan loads which can be rolled back, 0 to disable
 	"digest_separator": ",",
 	"digest_equal": ":",
 	"rsr_separator": ";",
@@ -66,10 +67,10 @@ const CGRATES_CFG_JSON = `
 
 
 "stor_db": {								// database used to store offline tariff plans and CDRs
-	"db_type": "mysql",						// stor database type to use: <*mongo|*mysql|*postgres|*internal>
+	"db_type": "mysql",						// stor database type to use: <*mongo|*mysql|*postgres|*sqlite|*internal>
 	"db_host": "127.0.0.1",					// the host to connect to
 	"db_port": 3306,						// the port to reach the stordb
-	"db_name": "cgrates",					// stor database name
+	"db_name": "cgrates",					// stor database name, path to the database file for *sqlite
 	"db_user": "cgrates",					// username to use when connecting to stordb
 	"db_password": "",						// password to use when connecting to stordb
 	"max_open_conns": 100,					// maximum database connections opened, not applying for mongo
@@ -113,6 +114,7 @@ const CGRATES_CFG_JSON = `
 "scheduler": {
 	"enabled": false,				// start Scheduler service: <true|false>
 	"cdrs_conns": [],				// address where to reach CDR Server, empty to disable CDR capturing <*internal|x.y.z.y:1234>
+	"sessions_conns": [],			// address where to reach SessionS, used by *reauthorize_session <*internal|x.y.z.y:1234>
 },
 
 
@@ -147,6 +149,7 @@ const CGRATES_CFG_JSON = `
 	"charger_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 					// control charger filter indexes caching
 	"dispatcher_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control dispatcher filter indexes caching
 	"dispatcher_routes": {"limit": -1, "ttl": "", "static_ttl": false}, 						// control dispatcher routes caching
+	"ported_numbers": {"limit": -1, "ttl": "", "static_ttl": false},							// control ported numbers caching
 	"diameter_messages": {"limit": -1, "ttl": "3h", "static_ttl": false},						// diameter messages caching
 },
 
@@ -163,6 +166,10 @@ const CGRATES_CFG_JSON = `
 	"stats_conns": [],						// address where to reach the stat service, empty to disable stats functionality: <""|*internal|x.y.z.y:1234>
 	"rp_subject_prefix_matching": false,	// enables prefix matching for the rating profile subject
 	"remove_expired":true,					// enables remove of expired balances
+	"balance_history": false,				// record every balance change into stor_db
+	"expiry_scan_interval": "0s",			// scan the accounts for expiring balances and notify ThresholdS, enable it on one engine per data_db, <""|$dur> 0 to disable
+	"expiry_notify_before": "24h",			// notify the balances expiring within this interval
+	"expiry_grace_period": "0s",			// expired balances can still be restored by a *topup action within this interval
 	"max_computed_usage": {					// do not compute usage higher than this, prevents memory overload
 		"*any": "189h",
 		"*voice": "72h",
@@ -363,6 +370,8 @@ const CGRATES_CFG_JSON = `
 	"enabled": false,											// enables the diameter agent: <true|false>
 	"listen": "127.0.0.1:3868",									// address where to listen for diameter requests <x.y.z.y/x1.y1.z1.y1:1234>
 	"listen_net": "tcp",										// transport type for diameter <tcp|sctp>
+	"tls": false,												// enable Diameter over TLS using the certificates from tls section <true|false>
+	"allowed_peers": [],										// peers allowed to connect, checked on CER, empty to allow all: [{"origin_host": "", "origin_realm": ""}]
 	"dictionaries_path": "/usr/share/cgrates/diameter/dict/",	// path towards directory holding additional dictionaries to load
 	"sessions_conns": [
 		{"address": "*internal"}								// connection towards SessionService
@@ -373,6 +382,7 @@ const CGRATES_CFG_JSON = `
 	"product_name": "CGRateS",									// diameter Product-Name AVP used in replies
 	"max_active_requests": -1,									// limit the number of active requests processed by the server <-1|0-n>
 	"asr_template": "",											// enable AbortSession message being sent to client on DisconnectSession
+	"rar_template": "",											// enable ReAuthRequest message being sent to client on ReAuthorize
 	"templates":{
 		"*err": [
 				{"tag": "SessionId", "field_id": "Session-Id", "type": "*composed", 
@@ -415,8 +425,25 @@ const CGRATES_CFG_JSON = `
 					"value": "~*req.User-Name", "mandatory": true},
 				{"tag": "OriginStateID", "field_id": "Origin-State-Id", "type": "*constant", 
 					"value": "1"},
+		],
+		"*rar": [
+				{"tag": "SessionId", "field_id": "Session-Id", "type": "*variable", 
+					"value": "~*req.Session-Id", "mandatory": true},
+				{"tag": "OriginHost", "field_id": "Origin-Host", "type": "*variable", 
+					"value": "~*req.Destination-Host", "mandatory": true},
+				{"tag": "OriginRealm", "field_id": "Origin-Realm", "type": "*variable", 
+					"value": "~*req.Destination-Realm", "mandatory": true},
+				{"tag": "DestinationRealm", "field_id": "Destination-Realm", "type": "*variable", 
+					"value": "~*req.Origin-Realm", "mandatory": true},
+				{"tag": "DestinationHost", "field_id": "Destination-Host", "type": "*variable", 
+					"value": "~*req.Origin-Host", "mandatory": true},
+				{"tag": "AuthApplicationId", "field_id": "Auth-Application-Id", "type": "*variable",
+					 "value": "~*vars.*appid", "mandatory": true},
+				{"tag": "ReAuthRequestType", "field_id": "Re-Auth-Request-Type", "type": "*constant", 
+					"value": "0"},
 		]
 	},
+	"peers": [],												// upstream peers used by *proxy request processors: [{"id": "", "address": "", "transport": "tcp", "reply_timeout": "2s", "watchdog_interval": "30s"}]
 	"request_processors": [],
 },
 
@@ -443,11 +470,54 @@ const CGRATES_CFG_JSON = `
 ],
 
 
+"sip_agent": {
+	"enabled": false,											// enables the SIP redirect server agent: <true|false>
+	"listen": "127.0.0.1:5060",									// address where to listen for SIP requests <x.y.z.y:1234>
+	"listen_net": "udp",										// network to listen on <udp|tcp>
+	"sessions_conns": [
+		{"address": "*internal"}								// connection towards SessionService
+	],
+	"templates":{
+		"*302": [
+				{"tag": "ReplyCode", "field_id": "*sipReplyCode", "type": "*constant", 
+					"value": "302"},
+				{"tag": "Contact", "field_id": "Contact", "type": "*composed", 
+					"value": "~*vars.*sipContacts", "mandatory": true},
+		],
+	},
+	"request_processors": [],
+},
+
+
+"smpp_agent": {
+	"enabled": false,											// enables the SMPP agent: <true|false>
+	"listen": "127.0.0.1:2775",									// address where to accept ESME binds as SMSC, empty to disable <""|x.y.z.y:1234>
+	"smsc_address": "",											// SMSC address to bind to as ESME receiver, empty to disable <""|x.y.z.y:1234>
+	"system_id": "CGRateS",										// system_id expected on binds or used when binding to the SMSC
+	"password": "",												// password expected on binds or used when binding to the SMSC, empty to not check
+	"system_type": "",											// system_type used when binding to the SMSC
+	"enquire_link_interval": "30s",								// interval between enquire_link PDUs sent towards the SMSC
+	"sessions_conns": [
+		{"address": "*internal"}								// connection towards SessionService
+	],
+	"templates":{
+		"*smpp_status": [
+				{"tag": "InsufficientCredit", "filters": ["*rsr::~*cgrep.Error(INSUFFICIENT_CREDIT)"], 
+					"field_id": "*smppCommandStatus", "type": "*constant", "value": "0x45", "blocker": true},
+				{"tag": "SystemError", "filters": ["*rsr::~*cgrep.Error(!^$)"], 
+					"field_id": "*smppCommandStatus", "type": "*constant", "value": "0x08", "blocker": true},
+		],
+	},
+	"request_processors": [],
+},
+
+
 "attributes": {								// Attribute service
 	"enabled": false,						// starts attribute service: <true|false>.
 	//"string_indexed_fields": [],			// query indexes based on these fields for faster processing
 	"prefix_indexed_fields": [],			// query indexes based on these fields for faster processing
 	"process_runs": 1,						// number of run loops when processing event
+	"numbers_conns": [],					// address where to reach the NumberS to normalize the numbers before processing <""|*internal|127.0.0.1:2013>
 },
 
 
@@ -459,6 +529,22 @@ const CGRATES_CFG_JSON = `
 },
 
 
+"numbers": {								// Number service
+	"enabled": false,						// starts number service: <true|false>.
+	"normalize_fields": ["Account", "Subject", "Destination"],	// fields normalized to E.164 using the dial plans
+	"portability_fields": ["Destination"],	// fields prefixed with the routing number of the ported numbers
+	"dial_plans": [							// first dial plan matching the event filters normalizes its numbers
+		// {
+		// 	"id": "DE",							// dial plan identifier
+		// 	"filters": [],						// filters matching the events the dial plan applies to
+		// 	"country_code": "49",				// country code replacing the national_prefix
+		// 	"national_prefix": "0",				// prefix of the numbers dialed nationally
+		// 	"international_prefix": "00",		// prefix of the numbers dialed internationally, + always considered
+		// },
+	],
+},
+
+
 "resources": {								// Resource service (*new)
 	"enabled": false,						// starts ResourceLimiter service: <true|false>.
 	"store_interval": "",					// dump cache regularly to dataDB, 0 - dump at start/shutdown: <""|$dur>
@@ -504,6 +590,7 @@ const CGRATES_CFG_JSON = `
 		"enabled": false,									// starts as service: <true|false>.
 		"tenant": "cgrates.org",							// tenant used in filterS.Pass
 		"dry_run": false,									// do not send the CDRs to CDRS, just parse them
+		"activation_time": "",								// stage the loaded data and switch it live at this time, empty to load it live <""|*midnight|$time>
 		"run_delay": 0,										// sleep interval in seconds between consecutive runs, 0 to use automation via inotify
 		"lock_filename": ".cgr.lck",						// Filename containing concurrency lock in case of delayed processing
 		"caches_conns": [
@@ -526,7 +613,10 @@ const CGRATES_CFG_JSON = `
 					{"tag": "Initial", "field_id": "Initial", "type": "*composed", "value": "~6"},
 					{"tag": "Substitute", "field_id": "Substitute", "type": "*composed", "value": "~7"},
 					{"tag": "Append", "field_id": "Append", "type": "*composed", "value": "~8"},
-					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~9"},
+					{"tag": "Blocker", "field_id": "Blocker", "type": "*composed", "value": "~9"},
+					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~10"},
+					{"tag": "AttributeFilterIDs", "field_id": "AttributeFilterIDs", "type": "*composed", "value": "~11"},
+					{"tag": "Type", "field_id": "Type", "type": "*composed", "value": "~12"},
 				],
 			},
 			{
@@ -648,6 +738,17 @@ const CGRATES_CFG_JSON = `
 					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~12"},
 				],
 			},
+			{
+				"type": "*ported_numbers",					// data source type
+				"file_name": "PortedNumbers.csv",			// file name in the tp_in_dir
+				"fields": [
+					{"tag": "Tenant", "field_id": "Tenant", "type": "*composed", "value": "~0", "mandatory": true},
+					{"tag": "ID", "field_id": "ID", "type": "*composed", "value": "~1", "mandatory": true},
+					{"tag": "First", "field_id": "First", "type": "*composed", "value": "~2"},
+					{"tag": "Last", "field_id": "Last", "type": "*composed", "value": "~3"},
+					{"tag": "RoutingNumber", "field_id": "RoutingNumber", "type": "*composed", "value": "~4", "mandatory": true},
+				],
+			},
 		],
 	},
 ],
@@ -727,6 +828,7 @@ const CGRATES_CFG_JSON = `
 	//"string_indexed_fields": [],			// query indexes based on these fields for faster processing
 	"prefix_indexed_fields": [],			// query indexes based on these fields for faster processing
 	"attributes_conns": [],					// address where to reach the attribute service, empty to disable auth functionality: <""|*internal|x.y.z.y:1234>
+	"thresholds_conns": [],					// address where to reach the thresholds service, empty to disable API key limit events: <""|*internal|x.y.z.y:1234>
 	"conns": {
 		"sessions_eu": [
 			{"address": "127.0.0.1:2012", "transport": "*json"},
@@ -749,4 +851,20 @@ const CGRATES_CFG_JSON = `
 },
 
 
+"event_bus": {
+	"queue_size": 1000,						// maximum number of events queued towards one asynchronous connection
+	"overflow_strategy": "*block",			// behaviour when the queue is full: <*block|*drop>
+	"persist_dir": "",						// save the queued events on shutdown and load them on start from this directory, empty to disable
+},
+
+
+"account_exporter": {
+	"enabled": false,						// export the changes of the accounts and of their balances: <true|false>
+	"filters": [],							// only the changes matching these filters are exported
+	"export_path": "",						// address where the changes are posted
+	"transport": "*http_json_map",			// transport used to post the changes: <*http_post|*http_json_map|*amqp_json_map|*sqs_json_map|*aws_json_map>
+	"attempts": 3,							// number of post attempts before writing the change into failed_posts_dir
+},
+
+
 }`
@@ -25,46 +25,51 @@ import (
 )
 
 const (
-	GENERAL_JSN        = "general"
-	CACHE_JSN          = "cache"
-	LISTEN_JSN         = "listen"
-	HTTP_JSN           = "http"
-	DATADB_JSN         = "data_db"
-	STORDB_JSN         = "stor_db"
-	FilterSjsn         = "filters"
-	RALS_JSN           = "rals"
-	SCHEDULER_JSN      = "scheduler"
-	CDRS_JSN           = "cdrs"
-	MEDIATOR_JSN       = "mediator"
-	CDRE_JSN           = "cdre"
-	CDRC_JSN           = "cdrc"
-	SessionSJson       = "sessions"
-	FreeSWITCHAgentJSN = "freeswitch_agent"
-	KamailioAgentJSN   = "kamailio_agent"
-	AsteriskAgentJSN   = "asterisk_agent"
-	SM_JSN             = "session_manager"
-	FS_JSN             = "freeswitch"
-	OSIPS_JSN          = "opensips"
-	DA_JSN             = "diameter_agent"
-	RA_JSN             = "radius_agent"
-	HttpAgentJson      = "http_agent"
-	HISTSERV_JSN       = "historys"
-	ATTRIBUTE_JSN      = "attributes"
-	RESOURCES_JSON     = "resources"
-	STATS_JSON         = "stats"
-	THRESHOLDS_JSON    = "thresholds"
-	SupplierSJson      = "suppliers"
-	FILTERS_JSON       = "filters"
-	LoaderJson         = "loaders"
-	MAILER_JSN         = "mailer"
-	SURETAX_JSON       = "suretax"
-	DispatcherJson     = "dispatcher"
-	DispatcherSJson    = "dispatchers"
-	CgrLoaderCfgJson   = "loader"
-	CgrMigratorCfgJson = "migrator"
-	ChargerSCfgJson    = "chargers"
-	TlsCfgJson         = "tls"
-	AnalyzerCfgJson    = "analyzers"
+	GENERAL_JSN         = "general"
+	CACHE_JSN           = "cache"
+	LISTEN_JSN          = "listen"
+	HTTP_JSN            = "http"
+	DATADB_JSN          = "data_db"
+	STORDB_JSN          = "stor_db"
+	FilterSjsn          = "filters"
+	RALS_JSN            = "rals"
+	SCHEDULER_JSN       = "scheduler"
+	CDRS_JSN            = "cdrs"
+	MEDIATOR_JSN        = "mediator"
+	CDRE_JSN            = "cdre"
+	CDRC_JSN            = "cdrc"
+	SessionSJson        = "sessions"
+	FreeSWITCHAgentJSN  = "freeswitch_agent"
+	KamailioAgentJSN    = "kamailio_agent"
+	AsteriskAgentJSN    = "asterisk_agent"
+	SM_JSN              = "session_manager"
+	FS_JSN              = "freeswitch"
+	OSIPS_JSN           = "opensips"
+	DA_JSN              = "diameter_agent"
+	RA_JSN              = "radius_agent"
+	HttpAgentJson       = "http_agent"
+	SIPAgentJson        = "sip_agent"
+	SMPPAgentJson       = "smpp_agent"
+	HISTSERV_JSN        = "historys"
+	ATTRIBUTE_JSN       = "attributes"
+	RESOURCES_JSON      = "resources"
+	STATS_JSON          = "stats"
+	THRESHOLDS_JSON     = "thresholds"
+	SupplierSJson       = "suppliers"
+	FILTERS_JSON        = "filters"
+	LoaderJson          = "loaders"
+	MAILER_JSN          = "mailer"
+	SURETAX_JSON        = "suretax"
+	DispatcherJson      = "dispatcher"
+	DispatcherSJson     = "dispatchers"
+	CgrLoaderCfgJson    = "loader"
+	CgrMigratorCfgJson  = "migrator"
+	ChargerSCfgJson     = "chargers"
+	NumberSCfgJson      = "numbers"
+	TlsCfgJson          = "tls"
+	AnalyzerCfgJson     = "analyzers"
+	EventBusCfgJson     = "event_bus"
+	AccountExporterJson = "account_exporter"
 )
 
 // Loads the json config out of io.Reader, eg other sources than file, maybe over http
@@ -306,6 +311,30 @@ func (self CgrJsonCfg) HttpAgentJsonCfg() (*[]*HttpAgentJsonCfg, error) {
 	return &httpAgnt, nil
 }
 
+func (self CgrJsonCfg) SIPAgentJsonCfg() (*SIPAgentJsonCfg, error) {
+	rawCfg, hasKey := self[SIPAgentJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(SIPAgentJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (self CgrJsonCfg) SMPPAgentJsonCfg() (*SMPPAgentJsonCfg, error) {
+	rawCfg, hasKey := self[SMPPAgentJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(SMPPAgentJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func (cgrJsn CgrJsonCfg) AttributeServJsonCfg() (*AttributeSJsonCfg, error) {
 	rawCfg, hasKey := cgrJsn[ATTRIBUTE_JSN]
 	if !hasKey {
@@ -318,6 +347,18 @@ func (cgrJsn CgrJsonCfg) AttributeServJsonCfg() (*AttributeSJsonCfg, error) {
 	return cfg, nil
 }
 
+func (cgrJsn CgrJsonCfg) NumberServJsonCfg() (*NumberSJsonCfg, error) {
+	rawCfg, hasKey := cgrJsn[NumberSCfgJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(NumberSJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func (cgrJsn CgrJsonCfg) ChargerServJsonCfg() (*ChargerSJsonCfg, error) {
 	rawCfg, hasKey := cgrJsn[ChargerSCfgJson]
 	if !hasKey {
@@ -473,3 +514,27 @@ func (self CgrJsonCfg) AnalyzerCfgJson() (*AnalyzerSJsonCfg, error) {
 	}
 	return cfg, nil
 }
+
+func (self CgrJsonCfg) EventBusCfgJson() (*EventBusJsonCfg, error) {
+	rawCfg, hasKey := self[EventBusCfgJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(EventBusJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (self CgrJsonCfg) AccountExporterJsonCfg() (*AccountExporterJsonCfg, error) {
+	rawCfg, hasKey := self[AccountExporterJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(AccountExporterJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -58,6 +58,7 @@ func TestDfGeneralJsonCfg(t *testing.T) {
 		Response_cache_ttl:   utils.StringPointer("0s"),
 		Internal_ttl:         utils.StringPointer("2m"),
 		Locking_timeout:      utils.StringPointer("0"),
+		Load_history_size:    utils.IntPointer(10),
 		Digest_separator:     utils.StringPointer(","),
 		Digest_equal:         utils.StringPointer(":"),
 		Rsr_separator:        utils.StringPointer(";"),
@@ -152,6 +153,8 @@ func TestCacheJsonCfg(t *testing.T) {
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheDispatcherRoutes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
+		utils.CachePortedNumbers: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheDiameterMessages: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer("3h"), Static_ttl: utils.BoolPointer(false)},
 	}
@@ -223,6 +226,10 @@ func TestDfRalsJsonCfg(t *testing.T) {
 		Stats_conns:                &[]*HaPoolJsonCfg{},
 		Rp_subject_prefix_matching: utils.BoolPointer(false),
 		Remove_expired:             utils.BoolPointer(true),
+		Balance_history:            utils.BoolPointer(false),
+		Expiry_scan_interval:       utils.StringPointer("0s"),
+		Expiry_notify_before:       utils.StringPointer("24h"),
+		Expiry_grace_period:        utils.StringPointer("0s"),
 		Max_computed_usage: &map[string]string{
 			utils.ANY:   "189h",
 			utils.VOICE: "72h",
@@ -238,8 +245,9 @@ func TestDfRalsJsonCfg(t *testing.T) {
 
 func TestDfSchedulerJsonCfg(t *testing.T) {
 	eCfg := &SchedulerJsonCfg{
-		Enabled:    utils.BoolPointer(false),
-		Cdrs_conns: &[]*HaPoolJsonCfg{},
+		Enabled:        utils.BoolPointer(false),
+		Cdrs_conns:     &[]*HaPoolJsonCfg{},
+		Sessions_conns: &[]*HaPoolJsonCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.SchedulerJsonCfg(); err != nil {
 		t.Error(err)
@@ -572,6 +580,8 @@ func TestDiameterAgentJsonCfg(t *testing.T) {
 		Enabled:           utils.BoolPointer(false),
 		Listen:            utils.StringPointer("127.0.0.1:3868"),
 		Listen_net:        utils.StringPointer("tcp"),
+		Tls:               utils.BoolPointer(false),
+		Allowed_peers:     &[]*DiameterAllowedPeerJsonCfg{},
 		Dictionaries_path: utils.StringPointer("/usr/share/cgrates/diameter/dict/"),
 		Sessions_conns: &[]*HaPoolJsonCfg{
 			{
@@ -583,6 +593,7 @@ func TestDiameterAgentJsonCfg(t *testing.T) {
 		Product_name:        utils.StringPointer("CGRateS"),
 		Max_active_requests: utils.IntPointer(-1),
 		Asr_template:        utils.StringPointer(""),
+		Rar_template:        utils.StringPointer(""),
 		Templates: map[string][]*FcTemplateJsonCfg{
 			utils.MetaErr: {
 				{Tag: utils.StringPointer("SessionId"),
@@ -678,7 +689,44 @@ func TestDiameterAgentJsonCfg(t *testing.T) {
 					Type:     utils.StringPointer(utils.META_CONSTANT),
 					Value:    utils.StringPointer("1")},
 			},
+			utils.MetaRAR: {
+				{Tag: utils.StringPointer("SessionId"),
+					Field_id:  utils.StringPointer("Session-Id"),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*req.Session-Id"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("OriginHost"),
+					Field_id:  utils.StringPointer("Origin-Host"),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*req.Destination-Host"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("OriginRealm"),
+					Field_id:  utils.StringPointer("Origin-Realm"),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*req.Destination-Realm"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("DestinationRealm"),
+					Field_id:  utils.StringPointer("Destination-Realm"),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*req.Origin-Realm"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("DestinationHost"),
+					Field_id:  utils.StringPointer("Destination-Host"),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*req.Origin-Host"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("AuthApplicationId"),
+					Field_id:  utils.StringPointer("Auth-Application-Id"),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*vars.*appid"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("ReAuthRequestType"),
+					Field_id: utils.StringPointer("Re-Auth-Request-Type"),
+					Type:     utils.StringPointer(utils.META_CONSTANT),
+					Value:    utils.StringPointer("0")},
+			},
 		},
+		Peers:              &[]*DiameterPeerJsonCfg{},
 		Request_processors: &[]*DARequestProcessorJsnCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.DiameterAgentJsonCfg(); err != nil {
@@ -723,12 +771,82 @@ func TestHttpAgentJsonCfg(t *testing.T) {
 	}
 }
 
+func TestSIPAgentJsonCfg(t *testing.T) {
+	eCfg := &SIPAgentJsonCfg{
+		Enabled:    utils.BoolPointer(false),
+		Listen:     utils.StringPointer("127.0.0.1:5060"),
+		Listen_net: utils.StringPointer(utils.UDP),
+		Sessions_conns: &[]*HaPoolJsonCfg{
+			{
+				Address: utils.StringPointer(utils.MetaInternal),
+			}},
+		Templates: map[string][]*FcTemplateJsonCfg{
+			"*302": {
+				{Tag: utils.StringPointer("ReplyCode"),
+					Field_id: utils.StringPointer("*sipReplyCode"),
+					Type:     utils.StringPointer(utils.META_CONSTANT),
+					Value:    utils.StringPointer("302")},
+				{Tag: utils.StringPointer("Contact"),
+					Field_id:  utils.StringPointer("Contact"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*vars.*sipContacts"),
+					Mandatory: utils.BoolPointer(true)},
+			},
+		},
+		Request_processors: &[]*SIPReqProcessorJsnCfg{},
+	}
+	if cfg, err := dfCgrJsonCfg.SIPAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
+func TestSMPPAgentJsonCfg(t *testing.T) {
+	eCfg := &SMPPAgentJsonCfg{
+		Enabled:               utils.BoolPointer(false),
+		Listen:                utils.StringPointer("127.0.0.1:2775"),
+		Smsc_address:          utils.StringPointer(""),
+		System_id:             utils.StringPointer("CGRateS"),
+		Password:              utils.StringPointer(""),
+		System_type:           utils.StringPointer(""),
+		Enquire_link_interval: utils.StringPointer("30s"),
+		Sessions_conns: &[]*HaPoolJsonCfg{
+			{
+				Address: utils.StringPointer(utils.MetaInternal),
+			}},
+		Templates: map[string][]*FcTemplateJsonCfg{
+			"*smpp_status": {
+				{Tag: utils.StringPointer("InsufficientCredit"),
+					Filters:  &[]string{"*rsr::~*cgrep.Error(INSUFFICIENT_CREDIT)"},
+					Field_id: utils.StringPointer("*smppCommandStatus"),
+					Type:     utils.StringPointer(utils.META_CONSTANT),
+					Value:    utils.StringPointer("0x45"),
+					Blocker:  utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("SystemError"),
+					Filters:  &[]string{"*rsr::~*cgrep.Error(!^$)"},
+					Field_id: utils.StringPointer("*smppCommandStatus"),
+					Type:     utils.StringPointer(utils.META_CONSTANT),
+					Value:    utils.StringPointer("0x08"),
+					Blocker:  utils.BoolPointer(true)},
+			},
+		},
+		Request_processors: &[]*SMPPReqProcessorJsnCfg{},
+	}
+	if cfg, err := dfCgrJsonCfg.SMPPAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
 func TestDfAttributeServJsonCfg(t *testing.T) {
 	eCfg := &AttributeSJsonCfg{
 		Enabled:               utils.BoolPointer(false),
 		String_indexed_fields: nil,
 		Prefix_indexed_fields: &[]string{},
 		Process_runs:          utils.IntPointer(1),
+		Numbers_conns:         &[]*HaPoolJsonCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.AttributeServJsonCfg(); err != nil {
 		t.Error(err)
@@ -737,6 +855,20 @@ func TestDfAttributeServJsonCfg(t *testing.T) {
 	}
 }
 
+func TestDfNumberServJsonCfg(t *testing.T) {
+	eCfg := &NumberSJsonCfg{
+		Enabled:            utils.BoolPointer(false),
+		Normalize_fields:   &[]string{utils.Account, utils.Subject, utils.Destination},
+		Portability_fields: &[]string{utils.Destination},
+		Dial_plans:         &[]*DialPlanJsonCfg{},
+	}
+	if cfg, err := dfCgrJsonCfg.NumberServJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Error("Received: ", utils.ToJSON(cfg))
+	}
+}
+
 func TestDfChargerServJsonCfg(t *testing.T) {
 	eCfg := &ChargerSJsonCfg{
 		Enabled:               utils.BoolPointer(false),
@@ -831,12 +963,13 @@ func TestDfSupplierSJsonCfg(t *testing.T) {
 func TestDfLoaderJsonCfg(t *testing.T) {
 	eCfg := []*LoaderJsonCfg{
 		{
-			ID:            utils.StringPointer(utils.META_DEFAULT),
-			Enabled:       utils.BoolPointer(false),
-			Tenant:        utils.StringPointer("cgrates.org"),
-			Dry_run:       utils.BoolPointer(false),
-			Run_delay:     utils.IntPointer(0),
-			Lock_filename: utils.StringPointer(".cgr.lck"),
+			ID:              utils.StringPointer(utils.META_DEFAULT),
+			Enabled:         utils.BoolPointer(false),
+			Tenant:          utils.StringPointer("cgrates.org"),
+			Dry_run:         utils.BoolPointer(false),
+			Activation_time: utils.StringPointer(""),
+			Run_delay:       utils.IntPointer(0),
+			Lock_filename:   utils.StringPointer(".cgr.lck"),
 			Caches_conns: &[]*HaPoolJsonCfg{{
 				Address: utils.StringPointer(utils.MetaInternal),
 			}},
@@ -886,10 +1019,22 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 							Field_id: utils.StringPointer(utils.Append),
 							Type:     utils.StringPointer(utils.META_COMPOSED),
 							Value:    utils.StringPointer("~8")},
+						{Tag: utils.StringPointer("Blocker"),
+							Field_id: utils.StringPointer("Blocker"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~9")},
 						{Tag: utils.StringPointer("Weight"),
 							Field_id: utils.StringPointer(utils.Weight),
 							Type:     utils.StringPointer(utils.META_COMPOSED),
-							Value:    utils.StringPointer("~9")},
+							Value:    utils.StringPointer("~10")},
+						{Tag: utils.StringPointer("AttributeFilterIDs"),
+							Field_id: utils.StringPointer("AttributeFilterIDs"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~11")},
+						{Tag: utils.StringPointer("Type"),
+							Field_id: utils.StringPointer("Type"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~12")},
 					},
 				},
 				{
@@ -1256,6 +1401,35 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 							Value:    utils.StringPointer("~12")},
 					},
 				},
+				{
+					Type:      utils.StringPointer(utils.MetaPortedNumbers),
+					File_name: utils.StringPointer(utils.PortedNumbersCsv),
+					Fields: &[]*FcTemplateJsonCfg{
+						{Tag: utils.StringPointer(utils.Tenant),
+							Field_id:  utils.StringPointer(utils.Tenant),
+							Type:      utils.StringPointer(utils.META_COMPOSED),
+							Value:     utils.StringPointer("~0"),
+							Mandatory: utils.BoolPointer(true)},
+						{Tag: utils.StringPointer(utils.ID),
+							Field_id:  utils.StringPointer(utils.ID),
+							Type:      utils.StringPointer(utils.META_COMPOSED),
+							Value:     utils.StringPointer("~1"),
+							Mandatory: utils.BoolPointer(true)},
+						{Tag: utils.StringPointer("First"),
+							Field_id: utils.StringPointer("First"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~2")},
+						{Tag: utils.StringPointer("Last"),
+							Field_id: utils.StringPointer("Last"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~3")},
+						{Tag: utils.StringPointer("RoutingNumber"),
+							Field_id:  utils.StringPointer("RoutingNumber"),
+							Type:      utils.StringPointer(utils.META_COMPOSED),
+							Value:     utils.StringPointer("~4"),
+							Mandatory: utils.BoolPointer(true)},
+					},
+				},
 			},
 		},
 	}
@@ -1401,6 +1575,7 @@ func TestDfDispatcherSJsonCfg(t *testing.T) {
 		String_indexed_fields: nil,
 		Prefix_indexed_fields: &[]string{},
 		Attributes_conns:      &[]*HaPoolJsonCfg{},
+		Thresholds_conns:      &[]*HaPoolJsonCfg{},
 		Conns: &map[string]*[]*HaPoolJsonCfg{
 			"sessions_eu": &[]*HaPoolJsonCfg{
 				{Address: utils.StringPointer("127.0.0.1:2012"), Transport: utils.StringPointer(utils.MetaJSONrpc)},
@@ -1488,6 +1663,34 @@ func TestDfTlsCfg(t *testing.T) {
 	}
 }
 
+func TestDfEventBusCfg(t *testing.T) {
+	eCfg := &EventBusJsonCfg{
+		Queue_size:        utils.IntPointer(1000),
+		Overflow_strategy: utils.StringPointer(utils.MetaBlock),
+		Persist_dir:       utils.StringPointer(""),
+	}
+	if cfg, err := dfCgrJsonCfg.EventBusCfgJson(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("Expected: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
+func TestDfAccountExporterCfg(t *testing.T) {
+	eCfg := &AccountExporterJsonCfg{
+		Enabled:     utils.BoolPointer(false),
+		Filters:     &[]string{},
+		Export_path: utils.StringPointer(""),
+		Transport:   utils.StringPointer(utils.MetaHTTPjsonMap),
+		Attempts:    utils.IntPointer(3),
+	}
+	if cfg, err := dfCgrJsonCfg.AccountExporterJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("Expected: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
 func TestDfAnalyzerCfg(t *testing.T) {
 	eCfg := &AnalyzerSJsonCfg{
 		Enabled: utils.BoolPointer(false),
@@ -370,6 +370,9 @@ func TestCgrCfgJSONDefaultsGeneral(t *testing.T) {
 	if cgrCfg.GeneralCfg().LockingTimeout != 0 {
 		t.Errorf("Expected: 0, received: %+v", cgrCfg.GeneralCfg().LockingTimeout)
 	}
+	if cgrCfg.GeneralCfg().LoadHistorySize != 10 {
+		t.Errorf("Expected: 10, received: %+v", cgrCfg.GeneralCfg().LoadHistorySize)
+	}
 	if cgrCfg.GeneralCfg().Logger != utils.MetaSysLog {
 		t.Errorf("Expected: %+v, received: %+v", utils.MetaSysLog, cgrCfg.GeneralCfg().Logger)
 	}
@@ -468,6 +471,18 @@ func TestCgrCfgJSONDefaultsRALs(t *testing.T) {
 	if cgrCfg.RalsCfg().RpSubjectPrefixMatching != false {
 		t.Errorf("Expecting: false , received: %+v", cgrCfg.RalsCfg().RpSubjectPrefixMatching)
 	}
+	if cgrCfg.RalsCfg().BalanceHistory != false {
+		t.Errorf("Expecting: false , received: %+v", cgrCfg.RalsCfg().BalanceHistory)
+	}
+	if cgrCfg.RalsCfg().ExpiryScanInterval != 0 {
+		t.Errorf("Expecting: 0 , received: %+v", cgrCfg.RalsCfg().ExpiryScanInterval)
+	}
+	if cgrCfg.RalsCfg().ExpiryNotifyBefore != time.Duration(24*time.Hour) {
+		t.Errorf("Expecting: 24h , received: %+v", cgrCfg.RalsCfg().ExpiryNotifyBefore)
+	}
+	if cgrCfg.RalsCfg().ExpiryGracePeriod != 0 {
+		t.Errorf("Expecting: 0 , received: %+v", cgrCfg.RalsCfg().ExpiryGracePeriod)
+	}
 	eMaxCU := map[string]time.Duration{
 		utils.ANY:   time.Duration(189 * time.Hour),
 		utils.VOICE: time.Duration(72 * time.Hour),
@@ -481,8 +496,9 @@ func TestCgrCfgJSONDefaultsRALs(t *testing.T) {
 
 func TestCgrCfgJSONDefaultsScheduler(t *testing.T) {
 	eSchedulerCfg := &SchedulerCfg{
-		Enabled:   false,
-		CDRsConns: []*HaPoolConfig{},
+		Enabled:       false,
+		CDRsConns:     []*HaPoolConfig{},
+		SessionSConns: []*HaPoolConfig{},
 	}
 
 	if !reflect.DeepEqual(cgrCfg.schedulerCfg, eSchedulerCfg) {
@@ -703,6 +719,8 @@ func TestCgrCfgJSONDefaultsCacheCFG(t *testing.T) {
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheDispatcherRoutes: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CachePortedNumbers: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheDiameterMessages: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(3 * time.Hour), StaticTTL: false},
 	}
@@ -987,10 +1005,53 @@ func TestRadiusAgentCfg(t *testing.T) {
 	}
 }
 
+func TestSIPAgentCfg(t *testing.T) {
+	if cgrCfg.SIPAgentCfg().Enabled {
+		t.Error("SIPAgent should be disabled by default")
+	}
+	if cgrCfg.SIPAgentCfg().Listen != "127.0.0.1:5060" ||
+		cgrCfg.SIPAgentCfg().ListenNet != utils.UDP {
+		t.Errorf("received: %s", utils.ToJSON(cgrCfg.SIPAgentCfg()))
+	}
+	if tpl, has := cgrCfg.SIPAgentCfg().Templates["*302"]; !has || len(tpl) != 2 {
+		t.Errorf("received templates: %s", utils.ToJSON(cgrCfg.SIPAgentCfg().Templates))
+	}
+}
+
+func TestSMPPAgentCfg(t *testing.T) {
+	if cgrCfg.SMPPAgentCfg().Enabled {
+		t.Error("SMPPAgent should be disabled by default")
+	}
+	if cgrCfg.SMPPAgentCfg().Listen != "127.0.0.1:2775" ||
+		cgrCfg.SMPPAgentCfg().SystemID != "CGRateS" ||
+		cgrCfg.SMPPAgentCfg().EnquireLinkInterval != 30*time.Second {
+		t.Errorf("received: %s", utils.ToJSON(cgrCfg.SMPPAgentCfg()))
+	}
+	if tpl, has := cgrCfg.SMPPAgentCfg().Templates["*smpp_status"]; !has || len(tpl) != 2 {
+		t.Errorf("received templates: %s", utils.ToJSON(cgrCfg.SMPPAgentCfg().Templates))
+	}
+}
+
+func TestNumberSCfg(t *testing.T) {
+	eCfg := &NumberSCfg{
+		Enabled:           false,
+		NormalizeFields:   []string{utils.Account, utils.Subject, utils.Destination},
+		PortabilityFields: []string{utils.Destination},
+		DialPlans:         []*DialPlanCfg{},
+	}
+	if !reflect.DeepEqual(eCfg, cgrCfg.NumberSCfg()) {
+		t.Errorf("expecting: %s, received: %s",
+			utils.ToJSON(eCfg), utils.ToJSON(cgrCfg.NumberSCfg()))
+	}
+	if len(cgrCfg.AttributeSCfg().NumberSConns) != 0 {
+		t.Errorf("received: %s", utils.ToJSON(cgrCfg.AttributeSCfg().NumberSConns))
+	}
+}
+
 func TestDbDefaults(t *testing.T) {
 	dbdf := NewDbDefaults()
 	flagInput := utils.MetaDynamic
-	dbs := []string{utils.MONGO, utils.REDIS, utils.MYSQL, utils.INTERNAL}
+	dbs := []string{utils.MONGO, utils.REDIS, utils.MYSQL, utils.SQLITE, utils.INTERNAL}
 	for _, dbtype := range dbs {
 		host := dbdf.DBHost(dbtype, flagInput)
 		if host != utils.LOCALHOST {
@@ -1075,10 +1136,22 @@ func TestCgrLoaderCfgITDefaults(t *testing.T) {
 							FieldId: "Append",
 							Type:    utils.META_COMPOSED,
 							Value:   NewRSRParsersMustCompile("~8", true, utils.INFIELD_SEP)},
+						{Tag: "Blocker",
+							FieldId: "Blocker",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~9", true, utils.INFIELD_SEP)},
 						{Tag: "Weight",
 							FieldId: "Weight",
 							Type:    utils.META_COMPOSED,
-							Value:   NewRSRParsersMustCompile("~9", true, utils.INFIELD_SEP)},
+							Value:   NewRSRParsersMustCompile("~10", true, utils.INFIELD_SEP)},
+						{Tag: "AttributeFilterIDs",
+							FieldId: "AttributeFilterIDs",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~11", true, utils.INFIELD_SEP)},
+						{Tag: "Type",
+							FieldId: "Type",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~12", true, utils.INFIELD_SEP)},
 					},
 				},
 				{
@@ -1456,6 +1529,37 @@ func TestCgrLoaderCfgITDefaults(t *testing.T) {
 						},
 					},
 				},
+				{
+					Type:     utils.MetaPortedNumbers,
+					Filename: utils.PortedNumbersCsv,
+					Fields: []*FCTemplate{
+						{Tag: "Tenant",
+							FieldId:   "Tenant",
+							Type:      utils.META_COMPOSED,
+							Value:     NewRSRParsersMustCompile("~0", true, utils.INFIELD_SEP),
+							Mandatory: true},
+						{Tag: "ID",
+							FieldId:   "ID",
+							Type:      utils.META_COMPOSED,
+							Value:     NewRSRParsersMustCompile("~1", true, utils.INFIELD_SEP),
+							Mandatory: true},
+						{Tag: "First",
+							FieldId: "First",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~2", true, utils.INFIELD_SEP),
+						},
+						{Tag: "Last",
+							FieldId: "Last",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~3", true, utils.INFIELD_SEP),
+						},
+						{Tag: "RoutingNumber",
+							FieldId:   "RoutingNumber",
+							Type:      utils.META_COMPOSED,
+							Value:     NewRSRParsersMustCompile("~4", true, utils.INFIELD_SEP),
+							Mandatory: true},
+					},
+				},
 			},
 		},
 	}
@@ -1471,6 +1575,7 @@ func TestCgrCfgJSONDefaultDispatcherSCfg(t *testing.T) {
 		StringIndexedFields: nil,
 		PrefixIndexedFields: &[]string{},
 		AttributeSConns:     []*HaPoolConfig{},
+		ThresholdSConns:     []*HaPoolConfig{},
 		Conns: map[string][]*HaPoolConfig{
 			"sessions_eu": []*HaPoolConfig{
 				{Address: "127.0.0.1:2012", Transport: utils.MetaJSONrpc},
@@ -1686,6 +1791,42 @@ func TestCfgTlsCfg(t *testing.T) {
 	}
 }
 
+func TestCgrCfgJSONDefaultEventBusCfg(t *testing.T) {
+	eCfg := &EventBusCfg{
+		QueueSize:        1000,
+		OverflowStrategy: utils.MetaBlock,
+		PersistDir:       "",
+	}
+	if !reflect.DeepEqual(cgrCfg.eventBusCfg, eCfg) {
+		t.Errorf("received: %+v, expecting: %+v", cgrCfg.eventBusCfg, eCfg)
+	}
+}
+
+func TestCgrCfgJSONEventBusCfgError(t *testing.T) {
+	if _, err := NewCGRConfigFromJsonStringWithDefaults(`{"event_bus": {"queue_size": "1000"}}`); err == nil {
+		t.Error("expecting error for invalid queue_size")
+	}
+}
+
+func TestCgrCfgJSONDefaultAccountExporterCfg(t *testing.T) {
+	eCfg := &AccountExporterCfg{
+		Enabled:    false,
+		Filters:    []string{},
+		ExportPath: "",
+		Transport:  utils.MetaHTTPjsonMap,
+		Attempts:   3,
+	}
+	if !reflect.DeepEqual(cgrCfg.accountExporterCfg, eCfg) {
+		t.Errorf("received: %+v, expecting: %+v", cgrCfg.accountExporterCfg, eCfg)
+	}
+}
+
+func TestCgrCfgJSONAccountExporterCfgError(t *testing.T) {
+	if _, err := NewCGRConfigFromJsonStringWithDefaults(`{"account_exporter": {"attempts": "3"}}`); err == nil {
+		t.Error("expecting error for invalid attempts")
+	}
+}
+
 func TestCgrCfgJSONDefaultAnalyzerSCfg(t *testing.T) {
 	aSCfg := &AnalyzerSCfg{
 		Enabled: false,
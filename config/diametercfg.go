@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package config
 
 import (
+	"time"
+
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -26,6 +28,7 @@ type DiameterAgentCfg struct {
 	Enabled           bool   // enables the diameter agent: <true|false>
 	ListenNet         string // sctp or tcp
 	Listen            string // address where to listen for diameter requests <x.y.z.y:1234>
+	TLS               bool   // enable Diameter over TLS, using the certificates from tls section
 	DictionariesPath  string
 	SessionSConns     []*HaPoolConfig // connections towards SMG component
 	OriginHost        string
@@ -34,7 +37,10 @@ type DiameterAgentCfg struct {
 	ProductName       string
 	MaxActiveReqs     int // limit the maximum number of requests processed
 	ASRTemplate       string
+	RARTemplate       string
 	Templates         map[string][]*FCTemplate
+	AllowedPeers      []*DiameterAllowedPeerCfg // peers allowed to connect, checked on CER, empty to allow all
+	Peers             []*DiameterPeerCfg        // upstream peers used when proxying requests
 	RequestProcessors []*DARequestProcessor
 }
 
@@ -51,6 +57,16 @@ func (da *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg, separa
 	if jsnCfg.Listen_net != nil {
 		da.ListenNet = *jsnCfg.Listen_net
 	}
+	if jsnCfg.Tls != nil {
+		da.TLS = *jsnCfg.Tls
+	}
+	if jsnCfg.Allowed_peers != nil {
+		da.AllowedPeers = make([]*DiameterAllowedPeerCfg, len(*jsnCfg.Allowed_peers))
+		for i, jsnPeer := range *jsnCfg.Allowed_peers {
+			da.AllowedPeers[i] = new(DiameterAllowedPeerCfg)
+			da.AllowedPeers[i].loadFromJsonCfg(jsnPeer)
+		}
+	}
 	if jsnCfg.Dictionaries_path != nil {
 		da.DictionariesPath = *jsnCfg.Dictionaries_path
 	}
@@ -79,6 +95,9 @@ func (da *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg, separa
 	if jsnCfg.Asr_template != nil {
 		da.ASRTemplate = *jsnCfg.Asr_template
 	}
+	if jsnCfg.Rar_template != nil {
+		da.RARTemplate = *jsnCfg.Rar_template
+	}
 	if jsnCfg.Templates != nil {
 		if da.Templates == nil {
 			da.Templates = make(map[string][]*FCTemplate)
@@ -89,6 +108,25 @@ func (da *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg, separa
 			}
 		}
 	}
+	if jsnCfg.Peers != nil {
+		for _, peerJsn := range *jsnCfg.Peers {
+			peer := NewDfltDiameterPeerCfg()
+			var haveID bool
+			for _, peerSet := range da.Peers {
+				if peerJsn.Id != nil && peerSet.ID == *peerJsn.Id {
+					peer = peerSet // Will load data into the one set
+					haveID = true
+					break
+				}
+			}
+			if err = peer.loadFromJsonCfg(peerJsn); err != nil {
+				return
+			}
+			if !haveID {
+				da.Peers = append(da.Peers, peer)
+			}
+		}
+	}
 	if jsnCfg.Request_processors != nil {
 		for _, reqProcJsn := range *jsnCfg.Request_processors {
 			rp := new(DARequestProcessor)
@@ -119,6 +157,8 @@ type DARequestProcessor struct {
 	Flags             utils.StringMap
 	Timezone          string // timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
 	ContinueOnSuccess bool
+	ProxyPeer         string        // ID of the peer the request is forwarded to when *proxy flag is present
+	ProxyFields       []*FCTemplate // build the forwarded request out of these, empty to forward the original AVPs
 	RequestFields     []*FCTemplate
 	ReplyFields       []*FCTemplate
 }
@@ -150,6 +190,14 @@ func (dap *DARequestProcessor) loadFromJsonCfg(jsnCfg *DARequestProcessorJsnCfg,
 	if jsnCfg.Continue_on_success != nil {
 		dap.ContinueOnSuccess = *jsnCfg.Continue_on_success
 	}
+	if jsnCfg.Proxy_peer != nil {
+		dap.ProxyPeer = *jsnCfg.Proxy_peer
+	}
+	if jsnCfg.Proxy_fields != nil {
+		if dap.ProxyFields, err = FCTemplatesFromFCTemplatesJsonCfg(*jsnCfg.Proxy_fields, separator); err != nil {
+			return
+		}
+	}
 	if jsnCfg.Request_fields != nil {
 		if dap.RequestFields, err = FCTemplatesFromFCTemplatesJsonCfg(*jsnCfg.Request_fields, separator); err != nil {
 			return
@@ -162,3 +210,71 @@ func (dap *DARequestProcessor) loadFromJsonCfg(jsnCfg *DARequestProcessorJsnCfg,
 	}
 	return nil
 }
+
+// DiameterAllowedPeerCfg identifies one peer allowed to connect to DiameterAgent
+type DiameterAllowedPeerCfg struct {
+	OriginHost  string // empty to allow any host within the realm
+	OriginRealm string
+}
+
+func (ap *DiameterAllowedPeerCfg) loadFromJsonCfg(jsnCfg *DiameterAllowedPeerJsonCfg) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Origin_host != nil {
+		ap.OriginHost = *jsnCfg.Origin_host
+	}
+	if jsnCfg.Origin_realm != nil {
+		ap.OriginRealm = *jsnCfg.Origin_realm
+	}
+}
+
+// Allows checks if the peer identified by originHost and originRealm matches this entry
+func (ap *DiameterAllowedPeerCfg) Allows(originHost, originRealm string) bool {
+	return ap.OriginRealm == originRealm &&
+		(ap.OriginHost == "" || ap.OriginHost == originHost)
+}
+
+// NewDfltDiameterPeerCfg returns a peer configuration populated with the default values
+func NewDfltDiameterPeerCfg() *DiameterPeerCfg {
+	return &DiameterPeerCfg{
+		Transport:        utils.TCP,
+		ReplyTimeout:     2 * time.Second,
+		WatchdogInterval: 30 * time.Second,
+	}
+}
+
+// DiameterPeerCfg is one upstream Diameter peer (ie: OCS) DiameterAgent can proxy requests to
+type DiameterPeerCfg struct {
+	ID               string
+	Address          string        // address of the peer <x.y.z.y:1234>
+	Transport        string        // transport used towards the peer <tcp|sctp>
+	ReplyTimeout     time.Duration // time to wait for an answer from the peer
+	WatchdogInterval time.Duration // interval between DWR messages sent to the peer
+}
+
+func (dp *DiameterPeerCfg) loadFromJsonCfg(jsnCfg *DiameterPeerJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return nil
+	}
+	if jsnCfg.Id != nil {
+		dp.ID = *jsnCfg.Id
+	}
+	if jsnCfg.Address != nil {
+		dp.Address = *jsnCfg.Address
+	}
+	if jsnCfg.Transport != nil {
+		dp.Transport = *jsnCfg.Transport
+	}
+	if jsnCfg.Reply_timeout != nil {
+		if dp.ReplyTimeout, err = utils.ParseDurationWithNanosecs(*jsnCfg.Reply_timeout); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Watchdog_interval != nil {
+		if dp.WatchdogInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Watchdog_interval); err != nil {
+			return
+		}
+	}
+	return nil
+}
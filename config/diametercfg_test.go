@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -108,3 +109,88 @@ func TestDARequestProcessorloadFromJsonCfg(t *testing.T) {
 		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(dareq))
 	}
 }
+
+func TestDiameterPeerCfgloadFromJsonCfg(t *testing.T) {
+	var dpCfg, expected DiameterPeerCfg
+	if err := dpCfg.loadFromJsonCfg(nil); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(dpCfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, dpCfg)
+	}
+	cfgJSONStr := `{
+"diameter_agent": {
+	"peers": [
+		{"id": "OCS1", "address": "127.0.0.1:3869", "reply_timeout": "500ms"},
+		{"id": "OCS2", "address": "127.0.0.1:3870", "transport": "sctp", "watchdog_interval": "10s"},
+	],
+	"request_processors": [
+		{"id": "Proxy", "flags": ["*proxy", "*cdrs"], "proxy_peer": "OCS1",
+			"proxy_fields": [
+				{"tag": "SessionId", "field_id": "Session-Id", "type": "*composed",
+					"value": "~*req.Session-Id", "mandatory": true},
+			],
+		},
+	],
+},
+}`
+	eDaCfg := &DiameterAgentCfg{
+		Peers: []*DiameterPeerCfg{
+			{
+				ID:               "OCS1",
+				Address:          "127.0.0.1:3869",
+				Transport:        utils.TCP,
+				ReplyTimeout:     500 * time.Millisecond,
+				WatchdogInterval: 30 * time.Second,
+			},
+			{
+				ID:               "OCS2",
+				Address:          "127.0.0.1:3870",
+				Transport:        utils.SCTP,
+				ReplyTimeout:     2 * time.Second,
+				WatchdogInterval: 10 * time.Second,
+			},
+		},
+		RequestProcessors: []*DARequestProcessor{
+			{
+				ID:        "Proxy",
+				Flags:     utils.StringMap{utils.MetaProxy: true, utils.MetaCDRs: true},
+				ProxyPeer: "OCS1",
+				ProxyFields: []*FCTemplate{
+					{Tag: "SessionId", FieldId: "Session-Id", Type: utils.META_COMPOSED,
+						Value:     NewRSRParsersMustCompile("~*req.Session-Id", true, utils.INFIELD_SEP),
+						Mandatory: true},
+				},
+			},
+		},
+	}
+	daCfg := new(DiameterAgentCfg)
+	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
+		t.Error(err)
+	} else if jsnDaCfg, err := jsnCfg.DiameterAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if err = daCfg.loadFromJsonCfg(jsnDaCfg, utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eDaCfg, daCfg) {
+		t.Errorf("Expected: %s , recived: %s", utils.ToJSON(eDaCfg), utils.ToJSON(daCfg))
+	}
+}
+
+func TestDiameterAllowedPeerCfgAllows(t *testing.T) {
+	var ap DiameterAllowedPeerCfg
+	ap.loadFromJsonCfg(&DiameterAllowedPeerJsonCfg{
+		Origin_realm: utils.StringPointer("roaming.org")})
+	if !ap.Allows("pcef1", "roaming.org") {
+		t.Error("any host within roaming.org should be allowed")
+	}
+	ap.loadFromJsonCfg(&DiameterAllowedPeerJsonCfg{
+		Origin_host: utils.StringPointer("pcef1")})
+	if !ap.Allows("pcef1", "roaming.org") {
+		t.Error("pcef1@roaming.org should be allowed")
+	}
+	if ap.Allows("pcef2", "roaming.org") {
+		t.Error("pcef2@roaming.org should not be allowed")
+	}
+	if ap.Allows("pcef1", "other.org") {
+		t.Error("pcef1@other.org should not be allowed")
+	}
+}
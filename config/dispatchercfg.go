@@ -24,6 +24,7 @@ type DispatcherSCfg struct {
 	StringIndexedFields *[]string
 	PrefixIndexedFields *[]string
 	AttributeSConns     []*HaPoolConfig
+	ThresholdSConns     []*HaPoolConfig
 	Conns               map[string][]*HaPoolConfig
 }
 
@@ -55,6 +56,13 @@ func (dps *DispatcherSCfg) loadFromJsonCfg(jsnCfg *DispatcherSJsonCfg) (err erro
 			dps.AttributeSConns[idx].loadFromJsonCfg(jsnHaCfg)
 		}
 	}
+	if jsnCfg.Thresholds_conns != nil {
+		dps.ThresholdSConns = make([]*HaPoolConfig, len(*jsnCfg.Thresholds_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Thresholds_conns {
+			dps.ThresholdSConns[idx] = NewDfltHaPoolConfig()
+			dps.ThresholdSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
 	if jsnCfg.Conns != nil {
 		dps.Conns = make(map[string][]*HaPoolConfig, len(*jsnCfg.Conns))
 		for id, conns := range *jsnCfg.Conns {
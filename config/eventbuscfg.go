@@ -0,0 +1,42 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+// EventBusCfg is the configuration of the internal event bus serving the asynchronous connections
+type EventBusCfg struct {
+	QueueSize        int
+	OverflowStrategy string
+	PersistDir       string
+}
+
+func (eb *EventBusCfg) loadFromJsonCfg(jsnCfg *EventBusJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Queue_size != nil {
+		eb.QueueSize = *jsnCfg.Queue_size
+	}
+	if jsnCfg.Overflow_strategy != nil {
+		eb.OverflowStrategy = *jsnCfg.Overflow_strategy
+	}
+	if jsnCfg.Persist_dir != nil {
+		eb.PersistDir = *jsnCfg.Persist_dir
+	}
+	return nil
+}
@@ -47,6 +47,7 @@ type GeneralCfg struct {
 	ResponseCacheTTL  time.Duration // the life span of a cached response
 	InternalTtl       time.Duration // maximum duration to wait for internal connections before giving up
 	LockingTimeout    time.Duration // locking mechanism timeout to avoid deadlocks
+	LoadHistorySize   int           // number of tariff plan loads kept in history, 0 disables versioning of loads
 	DigestSeparator   string
 	DigestEqual       string
 	RsrSepatarot      string // separator used to split RSRParser (by degault is used ";")
@@ -128,6 +129,9 @@ func (gencfg *GeneralCfg) loadFromJsonCfg(jsnGeneralCfg *GeneralJsonCfg) (err er
 			return err
 		}
 	}
+	if jsnGeneralCfg.Load_history_size != nil {
+		gencfg.LoadHistorySize = *jsnGeneralCfg.Load_history_size
+	}
 	if jsnGeneralCfg.Digest_separator != nil {
 		gencfg.DigestSeparator = *jsnGeneralCfg.Digest_separator
 	}
@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package config
 
 import (
+	"time"
+
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -53,12 +55,15 @@ func (hcfgs *HttpAgentCfgs) loadFromJsonCfg(jsnHttpAgntCfg *[]*HttpAgentJsonCfg,
 }
 
 type HttpAgentCfg struct {
-	ID                string // identifier for the agent, so we can update it's processors
-	Url               string
-	SessionSConns     []*HaPoolConfig
-	RequestPayload    string
-	ReplyPayload      string
-	RequestProcessors []*HttpAgntProcCfg
+	ID                 string // identifier for the agent, so we can update it's processors
+	Url                string
+	SessionSConns      []*HaPoolConfig
+	RequestPayload     string
+	ReplyPayload       string
+	SessionKey         RSRParsers    // correlates the requests of the same session, empty for stateless processing
+	SessionIdleTimeout time.Duration // terminate the session via SessionS if no request received within, 0 to disable
+	DisconnectURL      string        // webhook called on session disconnects from SessionS
+	RequestProcessors  []*HttpAgntProcCfg
 }
 
 func (ca *HttpAgentCfg) appendHttpAgntProcCfgs(hps *[]*HttpAgentProcessorJsnCfg, separator string) (err error) {
@@ -110,6 +115,19 @@ func (ca *HttpAgentCfg) loadFromJsonCfg(jsnCfg *HttpAgentJsonCfg, separator stri
 	if jsnCfg.Reply_payload != nil {
 		ca.ReplyPayload = *jsnCfg.Reply_payload
 	}
+	if jsnCfg.Session_key != nil {
+		if ca.SessionKey, err = NewRSRParsers(*jsnCfg.Session_key, true, separator); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Session_idle_timeout != nil {
+		if ca.SessionIdleTimeout, err = utils.ParseDurationWithNanosecs(*jsnCfg.Session_idle_timeout); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Disconnect_url != nil {
+		ca.DisconnectURL = *jsnCfg.Disconnect_url
+	}
 	if err = ca.appendHttpAgntProcCfgs(jsnCfg.Request_processors, separator); err != nil {
 		return err
 	}
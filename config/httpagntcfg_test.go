@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -400,3 +401,70 @@ func TestHttpAgntProcCfgloadFromJsonCfg(t *testing.T) {
 		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(httpcfg))
 	}
 }
+
+func TestHttpAgentCfgSessions(t *testing.T) {
+	var httpcfg HttpAgentCfgs
+	cfgJSONStr := `{
+"http_agent": [
+	{
+		"id": "metering",
+		"url": "/metering",
+		"request_payload":	"*json",
+		"reply_payload":	"*json",
+		"session_key": "~*hdr.X-Session-ID",
+		"session_idle_timeout": "30s",
+		"disconnect_url": "http://127.0.0.1:8080/disconnect",
+	},
+	],
+}`
+	expected := HttpAgentCfgs{&HttpAgentCfg{
+		ID:                 "metering",
+		Url:                "/metering",
+		RequestPayload:     utils.MetaJSON,
+		ReplyPayload:       utils.MetaJSON,
+		SessionKey:         NewRSRParsersMustCompile("~*hdr.X-Session-ID", true, utils.INFIELD_SEP),
+		SessionIdleTimeout: 30 * time.Second,
+		DisconnectURL:      "http://127.0.0.1:8080/disconnect",
+	}}
+	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
+		t.Error(err)
+	} else if jsnhttpCfg, err := jsnCfg.HttpAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if err = httpcfg.loadFromJsonCfg(jsnhttpCfg, utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, httpcfg) {
+		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(httpcfg))
+	}
+	if cfg, err := NewCGRConfigFromJsonStringWithDefaults(`{
+"http_agent": [
+	{
+		"id": "metering",
+		"url": "/metering",
+		"request_payload":	"*json",
+		"reply_payload":	"*json",
+		"session_idle_timeout": "30s",
+	},
+	],
+}`); err != nil {
+		t.Error(err)
+	} else if err := cfg.checkConfigSanity(); err == nil {
+		t.Error("expecting error on missing session_key")
+	}
+	if cfg, err := NewCGRConfigFromJsonStringWithDefaults(`{
+"http_agent": [
+	{
+		"id": "metering",
+		"url": "/metering",
+		"sessions_conns": [{"address": "127.0.0.1:2012", "transport": "*json"}],
+		"request_payload":	"*json",
+		"reply_payload":	"*json",
+		"session_key": "~*hdr.X-Session-ID",
+		"disconnect_url": "http://127.0.0.1:8080/disconnect",
+	},
+	],
+}`); err != nil {
+		t.Error(err)
+	} else if err := cfg.checkConfigSanity(); err == nil {
+		t.Error("expecting error on disconnect_url with remote sessions_conns")
+	}
+}
@@ -40,6 +40,7 @@ type GeneralJsonCfg struct {
 	Response_cache_ttl   *string
 	Internal_ttl         *string
 	Locking_timeout      *string
+	Load_history_size    *int
 	Digest_separator     *string
 	Digest_equal         *string
 	Rsr_separator        *string
@@ -103,13 +104,18 @@ type RalsJsonCfg struct {
 	Stats_conns                *[]*HaPoolJsonCfg
 	Rp_subject_prefix_matching *bool
 	Remove_expired             *bool
+	Balance_history            *bool
+	Expiry_scan_interval       *string
+	Expiry_notify_before       *string
+	Expiry_grace_period        *string
 	Max_computed_usage         *map[string]string
 }
 
 // Scheduler config section
 type SchedulerJsonCfg struct {
-	Enabled    *bool
-	Cdrs_conns *[]*HaPoolJsonCfg
+	Enabled        *bool
+	Cdrs_conns     *[]*HaPoolJsonCfg
+	Sessions_conns *[]*HaPoolJsonCfg
 }
 
 // Cdrs config section
@@ -259,6 +265,7 @@ type HaPoolJsonCfg struct {
 	Transport   *string
 	Synchronous *bool
 	Tls         *bool
+	Async       *bool
 }
 
 type AstConnJsonCfg struct {
@@ -324,6 +331,8 @@ type DiameterAgentJsonCfg struct {
 	Enabled             *bool   // enables the diameter agent: <true|false>
 	Listen              *string // address where to listen for diameter requests <x.y.z.y:1234>
 	Listen_net          *string
+	Tls                 *bool
+	Allowed_peers       *[]*DiameterAllowedPeerJsonCfg
 	Dictionaries_path   *string           // path towards additional dictionaries
 	Sessions_conns      *[]*HaPoolJsonCfg // Connections towards SessionS
 	Origin_host         *string
@@ -332,10 +341,27 @@ type DiameterAgentJsonCfg struct {
 	Product_name        *string
 	Max_active_requests *int
 	Asr_template        *string
+	Rar_template        *string
 	Templates           map[string][]*FcTemplateJsonCfg
+	Peers               *[]*DiameterPeerJsonCfg
 	Request_processors  *[]*DARequestProcessorJsnCfg
 }
 
+// One peer allowed to connect to DiameterAgent
+type DiameterAllowedPeerJsonCfg struct {
+	Origin_host  *string
+	Origin_realm *string
+}
+
+// One upstream Diameter peer
+type DiameterPeerJsonCfg struct {
+	Id                *string
+	Address           *string
+	Transport         *string
+	Reply_timeout     *string
+	Watchdog_interval *string
+}
+
 // One Diameter request processor configuration
 type DARequestProcessorJsnCfg struct {
 	Id                  *string
@@ -344,6 +370,8 @@ type DARequestProcessorJsnCfg struct {
 	Flags               *[]string
 	Timezone            *string // timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
 	Continue_on_success *bool
+	Proxy_peer          *string
+	Proxy_fields        *[]*FcTemplateJsonCfg
 	Request_fields      *[]*FcTemplateJsonCfg
 	Reply_fields        *[]*FcTemplateJsonCfg
 }
@@ -375,12 +403,15 @@ type RAReqProcessorJsnCfg struct {
 
 // Conecto Agent configuration section
 type HttpAgentJsonCfg struct {
-	Id                 *string
-	Url                *string
-	Sessions_conns     *[]*HaPoolJsonCfg
-	Request_payload    *string
-	Reply_payload      *string
-	Request_processors *[]*HttpAgentProcessorJsnCfg
+	Id                   *string
+	Url                  *string
+	Sessions_conns       *[]*HaPoolJsonCfg
+	Request_payload      *string
+	Reply_payload        *string
+	Session_key          *string
+	Session_idle_timeout *string
+	Disconnect_url       *string
+	Request_processors   *[]*HttpAgentProcessorJsnCfg
 }
 
 type HttpAgentProcessorJsnCfg struct {
@@ -394,6 +425,52 @@ type HttpAgentProcessorJsnCfg struct {
 	Reply_fields        *[]*FcTemplateJsonCfg
 }
 
+// SIPAgent config section
+type SIPAgentJsonCfg struct {
+	Enabled            *bool
+	Listen             *string
+	Listen_net         *string
+	Sessions_conns     *[]*HaPoolJsonCfg
+	Templates          map[string][]*FcTemplateJsonCfg
+	Request_processors *[]*SIPReqProcessorJsnCfg
+}
+
+type SIPReqProcessorJsnCfg struct {
+	Id                  *string
+	Filters             *[]string
+	Tenant              *string
+	Timezone            *string
+	Flags               *[]string
+	Continue_on_success *bool
+	Request_fields      *[]*FcTemplateJsonCfg
+	Reply_fields        *[]*FcTemplateJsonCfg
+}
+
+// SMPPAgent config section
+type SMPPAgentJsonCfg struct {
+	Enabled               *bool
+	Listen                *string
+	Smsc_address          *string
+	System_id             *string
+	Password              *string
+	System_type           *string
+	Enquire_link_interval *string
+	Sessions_conns        *[]*HaPoolJsonCfg
+	Templates             map[string][]*FcTemplateJsonCfg
+	Request_processors    *[]*SMPPReqProcessorJsnCfg
+}
+
+type SMPPReqProcessorJsnCfg struct {
+	Id                  *string
+	Filters             *[]string
+	Tenant              *string
+	Timezone            *string
+	Flags               *[]string
+	Continue_on_success *bool
+	Request_fields      *[]*FcTemplateJsonCfg
+	Reply_fields        *[]*FcTemplateJsonCfg
+}
+
 // History server config section
 type HistServJsonCfg struct {
 	Enabled       *bool
@@ -407,6 +484,7 @@ type AttributeSJsonCfg struct {
 	String_indexed_fields *[]string
 	Prefix_indexed_fields *[]string
 	Process_runs          *int
+	Numbers_conns         *[]*HaPoolJsonCfg
 }
 
 // ChargerSJsonCfg service config section
@@ -417,6 +495,23 @@ type ChargerSJsonCfg struct {
 	Prefix_indexed_fields *[]string
 }
 
+// NumberSJsonCfg service config section
+type NumberSJsonCfg struct {
+	Enabled            *bool
+	Normalize_fields   *[]string
+	Portability_fields *[]string
+	Dial_plans         *[]*DialPlanJsonCfg
+}
+
+// DialPlanJsonCfg describes one country dial plan
+type DialPlanJsonCfg struct {
+	Id                   *string
+	Filters              *[]string
+	Country_code         *string
+	National_prefix      *string
+	International_prefix *string
+}
+
 // ResourceLimiter service config section
 type ResourceSJsonCfg struct {
 	Enabled               *bool
@@ -465,6 +560,7 @@ type LoaderJsonCfg struct {
 	Enabled         *bool
 	Tenant          *string
 	Dry_run         *bool
+	Activation_time *string
 	Run_delay       *int
 	Lock_filename   *string
 	Caches_conns    *[]*HaPoolJsonCfg
@@ -517,6 +613,7 @@ type DispatcherSJsonCfg struct {
 	String_indexed_fields *[]string
 	Prefix_indexed_fields *[]string
 	Attributes_conns      *[]*HaPoolJsonCfg
+	Thresholds_conns      *[]*HaPoolJsonCfg
 	Conns                 *map[string]*[]*HaPoolJsonCfg
 }
 
@@ -573,3 +670,19 @@ type FcTemplateJsonCfg struct {
 type AnalyzerSJsonCfg struct {
 	Enabled *bool
 }
+
+// Event bus json config section
+type EventBusJsonCfg struct {
+	Queue_size        *int
+	Overflow_strategy *string
+	Persist_dir       *string
+}
+
+// Account exporter json config section
+type AccountExporterJsonCfg struct {
+	Enabled     *bool
+	Filters     *[]string
+	Export_path *string
+	Transport   *string
+	Attempts    *int
+}
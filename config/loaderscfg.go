@@ -35,6 +35,7 @@ type LoaderSCfg struct {
 	Enabled        bool
 	Tenant         RSRParsers
 	DryRun         bool
+	ActivationTime string // stage the loaded data and switch it live at this time, empty to load it live
 	RunDelay       time.Duration
 	LockFileName   string
 	CacheSConns    []*HaPoolConfig
@@ -94,6 +95,9 @@ func (self *LoaderSCfg) loadFromJsonCfg(jsnCfg *LoaderJsonCfg, separator string)
 	if jsnCfg.Dry_run != nil {
 		self.DryRun = *jsnCfg.Dry_run
 	}
+	if jsnCfg.Activation_time != nil {
+		self.ActivationTime = *jsnCfg.Activation_time
+	}
 	if jsnCfg.Run_delay != nil {
 		self.RunDelay = time.Duration(*jsnCfg.Run_delay) * time.Second
 	}
@@ -136,6 +140,7 @@ func (self *LoaderSCfg) Clone() *LoaderSCfg {
 	clnLoader.Enabled = self.Enabled
 	clnLoader.Tenant = self.Tenant
 	clnLoader.DryRun = self.DryRun
+	clnLoader.ActivationTime = self.ActivationTime
 	clnLoader.RunDelay = self.RunDelay
 	clnLoader.LockFileName = self.LockFileName
 	clnLoader.CacheSConns = make([]*HaPoolConfig, len(self.CacheSConns))
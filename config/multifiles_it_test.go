@@ -119,6 +119,7 @@ func TestMfEnvReaderITRead(t *testing.T) {
 		ResponseCacheTTL:  time.Duration(0),
 		InternalTtl:       time.Duration(2 * time.Minute),
 		LockingTimeout:    time.Duration(0),
+		LoadHistorySize:   10,
 		DigestSeparator:   ",",
 		DigestEqual:       ":",
 		RsrSepatarot:      ";",
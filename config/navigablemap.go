@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -366,3 +367,73 @@ func (nM *NavigableMap) AsXMLElements() (ents []*XMLElement, err error) {
 	}
 	return
 }
+
+// AsJSONMap returns the values as layered map[string]interface{} which can be later marshaled into a JSON object
+// considers each value in the form of []*NMItem, otherwise errors
+// multiple items on the same path are exported as JSON array
+func (nM *NavigableMap) AsJSONMap() (mp map[string]interface{}, err error) {
+	mp = make(map[string]interface{})
+	if len(nM.order) == 0 {
+		indexMapPaths(nM.data, nil, &nM.order)
+	}
+	seen := make(map[string]bool) // the same path can be ordered multiple times
+	for _, path := range nM.order {
+		pathStr := strings.Join(path, utils.NestingSep)
+		if seen[pathStr] {
+			continue
+		}
+		seen[pathStr] = true
+		val, _ := nM.FieldAsInterface(path)
+		nmItms, isNMItems := val.([]*NMItem)
+		if !isNMItems {
+			return nil, fmt.Errorf("value: %+v is not []*NMItem", val)
+		}
+		lastMp := mp
+		for _, spath := range path[:len(path)-1] {
+			elmnt, has := lastMp[spath]
+			if !has {
+				elmnt = make(map[string]interface{})
+				lastMp[spath] = elmnt
+			}
+			var canCast bool
+			if lastMp, canCast = elmnt.(map[string]interface{}); !canCast {
+				return nil, fmt.Errorf("cannot add field with path: <%s> under value: %s",
+					pathStr, utils.ToJSON(elmnt))
+			}
+		}
+		lastKey := path[len(path)-1]
+		for _, nmItm := range nmItms {
+			if prevVal, has := lastMp[lastKey]; !has {
+				lastMp[lastKey] = nmItm.Data
+			} else if prevVals, isSlice := prevVal.([]interface{}); isSlice {
+				lastMp[lastKey] = append(prevVals, nmItm.Data)
+			} else {
+				lastMp[lastKey] = []interface{}{prevVal, nmItm.Data}
+			}
+		}
+	}
+	return
+}
+
+// Remove deletes the branch with the path specified, together with its order information
+func (nM *NavigableMap) Remove(path []string) {
+	if len(path) == 0 {
+		return
+	}
+	mp := nM.data
+	for _, spath := range path[:len(path)-1] {
+		var canCast bool
+		if mp, canCast = mp[spath].(map[string]interface{}); !canCast {
+			return
+		}
+	}
+	delete(mp, path[len(path)-1])
+	order := nM.order[:0]
+	for _, ordPath := range nM.order {
+		if len(ordPath) < len(path) ||
+			!reflect.DeepEqual(ordPath[:len(path)], path) {
+			order = append(order, ordPath)
+		}
+	}
+	nM.order = order
+}
@@ -873,3 +873,54 @@ func TestNavMapMerge(t *testing.T) {
 		t.Errorf("expecting: %+v, received: %+v", nM2, nM)
 	}
 }
+
+func TestNavMapAsJSONMap(t *testing.T) {
+	nM := NewNavigableMap(nil)
+	nM.Set([]string{"Result", "Code"}, []*NMItem{
+		&NMItem{Path: []string{"Result", "Code"}, Data: "200"}}, false, true)
+	nM.Set([]string{"Result", "Message"}, []*NMItem{
+		&NMItem{Path: []string{"Result", "Message"}, Data: "OK"}}, false, true)
+	nM.Set([]string{"MaxUsage"}, []*NMItem{
+		&NMItem{Path: []string{"MaxUsage"}, Data: 10}}, false, true)
+	nM.Set([]string{"Suppliers"}, []*NMItem{
+		&NMItem{Path: []string{"Suppliers"}, Data: "supplier1"}}, true, true)
+	nM.Set([]string{"Suppliers"}, []*NMItem{
+		&NMItem{Path: []string{"Suppliers"}, Data: "supplier2"}}, true, true)
+	eMp := map[string]interface{}{
+		"Result": map[string]interface{}{
+			"Code":    "200",
+			"Message": "OK",
+		},
+		"MaxUsage":  10,
+		"Suppliers": []interface{}{"supplier1", "supplier2"},
+	}
+	if mp, err := nM.AsJSONMap(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eMp, mp) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eMp), utils.ToJSON(mp))
+	}
+	nM.Set([]string{"MaxUsage", "Unit"}, []*NMItem{
+		&NMItem{Path: []string{"MaxUsage", "Unit"}, Data: "s"}}, false, true)
+	if _, err := nM.AsJSONMap(); err == nil {
+		t.Error("expecting error for field under value")
+	}
+}
+
+func TestNavMapRemove(t *testing.T) {
+	nM := NewNavigableMap(nil)
+	nM.Set([]string{utils.MetaHdr, "Content-Type"}, []*NMItem{
+		&NMItem{Path: []string{utils.MetaHdr, "Content-Type"}, Data: "text/plain"}}, false, true)
+	nM.Set([]string{"Result"}, []*NMItem{
+		&NMItem{Path: []string{"Result"}, Data: "OK"}}, false, true)
+	nM.Remove([]string{utils.MetaHdr})
+	if _, err := nM.FieldAsInterface([]string{utils.MetaHdr, "Content-Type"}); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if eOrder := [][]string{{"Result"}}; !reflect.DeepEqual(eOrder, nM.order) {
+		t.Errorf("expecting: %+v, received: %+v", eOrder, nM.order)
+	}
+	nM.Remove([]string{"Missing", "Field"})
+	if len(nM.Values()) != 1 {
+		t.Errorf("unexpected values: %s", utils.ToJSON(nM.Values()))
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+// DialPlanCfg describes how the numbers of one country are dialed
+// so they can be normalized to E.164
+type DialPlanCfg struct {
+	ID                  string
+	FilterIDs           []string // the dial plan applies to the events matching these filters
+	CountryCode         string
+	NationalPrefix      string // replaced with the country code
+	InternationalPrefix string // removed from the numbers dialed internationally
+}
+
+func (dp *DialPlanCfg) loadFromJsonCfg(jsnCfg *DialPlanJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Id != nil {
+		dp.ID = *jsnCfg.Id
+	}
+	if jsnCfg.Filters != nil {
+		dp.FilterIDs = make([]string, len(*jsnCfg.Filters))
+		for i, fltr := range *jsnCfg.Filters {
+			dp.FilterIDs[i] = fltr
+		}
+	}
+	if jsnCfg.Country_code != nil {
+		dp.CountryCode = *jsnCfg.Country_code
+	}
+	if jsnCfg.National_prefix != nil {
+		dp.NationalPrefix = *jsnCfg.National_prefix
+	}
+	if jsnCfg.International_prefix != nil {
+		dp.InternationalPrefix = *jsnCfg.International_prefix
+	}
+	return
+}
+
+// NumberSCfg is the configuration of number service
+type NumberSCfg struct {
+	Enabled           bool
+	NormalizeFields   []string
+	PortabilityFields []string
+	DialPlans         []*DialPlanCfg
+}
+
+func (nS *NumberSCfg) loadFromJsonCfg(jsnCfg *NumberSJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Enabled != nil {
+		nS.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Normalize_fields != nil {
+		nS.NormalizeFields = make([]string, len(*jsnCfg.Normalize_fields))
+		for i, fldName := range *jsnCfg.Normalize_fields {
+			nS.NormalizeFields[i] = fldName
+		}
+	}
+	if jsnCfg.Portability_fields != nil {
+		nS.PortabilityFields = make([]string, len(*jsnCfg.Portability_fields))
+		for i, fldName := range *jsnCfg.Portability_fields {
+			nS.PortabilityFields[i] = fldName
+		}
+	}
+	if jsnCfg.Dial_plans != nil {
+		nS.DialPlans = make([]*DialPlanCfg, len(*jsnCfg.Dial_plans))
+		for i, jsnDP := range *jsnCfg.Dial_plans {
+			nS.DialPlans[i] = new(DialPlanCfg)
+			if err = nS.DialPlans[i].loadFromJsonCfg(jsnDP); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,107 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestNumberSCfgloadFromJsonCfg(t *testing.T) {
+	var numscfg, expected NumberSCfg
+	if err := numscfg.loadFromJsonCfg(nil); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(numscfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, numscfg)
+	}
+	if err := numscfg.loadFromJsonCfg(new(NumberSJsonCfg)); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(numscfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, numscfg)
+	}
+	cfgJSONStr := `{
+"numbers": {
+	"enabled": true,
+	"normalize_fields": ["Destination"],
+	"portability_fields": ["Destination"],
+	"dial_plans": [
+		{
+			"id": "DE",
+			"filters": ["*string:Tenant:cgrates.de"],
+			"country_code": "49",
+			"national_prefix": "0",
+			"international_prefix": "00",
+		},
+	],
+},
+}`
+	expected = NumberSCfg{
+		Enabled:           true,
+		NormalizeFields:   []string{"Destination"},
+		PortabilityFields: []string{"Destination"},
+		DialPlans: []*DialPlanCfg{
+			{
+				ID:                  "DE",
+				FilterIDs:           []string{"*string:Tenant:cgrates.de"},
+				CountryCode:         "49",
+				NationalPrefix:      "0",
+				InternationalPrefix: "00",
+			},
+		},
+	}
+	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
+		t.Error(err)
+	} else if jsnNumSCfg, err := jsnCfg.NumberServJsonCfg(); err != nil {
+		t.Error(err)
+	} else if err = numscfg.loadFromJsonCfg(jsnNumSCfg); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, numscfg) {
+		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(numscfg))
+	}
+}
+
+func TestNumberSCfgSanity(t *testing.T) {
+	if cfg, err := NewCGRConfigFromJsonStringWithDefaults(`{
+"numbers": {
+	"enabled": true,
+	"dial_plans": [
+		{"id": "DE", "country_code": "+49", "national_prefix": "0"},
+	],
+},
+}`); err != nil {
+		t.Error(err)
+	} else if err := cfg.checkConfigSanity(); err == nil {
+		t.Error("expecting error on non numeric country_code")
+	}
+	if cfg, err := NewCGRConfigFromJsonStringWithDefaults(`{
+"attributes": {
+	"enabled": true,
+	"numbers_conns": [
+		{"address": "*internal"},
+	],
+},
+}`); err != nil {
+		t.Error(err)
+	} else if err := cfg.checkConfigSanity(); err == nil {
+		t.Error("expecting error on NumberS not enabled")
+	}
+}
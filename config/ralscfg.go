@@ -31,6 +31,10 @@ type RalsCfg struct {
 	RALsStatSConns          []*HaPoolConfig
 	RpSubjectPrefixMatching bool // enables prefix matching for the rating profile subject
 	RemoveExpired           bool
+	BalanceHistory          bool          // record every balance change into StorDB
+	ExpiryScanInterval      time.Duration // scan the accounts for expiring balances, 0 to disable
+	ExpiryNotifyBefore      time.Duration // notify the balances expiring within this interval
+	ExpiryGracePeriod       time.Duration // expired balances can still be restored by a top-up within this interval
 	RALsMaxComputedUsage    map[string]time.Duration
 }
 
@@ -62,6 +66,24 @@ func (ralsCfg *RalsCfg) loadFromJsonCfg(jsnRALsCfg *RalsJsonCfg) (err error) {
 	if jsnRALsCfg.Remove_expired != nil {
 		ralsCfg.RemoveExpired = *jsnRALsCfg.Remove_expired
 	}
+	if jsnRALsCfg.Balance_history != nil {
+		ralsCfg.BalanceHistory = *jsnRALsCfg.Balance_history
+	}
+	if jsnRALsCfg.Expiry_scan_interval != nil {
+		if ralsCfg.ExpiryScanInterval, err = utils.ParseDurationWithNanosecs(*jsnRALsCfg.Expiry_scan_interval); err != nil {
+			return
+		}
+	}
+	if jsnRALsCfg.Expiry_notify_before != nil {
+		if ralsCfg.ExpiryNotifyBefore, err = utils.ParseDurationWithNanosecs(*jsnRALsCfg.Expiry_notify_before); err != nil {
+			return
+		}
+	}
+	if jsnRALsCfg.Expiry_grace_period != nil {
+		if ralsCfg.ExpiryGracePeriod, err = utils.ParseDurationWithNanosecs(*jsnRALsCfg.Expiry_grace_period); err != nil {
+			return
+		}
+	}
 	if jsnRALsCfg.Max_computed_usage != nil {
 		for k, v := range *jsnRALsCfg.Max_computed_usage {
 			if ralsCfg.RALsMaxComputedUsage[k], err = utils.ParseDurationWithNanosecs(v); err != nil {
@@ -45,6 +45,8 @@ func TestRalsCfgFromJsonCfg(t *testing.T) {
 	"stats_conns": [],						// address where to reach the stat service, empty to disable stats functionality: <""|*internal|x.y.z.y:1234>
 	"users_conns": [],						// address where to reach the user service, empty to disable user profile functionality: <""|*internal|x.y.z.y:1234>
 	"rp_subject_prefix_matching": false,	// enables prefix matching for the rating profile subject
+	"expiry_scan_interval": "1h",
+	"expiry_grace_period": "72h",
 	"max_computed_usage": {					// do not compute usage higher than this, prevents memory overload
 		"*any": "189h",
 		"*voice": "72h",
@@ -59,6 +61,8 @@ func TestRalsCfgFromJsonCfg(t *testing.T) {
 		RALsThresholdSConns:     []*HaPoolConfig{},
 		RALsStatSConns:          []*HaPoolConfig{},
 		RpSubjectPrefixMatching: false,
+		ExpiryScanInterval:      time.Duration(time.Hour),
+		ExpiryGracePeriod:       time.Duration(72 * time.Hour),
 		RALsMaxComputedUsage: map[string]time.Duration{
 			utils.ANY:   time.Duration(189 * time.Hour),
 			utils.VOICE: time.Duration(72 * time.Hour),
@@ -19,8 +19,9 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package config
 
 type SchedulerCfg struct {
-	Enabled   bool
-	CDRsConns []*HaPoolConfig
+	Enabled       bool
+	CDRsConns     []*HaPoolConfig
+	SessionSConns []*HaPoolConfig
 }
 
 func (schdcfg *SchedulerCfg) loadFromJsonCfg(jsnCfg *SchedulerJsonCfg) error {
@@ -38,5 +39,12 @@ func (schdcfg *SchedulerCfg) loadFromJsonCfg(jsnCfg *SchedulerJsonCfg) error {
 			schdcfg.CDRsConns[idx].loadFromJsonCfg(jsnHaCfg)
 		}
 	}
+	if jsnCfg.Sessions_conns != nil {
+		schdcfg.SessionSConns = make([]*HaPoolConfig, len(*jsnCfg.Sessions_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Sessions_conns {
+			schdcfg.SessionSConns[idx] = NewDfltHaPoolConfig()
+			schdcfg.SessionSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
 	return nil
 }
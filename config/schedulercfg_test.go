@@ -21,6 +21,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestSchedulerCfgloadFromJsonCfg(t *testing.T) {
@@ -39,11 +41,13 @@ func TestSchedulerCfgloadFromJsonCfg(t *testing.T) {
 "scheduler": {
 	"enabled": true,				// start Scheduler service: <true|false>
 	"cdrs_conns": [],				// address where to reach CDR Server, empty to disable CDR capturing <*internal|x.y.z.y:1234>
+	"sessions_conns": [{"address": "*internal"}],
 	},
 }`
 	expected = SchedulerCfg{
-		Enabled:   true,
-		CDRsConns: []*HaPoolConfig{},
+		Enabled:       true,
+		CDRsConns:     []*HaPoolConfig{},
+		SessionSConns: []*HaPoolConfig{{Address: utils.MetaInternal}},
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
 		t.Error(err)
@@ -0,0 +1,97 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSIPAgentCfgloadFromJsonCfg(t *testing.T) {
+	var sacfg, expected SIPAgentCfg
+	if err := sacfg.loadFromJsonCfg(nil, utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(sacfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, sacfg)
+	}
+	if err := sacfg.loadFromJsonCfg(new(SIPAgentJsonCfg), utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(sacfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, sacfg)
+	}
+	cfgJSONStr := `{
+"sip_agent": {
+	"enabled": true,
+	"listen": "127.0.0.1:5070",
+	"listen_net": "tcp",
+	"sessions_conns": [
+		{"address": "*internal"}
+	],
+	"request_processors": [
+		{
+			"id": "redirect",
+			"filters": ["*string:*vars.*sipMethod:INVITE"],
+			"flags": ["*auth", "*suppliers"],
+			"continue_on_success": true,
+		},
+	],
+},
+}`
+	expected = SIPAgentCfg{
+		Enabled:       true,
+		Listen:        "127.0.0.1:5070",
+		ListenNet:     "tcp",
+		SessionSConns: []*HaPoolConfig{{Address: "*internal"}},
+		RequestProcessors: []*SIPReqProcessor{
+			{
+				ID:                "redirect",
+				Filters:           []string{"*string:*vars.*sipMethod:INVITE"},
+				Flags:             utils.StringMap{"*auth": true, "*suppliers": true},
+				ContinueOnSuccess: true,
+			},
+		},
+	}
+	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
+		t.Error(err)
+	} else if jsnSaCfg, err := jsnCfg.SIPAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if err = sacfg.loadFromJsonCfg(jsnSaCfg, utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, sacfg) {
+		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(sacfg))
+	}
+	// processors with the same ID are updated instead of appended
+	jsnProcs := &[]*SIPReqProcessorJsnCfg{
+		{
+			Id:       utils.StringPointer("redirect"),
+			Timezone: utils.StringPointer("UTC"),
+		},
+	}
+	if err := sacfg.loadFromJsonCfg(&SIPAgentJsonCfg{Request_processors: jsnProcs},
+		utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if len(sacfg.RequestProcessors) != 1 {
+		t.Errorf("received processors: %s", utils.ToJSON(sacfg.RequestProcessors))
+	} else if sacfg.RequestProcessors[0].Timezone != "UTC" {
+		t.Errorf("received timezone: <%s>", sacfg.RequestProcessors[0].Timezone)
+	}
+}
@@ -39,6 +39,7 @@ type HaPoolConfig struct {
 	Transport   string
 	Synchronous bool
 	Tls         bool
+	Async       bool // requests are queued on the event bus instead of waiting for the reply
 }
 
 func (self *HaPoolConfig) loadFromJsonCfg(jsnCfg *HaPoolJsonCfg) error {
@@ -57,6 +58,9 @@ func (self *HaPoolConfig) loadFromJsonCfg(jsnCfg *HaPoolJsonCfg) error {
 	if jsnCfg.Tls != nil {
 		self.Tls = *jsnCfg.Synchronous
 	}
+	if jsnCfg.Async != nil {
+		self.Async = *jsnCfg.Async
+	}
 	return nil
 }
 
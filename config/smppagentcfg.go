@@ -0,0 +1,157 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SMPPAgentCfg is the configuration of the SMPP agent
+type SMPPAgentCfg struct {
+	Enabled             bool   // enables the SMPP agent: <true|false>
+	Listen              string // address where to accept ESME binds as SMSC, empty to disable
+	SMSCAddress         string // address of the SMSC to bind to as ESME receiver, empty to disable
+	SystemID            string // system_id expected on binds or sent when binding to the SMSC
+	Password            string // password expected on binds or sent when binding to the SMSC
+	SystemType          string // system_type sent when binding to the SMSC
+	EnquireLinkInterval time.Duration
+	SessionSConns       []*HaPoolConfig
+	Templates           map[string][]*FCTemplate
+	RequestProcessors   []*SMPPReqProcessor
+}
+
+func (sa *SMPPAgentCfg) loadFromJsonCfg(jsnCfg *SMPPAgentJsonCfg, separator string) (err error) {
+	if jsnCfg == nil {
+		return nil
+	}
+	if jsnCfg.Enabled != nil {
+		sa.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Listen != nil {
+		sa.Listen = *jsnCfg.Listen
+	}
+	if jsnCfg.Smsc_address != nil {
+		sa.SMSCAddress = *jsnCfg.Smsc_address
+	}
+	if jsnCfg.System_id != nil {
+		sa.SystemID = *jsnCfg.System_id
+	}
+	if jsnCfg.Password != nil {
+		sa.Password = *jsnCfg.Password
+	}
+	if jsnCfg.System_type != nil {
+		sa.SystemType = *jsnCfg.System_type
+	}
+	if jsnCfg.Enquire_link_interval != nil {
+		if sa.EnquireLinkInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Enquire_link_interval); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Sessions_conns != nil {
+		sa.SessionSConns = make([]*HaPoolConfig, len(*jsnCfg.Sessions_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Sessions_conns {
+			sa.SessionSConns[idx] = NewDfltHaPoolConfig()
+			sa.SessionSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	if jsnCfg.Templates != nil {
+		if sa.Templates == nil {
+			sa.Templates = make(map[string][]*FCTemplate)
+		}
+		for k, jsnTpls := range jsnCfg.Templates {
+			if sa.Templates[k], err = FCTemplatesFromFCTemplatesJsonCfg(jsnTpls, separator); err != nil {
+				return
+			}
+		}
+	}
+	if jsnCfg.Request_processors != nil {
+		for _, reqProcJsn := range *jsnCfg.Request_processors {
+			rp := new(SMPPReqProcessor)
+			var haveID bool
+			for _, rpSet := range sa.RequestProcessors {
+				if reqProcJsn.Id != nil && rpSet.ID == *reqProcJsn.Id {
+					rp = rpSet // Will load data into the one set
+					haveID = true
+					break
+				}
+			}
+			if err = rp.loadFromJsonCfg(reqProcJsn, separator); err != nil {
+				return
+			}
+			if !haveID {
+				sa.RequestProcessors = append(sa.RequestProcessors, rp)
+			}
+		}
+	}
+	return nil
+}
+
+// SMPPReqProcessor is one SMPP request processor configuration
+type SMPPReqProcessor struct {
+	ID                string
+	Tenant            RSRParsers
+	Filters           []string
+	Flags             utils.StringMap
+	Timezone          string // timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
+	ContinueOnSuccess bool
+	RequestFields     []*FCTemplate
+	ReplyFields       []*FCTemplate
+}
+
+func (sp *SMPPReqProcessor) loadFromJsonCfg(jsnCfg *SMPPReqProcessorJsnCfg, separator string) (err error) {
+	if jsnCfg == nil {
+		return nil
+	}
+	if jsnCfg.Id != nil {
+		sp.ID = *jsnCfg.Id
+	}
+	if jsnCfg.Tenant != nil {
+		if sp.Tenant, err = NewRSRParsers(*jsnCfg.Tenant, true, separator); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Filters != nil {
+		sp.Filters = make([]string, len(*jsnCfg.Filters))
+		for i, fltr := range *jsnCfg.Filters {
+			sp.Filters[i] = fltr
+		}
+	}
+	if jsnCfg.Flags != nil {
+		sp.Flags = utils.StringMapFromSlice(*jsnCfg.Flags)
+	}
+	if jsnCfg.Timezone != nil {
+		sp.Timezone = *jsnCfg.Timezone
+	}
+	if jsnCfg.Continue_on_success != nil {
+		sp.ContinueOnSuccess = *jsnCfg.Continue_on_success
+	}
+	if jsnCfg.Request_fields != nil {
+		if sp.RequestFields, err = FCTemplatesFromFCTemplatesJsonCfg(*jsnCfg.Request_fields, separator); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Reply_fields != nil {
+		if sp.ReplyFields, err = FCTemplatesFromFCTemplatesJsonCfg(*jsnCfg.Reply_fields, separator); err != nil {
+			return
+		}
+	}
+	return nil
+}
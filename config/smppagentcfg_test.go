@@ -0,0 +1,94 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSMPPAgentCfgloadFromJsonCfg(t *testing.T) {
+	var sacfg, expected SMPPAgentCfg
+	if err := sacfg.loadFromJsonCfg(nil, utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(sacfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, sacfg)
+	}
+	if err := sacfg.loadFromJsonCfg(new(SMPPAgentJsonCfg), utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(sacfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, sacfg)
+	}
+	cfgJSONStr := `{
+"smpp_agent": {
+	"enabled": true,
+	"listen": "",
+	"smsc_address": "127.0.0.1:2776",
+	"system_id": "cgr_esme",
+	"password": "secret",
+	"system_type": "OCS",
+	"enquire_link_interval": "1m",
+	"sessions_conns": [
+		{"address": "*internal"}
+	],
+	"request_processors": [
+		{
+			"id": "sms",
+			"filters": ["*string:*vars.*smppCmd:submit_sm"],
+			"flags": ["*event", "*accounts"],
+			"continue_on_success": true,
+		},
+	],
+},
+}`
+	expected = SMPPAgentCfg{
+		Enabled:             true,
+		SMSCAddress:         "127.0.0.1:2776",
+		SystemID:            "cgr_esme",
+		Password:            "secret",
+		SystemType:          "OCS",
+		EnquireLinkInterval: time.Minute,
+		SessionSConns:       []*HaPoolConfig{{Address: "*internal"}},
+		RequestProcessors: []*SMPPReqProcessor{
+			{
+				ID:                "sms",
+				Filters:           []string{"*string:*vars.*smppCmd:submit_sm"},
+				Flags:             utils.StringMap{"*event": true, "*accounts": true},
+				ContinueOnSuccess: true,
+			},
+		},
+	}
+	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
+		t.Error(err)
+	} else if jsnSaCfg, err := jsnCfg.SMPPAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if err = sacfg.loadFromJsonCfg(jsnSaCfg, utils.INFIELD_SEP); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, sacfg) {
+		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(sacfg))
+	}
+	if err := sacfg.loadFromJsonCfg(&SMPPAgentJsonCfg{
+		Enquire_link_interval: utils.StringPointer("invalid")}, utils.INFIELD_SEP); err == nil {
+		t.Error("expecting error on invalid enquire_link_interval")
+	}
+}
@@ -56,6 +56,8 @@ func (self *CmdApierPing) RpcMethod() string {
 		return utils.AttributeSv1Ping
 	case utils.ChargerSLow:
 		return utils.ChargerSv1Ping
+	case utils.NumberSLow:
+		return utils.NumberSv1Ping
 	case utils.ResourcesLow:
 		return utils.ResourceSv1Ping
 	case utils.StatServiceLow:
@@ -50,3 +50,27 @@ CREATE TABLE session_costs (
   KEY run_origin_idx (run_id, origin_id),
   KEY deleted_at_idx (deleted_at)
 );
+
+--
+-- Table structure for table `balance_history`
+--
+
+DROP TABLE IF EXISTS balance_history;
+CREATE TABLE balance_history (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  tenant varchar(64) NOT NULL,
+  account varchar(128) NOT NULL,
+  balance_uuid varchar(64) NOT NULL,
+  balance_id varchar(128) NOT NULL,
+  balance_type varchar(16) NOT NULL,
+  reason varchar(64) NOT NULL,
+  cgrid varchar(40) NOT NULL,
+  action_id varchar(64) NOT NULL,
+  action_plan_id varchar(64) NOT NULL,
+  old_value DOUBLE NOT NULL,
+  new_value DOUBLE NOT NULL,
+  movement_time TIMESTAMP(6) NULL,
+  PRIMARY KEY (`id`),
+  KEY account_time_idx (tenant, account, movement_time),
+  KEY cgrid_idx (cgrid)
+);
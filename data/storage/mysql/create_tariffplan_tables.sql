@@ -391,11 +391,13 @@ CREATE TABLE tp_attributes (
   `append` BOOLEAN NOT NULL,
   `blocker` BOOLEAN NOT NULL,
   `weight` decimal(8,2) NOT NULL,
+  `attribute_filter_ids` varchar(64) NOT NULL,
+  `type` varchar(64) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`pk`),
   KEY `tpid` (`tpid`),
   UNIQUE KEY `unique_tp_attributes` (`tpid`,`tenant`,
-    `id`,`filter_ids`,`field_name`,`initial`,`substitute` )
+    `id`,`filter_ids`,`attribute_filter_ids`,`field_name`,`initial`,`substitute` )
 );
 
 --
@@ -57,3 +57,28 @@ DROP INDEX IF EXISTS run_origin_sessionscost_idx;
 CREATE INDEX run_origin_sessionscost_idx ON session_costs (run_id, origin_id);
 DROP INDEX IF EXISTS deleted_at_sessionscost_idx;
 CREATE INDEX deleted_at_sessionscost_idx ON session_costs (deleted_at);
+
+--
+-- Table structure for table `balance_history`
+--
+
+DROP TABLE IF EXISTS balance_history;
+CREATE TABLE balance_history (
+  id SERIAL PRIMARY KEY,
+  tenant VARCHAR(64) NOT NULL,
+  account VARCHAR(128) NOT NULL,
+  balance_uuid VARCHAR(64) NOT NULL,
+  balance_id VARCHAR(128) NOT NULL,
+  balance_type VARCHAR(16) NOT NULL,
+  reason VARCHAR(64) NOT NULL,
+  cgrid VARCHAR(40) NOT NULL,
+  action_id VARCHAR(64) NOT NULL,
+  action_plan_id VARCHAR(64) NOT NULL,
+  old_value DOUBLE PRECISION NOT NULL,
+  new_value DOUBLE PRECISION NOT NULL,
+  movement_time TIMESTAMP WITH TIME ZONE
+);
+DROP INDEX IF EXISTS account_time_balancehistory_idx;
+CREATE INDEX account_time_balancehistory_idx ON balance_history (tenant, account, movement_time);
+DROP INDEX IF EXISTS cgrid_balancehistory_idx;
+CREATE INDEX cgrid_balancehistory_idx ON balance_history (cgrid);
@@ -382,11 +382,13 @@ CREATE INDEX tp_suppliers_unique ON tp_suppliers  ("tpid",  "tenant", "id",
     "append" BOOLEAN NOT NULL,
     "blocker" BOOLEAN NOT NULL,
     "weight" decimal(8,2) NOT NULL,
+    "attribute_filter_ids" varchar(64) NOT NULL,
+    "type" varchar(64) NOT NULL,
     "created_at" TIMESTAMP WITH TIME ZONE
   );
   CREATE INDEX tp_attributes_ids ON tp_attributes (tpid);
   CREATE INDEX tp_attributes_unique ON tp_attributes  ("tpid",  "tenant", "id",
-    "filter_ids","field_name","initial","substitute");
+    "filter_ids","attribute_filter_ids","field_name","initial","substitute");
 
   --
   -- Table structure for table `tp_chargers`
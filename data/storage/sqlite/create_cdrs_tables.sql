@@ -0,0 +1,83 @@
+--
+-- Table structure for table `cdrs`
+--
+
+DROP TABLE IF EXISTS cdrs;
+CREATE TABLE cdrs (
+ id INTEGER PRIMARY KEY AUTOINCREMENT,
+ cgrid VARCHAR(40) NOT NULL,
+ run_id VARCHAR(64) NOT NULL,
+ origin_host VARCHAR(64) NOT NULL,
+ source VARCHAR(64) NOT NULL,
+ origin_id VARCHAR(128) NOT NULL,
+ tor VARCHAR(16) NOT NULL,
+ request_type VARCHAR(24) NOT NULL,
+ tenant VARCHAR(64) NOT NULL,
+ category VARCHAR(64) NOT NULL,
+ account VARCHAR(128) NOT NULL,
+ subject VARCHAR(128) NOT NULL,
+ destination VARCHAR(128) NOT NULL,
+ setup_time DATETIME NOT NULL,
+ answer_time DATETIME NOT NULL,
+ usage BIGINT NOT NULL,
+ extra_fields TEXT NOT NULL,
+ cost_source VARCHAR(64) NOT NULL,
+ cost NUMERIC(20,4) DEFAULT NULL,
+ cost_details TEXT,
+ extra_info text,
+ created_at DATETIME,
+ updated_at DATETIME NULL,
+ deleted_at DATETIME NULL,
+ UNIQUE (cgrid, run_id, origin_id)
+);
+DROP INDEX IF EXISTS deleted_at_cp_idx;
+CREATE INDEX deleted_at_cp_idx ON cdrs (deleted_at);
+
+
+DROP TABLE IF EXISTS session_costs;
+CREATE TABLE session_costs (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  cgrid VARCHAR(40) NOT NULL,
+  run_id  VARCHAR(64) NOT NULL,
+  origin_host VARCHAR(64) NOT NULL,
+  origin_id VARCHAR(128) NOT NULL,
+  cost_source VARCHAR(64) NOT NULL,
+  usage BIGINT NOT NULL,
+  cost_details TEXT,
+  created_at DATETIME,
+  deleted_at DATETIME NULL,
+  UNIQUE (cgrid, run_id)
+);
+DROP INDEX IF EXISTS cgrid_sessionscost_idx;
+CREATE INDEX cgrid_sessionscost_idx ON session_costs (cgrid, run_id);
+DROP INDEX IF EXISTS origin_sessionscost_idx;
+CREATE INDEX origin_sessionscost_idx ON session_costs (origin_host, origin_id);
+DROP INDEX IF EXISTS run_origin_sessionscost_idx;
+CREATE INDEX run_origin_sessionscost_idx ON session_costs (run_id, origin_id);
+DROP INDEX IF EXISTS deleted_at_sessionscost_idx;
+CREATE INDEX deleted_at_sessionscost_idx ON session_costs (deleted_at);
+
+--
+-- Table structure for table `balance_history`
+--
+
+DROP TABLE IF EXISTS balance_history;
+CREATE TABLE balance_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant VARCHAR(64) NOT NULL,
+  account VARCHAR(128) NOT NULL,
+  balance_uuid VARCHAR(64) NOT NULL,
+  balance_id VARCHAR(128) NOT NULL,
+  balance_type VARCHAR(16) NOT NULL,
+  reason VARCHAR(64) NOT NULL,
+  cgrid VARCHAR(40) NOT NULL,
+  action_id VARCHAR(64) NOT NULL,
+  action_plan_id VARCHAR(64) NOT NULL,
+  old_value REAL NOT NULL,
+  new_value REAL NOT NULL,
+  movement_time DATETIME
+);
+DROP INDEX IF EXISTS account_time_balancehistory_idx;
+CREATE INDEX account_time_balancehistory_idx ON balance_history (tenant, account, movement_time);
+DROP INDEX IF EXISTS cgrid_balancehistory_idx;
+CREATE INDEX cgrid_balancehistory_idx ON balance_history (cgrid);
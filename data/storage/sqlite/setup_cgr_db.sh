@@ -0,0 +1,18 @@
+#! /usr/bin/env sh
+
+db_path=$1
+if [ -z "$1" ]; then
+	db_path="/var/lib/cgrates/stordb.sqlite"
+fi
+
+DIR="$(dirname "$(readlink -f "$0")")"
+
+sqlite3 $db_path < "$DIR"/create_cdrs_tables.sql
+cdrt=$?
+sqlite3 $db_path < "$DIR"/create_tariffplan_tables.sql
+tpt=$?
+
+if [ $cdrt = 0 ] && [ $tpt = 0 ]; then
+	echo -e "\n\t+++ CGR-DB successfully set-up! +++\n"
+	exit 0
+fi
@@ -0,0 +1,123 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (dS *DispatcherService) ApierV1GetAccount(args *AttrGetAccountWithApiKey,
+	reply *interface{}) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1GetAccount,
+			args.AttrGetAccount.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1GetAccount)
+	}
+	return dS.Dispatch(accountEvent(args.AttrGetAccount.Tenant, args.AttrGetAccount.Account),
+		utils.MetaApier, args.RouteID, utils.ApierV1GetAccount, &args.AttrGetAccount, reply)
+}
+
+func (dS *DispatcherService) ApierV1SetAccount(args *AttrSetAccountWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1SetAccount,
+			args.AttrSetAccount.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1SetAccount)
+	}
+	return dS.Dispatch(accountEvent(args.AttrSetAccount.Tenant, args.AttrSetAccount.Account),
+		utils.MetaApier, args.RouteID, utils.ApierV1SetAccount, args.AttrSetAccount, reply)
+}
+
+func (dS *DispatcherService) ApierV1RemoveAccount(args *AttrRemoveAccountWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1RemoveAccount,
+			args.AttrRemoveAccount.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1RemoveAccount)
+	}
+	return dS.Dispatch(accountEvent(args.AttrRemoveAccount.Tenant, args.AttrRemoveAccount.Account),
+		utils.MetaApier, args.RouteID, utils.ApierV1RemoveAccount, args.AttrRemoveAccount, reply)
+}
+
+func (dS *DispatcherService) ApierV1AddBalance(args *AttrAddBalanceWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1AddBalance,
+			args.AttrAddBalance.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1AddBalance)
+	}
+	return dS.Dispatch(accountEvent(args.AttrAddBalance.Tenant, args.AttrAddBalance.Account),
+		utils.MetaApier, args.RouteID, utils.ApierV1AddBalance, &args.AttrAddBalance, reply)
+}
+
+func (dS *DispatcherService) ApierV1DebitBalance(args *AttrAddBalanceWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1DebitBalance,
+			args.AttrAddBalance.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1DebitBalance)
+	}
+	return dS.Dispatch(accountEvent(args.AttrAddBalance.Tenant, args.AttrAddBalance.Account),
+		utils.MetaApier, args.RouteID, utils.ApierV1DebitBalance, &args.AttrAddBalance, reply)
+}
+
+func (dS *DispatcherService) ApierV1SetBalance(args *AttrSetBalanceWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1SetBalance,
+			args.AttrSetBalance.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1SetBalance)
+	}
+	return dS.Dispatch(accountEvent(args.AttrSetBalance.Tenant, args.AttrSetBalance.Account),
+		utils.MetaApier, args.RouteID, utils.ApierV1SetBalance, &args.AttrSetBalance, reply)
+}
+
+func (dS *DispatcherService) ApierV1RemoveBalances(args *AttrSetBalanceWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1RemoveBalances,
+			args.AttrSetBalance.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1RemoveBalances)
+	}
+	return dS.Dispatch(accountEvent(args.AttrSetBalance.Tenant, args.AttrSetBalance.Account),
+		utils.MetaApier, args.RouteID, utils.ApierV1RemoveBalances, &args.AttrSetBalance, reply)
+}
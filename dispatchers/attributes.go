@@ -32,6 +32,7 @@ func (dS *DispatcherService) AttributeSv1Ping(args *CGREvWithApiKey,
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.AttributeSv1Ping)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaAttributes, args.RouteID,
 		utils.AttributeSv1Ping, args.CGREvent, reply)
@@ -46,6 +47,7 @@ func (dS *DispatcherService) AttributeSv1GetAttributeForEvent(args *ArgsAttrProc
 			args.APIKey, args.AttrArgsProcessEvent.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.AttributeSv1GetAttributeForEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaAttributes, args.RouteID,
 		utils.AttributeSv1GetAttributeForEvent, args.AttrArgsProcessEvent, reply)
@@ -59,6 +61,7 @@ func (dS *DispatcherService) AttributeSv1ProcessEvent(args *ArgsAttrProcessEvent
 			args.APIKey, args.AttrArgsProcessEvent.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.AttributeSv1ProcessEvent)
 
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaAttributes, args.RouteID,
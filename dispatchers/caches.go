@@ -0,0 +1,197 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/ltcache"
+)
+
+func (dS *DispatcherService) CacheSv1Ping(args *CGREvWithApiKey, reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1Ping,
+			args.CGREvent.Tenant,
+			args.APIKey, args.CGREvent.Time); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1Ping)
+	}
+	return dS.Dispatch(&args.CGREvent, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1Ping, args.CGREvent, reply)
+}
+
+func (dS *DispatcherService) CacheSv1GetItemIDs(args *ArgsGetCacheItemIDsWithApiKey,
+	reply *[]string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1GetItemIDs,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1GetItemIDs)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1GetItemIDs, args.ArgsGetCacheItemIDs, reply)
+}
+
+func (dS *DispatcherService) CacheSv1HasItem(args *ArgsGetCacheItemWithApiKey,
+	reply *bool) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1HasItem,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1HasItem)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1HasItem, args.ArgsGetCacheItem, reply)
+}
+
+func (dS *DispatcherService) CacheSv1GetItemExpiryTime(args *ArgsGetCacheItemWithApiKey,
+	reply *time.Time) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1GetItemExpiryTime,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1GetItemExpiryTime)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1GetItemExpiryTime, args.ArgsGetCacheItem, reply)
+}
+
+// CacheSv1RemoveItem removes the item from the caches of all the engines in the dispatcher profile
+func (dS *DispatcherService) CacheSv1RemoveItem(args *ArgsGetCacheItemWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1RemoveItem,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1RemoveItem)
+	}
+	return dS.Broadcast(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches,
+		utils.CacheSv1RemoveItem, args.ArgsGetCacheItem, reply)
+}
+
+// CacheSv1Clear clears the caches of all the engines in the dispatcher profile
+func (dS *DispatcherService) CacheSv1Clear(args *AttrCacheIDsWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1Clear,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1Clear)
+	}
+	return dS.Broadcast(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches,
+		utils.CacheSv1Clear, args.CacheIDs, reply)
+}
+
+func (dS *DispatcherService) CacheSv1GetCacheStats(args *AttrCacheIDsWithApiKey,
+	reply *map[string]*ltcache.CacheStats) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1GetCacheStats,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1GetCacheStats)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1GetCacheStats, args.CacheIDs, reply)
+}
+
+func (dS *DispatcherService) CacheSv1PrecacheStatus(args *AttrCacheIDsWithApiKey,
+	reply *map[string]string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1PrecacheStatus,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1PrecacheStatus)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1PrecacheStatus, args.CacheIDs, reply)
+}
+
+func (dS *DispatcherService) CacheSv1HasGroup(args *ArgsGetGroupWithApiKey,
+	reply *bool) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1HasGroup,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1HasGroup)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1HasGroup, args.ArgsGetGroup, reply)
+}
+
+func (dS *DispatcherService) CacheSv1GetGroupItemIDs(args *ArgsGetGroupWithApiKey,
+	reply *[]string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1GetGroupItemIDs,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1GetGroupItemIDs)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches, args.RouteID,
+		utils.CacheSv1GetGroupItemIDs, args.ArgsGetGroup, reply)
+}
+
+// CacheSv1RemoveGroup removes the group from the caches of all the engines in the dispatcher profile
+func (dS *DispatcherService) CacheSv1RemoveGroup(args *ArgsGetGroupWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CacheSv1RemoveGroup,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CacheSv1RemoveGroup)
+	}
+	return dS.Broadcast(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches,
+		utils.CacheSv1RemoveGroup, args.ArgsGetGroup, reply)
+}
+
+// ApierV1ReloadCache reloads the caches of all the engines in the dispatcher profile
+func (dS *DispatcherService) ApierV1ReloadCache(args *AttrReloadCacheWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ApierV1ReloadCache,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ApierV1ReloadCache)
+	}
+	return dS.Broadcast(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaCaches,
+		utils.ApierV1ReloadCache, args.AttrReloadCache, reply)
+}
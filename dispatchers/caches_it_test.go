@@ -0,0 +1,119 @@
+// +build integration
+
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"testing"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+var sTestsDspChc = []func(t *testing.T){
+	testDspChcPing,
+	testDspChcTestAuthKey,
+	testDspChcClearBroadcast,
+}
+
+//Test start here
+func TestDspCacheSTMySQL(t *testing.T) {
+	testDsp(t, sTestsDspChc, "TestDspCacheS", "all", "all2", "attributes", "dispatchers", "tutorial", "oldtutorial", "dispatchers")
+}
+
+func TestDspCacheSMongo(t *testing.T) {
+	testDsp(t, sTestsDspChc, "TestDspCacheS", "all", "all2", "attributes_mongo", "dispatchers_mongo", "tutorial", "oldtutorial", "dispatchers")
+}
+
+func testDspChcPing(t *testing.T) {
+	var reply string
+	if err := allEngine.RCP.Call(utils.CacheSv1Ping, new(utils.CGREvent), &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.Pong {
+		t.Errorf("Received: %s", reply)
+	}
+	if err := dispEngine.RCP.Call(utils.CacheSv1Ping, &CGREvWithApiKey{
+		CGREvent: utils.CGREvent{
+			Tenant: "cgrates.org",
+		},
+		DispatcherResource: DispatcherResource{
+			APIKey: "chc12345",
+		},
+	}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.Pong {
+		t.Errorf("Received: %s", reply)
+	}
+}
+
+func testDspChcTestAuthKey(t *testing.T) {
+	var reply string
+	if err := dispEngine.RCP.Call(utils.CacheSv1Clear, &AttrCacheIDsWithApiKey{
+		TenantArg: utils.TenantArg{
+			Tenant: "cgrates.org",
+		},
+		DispatcherResource: DispatcherResource{
+			APIKey: "12345",
+		},
+	}, &reply); err == nil || err.Error() != utils.ErrUnauthorizedApi.Error() {
+		t.Error(err)
+	}
+}
+
+func testDspChcClearBroadcast(t *testing.T) {
+	argsHas := &engine.ArgsGetCacheItem{
+		CacheID: utils.CacheAttributeProfiles,
+		ItemID:  "cgrates.org:ATTR_1001_SIMPLEAUTH",
+	}
+	var has bool
+	// cache the profile on both engines
+	for _, eng := range []*testDispatcher{allEngine, allEngine2} {
+		var rplyEv engine.AttrSProcessEventReply
+		eng.RCP.Call(utils.AttributeSv1ProcessEvent, &engine.AttrArgsProcessEvent{
+			Context: utils.StringPointer("simpleauth"),
+			CGREvent: utils.CGREvent{
+				Tenant: "cgrates.org",
+				ID:     "testDspChcClearBroadcast",
+				Event:  map[string]interface{}{utils.Account: "1001"},
+			},
+		}, &rplyEv)
+	}
+	var reply string
+	if err := dispEngine.RCP.Call(utils.CacheSv1Clear, &AttrCacheIDsWithApiKey{
+		TenantArg: utils.TenantArg{
+			Tenant: "cgrates.org",
+		},
+		DispatcherResource: DispatcherResource{
+			APIKey: "chc12345",
+		},
+		CacheIDs: []string{utils.CacheAttributeProfiles},
+	}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Received: %s", reply)
+	}
+	for _, eng := range []*testDispatcher{allEngine, allEngine2} {
+		if err := eng.RCP.Call(utils.CacheSv1HasItem, argsHas, &has); err != nil {
+			t.Error(err)
+		} else if has {
+			t.Errorf("item not cleared on engine: %s", eng.CfgParh)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (dS *DispatcherService) CDRsV1ProcessCDR(args *CDRWithApiKey, reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CDRsV1ProcessCDR,
+			args.CDR.Tenant,
+			args.APIKey, &args.CDR.AnswerTime); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CDRsV1ProcessCDR)
+	}
+	return dS.Dispatch(args.CDR.AsCGREvent(), utils.MetaCDRs, args.RouteID,
+		utils.CDRsV1ProcessCDR, &args.CDR, reply)
+}
+
+func (dS *DispatcherService) CDRsV2ProcessCDR(args *ArgV2ProcessCDRWithApiKey, reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.CDRsV2ProcessCDR,
+			args.ArgV2ProcessCDR.CGREvent.Tenant,
+			args.APIKey, args.ArgV2ProcessCDR.CGREvent.Time); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.CDRsV2ProcessCDR)
+	}
+	return dS.Dispatch(&args.ArgV2ProcessCDR.CGREvent, utils.MetaCDRs, args.RouteID,
+		utils.CDRsV2ProcessCDR, &args.ArgV2ProcessCDR, reply)
+}
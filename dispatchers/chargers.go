@@ -30,6 +30,7 @@ func (dS *DispatcherService) ChargerSv1Ping(args *CGREvWithApiKey, reply *string
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ChargerSv1Ping)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaChargers, args.RouteID,
 		utils.ChargerSv1Ping, args.CGREvent, reply)
@@ -43,6 +44,7 @@ func (dS *DispatcherService) ChargerSv1GetChargersForEvent(args *CGREvWithApiKey
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ChargerSv1GetChargersForEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaChargers, args.RouteID,
 		utils.ChargerSv1GetChargersForEvent, args.CGREvent, reply)
@@ -56,6 +58,7 @@ func (dS *DispatcherService) ChargerSv1ProcessEvent(args *CGREvWithApiKey,
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ChargerSv1ProcessEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaChargers, args.RouteID,
 		utils.ChargerSv1ProcessEvent, args.CGREvent, reply)
@@ -32,23 +32,29 @@ import (
 // NewDispatcherService constructs a DispatcherService
 func NewDispatcherService(dm *engine.DataManager,
 	cfg *config.CGRConfig, fltrS *engine.FilterS,
-	attrS *rpcclient.RpcClientPool,
+	attrS, thdS *rpcclient.RpcClientPool,
 	conns map[string]*rpcclient.RpcClientPool) (*DispatcherService, error) {
 	if attrS != nil && reflect.ValueOf(attrS).IsNil() {
 		attrS = nil
 	}
+	if thdS != nil && reflect.ValueOf(thdS).IsNil() {
+		thdS = nil
+	}
 	return &DispatcherService{dm: dm, cfg: cfg,
-		fltrS: fltrS, attrS: attrS, conns: conns}, nil
+		fltrS: fltrS, attrS: attrS, thdS: thdS, conns: conns,
+		limiter: newAPILimiter()}, nil
 }
 
 // DispatcherService  is the service handling dispatching towards internal components
 // designed to handle automatic partitioning and failover
 type DispatcherService struct {
-	dm    *engine.DataManager
-	cfg   *config.CGRConfig
-	fltrS *engine.FilterS
-	attrS *rpcclient.RpcClientPool            // used for API auth
-	conns map[string]*rpcclient.RpcClientPool // available connections, accessed based on connID
+	dm      *engine.DataManager
+	cfg     *config.CGRConfig
+	fltrS   *engine.FilterS
+	attrS   *rpcclient.RpcClientPool            // used for API auth
+	thdS    *rpcclient.RpcClientPool            // used to notify the API key limits exceeded
+	conns   map[string]*rpcclient.RpcClientPool // available connections, accessed based on connID
+	limiter *apiLimiter                         // enforces the API key limits
 }
 
 // ListenAndServe will initialize the service
@@ -143,6 +149,15 @@ func (dS *DispatcherService) Dispatch(ev *utils.CGREvent, subsys string, routeID
 	if errDsp != nil {
 		return utils.NewErrDispatcherS(errDsp)
 	}
+	connIDs := d.ConnIDs()
+	if hd, canHash := d.(*HashDispatcher); canHash {
+		var routeKey string
+		routeKey, connIDs = hd.ConnIDsForEvent(ev)
+		if routeID == nil ||
+			*routeID == "" { // keep the route sticky for the key
+			routeID = &routeKey
+		}
+	}
 	var connID string
 	if routeID != nil &&
 		*routeID != "" {
@@ -155,7 +170,7 @@ func (dS *DispatcherService) Dispatch(ev *utils.CGREvent, subsys string, routeID
 			}
 		}
 	}
-	for _, connID = range d.ConnIDs() {
+	for _, connID = range connIDs {
 		conn, has := dS.conns[connID]
 		if !has {
 			err = utils.NewErrDispatcherS(
@@ -175,6 +190,31 @@ func (dS *DispatcherService) Dispatch(ev *utils.CGREvent, subsys string, routeID
 	return
 }
 
+// Broadcast sends the request to all the connections of the dispatcher matching the event
+// returning the last error met, after trying all of them
+func (dS *DispatcherService) Broadcast(ev *utils.CGREvent, subsys string,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	d, errDsp := dS.dispatcherForEvent(ev, subsys)
+	if errDsp != nil {
+		return utils.NewErrDispatcherS(errDsp)
+	}
+	for _, connID := range d.ConnIDs() {
+		conn, has := dS.conns[connID]
+		if !has {
+			err = utils.NewErrDispatcherS(
+				fmt.Errorf("no connection with id: <%s>", connID))
+			continue
+		}
+		if errCall := conn.Call(serviceMethod, args, reply); errCall != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s broadcasting %s to connection: <%s>",
+					utils.DispatcherS, errCall.Error(), serviceMethod, connID))
+			err = errCall
+		}
+	}
+	return
+}
+
 func (dS *DispatcherService) authorizeEvent(ev *utils.CGREvent,
 	reply *engine.AttrSProcessEventReply) (err error) {
 	if err = dS.attrS.Call(utils.AttributeSv1ProcessEvent,
@@ -212,5 +252,5 @@ func (dS *DispatcherService) authorize(method, tenant, apiKey string, evTime *ti
 	if !ParseStringMap(apiMethods).HasKey(method) {
 		return utils.ErrUnauthorizedApi
 	}
-	return
+	return dS.limitAPIKey(rplyEv.CGREvent, method, apiKey)
 }
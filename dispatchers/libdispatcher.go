@@ -20,6 +20,10 @@ package dispatchers
 
 import (
 	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/cgrates/cgrates/engine"
@@ -46,6 +50,8 @@ func newDispatcher(pfl *engine.DispatcherProfile) (d Dispatcher, err error) {
 		d = &RandomDispatcher{conns: pfl.Conns.Clone()}
 	case utils.MetaRoundRobin:
 		d = &RoundRobinDispatcher{conns: pfl.Conns.Clone()}
+	case utils.MetaHash:
+		d, err = newHashDispatcher(pfl)
 	default:
 		err = fmt.Errorf("unsupported dispatch strategy: <%s>", pfl.Strategy)
 	}
@@ -120,3 +126,132 @@ func (d *RoundRobinDispatcher) ConnIDs() (connIDs []string) {
 	d.RUnlock()
 	return conns.ConnIDs()
 }
+
+// hashRingReplicas is the number of points each connection gets on the hash ring
+const hashRingReplicas = 100
+
+// hashRingPoint is the position of a connection on the hash ring
+type hashRingPoint struct {
+	hash   uint32
+	connID string
+}
+
+// newHashDispatcher constructs a HashDispatcher out of the profile
+// the strategy parameters are the event fields building the hashing key
+func newHashDispatcher(pfl *engine.DispatcherProfile) (hd *HashDispatcher, err error) {
+	hd = &HashDispatcher{tntID: pfl.TenantID()}
+	if err = hd.setProfile(pfl); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// HashDispatcher selects the connection based on the consistent hash of event fields
+// so the same key is routed to the same connection and only the keys of a failed connection move
+type HashDispatcher struct {
+	sync.RWMutex
+	tntID  string
+	fields []string // event fields building the hashing key
+	conns  engine.DispatcherConns
+	ring   []*hashRingPoint // sorted on hash
+}
+
+// hashFields returns the hashing key fields out of the profile strategy parameters, in their order
+func hashFields(pfl *engine.DispatcherProfile) (flds []string, err error) {
+	idxs := make([]int, 0, len(pfl.StrategyParams))
+	for idxStr := range pfl.StrategyParams {
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid strategy parameter index: <%s>", idxStr)
+		}
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+	for _, idx := range idxs {
+		fld, err := utils.IfaceAsString(pfl.StrategyParams[strconv.Itoa(idx)])
+		if err != nil {
+			return nil, err
+		}
+		flds = append(flds, fld)
+	}
+	if len(flds) == 0 {
+		return nil, fmt.Errorf("no hashing fields defined for dispatch strategy: <%s>", utils.MetaHash)
+	}
+	return
+}
+
+func (hd *HashDispatcher) setProfile(pfl *engine.DispatcherProfile) (err error) {
+	flds, err := hashFields(pfl)
+	if err != nil {
+		return
+	}
+	pfl.Conns.Sort()
+	conns := pfl.Conns.Clone() // avoid concurrency on profile
+	ring := make([]*hashRingPoint, 0, len(conns)*hashRingReplicas)
+	for _, conn := range conns {
+		for i := 0; i < hashRingReplicas; i++ {
+			ring = append(ring, &hashRingPoint{
+				hash:   crc32.ChecksumIEEE([]byte(conn.ID + utils.CONCATENATED_KEY_SEP + strconv.Itoa(i))),
+				connID: conn.ID})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+	hd.Lock()
+	hd.fields, hd.conns, hd.ring = flds, conns, ring
+	hd.Unlock()
+	return
+}
+
+func (hd *HashDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	if err := hd.setProfile(pfl); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s updating dispatcher: <%s>",
+				utils.DispatcherS, err.Error(), hd.tntID))
+	}
+	return
+}
+
+// ConnIDs returns the connections sorted on weight, used when the event is not known
+func (hd *HashDispatcher) ConnIDs() (connIDs []string) {
+	hd.RLock()
+	connIDs = hd.conns.ConnIDs()
+	hd.RUnlock()
+	return
+}
+
+// hashKey builds the hashing key out of the event fields
+func (hd *HashDispatcher) hashKey(ev *utils.CGREvent) string {
+	vals := make([]string, len(hd.fields))
+	for i, fld := range hd.fields {
+		if fld == utils.Tenant {
+			vals[i] = ev.Tenant
+			continue
+		}
+		vals[i], _ = ev.FieldAsString(fld) // missing fields hash as empty
+	}
+	return strings.Join(vals, utils.CONCATENATED_KEY_SEP)
+}
+
+// ConnIDsForEvent returns the route key of the event together with the connections to try,
+// starting with the one owning the key on the hash ring and followed by the next ones on the ring
+func (hd *HashDispatcher) ConnIDsForEvent(ev *utils.CGREvent) (routeKey string, connIDs []string) {
+	hd.RLock()
+	defer hd.RUnlock()
+	key := hd.hashKey(ev)
+	routeKey = utils.ConcatenatedKey(hd.tntID, key)
+	if len(hd.ring) == 0 {
+		return
+	}
+	h := crc32.ChecksumIEEE([]byte(key))
+	start := sort.Search(len(hd.ring), func(i int) bool { return hd.ring[i].hash >= h })
+	seen := make(map[string]bool)
+	for i := 0; i < len(hd.ring) && len(connIDs) < len(hd.conns); i++ {
+		pnt := hd.ring[(start+i)%len(hd.ring)]
+		if seen[pnt.connID] {
+			continue
+		}
+		seen[pnt.connID] = true
+		connIDs = append(connIDs, pnt.connID)
+	}
+	return
+}
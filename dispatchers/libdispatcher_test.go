@@ -0,0 +1,87 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestHashDispatcherConnIDsForEvent(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "HASH1",
+		Strategy:       utils.MetaHash,
+		StrategyParams: map[string]interface{}{"0": utils.Tenant, "1": utils.Account},
+		Conns: engine.DispatcherConns{
+			{ID: "CONN1", Weight: 30},
+			{ID: "CONN2", Weight: 20},
+			{ID: "CONN3", Weight: 10},
+		},
+	}
+	d, err := newDispatcher(pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hd := d.(*HashDispatcher)
+	if connIDs := hd.ConnIDs(); !reflect.DeepEqual([]string{"CONN1", "CONN2", "CONN3"}, connIDs) {
+		t.Errorf("unexpected connections: %+v", connIDs)
+	}
+	owners := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		ev := &utils.CGREvent{Tenant: "cgrates.org",
+			Event: map[string]interface{}{utils.Account: fmt.Sprintf("10%02d", i)}}
+		routeKey, connIDs := hd.ConnIDsForEvent(ev)
+		if eKey := utils.ConcatenatedKey("cgrates.org", "HASH1", "cgrates.org", ev.Event[utils.Account].(string)); routeKey != eKey {
+			t.Errorf("expecting: %s, received: %s", eKey, routeKey)
+		}
+		if len(connIDs) != 3 {
+			t.Fatalf("unexpected connections: %+v", connIDs)
+		}
+		if _, rcvConnIDs := hd.ConnIDsForEvent(ev); !reflect.DeepEqual(connIDs, rcvConnIDs) {
+			t.Errorf("expecting: %+v, received: %+v", connIDs, rcvConnIDs)
+		}
+		owners[ev.Event[utils.Account].(string)] = connIDs[0]
+	}
+	// removing one connection moves only its keys
+	pfl.Conns = pfl.Conns[:2]
+	hd.SetProfile(pfl)
+	for acnt, owner := range owners {
+		_, connIDs := hd.ConnIDsForEvent(&utils.CGREvent{Tenant: "cgrates.org",
+			Event: map[string]interface{}{utils.Account: acnt}})
+		if owner != "CONN3" && connIDs[0] != owner {
+			t.Errorf("key %s moved from %s to %s", acnt, owner, connIDs[0])
+		}
+	}
+}
+
+func TestHashDispatcherNoFields(t *testing.T) {
+	if _, err := newDispatcher(&engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "HASH2",
+		Strategy: utils.MetaHash,
+		Conns:    engine.DispatcherConns{{ID: "CONN1"}},
+	}); err == nil {
+		t.Error("expecting error for missing hashing fields")
+	}
+}
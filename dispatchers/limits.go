@@ -0,0 +1,215 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// apiLimit is the number of requests an API key can send towards one method
+// within Interval for rate limits or at once for concurrency limits
+type apiLimit struct {
+	Limit    int
+	Interval time.Duration
+}
+
+// parseAPILimits parses the limits defined as method:limit/interval (rate limits)
+// or method:limit (concurrency limits), separated by &
+// *any as method limits the requests towards all the methods together
+func parseAPILimits(lmtsStr string, withInterval bool) (lmts map[string]*apiLimit, err error) {
+	lmts = make(map[string]*apiLimit)
+	if lmtsStr == "" {
+		return
+	}
+	for _, lmtStr := range strings.Split(lmtsStr, utils.ANDSep) {
+		sepIdx := strings.LastIndex(lmtStr, utils.InInFieldSep)
+		if sepIdx == -1 {
+			return nil, fmt.Errorf("invalid API limit: <%s>", lmtStr)
+		}
+		method, valStr := lmtStr[:sepIdx], lmtStr[sepIdx+1:]
+		lmt := new(apiLimit)
+		if withInterval {
+			splt := strings.Split(valStr, utils.HDR_VAL_SEP)
+			if len(splt) != 2 {
+				return nil, fmt.Errorf("invalid API rate limit: <%s>", lmtStr)
+			}
+			if lmt.Interval, err = utils.ParseDurationWithNanosecs(splt[1]); err != nil {
+				return nil, err
+			}
+			valStr = splt[0]
+		}
+		if lmt.Limit, err = strconv.Atoi(valStr); err != nil {
+			return nil, err
+		}
+		lmts[method] = lmt
+	}
+	return
+}
+
+// APIKeyCounter tracks the requests sent by an API key towards one method
+type APIKeyCounter struct {
+	Requests      int       // requests accepted since IntervalStart
+	IntervalStart time.Time // start of the rate limiting interval
+	Concurrent    int       // requests in progress
+}
+
+// newAPILimiter constructs an apiLimiter
+func newAPILimiter() *apiLimiter {
+	return &apiLimiter{counters: make(map[string]map[string]*APIKeyCounter)}
+}
+
+// apiLimiter enforces the rate and concurrency limits of the API keys
+type apiLimiter struct {
+	sync.Mutex
+	counters map[string]map[string]*APIKeyCounter // counters indexed on API key and method, *any counting all methods
+}
+
+// acquire accounts a new request of apiKey towards method, failing if it would exceed its limits
+// a successful acquire needs to be followed by release once the request is served
+func (al *apiLimiter) acquire(apiKey, method string, rateLmts,
+	concLmts map[string]*apiLimit) (lmtType string, err error) {
+	al.Lock()
+	defer al.Unlock()
+	if _, has := al.counters[apiKey]; !has {
+		al.counters[apiKey] = make(map[string]*APIKeyCounter)
+	}
+	now := time.Now()
+	methods := []string{method, utils.META_ANY}
+	for _, m := range methods {
+		cntr, has := al.counters[apiKey][m]
+		if !has {
+			cntr = &APIKeyCounter{IntervalStart: now}
+			al.counters[apiKey][m] = cntr
+		}
+		if lmt, has := rateLmts[m]; has {
+			if now.Sub(cntr.IntervalStart) >= lmt.Interval {
+				cntr.IntervalStart = now
+				cntr.Requests = 0
+			}
+			if cntr.Requests >= lmt.Limit {
+				return utils.MetaRateLimit, utils.ErrMaxRateExceeded
+			}
+		}
+		if lmt, has := concLmts[m]; has &&
+			cntr.Concurrent >= lmt.Limit {
+			return utils.MetaConcurrent, utils.ErrMaxConcurrentExceeded
+		}
+	}
+	for _, m := range methods {
+		al.counters[apiKey][m].Requests++
+		al.counters[apiKey][m].Concurrent++
+	}
+	return
+}
+
+// release marks a request acquired before as served
+func (al *apiLimiter) release(apiKey, method string) {
+	al.Lock()
+	for _, m := range []string{method, utils.META_ANY} {
+		if cntr, has := al.counters[apiKey][m]; has && cntr.Concurrent > 0 {
+			cntr.Concurrent--
+		}
+	}
+	al.Unlock()
+}
+
+// getCounters returns a copy of the counters for apiKey
+func (al *apiLimiter) getCounters(apiKey string) (cntrs map[string]*APIKeyCounter, err error) {
+	al.Lock()
+	defer al.Unlock()
+	if _, has := al.counters[apiKey]; !has {
+		return nil, utils.ErrNotFound
+	}
+	cntrs = make(map[string]*APIKeyCounter, len(al.counters[apiKey]))
+	for m, cntr := range al.counters[apiKey] {
+		cpy := *cntr
+		cntrs[m] = &cpy
+	}
+	return
+}
+
+// limitAPIKey checks the limits of apiKey as received from AttributeS before accepting a new request
+func (dS *DispatcherService) limitAPIKey(ev *utils.CGREvent, method, apiKey string) (err error) {
+	var rateLmts, concLmts map[string]*apiLimit
+	if rateLmtsStr, errFld := ev.FieldAsString(utils.APIRateLimits); errFld == nil {
+		if rateLmts, err = parseAPILimits(rateLmtsStr, true); err != nil {
+			return
+		}
+	}
+	if concLmtsStr, errFld := ev.FieldAsString(utils.APIConcurrency); errFld == nil {
+		if concLmts, err = parseAPILimits(concLmtsStr, false); err != nil {
+			return
+		}
+	}
+	var lmtType string
+	if lmtType, err = dS.limiter.acquire(apiKey, method, rateLmts, concLmts); err != nil {
+		go dS.notifyLimitExceeded(ev.Tenant, apiKey, method, lmtType)
+	}
+	return
+}
+
+// release frees the request of apiKey towards method, to be deferred after a successful authorize
+func (dS *DispatcherService) release(apiKey, method string) {
+	dS.limiter.release(apiKey, method)
+}
+
+// notifyLimitExceeded raises an event in ThresholdS for the requests rejected because of the API key limits
+func (dS *DispatcherService) notifyLimitExceeded(tenant, apiKey, method, lmtType string) {
+	if dS.thdS == nil {
+		return
+	}
+	thEv := &engine.ArgsProcessEvent{
+		CGREvent: utils.CGREvent{
+			Tenant: tenant,
+			ID:     utils.GenUUID(),
+			Event: map[string]interface{}{
+				utils.EventType:    utils.APILimitExceeded,
+				utils.EventSource:  utils.DispatcherS,
+				utils.APIKey:       apiKey,
+				utils.APIMethod:    method,
+				utils.APILimitType: lmtType,
+			},
+		},
+	}
+	var tIDs []string
+	if err := dS.thdS.Call(utils.ThresholdSv1ProcessEvent, thEv, &tIDs); err != nil &&
+		err.Error() != utils.ErrNotFound.Error() {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s processing limit event for API key: %s with %s",
+				utils.DispatcherS, err.Error(), apiKey, utils.ThresholdS))
+	}
+}
+
+// GetAPIKeyCounters returns the request counters of one API key, indexed on method
+func (dS *DispatcherService) GetAPIKeyCounters(apiKey string,
+	reply *map[string]*APIKeyCounter) (err error) {
+	cntrs, err := dS.limiter.getCounters(apiKey)
+	if err != nil {
+		return
+	}
+	*reply = cntrs
+	return
+}
@@ -0,0 +1,80 @@
+// +build integration
+
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+var sTestsDspLmt = []func(t *testing.T){
+	testDspLmtRateLimit,
+	testDspLmtCounters,
+}
+
+//Test start here
+func TestDspLimitsTMySQL(t *testing.T) {
+	testDsp(t, sTestsDspLmt, "TestDspLimits", "all", "all2", "attributes", "dispatchers", "tutorial", "oldtutorial", "dispatchers")
+}
+
+func TestDspLimitsMongo(t *testing.T) {
+	testDsp(t, sTestsDspLmt, "TestDspLimits", "all", "all2", "attributes_mongo", "dispatchers_mongo", "tutorial", "oldtutorial", "dispatchers")
+}
+
+func testDspLmtRateLimit(t *testing.T) {
+	ev := &CGREvWithApiKey{
+		CGREvent: utils.CGREvent{
+			Tenant: "cgrates.org",
+		},
+		DispatcherResource: DispatcherResource{
+			APIKey: "lmt12345",
+		},
+	}
+	var reply string
+	for i := 0; i < 2; i++ {
+		if err := dispEngine.RCP.Call(utils.ChargerSv1Ping, ev, &reply); err != nil {
+			t.Error(err)
+		} else if reply != utils.Pong {
+			t.Errorf("Received: %s", reply)
+		}
+	}
+	if err := dispEngine.RCP.Call(utils.ChargerSv1Ping, ev, &reply); err == nil ||
+		err.Error() != utils.ErrMaxRateExceeded.Error() {
+		t.Errorf("expecting: %v, received: %v", utils.ErrMaxRateExceeded, err)
+	}
+}
+
+func testDspLmtCounters(t *testing.T) {
+	var cntrs map[string]*APIKeyCounter
+	if err := dispEngine.RCP.Call(utils.DispatcherSv1GetAPIKeyCounters,
+		"lmt12345", &cntrs); err != nil {
+		t.Fatal(err)
+	}
+	if cntr, has := cntrs[utils.ChargerSv1Ping]; !has ||
+		cntr.Requests != 2 || cntr.Concurrent != 0 {
+		t.Errorf("unexpected counters: %s", utils.ToJSON(cntrs))
+	}
+	if err := dispEngine.RCP.Call(utils.DispatcherSv1GetAPIKeyCounters,
+		"unknown", &cntrs); err == nil || err.Error() != utils.ErrNotFound.Error() {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestParseAPILimits(t *testing.T) {
+	eLmts := map[string]*apiLimit{
+		utils.SessionSv1AuthorizeEvent: {Limit: 100, Interval: time.Second},
+		utils.META_ANY:                 {Limit: 1000, Interval: time.Minute},
+	}
+	if lmts, err := parseAPILimits("SessionSv1.AuthorizeEvent:100/1s&*any:1000/1m", true); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eLmts, lmts) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eLmts), utils.ToJSON(lmts))
+	}
+	eLmts = map[string]*apiLimit{
+		utils.SessionSv1AuthorizeEvent: {Limit: 10},
+	}
+	if lmts, err := parseAPILimits("SessionSv1.AuthorizeEvent:10", false); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eLmts, lmts) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eLmts), utils.ToJSON(lmts))
+	}
+	if _, err := parseAPILimits("SessionSv1.AuthorizeEvent:10", true); err == nil {
+		t.Error("expecting error for rate limit without interval")
+	}
+	if _, err := parseAPILimits("SessionSv1.AuthorizeEvent", false); err == nil {
+		t.Error("expecting error for limit without value")
+	}
+}
+
+func TestAPILimiter(t *testing.T) {
+	al := newAPILimiter()
+	rateLmts := map[string]*apiLimit{
+		utils.SessionSv1AuthorizeEvent: {Limit: 2, Interval: time.Hour},
+	}
+	concLmts := map[string]*apiLimit{
+		utils.META_ANY: {Limit: 2},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := al.acquire("key1", utils.SessionSv1AuthorizeEvent, rateLmts, concLmts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lmtType, err := al.acquire("key1", utils.SessionSv1AuthorizeEvent,
+		rateLmts, concLmts); err != utils.ErrMaxRateExceeded || lmtType != utils.MetaRateLimit {
+		t.Errorf("expecting: %v, received: %v", utils.ErrMaxRateExceeded, err)
+	}
+	if lmtType, err := al.acquire("key1", utils.SessionSv1InitiateSession,
+		rateLmts, concLmts); err != utils.ErrMaxConcurrentExceeded || lmtType != utils.MetaConcurrent {
+		t.Errorf("expecting: %v, received: %v", utils.ErrMaxConcurrentExceeded, err)
+	}
+	al.release("key1", utils.SessionSv1AuthorizeEvent)
+	if _, err := al.acquire("key1", utils.SessionSv1InitiateSession, rateLmts, concLmts); err != nil {
+		t.Error(err)
+	}
+	if _, err := al.acquire("key2", utils.SessionSv1AuthorizeEvent, rateLmts, concLmts); err != nil {
+		t.Error(err)
+	}
+	if cntrs, err := al.getCounters("key1"); err != nil {
+		t.Error(err)
+	} else if cntrs[utils.SessionSv1AuthorizeEvent].Requests != 2 ||
+		cntrs[utils.SessionSv1AuthorizeEvent].Concurrent != 1 ||
+		cntrs[utils.META_ANY].Requests != 3 || cntrs[utils.META_ANY].Concurrent != 2 {
+		t.Errorf("unexpected counters: %s", utils.ToJSON(cntrs))
+	}
+	if _, err := al.getCounters("key3"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (dS *DispatcherService) ResponderGetCost(args *CallDescriptorWithApiKey,
+	reply *engine.CallCost) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ResponderGetCost,
+			args.CallDescriptor.Tenant,
+			args.APIKey, &args.CallDescriptor.TimeStart); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ResponderGetCost)
+	}
+	return dS.Dispatch(args.AsCGREvent(), utils.MetaRALs, args.RouteID,
+		utils.ResponderGetCost, &args.CallDescriptor, reply)
+}
+
+func (dS *DispatcherService) ResponderDebit(args *CallDescriptorWithApiKey,
+	reply *engine.CallCost) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ResponderDebit,
+			args.CallDescriptor.Tenant,
+			args.APIKey, &args.CallDescriptor.TimeStart); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ResponderDebit)
+	}
+	return dS.Dispatch(args.AsCGREvent(), utils.MetaRALs, args.RouteID,
+		utils.ResponderDebit, &args.CallDescriptor, reply)
+}
+
+func (dS *DispatcherService) ResponderMaxDebit(args *CallDescriptorWithApiKey,
+	reply *engine.CallCost) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.ResponderMaxDebit,
+			args.CallDescriptor.Tenant,
+			args.APIKey, &args.CallDescriptor.TimeStart); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.ResponderMaxDebit)
+	}
+	return dS.Dispatch(args.AsCGREvent(), utils.MetaRALs, args.RouteID,
+		utils.ResponderMaxDebit, &args.CallDescriptor, reply)
+}
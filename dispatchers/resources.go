@@ -30,6 +30,7 @@ func (dS *DispatcherService) ResourceSv1Ping(args *CGREvWithApiKey, rpl *string)
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ResourceSv1Ping)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaResources, args.RouteID,
 		utils.ResourceSv1Ping, args.CGREvent, rpl)
@@ -43,6 +44,7 @@ func (dS *DispatcherService) ResourceSv1GetResourcesForEvent(args *ArgsV1ResUsag
 			args.APIKey, args.ArgRSv1ResourceUsage.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ResourceSv1GetResourcesForEvent)
 
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaResources, args.RouteID,
@@ -57,6 +59,7 @@ func (dS *DispatcherService) ResourceSv1AuthorizeResources(args *ArgsV1ResUsageW
 			args.APIKey, args.ArgRSv1ResourceUsage.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ResourceSv1AuthorizeResources)
 
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaResources, args.RouteID,
@@ -71,6 +74,7 @@ func (dS *DispatcherService) ResourceSv1AllocateResources(args *ArgsV1ResUsageWi
 			args.APIKey, args.ArgRSv1ResourceUsage.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ResourceSv1AllocateResources)
 
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaResources, args.RouteID,
@@ -85,6 +89,7 @@ func (dS *DispatcherService) ResourceSv1ReleaseResources(args *ArgsV1ResUsageWit
 			args.APIKey, args.ArgRSv1ResourceUsage.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ResourceSv1ReleaseResources)
 
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaResources, args.RouteID,
@@ -32,6 +32,7 @@ func (dS *DispatcherService) SessionSv1Ping(args *CGREvWithApiKey, reply *string
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1Ping)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1Ping, args.CGREvent, reply)
@@ -45,6 +46,7 @@ func (dS *DispatcherService) SessionSv1AuthorizeEvent(args *AuthorizeArgsWithApi
 			args.APIKey, args.V1AuthorizeArgs.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1AuthorizeEvent)
 	}
 	return dS.Dispatch(&args.V1AuthorizeArgs.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1AuthorizeEvent, args.V1AuthorizeArgs, reply)
@@ -58,6 +60,7 @@ func (dS *DispatcherService) SessionSv1AuthorizeEventWithDigest(args *AuthorizeA
 			args.APIKey, args.V1AuthorizeArgs.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1AuthorizeEventWithDigest)
 	}
 	return dS.Dispatch(&args.V1AuthorizeArgs.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1AuthorizeEventWithDigest, args.V1AuthorizeArgs, reply)
@@ -71,6 +74,7 @@ func (dS *DispatcherService) SessionSv1InitiateSession(args *InitArgsWithApiKey,
 			args.APIKey, args.V1InitSessionArgs.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1InitiateSession)
 	}
 	return dS.Dispatch(&args.V1InitSessionArgs.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1InitiateSession, args.V1InitSessionArgs, reply)
@@ -84,6 +88,7 @@ func (dS *DispatcherService) SessionSv1InitiateSessionWithDigest(args *InitArgsW
 			args.APIKey, args.V1InitSessionArgs.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1InitiateSessionWithDigest)
 	}
 	return dS.Dispatch(&args.V1InitSessionArgs.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1InitiateSessionWithDigest, args.V1InitSessionArgs, reply)
@@ -97,6 +102,7 @@ func (dS *DispatcherService) SessionSv1UpdateSession(args *UpdateSessionWithApiK
 			args.APIKey, args.V1UpdateSessionArgs.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1UpdateSession)
 	}
 	return dS.Dispatch(&args.V1UpdateSessionArgs.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1UpdateSession, args.V1UpdateSessionArgs, reply)
@@ -110,6 +116,7 @@ func (dS *DispatcherService) SessionSv1SyncSessions(args *TntWithApiKey,
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1SyncSessions)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1SyncSessions, &args.TenantArg.Tenant, reply)
@@ -123,6 +130,7 @@ func (dS *DispatcherService) SessionSv1TerminateSession(args *TerminateSessionWi
 			args.APIKey, args.V1TerminateSessionArgs.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1TerminateSession)
 	}
 	return dS.Dispatch(&args.V1TerminateSessionArgs.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1TerminateSession, args.V1TerminateSessionArgs, reply)
@@ -136,6 +144,7 @@ func (dS *DispatcherService) SessionSv1ProcessCDR(args *CGREvWithApiKey,
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1ProcessCDR)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1ProcessCDR, args.CGREvent, reply)
@@ -149,6 +158,7 @@ func (dS *DispatcherService) SessionSv1ProcessEvent(args *ProcessEventWithApiKey
 			args.APIKey, args.V1ProcessEventArgs.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1ProcessEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1ProcessEvent, args.V1ProcessEventArgs, reply)
@@ -162,6 +172,7 @@ func (dS *DispatcherService) SessionSv1GetActiveSessions(args *FilterSessionWith
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1GetActiveSessions)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1GetActiveSessions, args.Filters, reply)
@@ -175,6 +186,7 @@ func (dS *DispatcherService) SessionSv1GetActiveSessionsCount(args *FilterSessio
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1GetActiveSessionsCount)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1GetActiveSessionsCount, args.Filters, reply)
@@ -188,11 +200,26 @@ func (dS *DispatcherService) SessionSv1ForceDisconnect(args *FilterSessionWithAp
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1ForceDisconnect)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1ForceDisconnect, args.Filters, reply)
 }
 
+func (dS *DispatcherService) SessionSv1ReAuthorize(args *FilterSessionWithApiKey,
+	reply *string) (err error) {
+	if dS.attrS != nil {
+		if err = dS.authorize(utils.SessionSv1ReAuthorize,
+			args.TenantArg.Tenant,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+		defer dS.release(args.APIKey, utils.SessionSv1ReAuthorize)
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
+		utils.SessionSv1ReAuthorize, args.Filters, reply)
+}
+
 func (dS *DispatcherService) SessionSv1GetPassiveSessions(args *FilterSessionWithApiKey,
 	reply *[]*sessions.ActiveSession) (err error) {
 	if dS.attrS != nil {
@@ -201,6 +228,7 @@ func (dS *DispatcherService) SessionSv1GetPassiveSessions(args *FilterSessionWit
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1GetPassiveSessions)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1GetPassiveSessions, args.Filters, reply)
@@ -214,6 +242,7 @@ func (dS *DispatcherService) SessionSv1GetPassiveSessionsCount(args *FilterSessi
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1GetPassiveSessionsCount)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1GetPassiveSessionsCount, args.Filters, reply)
@@ -227,6 +256,7 @@ func (dS *DispatcherService) SessionSv1ReplicateSessions(args *ArgsReplicateSess
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1ReplicateSessions)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1ReplicateSessions, args.ArgsReplicateSessions, reply)
@@ -240,6 +270,7 @@ func (dS *DispatcherService) SessionSv1SetPassiveSession(args *SessionWithApiKey
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SessionSv1SetPassiveSession)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.Session.Tenant}, utils.MetaSessionS, args.RouteID,
 		utils.SessionSv1SetPassiveSession, args.Session, reply)
@@ -31,6 +31,7 @@ func (dS *DispatcherService) StatSv1Ping(args *CGREvWithApiKey, reply *string) (
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.StatSv1Ping)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaStats, args.RouteID,
 		utils.StatSv1Ping, args.CGREvent, reply)
@@ -44,6 +45,7 @@ func (dS *DispatcherService) StatSv1GetStatQueuesForEvent(args *ArgsStatProcessE
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.StatSv1GetStatQueuesForEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaStats, args.RouteID,
 		utils.StatSv1GetStatQueuesForEvent, args.StatsArgsProcessEvent, reply)
@@ -57,6 +59,7 @@ func (dS *DispatcherService) StatSv1GetQueueStringMetrics(args *TntIDWithApiKey,
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.StatSv1GetQueueStringMetrics)
 	}
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: args.Tenant,
@@ -73,6 +76,7 @@ func (dS *DispatcherService) StatSv1ProcessEvent(args *ArgsStatProcessEventWithA
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.StatSv1ProcessEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaStats, args.RouteID,
 		utils.StatSv1ProcessEvent, args.StatsArgsProcessEvent, reply)
@@ -86,6 +90,7 @@ func (dS *DispatcherService) StatSv1GetQueueFloatMetrics(args *TntIDWithApiKey,
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.StatSv1GetQueueFloatMetrics)
 	}
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: args.Tenant,
@@ -102,6 +107,7 @@ func (dS *DispatcherService) StatSv1GetQueueIDs(args *TntWithApiKey,
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.StatSv1GetQueueIDs)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant},
 		utils.MetaStats, args.RouteID, utils.StatSv1GetQueueIDs,
@@ -30,6 +30,7 @@ func (dS *DispatcherService) SupplierSv1Ping(args *CGREvWithApiKey, reply *strin
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SupplierSv1Ping)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaSuppliers, args.RouteID,
 		utils.SupplierSv1Ping, args.CGREvent, reply)
@@ -43,6 +44,7 @@ func (dS *DispatcherService) SupplierSv1GetSuppliers(args *ArgsGetSuppliersWithA
 			args.APIKey, args.ArgsGetSuppliers.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.SupplierSv1GetSuppliers)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaSuppliers, args.RouteID,
 		utils.SupplierSv1GetSuppliers, args.ArgsGetSuppliers, reply)
@@ -32,6 +32,7 @@ func (dS *DispatcherService) ThresholdSv1Ping(args *CGREvWithApiKey, reply *stri
 			args.APIKey, args.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ThresholdSv1Ping)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaThresholds, args.RouteID,
 		utils.ThresholdSv1Ping, args.CGREvent, reply)
@@ -45,6 +46,7 @@ func (dS *DispatcherService) ThresholdSv1GetThresholdsForEvent(args *ArgsProcess
 			args.APIKey, args.ArgsProcessEvent.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ThresholdSv1GetThresholdsForEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaThresholds, args.RouteID,
 		utils.ThresholdSv1GetThresholdsForEvent, args.ArgsProcessEvent, t)
@@ -58,6 +60,7 @@ func (dS *DispatcherService) ThresholdSv1ProcessEvent(args *ArgsProcessEventWith
 			args.APIKey, args.ArgsProcessEvent.CGREvent.Time); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ThresholdSv1ProcessEvent)
 	}
 	return dS.Dispatch(&args.CGREvent, utils.MetaThresholds, args.RouteID,
 		utils.ThresholdSv1ProcessEvent, args.ArgsProcessEvent, tIDs)
@@ -69,6 +72,7 @@ func (dS *DispatcherService) ThresholdSv1GetThresholdIDs(args *TntWithApiKey, tI
 			args.Tenant, args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ThresholdSv1GetThresholdIDs)
 	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaThresholds, args.RouteID,
 		utils.ThresholdSv1GetThresholdIDs, args.TenantArg, tIDs)
@@ -81,6 +85,7 @@ func (dS *DispatcherService) ThresholdSv1GetThreshold(args *TntIDWithApiKey, th
 			args.APIKey, utils.TimePointer(time.Now())); err != nil {
 			return
 		}
+		defer dS.release(args.APIKey, utils.ThresholdSv1GetThreshold)
 	}
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: args.Tenant,
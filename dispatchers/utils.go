@@ -120,6 +120,96 @@ type SessionWithApiKey struct {
 	sessions.Session
 }
 
+type CDRWithApiKey struct {
+	DispatcherResource
+	engine.CDR
+}
+
+type ArgV2ProcessCDRWithApiKey struct {
+	DispatcherResource
+	engine.ArgV2ProcessCDR
+}
+
+type CallDescriptorWithApiKey struct {
+	DispatcherResource
+	engine.CallDescriptor
+}
+
+type ArgsGetCacheItemIDsWithApiKey struct {
+	DispatcherResource
+	utils.TenantArg
+	engine.ArgsGetCacheItemIDs
+}
+
+type ArgsGetCacheItemWithApiKey struct {
+	DispatcherResource
+	utils.TenantArg
+	engine.ArgsGetCacheItem
+}
+
+type ArgsGetGroupWithApiKey struct {
+	DispatcherResource
+	utils.TenantArg
+	engine.ArgsGetGroup
+}
+
+type AttrCacheIDsWithApiKey struct {
+	DispatcherResource
+	utils.TenantArg
+	CacheIDs []string
+}
+
+type AttrReloadCacheWithApiKey struct {
+	DispatcherResource
+	utils.TenantArg
+	utils.AttrReloadCache
+}
+
+type AttrGetAccountWithApiKey struct {
+	DispatcherResource
+	utils.AttrGetAccount
+}
+
+type AttrSetAccountWithApiKey struct {
+	DispatcherResource
+	utils.AttrSetAccount
+}
+
+type AttrRemoveAccountWithApiKey struct {
+	DispatcherResource
+	utils.AttrRemoveAccount
+}
+
+type AttrAddBalanceWithApiKey struct {
+	DispatcherResource
+	utils.AttrAddBalance
+}
+
+type AttrSetBalanceWithApiKey struct {
+	DispatcherResource
+	utils.AttrSetBalance
+}
+
+// accountEvent builds the event used to route the requests on an account
+func accountEvent(tenant, account string) *utils.CGREvent {
+	return &utils.CGREvent{
+		Tenant: tenant,
+		ID:     utils.UUIDSha1Prefix(),
+		Event:  map[string]interface{}{utils.Account: account},
+	}
+}
+
+// AsCGREvent builds the event used to route the CallDescriptor
+func (cd *CallDescriptorWithApiKey) AsCGREvent() (ev *utils.CGREvent) {
+	ev = accountEvent(cd.CallDescriptor.Tenant, cd.CallDescriptor.Account)
+	ev.Time = utils.TimePointer(cd.CallDescriptor.TimeStart)
+	ev.Event[utils.Subject] = cd.CallDescriptor.Subject
+	ev.Event[utils.Destination] = cd.CallDescriptor.Destination
+	ev.Event[utils.Category] = cd.CallDescriptor.Category
+	ev.Event[utils.ToR] = cd.CallDescriptor.TOR
+	return
+}
+
 func ParseStringMap(s string) utils.StringMap {
 	if s == utils.ZERO {
 		return make(utils.StringMap)
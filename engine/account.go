@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -42,7 +43,13 @@ type Account struct {
 	ActionTriggers    ActionTriggers
 	AllowNegative     bool
 	Disabled          bool
+	Reservations      map[string]*BalanceReservation // value held until captured or released, indexed on reservation ID
+	Subscriptions     map[string]*Subscription       // recurring products charged, indexed on product ID
+	CreditLimit       float64                        // how far the default monetary balance may go negative when allowed to, 0 for no limit
+	SpendingCaps      map[string]*SpendingCap        // monetary value allowed to be spent, indexed on *daily or *monthly
 	executingTriggers bool
+	journal           *balanceJournal    // balance movements not yet written into the balance history
+	cdrLogs           []*postponedCdrLog // CDRLOG actions waiting for the account to be saved
 }
 
 // User's available minutes for the specified destination
@@ -199,9 +206,17 @@ func (ub *Account) debitBalanceAction(a *Action, reset, resetIfNegative bool) er
 	}
 	found := false
 	balanceType := a.Balance.GetType()
+	restore := a.ActionType == TOPUP || a.ActionType == TOPUP_RESET
 	for _, b := range ub.BalanceMap[balanceType] {
 		if b.IsExpired() {
-			continue // just to be safe (cleaned expired balances above)
+			if !restore || !b.isInGracePeriod() || !b.MatchFilter(a.Balance, false, true) {
+				continue // just to be safe (cleaned expired balances above)
+			}
+			// restored by the top-up within the grace period, expiring as requested by the action
+			// or keeping its original expiry when the action has none
+			if a.Balance.ExpirationDate != nil {
+				b.ExpirationDate = *a.Balance.ExpirationDate
+			}
 		}
 		b.account = ub
 		if b.MatchFilter(a.Balance, false, false) {
@@ -287,7 +302,8 @@ func (ub *Account) getBalancesForPrefix(prefix, category, tor string, sharedGrou
 		if b.Disabled {
 			continue
 		}
-		if b.IsExpired() || (len(b.SharedGroups) == 0 && b.GetValue() <= 0 && !b.Blocker) {
+		b.reserved = ub.reservedValue(b.Uuid)
+		if b.IsExpired() || (len(b.SharedGroups) == 0 && b.getAvailableValue() <= 0 && !b.Blocker) {
 			continue
 		}
 		if sharedGroup != "" && b.SharedGroups[sharedGroup] == false {
@@ -497,6 +513,10 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 		if len(leftCC.Timespans) > 0 && leftCC.Cost > 0 && !ub.AllowNegative && !dryRun {
 			utils.Logger.Warning(fmt.Sprintf("<Rater> Going negative on account %s with AllowNegative: false", cd.GetAccountKey()))
 		}
+		debtLimit := -1.0 // how far the default balance may go negative, -1 for no limit
+		if lmt := ub.creditLimit(); lmt > 0 && !dryRun {
+			debtLimit = lmt
+		}
 		leftCC.Timespans.Decompress()
 		for tsIndex, ts := range leftCC.Timespans {
 			if ts.Increments == nil {
@@ -531,6 +551,12 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 
 				cost := increment.Cost
 				defaultBalance := ub.GetDefaultMoneyBalance()
+				if debtLimit >= 0 && defaultBalance.GetValue()-cost < -debtLimit {
+					// debit only up to the credit limit, the rest stays unpaid
+					cost = math.Max(defaultBalance.GetValue()+debtLimit, 0)
+					increment.Cost = cost
+					err = utils.ErrInsufficientCredit
+				}
 				defaultBalance.SubstractValue(cost)
 				increment.BalanceInfo.Monetary = &MonetaryInfo{
 					UUID:  defaultBalance.Uuid,
@@ -549,6 +575,9 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 							DestinationIDs: utils.NewStringMap(leftCC.Destination),
 						})
 				}
+				if err == utils.ErrInsufficientCredit {
+					goto COMMIT
+				}
 			}
 		}
 	}
@@ -743,7 +772,7 @@ func (acc *Account) CleanExpiredStuff() {
 	if config.CgrConfig().RalsCfg().RemoveExpired {
 		for key, bm := range acc.BalanceMap {
 			for i := 0; i < len(bm); i++ {
-				if bm[i].IsExpired() {
+				if bm[i].IsExpired() && !bm[i].isInGracePeriod() {
 					// delete it
 					bm = append(bm[:i], bm[i+1:]...)
 				}
@@ -757,6 +786,7 @@ func (acc *Account) CleanExpiredStuff() {
 			acc.ActionTriggers = append(acc.ActionTriggers[:i], acc.ActionTriggers[i+1:]...)
 		}
 	}
+	acc.cleanExpiredReservations()
 }
 
 func (acc *Account) allBalancesExpired() bool {
@@ -819,10 +849,31 @@ func (acc *Account) Clone() *Account {
 		ActionTriggers: nil, // not used when cloned (dryRun)
 		AllowNegative:  acc.AllowNegative,
 		Disabled:       acc.Disabled,
+		CreditLimit:    acc.CreditLimit,
 	}
 	for key, balanceChain := range acc.BalanceMap {
 		newAcc.BalanceMap[key] = balanceChain.Clone()
 	}
+	if acc.Reservations != nil {
+		newAcc.Reservations = make(map[string]*BalanceReservation, len(acc.Reservations))
+		for rsvID, rsv := range acc.Reservations {
+			newAcc.Reservations[rsvID] = rsv
+		}
+	}
+	if acc.Subscriptions != nil {
+		newAcc.Subscriptions = make(map[string]*Subscription, len(acc.Subscriptions))
+		for prdctID, sub := range acc.Subscriptions {
+			cln := *sub
+			newAcc.Subscriptions[prdctID] = &cln
+		}
+	}
+	if acc.SpendingCaps != nil {
+		newAcc.SpendingCaps = make(map[string]*SpendingCap, len(acc.SpendingCaps))
+		for period, sc := range acc.SpendingCaps {
+			cln := *sc
+			newAcc.SpendingCaps[period] = &cln
+		}
+	}
 	return newAcc
 }
 
@@ -834,7 +885,7 @@ func (acc *Account) DebitConnectionFee(cc *CallCost, usefulMoneyBalances Balance
 		//log.Print("CONNECT FEE: %f", connectFee)
 		connectFeePaid := false
 		for _, b := range usefulMoneyBalances {
-			if b.GetValue() >= connectFee {
+			if b.getAvailableValue() >= connectFee {
 				b.SubstractValue(connectFee)
 				// the conect fee is not refundable!
 				if count {
@@ -1053,7 +1104,9 @@ func (acc *Account) AsAccountSummary() *AccountSummary {
 	}
 	for balanceType, balances := range acc.BalanceMap {
 		for _, balance := range balances {
-			ad.BalanceSummaries = append(ad.BalanceSummaries, balance.AsBalanceSummary(balanceType))
+			bs := balance.AsBalanceSummary(balanceType)
+			bs.Reserved = acc.reservedValue(balance.Uuid)
+			ad.BalanceSummaries = append(ad.BalanceSummaries, bs)
 		}
 	}
 	return ad
@@ -1548,7 +1548,7 @@ func TestMaxDurationShared(t *testing.T) {
 
 	dm.DataDB().SetAccount(groupie)
 	dm.SetSharedGroup(sg, utils.NonTransactional)
-	duration, err := cd.getMaxSessionDuration(rif)
+	duration, err := cd.getMaxSessionDuration(rif, false)
 	if err != nil {
 		t.Error("Error getting max session duration from shared group: ", err)
 	}
@@ -1574,7 +1574,7 @@ func TestMaxDurationConnectFeeOnly(t *testing.T) {
 		utils.MONETARY: Balances{&Balance{Uuid: "moneya", Value: 0.2}},
 	}}
 
-	duration, err := cd.getMaxSessionDuration(rif)
+	duration, err := cd.getMaxSessionDuration(rif, false)
 	if err != nil {
 		t.Error("Error getting max session duration: ", err)
 	}
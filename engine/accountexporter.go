@@ -0,0 +1,297 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+var accountExporter *AccountExporter // exports the account changes journaled on save
+
+// SetAccountExporter sets the AccountExporter receiving the changes of the accounts
+func SetAccountExporter(ae *AccountExporter) {
+	accountExporter = ae
+}
+
+// accountState is the part of an Account exported on change
+type accountState struct {
+	allowNegative bool
+	disabled      bool
+	triggers      map[string]string        // action trigger IDs, indexed on UniqueID
+	balances      map[string]*balanceValue // indexed on UUID
+}
+
+// exportedState returns the current state of the account as compared by the AccountExporter
+func (acc *Account) exportedState() (st *accountState) {
+	st = &accountState{allowNegative: acc.AllowNegative, disabled: acc.Disabled,
+		triggers: make(map[string]string), balances: acc.balanceValues()}
+	for _, at := range acc.ActionTriggers {
+		st.triggers[at.UniqueID] = at.ID
+	}
+	return
+}
+
+// accountChanges returns the events describing the differences between the old and the current state of the account
+func accountChanges(acntID string, old, crrnt *accountState, rsn *BalanceMovement) (evs []map[string]interface{}) {
+	tntID := utils.NewTenantID(acntID)
+	now := time.Now()
+	newEvent := func(evType string) (ev map[string]interface{}) {
+		ev = map[string]interface{}{
+			utils.EventType:   evType,
+			utils.EventSource: utils.AccountService,
+			utils.Tenant:      tntID.Tenant,
+			utils.Account:     tntID.ID,
+			utils.EventTime:   now.Format(time.RFC3339),
+		}
+		if rsn != nil {
+			ev[utils.Reason] = rsn.Reason
+			if rsn.CGRID != "" {
+				ev[utils.CGRID] = rsn.CGRID
+			}
+			if rsn.ActionID != "" {
+				ev[utils.ActionID] = rsn.ActionID
+			}
+		}
+		return
+	}
+	var newTrgIDs []string
+	for uniqID, trgID := range crrnt.triggers {
+		if _, has := old.triggers[uniqID]; !has && !utils.IsSliceMember(newTrgIDs, trgID) {
+			newTrgIDs = append(newTrgIDs, trgID)
+		}
+	}
+	if old.allowNegative != crrnt.allowNegative || old.disabled != crrnt.disabled ||
+		len(newTrgIDs) != 0 {
+		ev := newEvent(utils.AccountUpdate)
+		ev[utils.AllowNegative] = crrnt.allowNegative
+		ev[utils.Disabled] = crrnt.disabled
+		if len(newTrgIDs) != 0 {
+			sort.Strings(newTrgIDs)
+			ev[utils.ActionTriggerIDs] = newTrgIDs
+		}
+		evs = append(evs, ev)
+	}
+	uuids := make([]string, 0, len(crrnt.balances))
+	for uuid := range crrnt.balances {
+		uuids = append(uuids, uuid)
+	}
+	for uuid := range old.balances {
+		if _, has := crrnt.balances[uuid]; !has {
+			uuids = append(uuids, uuid)
+		}
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		oldBlnc, hasOld := old.balances[uuid]
+		blnc, hasCrr := crrnt.balances[uuid]
+		var ev map[string]interface{}
+		switch {
+		case hasOld && hasCrr:
+			if *oldBlnc == *blnc {
+				continue
+			}
+			ev = newEvent(utils.BalanceUpdate)
+		case hasCrr: // new balance
+			ev = newEvent(utils.BalanceUpdate)
+		default: // removed balance
+			blnc = oldBlnc
+			if !blnc.ExpirationDate.IsZero() && blnc.ExpirationDate.Before(now) {
+				ev = newEvent(utils.BalanceExpired)
+			} else {
+				ev = newEvent(utils.BalanceRemoved)
+			}
+		}
+		ev[utils.BalanceUUID] = uuid
+		ev[utils.BalanceID] = blnc.ID
+		ev[utils.BalanceType] = blnc.Type
+		ev[utils.Units] = blnc.Value
+		ev[utils.Disabled] = blnc.Disabled
+		if !blnc.ExpirationDate.IsZero() {
+			ev[utils.ExpiryTime] = blnc.ExpirationDate.Format(time.RFC3339)
+		}
+		evs = append(evs, ev)
+	}
+	return
+}
+
+// NewAccountExporter constructs the AccountExporter out of the account_exporter config
+func NewAccountExporter(cfg *config.CGRConfig, filterS *FilterS) *AccountExporter {
+	return &AccountExporter{
+		filterS:     filterS,
+		filterIDs:   cfg.AccountExporterCfg().Filters,
+		exportPath:  cfg.AccountExporterCfg().ExportPath,
+		transport:   cfg.AccountExporterCfg().Transport,
+		attempts:    cfg.AccountExporterCfg().Attempts,
+		fallbackDir: cfg.GeneralCfg().FailedPostsDir,
+		httpPoster: NewHTTPPoster(cfg.GeneralCfg().HttpSkipTlsVerify,
+			cfg.GeneralCfg().ReplyTimeout),
+		evsQueue:   make(chan *accountEvents, accountExporterQueueLen),
+		stopExport: make(chan struct{}),
+		exportDone: make(chan struct{}),
+	}
+}
+
+// accountExporterQueueLen is the number of account saves queued, the changes of the saves over it being dropped
+const accountExporterQueueLen = 1000
+
+// accountEvents are the change events of one account save
+type accountEvents struct {
+	acntID string
+	evs    []map[string]interface{}
+}
+
+// AccountExporter posts the changes of the accounts and of their balances to the poster backends
+// acting as change data capture feed towards external systems
+type AccountExporter struct {
+	filterS     *FilterS
+	filterIDs   []string
+	exportPath  string
+	transport   string
+	attempts    int
+	fallbackDir string
+	httpPoster  *HTTPPoster
+	evsQueue    chan *accountEvents // the saves are exported one by one, in the order they happened
+	stopExport  chan struct{}
+	exportDone  chan struct{}
+	dropped     uint64 // account saves whose changes were dropped on full queue
+}
+
+// ListenAndServe exports the queued account changes until Shutdown is called
+// the changes queued at shutdown are exported before returning
+func (ae *AccountExporter) ListenAndServe() {
+	defer close(ae.exportDone)
+	for {
+		select {
+		case <-ae.stopExport:
+			for {
+				select {
+				case acntEvs := <-ae.evsQueue:
+					ae.exportEvents(acntEvs)
+				default:
+					return
+				}
+			}
+		case acntEvs := <-ae.evsQueue:
+			ae.exportEvents(acntEvs)
+		}
+	}
+}
+
+// Shutdown stops the export, waiting for the queued changes to be exported
+func (ae *AccountExporter) Shutdown() {
+	close(ae.stopExport)
+	<-ae.exportDone
+}
+
+// ShutdownAccountExporter stops the AccountExporter set, if any
+func ShutdownAccountExporter() {
+	if accountExporter != nil {
+		accountExporter.Shutdown()
+	}
+}
+
+// exportEvents posts the change events of one account save, in order
+func (ae *AccountExporter) exportEvents(acntEvs *accountEvents) {
+	for _, ev := range acntEvs.evs {
+		if err := ae.export(ev); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s exporting event: %s of account: %s",
+					utils.AccountExporter, err.Error(), utils.ToJSON(ev), acntEvs.acntID))
+		}
+	}
+}
+
+// journalChanges records the changes of the account since they were last journaled, to be exported once the account is saved
+func (acc *Account) journalChanges() {
+	crrnt := acc.exportedState()
+	acc.journal.evs = append(acc.journal.evs,
+		accountChanges(acc.ID, acc.journal.exported, crrnt, acc.journal.reason)...)
+	acc.journal.exported = crrnt
+}
+
+// exportChanges queues the change events of one account save
+// the events of consecutive saves are exported in the same order
+// called with the account locked, it never waits for the export: on full queue the events are dropped
+func (ae *AccountExporter) exportChanges(acntID string, evs []map[string]interface{}) {
+	select {
+	case ae.evsQueue <- &accountEvents{acntID: acntID, evs: evs}:
+	default:
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> queue full, dropping events: %s of account: %s, dropped saves: %d",
+				utils.AccountExporter, utils.ToJSON(evs), acntID, atomic.AddUint64(&ae.dropped, 1)))
+	}
+}
+
+// export posts one change event if it passes the filters
+func (ae *AccountExporter) export(ev map[string]interface{}) (err error) {
+	if pass, err := ae.filterS.Pass(ev[utils.Tenant].(string), ae.filterIDs,
+		config.NewNavigableMap(ev)); err != nil || !pass {
+		return err
+	}
+	var body interface{}
+	if ae.transport == utils.META_HTTP_POST {
+		vals := url.Values{}
+		for fld, val := range ev {
+			if ids, isSlice := val.([]string); isSlice {
+				vals[fld] = ids
+				continue
+			}
+			strVal, _ := utils.IfaceAsString(val) // the event fields are all convertible
+			vals.Set(fld, strVal)
+		}
+		body = vals
+	} else if body, err = json.Marshal(ev); err != nil {
+		return
+	}
+	fallbackFileName := (&utils.FallbackFileName{
+		Module:     fmt.Sprintf("%s>%s", utils.AccountsPoster, ev[utils.EventType]),
+		Transport:  ae.transport,
+		Address:    ae.exportPath,
+		RequestID:  utils.GenUUID(),
+		FileSuffix: utils.CDREFileSuffixes[ae.transport],
+	}).AsString()
+	switch ae.transport {
+	case utils.MetaHTTPjsonMap, utils.META_HTTP_POST:
+		fallbackPath := utils.META_NONE
+		if ae.fallbackDir != utils.META_NONE {
+			fallbackPath = path.Join(ae.fallbackDir, fallbackFileName)
+		}
+		_, err = ae.httpPoster.Post(ae.exportPath, utils.PosterTransportContentTypes[ae.transport],
+			body, ae.attempts, fallbackPath)
+	case utils.MetaAMQPjsonMap:
+		err = PostersCache.PostAMQP(ae.exportPath, ae.attempts, body.([]byte),
+			utils.PosterTransportContentTypes[ae.transport], ae.fallbackDir, fallbackFileName)
+	case utils.MetaAWSjsonMap:
+		err = PostersCache.PostAWS(ae.exportPath, ae.attempts, body.([]byte), ae.fallbackDir, fallbackFileName)
+	case utils.MetaSQSjsonMap:
+		err = PostersCache.PostSQS(ae.exportPath, ae.attempts, body.([]byte), ae.fallbackDir, fallbackFileName)
+	default:
+		err = fmt.Errorf("unsupported transport: <%s>", ae.transport)
+	}
+	return
+}
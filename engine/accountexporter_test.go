@@ -0,0 +1,158 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountChanges(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	old := &accountState{
+		triggers: map[string]string{"uniq1": "TRG1"},
+		balances: map[string]*balanceValue{
+			"uuid1": {ID: "MONEY", Type: utils.MONETARY, Value: 10},
+			"uuid2": {ID: "SAME", Type: utils.MONETARY, Value: 5},
+			"uuid3": {ID: "EXPIRED", Type: utils.VOICE, Value: 60,
+				ExpirationDate: time.Now().Add(-time.Minute)},
+			"uuid4": {ID: "REMOVED", Type: utils.SMS, Value: 1},
+		},
+	}
+	crrnt := &accountState{
+		disabled: true,
+		triggers: map[string]string{"uniq1": "TRG1", "uniq2": "TRG2", "uniq3": "TRG2"},
+		balances: map[string]*balanceValue{
+			"uuid1": {ID: "MONEY", Type: utils.MONETARY, Value: 10, ExpirationDate: expiry},
+			"uuid2": {ID: "SAME", Type: utils.MONETARY, Value: 5},
+		},
+	}
+	evs := accountChanges("cgrates.org:1001", old, crrnt,
+		&BalanceMovement{Reason: TOPUP, ActionID: "ACT_1"})
+	if len(evs) != 4 {
+		t.Fatalf("unexpected events: %s", utils.ToJSON(evs))
+	}
+	if evs[0][utils.EventType] != utils.AccountUpdate || evs[0][utils.Disabled] != true ||
+		!reflect.DeepEqual(evs[0][utils.ActionTriggerIDs], []string{"TRG2"}) ||
+		evs[0][utils.Tenant] != "cgrates.org" || evs[0][utils.Account] != "1001" ||
+		evs[0][utils.Reason] != TOPUP || evs[0][utils.ActionID] != "ACT_1" {
+		t.Errorf("unexpected account event: %s", utils.ToJSON(evs[0]))
+	}
+	if evs[1][utils.EventType] != utils.BalanceUpdate || evs[1][utils.BalanceUUID] != "uuid1" ||
+		evs[1][utils.Units] != 10.0 || evs[1][utils.ExpiryTime] != expiry.Format(time.RFC3339) {
+		t.Errorf("unexpected balance update: %s", utils.ToJSON(evs[1]))
+	}
+	if evs[2][utils.EventType] != utils.BalanceExpired || evs[2][utils.BalanceID] != "EXPIRED" ||
+		evs[2][utils.BalanceType] != utils.VOICE {
+		t.Errorf("unexpected balance expiry: %s", utils.ToJSON(evs[2]))
+	}
+	if evs[3][utils.EventType] != utils.BalanceRemoved || evs[3][utils.BalanceID] != "REMOVED" {
+		t.Errorf("unexpected balance removal: %s", utils.ToJSON(evs[3]))
+	}
+	if evs := accountChanges("cgrates.org:1001", crrnt, crrnt, nil); len(evs) != 0 {
+		t.Errorf("unexpected events: %s", utils.ToJSON(evs))
+	}
+}
+
+func TestAccountExporterExportChanges(t *testing.T) {
+	rcvEvs := make(chan map[string]interface{}, 3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var ev map[string]interface{}
+		if err := json.Unmarshal(body, &ev); err != nil {
+			t.Error(err)
+		}
+		if ev[utils.Units] == 8.0 { // slow first post, the next save should still be exported after it
+			time.Sleep(50 * time.Millisecond)
+		}
+		rcvEvs <- ev
+	}))
+	defer srv.Close()
+	ae := &AccountExporter{exportPath: srv.URL, transport: utils.MetaHTTPjsonMap,
+		attempts: 1, fallbackDir: utils.META_NONE, httpPoster: NewHTTPPoster(false, time.Second),
+		evsQueue:   make(chan *accountEvents, accountExporterQueueLen),
+		stopExport: make(chan struct{}), exportDone: make(chan struct{})}
+	go ae.ListenAndServe()
+	defer ae.Shutdown()
+	SetAccountExporter(ae)
+	defer SetAccountExporter(nil)
+	acc := &Account{
+		ID: "cgrates.org:cdc",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: {&Balance{Uuid: "uuid1", ID: "MONEY", Value: 10}},
+		},
+	}
+	acc.journalBalances(&BalanceMovement{Reason: utils.MetaDebit, CGRID: "cgrid1"})
+	acc.BalanceMap[utils.MONETARY][0].SubstractValue(2)
+	acc.journalBalances(&BalanceMovement{Reason: DISABLE_ACCOUNT})
+	acc.Disabled = true
+	acc.storeBalanceJournal()
+	if len(acc.journal.evs) != 0 || len(acc.journal.mvs) != 0 {
+		t.Errorf("journal not flushed: %s", utils.ToJSON(acc.journal.evs))
+	}
+	acc.journalBalances(&BalanceMovement{Reason: utils.MetaDebit, CGRID: "cgrid2"})
+	acc.BalanceMap[utils.MONETARY][0].SubstractValue(3)
+	acc.storeBalanceJournal()
+	for _, eEv := range []map[string]interface{}{
+		{utils.EventType: utils.BalanceUpdate, utils.Reason: utils.MetaDebit,
+			utils.CGRID: "cgrid1", utils.Units: 8.0},
+		{utils.EventType: utils.AccountUpdate, utils.Reason: DISABLE_ACCOUNT,
+			utils.Disabled: true},
+		{utils.EventType: utils.BalanceUpdate, utils.Reason: utils.MetaDebit,
+			utils.CGRID: "cgrid2", utils.Units: 5.0},
+	} {
+		select {
+		case ev := <-rcvEvs:
+			for fld, val := range eEv {
+				if ev[fld] != val {
+					t.Errorf("expecting %s: %v, received event: %s", fld, val, utils.ToJSON(ev))
+				}
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event not exported: %s", utils.ToJSON(eEv))
+		}
+	}
+}
+
+func TestAccountExporterFullQueue(t *testing.T) {
+	ae := &AccountExporter{evsQueue: make(chan *accountEvents, 1),
+		stopExport: make(chan struct{}), exportDone: make(chan struct{})}
+	evs := []map[string]interface{}{{utils.EventType: utils.AccountUpdate}}
+	done := make(chan struct{})
+	go func() { // no worker consuming the queue, the second save should not wait
+		ae.exportChanges("cgrates.org:1001", evs)
+		ae.exportChanges("cgrates.org:1001", evs)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("export blocked on full queue")
+	}
+	if ae.dropped != 1 || len(ae.evsQueue) != 1 {
+		t.Errorf("dropped: %d, queued: %d", ae.dropped, len(ae.evsQueue))
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"math"
 	"net"
 	"net/smtp"
 	"path"
@@ -84,6 +85,12 @@ const (
 	SetExpiry                 = "*set_expiry"
 	MetaPublishAccount        = "*publish_account"
 	MetaPublishBalance        = "*publish_balance"
+	MetaActivateStagedLoad    = "*activate_staged_load"
+	MetaRollover              = "*rollover"
+	MetaRenewSubscriptions    = "*renew_subscriptions"
+	MetaSetCreditLimit        = "*set_credit_limit"
+	MetaSetSpendingCap        = "*set_spending_cap"
+	MetaReAuthorizeSession    = "*reauthorize_session"
 )
 
 func (a *Action) Clone() *Action {
@@ -127,6 +134,12 @@ func getActionFunc(typ string) (actionTypeFunc, bool) {
 		utils.MetaAMQPjsonMap:     sendAMQP,
 		utils.MetaAWSjsonMap:      sendAWS,
 		utils.MetaSQSjsonMap:      sendSQS,
+		MetaActivateStagedLoad:    activateStagedLoad,
+		MetaRollover:              rolloverAction,
+		MetaRenewSubscriptions:    renewSubscriptionsAction,
+		MetaSetCreditLimit:        setCreditLimitAction,
+		MetaSetSpendingCap:        setSpendingCapAction,
+		MetaReAuthorizeSession:    reAuthorizeSessionAction,
 	}
 	f, exists := actionFuncMap[typ]
 	return f, exists
@@ -826,6 +839,108 @@ func publishBalance(ub *Account, a *Action, acs Actions, extraData interface{})
 	return nil
 }
 
+// activateStagedLoad writes live the staged load with the ID in ExtraParameters
+func activateStagedLoad(ub *Account, a *Action, acs Actions, extraData interface{}) error {
+	return dm.ActivateStagedLoad(a.ExtraParameters,
+		config.CgrConfig().GeneralCfg().LoadHistorySize)
+}
+
+// rolloverCap parses the ExtraParameters of *rollover: the maximum value rolled over,
+// either absolute (eg: 300) or as percentage out of the value left (eg: 50%), no cap if empty
+func rolloverCap(extraParams string) (limit float64, percent bool, err error) {
+	if extraParams == "" {
+		return -1, false, nil
+	}
+	if percent = strings.HasSuffix(extraParams, "%"); percent {
+		extraParams = strings.TrimSuffix(extraParams, "%")
+	}
+	if limit, err = strconv.ParseFloat(extraParams, 64); err != nil {
+		return
+	}
+	if limit < 0 {
+		err = fmt.Errorf("negative rollover cap: %s", extraParams)
+	} else if percent && limit > 100 {
+		err = fmt.Errorf("rollover cap over 100%%: %s%%", extraParams)
+	}
+	return
+}
+
+// rolloverAction moves the value left on the matching balances into new balances, expiring with the action
+// the value over the cap is lost and the rolled over balances are not rolled over again
+// the value held by reservations stays on the source balance
+func rolloverAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if ub == nil {
+		return errors.New("nil account")
+	}
+	limit, percent, err := rolloverCap(a.ExtraParameters)
+	if err != nil {
+		return
+	}
+	balanceType := a.Balance.GetType()
+	var rolled Balances
+	for _, b := range ub.BalanceMap[balanceType] {
+		if b.RolloverOf != "" || b.GetValue() <= 0 ||
+			!b.MatchFilter(a.Balance, false, true) { // the expiry in action is the one of the new balance
+			continue
+		}
+		reserved := ub.reservedValue(b.Uuid)
+		value := b.GetValue() - reserved
+		if value <= 0 {
+			continue
+		}
+		if percent {
+			value = value * limit / 100
+		} else if limit >= 0 {
+			value = math.Min(value, limit)
+		}
+		b.SetValue(reserved)
+		if value == 0 {
+			continue
+		}
+		nb := b.Clone()
+		nb.Uuid = utils.GenUUID()
+		nb.ID = ""
+		nb.Factor = b.Factor
+		nb.ExpirationDate = a.Balance.GetExpirationDate()
+		nb.RolloverOf = b.Uuid
+		nb.SetValue(value)
+		rolled = append(rolled, nb)
+	}
+	ub.BalanceMap[balanceType] = append(ub.BalanceMap[balanceType], rolled...)
+	return
+}
+
+// reAuthorizeSessionAction asks SessionS to re-authorize the active sessions of the account
+// ExtraParameters can hold additional session filters as JSON (ie: {"Category":"call"})
+func reAuthorizeSessionAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if ub == nil {
+		return errors.New("nil account")
+	}
+	if schedSessionSConns == nil {
+		return fmt.Errorf("no connection with SessionS")
+	}
+	fltr := make(map[string]string)
+	if a.ExtraParameters != "" {
+		if err = json.Unmarshal([]byte(a.ExtraParameters), &fltr); err != nil {
+			return
+		}
+	}
+	acntTnt := utils.NewTenantID(ub.ID)
+	fltr[utils.Tenant] = acntTnt.Tenant
+	fltr[utils.Account] = acntTnt.ID
+	var reply string
+	if err = schedSessionSConns.Call(utils.SessionSv1ReAuthorize, fltr, &reply); err != nil {
+		if err.Error() == utils.ErrNotFound.Error() { // no active sessions
+			err = nil
+		}
+		return
+	}
+	if reply != utils.OK {
+		err = errors.New(reply)
+	}
+	return
+}
+
 // Structure to store actions according to weight
 type Actions []*Action
 
@@ -334,6 +334,8 @@ func (at *ActionTiming) Execute(successActions, failedActions chan *Action) (err
 					transactionFailed = true
 					break
 				}
+				acc.journalBalances(&BalanceMovement{Reason: a.ActionType,
+					ActionID: a.Id, ActionPlanID: at.GetActionPlanID()})
 				if err := actionFunction(acc, a, aac, at.ExtraData); err != nil {
 					utils.Logger.Err(fmt.Sprintf("Error executing action %s: %v!", a.ActionType, err))
 					transactionFailed = true
@@ -351,6 +353,8 @@ func (at *ActionTiming) Execute(successActions, failedActions chan *Action) (err
 			}
 			if !transactionFailed && !removeAccountActionFound {
 				dm.DataDB().SetAccount(acc)
+				acc.storeBalanceJournal()
+				acc.postCdrLogs()
 			}
 			return 0, nil
 		}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
@@ -67,6 +67,7 @@ func (at *ActionTrigger) Execute(ub *Account) (err error) {
 	at.Executed = true
 	transactionFailed := false
 	removeAccountActionFound := false
+	journaling := false
 	for _, a := range aac {
 		// check action filter
 		if len(a.Filter) > 0 {
@@ -96,6 +97,13 @@ func (at *ActionTrigger) Execute(ub *Account) (err error) {
 			break
 		}
 		//go utils.Logger.Info(fmt.Sprintf("Executing %v, %v: %v", ub, sq, a))
+		if ub != nil {
+			if prevRsn := ub.journalBalances(&BalanceMovement{Reason: a.ActionType,
+				ActionID: a.Id}); !journaling {
+				journaling = true
+				defer ub.journalBalances(prevRsn) // back to the changes triggering us
+			}
+		}
 		if err := actionFunction(ub, a, aac, nil); err != nil {
 			utils.Logger.Err(fmt.Sprintf("Error executing action %s: %v!", a.ActionType, err))
 			transactionFailed = false
@@ -110,6 +118,8 @@ func (at *ActionTrigger) Execute(ub *Account) (err error) {
 	}
 	if !transactionFailed && ub != nil && !removeAccountActionFound {
 		dm.DataDB().SetAccount(ub)
+		ub.storeBalanceJournal()
+		ub.postCdrLogs()
 	}
 	return
 }
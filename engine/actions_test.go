@@ -2497,6 +2497,141 @@ func TestCacheGetClonedActions(t *testing.T) {
 	}
 }
 
+func TestActionRollover(t *testing.T) {
+	acc := &Account{
+		ID: "cgrates.org:rollover",
+		BalanceMap: map[string]Balances{
+			utils.VOICE: {
+				&Balance{Uuid: "uuid1", ID: "BUNDLE", Value: 100,
+					DestinationIDs: utils.NewStringMap("NAT"), Weight: 10},
+				&Balance{Uuid: "uuid2", ID: "OTHER", Value: 50},
+			},
+		},
+	}
+	expTime := time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)
+	a := &Action{
+		ActionType:      MetaRollover,
+		ExtraParameters: "50%",
+		Balance: &BalanceFilter{
+			ID:             utils.StringPointer("BUNDLE"),
+			Type:           utils.StringPointer(utils.VOICE),
+			ExpirationDate: &expTime,
+		},
+	}
+	if err := rolloverAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.BalanceMap[utils.VOICE]) != 3 {
+		t.Fatalf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	if acc.BalanceMap[utils.VOICE][0].Value != 0 || acc.BalanceMap[utils.VOICE][1].Value != 50 {
+		t.Errorf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	if b := acc.BalanceMap[utils.VOICE][2]; b.Value != 50 || b.RolloverOf != "uuid1" ||
+		!b.ExpirationDate.Equal(expTime) || b.Weight != 10 ||
+		!b.DestinationIDs.Equal(utils.NewStringMap("NAT")) || b.Uuid == "uuid1" {
+		t.Errorf("unexpected rolled over balance: %s", utils.ToJSON(b))
+	}
+	// absolute cap, rolled over balances are not rolled over again
+	acc.BalanceMap[utils.VOICE][0].SetValue(100)
+	a.ExtraParameters = "30"
+	a.Balance.ID = nil
+	if err := rolloverAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.BalanceMap[utils.VOICE]) != 5 {
+		t.Fatalf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	for _, b := range acc.BalanceMap[utils.VOICE][3:] {
+		if b.Value != 30 || (b.RolloverOf != "uuid1" && b.RolloverOf != "uuid2") {
+			t.Errorf("unexpected rolled over balance: %s", utils.ToJSON(b))
+		}
+	}
+	if acc.BalanceMap[utils.VOICE][2].Value != 50 {
+		t.Errorf("rolled over balance changed: %s", utils.ToJSON(acc.BalanceMap[utils.VOICE][2]))
+	}
+	a.ExtraParameters = "-10%"
+	if err := rolloverAction(acc, a, nil, nil); err == nil {
+		t.Error("expecting error for negative cap")
+	}
+	a.ExtraParameters = "150%"
+	if err := rolloverAction(acc, a, nil, nil); err == nil {
+		t.Error("expecting error for cap over 100%")
+	}
+	// the reserved value stays on the source balance
+	acc.BalanceMap[utils.VOICE] = Balances{
+		&Balance{Uuid: "uuid1", ID: "BUNDLE", Value: 100},
+	}
+	acc.Reservations = map[string]*BalanceReservation{
+		"RSV1": {ID: "RSV1", BalanceType: utils.VOICE,
+			Amounts: map[string]float64{"uuid1": 40}},
+	}
+	a.ExtraParameters = "100%"
+	if err := rolloverAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.BalanceMap[utils.VOICE]) != 2 {
+		t.Fatalf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	if b := acc.BalanceMap[utils.VOICE][0]; b.Value != 40 {
+		t.Errorf("unexpected source balance: %s", utils.ToJSON(b))
+	}
+	if b := acc.BalanceMap[utils.VOICE][1]; b.Value != 60 || b.RolloverOf != "uuid1" {
+		t.Errorf("unexpected rolled over balance: %s", utils.ToJSON(b))
+	}
+	// nothing to roll over if all of the balance is reserved
+	acc.Reservations["RSV1"].Amounts["uuid1"] = 40
+	acc.BalanceMap[utils.VOICE] = acc.BalanceMap[utils.VOICE][:1]
+	if err := rolloverAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.BalanceMap[utils.VOICE]) != 1 || acc.BalanceMap[utils.VOICE][0].Value != 40 {
+		t.Errorf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+}
+
+type testReAuthSessionS struct {
+	fltrs []map[string]string
+}
+
+func (sS *testReAuthSessionS) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if serviceMethod != utils.SessionSv1ReAuthorize {
+		return utils.ErrNotImplemented
+	}
+	fltr := args.(map[string]string)
+	sS.fltrs = append(sS.fltrs, fltr)
+	if fltr[utils.Account] == "nosessions" {
+		return utils.ErrNotFound
+	}
+	*reply.(*string) = utils.OK
+	return nil
+}
+
+func TestActionReAuthorizeSession(t *testing.T) {
+	a := &Action{ActionType: MetaReAuthorizeSession, ExtraParameters: `{"Category":"call"}`}
+	acc := &Account{ID: "cgrates.org:reauth"}
+	SetSchedSessionSConns(nil)
+	if err := reAuthorizeSessionAction(acc, a, nil, nil); err == nil {
+		t.Error("expecting error without SessionS connection")
+	}
+	sS := new(testReAuthSessionS)
+	SetSchedSessionSConns(sS)
+	defer SetSchedSessionSConns(nil)
+	if err := reAuthorizeSessionAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	eFltr := map[string]string{utils.Tenant: "cgrates.org", utils.Account: "reauth",
+		utils.Category: "call"}
+	if len(sS.fltrs) != 1 || !reflect.DeepEqual(eFltr, sS.fltrs[0]) {
+		t.Errorf("expecting: %+v, received: %+v", eFltr, sS.fltrs)
+	}
+	// no active sessions for the account is not an error
+	a.ExtraParameters = ""
+	if err := reAuthorizeSessionAction(&Account{ID: "cgrates.org:nosessions"}, a, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
+
 /**************** Benchmarks ********************************/
 
 func BenchmarkUUID(b *testing.B) {
@@ -20,18 +20,24 @@ package engine
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
 )
 
 func NewAttributeService(dm *DataManager, filterS *FilterS,
 	stringIndexedFields, prefixIndexedFields *[]string,
-	processRuns int) (*AttributeService, error) {
+	processRuns int, numberS rpcclient.RpcClientConnection) (*AttributeService, error) {
+	if numberS != nil && reflect.ValueOf(numberS).IsNil() {
+		numberS = nil
+	}
 	return &AttributeService{dm: dm, filterS: filterS,
 		stringIndexedFields: stringIndexedFields,
 		prefixIndexedFields: prefixIndexedFields,
-		processRuns:         processRuns}, nil
+		processRuns:         processRuns,
+		numberS:             numberS}, nil
 }
 
 type AttributeService struct {
@@ -40,6 +46,7 @@ type AttributeService struct {
 	stringIndexedFields *[]string
 	prefixIndexedFields *[]string
 	processRuns         int
+	numberS             rpcclient.RpcClientConnection // normalizes the numbers before matching the profiles
 }
 
 // ListenAndServe will initialize the service
@@ -147,6 +154,20 @@ type AttrArgsProcessEvent struct {
 	utils.CGREvent
 }
 
+// attributeForEvent returns the first of attrs with the filters passing for the event, nil if none
+func (alS *AttributeService) attributeForEvent(args *AttrArgsProcessEvent,
+	attrs []*Attribute) (*Attribute, error) {
+	for _, attr := range attrs {
+		if pass, err := alS.filterS.Pass(args.Tenant, attr.FilterIDs,
+			config.NewNavigableMap(args.Event)); err != nil {
+			return nil, err
+		} else if pass {
+			return attr, nil
+		}
+	}
+	return nil, nil
+}
+
 // processEvent will match event with attribute profile and do the necessary replacements
 func (alS *AttributeService) processEvent(args *AttrArgsProcessEvent) (
 	rply *AttrSProcessEventReply, err error) {
@@ -170,9 +191,13 @@ func (alS *AttributeService) processEvent(args *AttrArgsProcessEvent) (
 	for fldName, initialMp := range attrPrf.attributesIdx {
 		initEvValIf, has := args.Event[fldName]
 		if !has {
-			anyInitial, hasAny := initialMp[utils.ANY]
-			if hasAny && anyInitial.Append { // add field name
-				substitute, err := anyInitial.Substitute.ParseEvent(args.Event)
+			anyInitial, err := alS.attributeForEvent(args, initialMp[utils.ANY])
+			if err != nil {
+				return nil, err
+			}
+			if anyInitial != nil && anyInitial.Append &&
+				anyInitial.Type != utils.MetaRemove { // add field name
+				substitute, err := anyInitial.substitute(args.Event)
 				if err != nil {
 					return nil, err
 				}
@@ -181,12 +206,13 @@ func (alS *AttributeService) processEvent(args *AttrArgsProcessEvent) (
 			}
 			continue
 		}
-		attrVal, has := initialMp[initEvValIf]
-		if !has {
-			attrVal, has = initialMp[utils.ANY]
+		attrVal, err := alS.attributeForEvent(args,
+			append(append([]*Attribute{}, initialMp[initEvValIf]...), initialMp[utils.ANY]...))
+		if err != nil {
+			return nil, err
 		}
-		if has {
-			substitute, err := attrVal.Substitute.ParseEvent(args.Event)
+		if attrVal != nil {
+			substitute, err := attrVal.substitute(args.Event)
 			if err != nil {
 				return nil, err
 			}
@@ -232,6 +258,18 @@ func (alS *AttributeService) V1ProcessEvent(args *AttrArgsProcessEvent,
 	if args.ProcessRuns == nil || *args.ProcessRuns == 0 {
 		args.ProcessRuns = utils.IntPointer(alS.processRuns)
 	}
+	var numAltered []string // fields changed by NumberS
+	if alS.numberS != nil {
+		var numRply NumberSProcessEventReply
+		if err = alS.numberS.Call(utils.NumberSv1ProcessEvent,
+			&args.CGREvent, &numRply); err != nil {
+			return utils.NewErrServerError(err)
+		}
+		if len(numRply.AlteredFields) != 0 {
+			numAltered = numRply.AlteredFields
+			args.CGREvent = *numRply.CGREvent
+		}
+	}
 	var apiRply *AttrSProcessEventReply // aggregate response here
 	for i := 0; i < *args.ProcessRuns; i++ {
 		evRply, err := alS.processEvent(args)
@@ -241,6 +279,11 @@ func (alS *AttributeService) V1ProcessEvent(args *AttrArgsProcessEvent,
 			} else if i != 0 { // ignore "not found" in a loop different than 0
 				err = nil
 				break
+			} else if err == utils.ErrNotFound && len(numAltered) != 0 { // the numbers were still changed
+				apiRply = &AttrSProcessEventReply{
+					MatchedProfiles: []string{},
+					CGREvent:        args.CGREvent.Clone()}
+				break
 			}
 			return err
 		}
@@ -270,6 +313,11 @@ func (alS *AttributeService) V1ProcessEvent(args *AttrArgsProcessEvent,
 			break
 		}
 	}
+	for _, fldName := range numAltered {
+		if !utils.IsSliceMember(apiRply.AlteredFields, fldName) {
+			apiRply.AlteredFields = append(apiRply.AlteredFields, fldName)
+		}
+	}
 	*reply = *apiRply
 	return
 }
@@ -19,6 +19,7 @@ package engine
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -31,13 +32,15 @@ var (
 	expTimeAttributes      = time.Now().Add(time.Duration(20 * time.Minute))
 	attrService            *AttributeService
 	dmAtr                  *DataManager
-	mapSubstitutes         = map[string]map[interface{}]*Attribute{
+	mapSubstitutes         = map[string]map[interface{}][]*Attribute{
 		utils.Account: {
 			utils.META_ANY: {
-				FieldName:  utils.Account,
-				Initial:    utils.META_ANY,
-				Substitute: config.NewRSRParsersMustCompile("1010", true, utils.INFIELD_SEP),
-				Append:     true,
+				{
+					FieldName:  utils.Account,
+					Initial:    utils.META_ANY,
+					Substitute: config.NewRSRParsersMustCompile("1010", true, utils.INFIELD_SEP),
+					Append:     true,
+				},
 			},
 		},
 	}
@@ -181,7 +184,7 @@ func TestAttributePopulateAttrService(t *testing.T) {
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
-	attrService, err = NewAttributeService(dmAtr, &FilterS{dm: dmAtr, cfg: defaultCfg}, nil, nil, 1)
+	attrService, err = NewAttributeService(dmAtr, &FilterS{dm: dmAtr, cfg: defaultCfg}, nil, nil, 1, nil)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
@@ -1209,3 +1212,242 @@ func TestAttributeProcessSubstitute(t *testing.T) {
 		t.Errorf("Expecting %+v, received: %+v", eRply.CGREvent.Event, reply.CGREvent.Event)
 	}
 }
+
+func TestAttributeProcessAttributeFilters(t *testing.T) {
+	//refresh the DM
+	if err := dmAtr.DataDB().Flush(""); err != nil {
+		t.Error(err)
+	}
+	attrPrf1 := &AttributeProfile{
+		Tenant:    config.CgrConfig().GeneralCfg().DefaultTenant,
+		ID:        "ATTR_FLTRS",
+		Contexts:  []string{utils.MetaSessionS},
+		FilterIDs: []string{"*string:Field1:Value1"},
+		Attributes: []*Attribute{
+			{
+				FilterIDs:  []string{"*string:Category:premium"},
+				FieldName:  utils.Subject,
+				Initial:    utils.META_ANY,
+				Substitute: config.NewRSRParsersMustCompile("SUBJ_PREMIUM", true, utils.INFIELD_SEP),
+				Append:     true,
+			},
+			{
+				FilterIDs:  []string{"*prefix:Destination:+49"},
+				FieldName:  utils.Subject,
+				Initial:    utils.META_ANY,
+				Substitute: config.NewRSRParsersMustCompile("SUBJ_DE", true, utils.INFIELD_SEP),
+				Append:     true,
+			},
+			{
+				FieldName:  utils.Subject,
+				Initial:    utils.META_ANY,
+				Substitute: config.NewRSRParsersMustCompile("SUBJ_DEFAULT", true, utils.INFIELD_SEP),
+				Append:     true,
+			},
+			{
+				FilterIDs:  []string{"*string:Category:premium"},
+				FieldName:  "Field2",
+				Initial:    utils.META_ANY,
+				Substitute: config.NewRSRParsersMustCompile("Value2", true, utils.INFIELD_SEP),
+				Append:     true,
+			},
+		},
+		Weight: 10,
+	}
+	if err := dmAtr.SetAttributeProfile(attrPrf1, true); err != nil {
+		t.Error(err)
+	}
+	for _, tc := range []struct {
+		ev      map[string]interface{}
+		eFields []string
+		eEv     map[string]interface{}
+	}{
+		{
+			ev: map[string]interface{}{
+				"Field1":          "Value1",
+				utils.Category:    "premium",
+				utils.Destination: "+4986517174963",
+			},
+			eFields: []string{utils.Subject, "Field2"},
+			eEv: map[string]interface{}{
+				"Field1":          "Value1",
+				utils.Category:    "premium",
+				utils.Destination: "+4986517174963",
+				utils.Subject:     "SUBJ_PREMIUM",
+				"Field2":          "Value2",
+			},
+		},
+		{
+			ev: map[string]interface{}{
+				"Field1":          "Value1",
+				utils.Destination: "+4986517174963",
+			},
+			eFields: []string{utils.Subject},
+			eEv: map[string]interface{}{
+				"Field1":          "Value1",
+				utils.Destination: "+4986517174963",
+				utils.Subject:     "SUBJ_DE",
+			},
+		},
+		{
+			ev: map[string]interface{}{
+				"Field1":          "Value1",
+				utils.Destination: "+4386517174963",
+			},
+			eFields: []string{utils.Subject},
+			eEv: map[string]interface{}{
+				"Field1":          "Value1",
+				utils.Destination: "+4386517174963",
+				utils.Subject:     "SUBJ_DEFAULT",
+			},
+		},
+	} {
+		attrArgs := &AttrArgsProcessEvent{
+			Context:     utils.StringPointer(utils.MetaSessionS),
+			ProcessRuns: utils.IntPointer(1),
+			CGREvent: utils.CGREvent{
+				Tenant: config.CgrConfig().GeneralCfg().DefaultTenant,
+				ID:     utils.GenUUID(),
+				Event:  tc.ev,
+			},
+		}
+		var reply AttrSProcessEventReply
+		if err := attrService.V1ProcessEvent(attrArgs, &reply); err != nil {
+			t.Errorf("Error: %+v", err)
+			continue
+		}
+		sort.Strings(tc.eFields)
+		sort.Strings(reply.AlteredFields)
+		if !reflect.DeepEqual(tc.eFields, reply.AlteredFields) {
+			t.Errorf("Expecting %+v, received: %+v", tc.eFields, reply.AlteredFields)
+		}
+		if !reflect.DeepEqual(tc.eEv, reply.CGREvent.Event) {
+			t.Errorf("Expecting %+v, received: %+v", tc.eEv, reply.CGREvent.Event)
+		}
+	}
+}
+
+func TestAttributeProcessComputedAndRemove(t *testing.T) {
+	//refresh the DM
+	if err := dmAtr.DataDB().Flush(""); err != nil {
+		t.Error(err)
+	}
+	attrPrf1 := &AttributeProfile{
+		Tenant:    config.CgrConfig().GeneralCfg().DefaultTenant,
+		ID:        "ATTR_COMPUTED",
+		Contexts:  []string{utils.MetaSessionS},
+		FilterIDs: []string{"*string:Field1:Value1"},
+		Attributes: []*Attribute{
+			{
+				FieldName:  utils.Usage,
+				Type:       utils.MetaSum,
+				Initial:    utils.META_ANY,
+				Substitute: config.NewRSRParsersMustCompile("~Usage;~ExtraUsage", true, utils.INFIELD_SEP),
+			},
+			{
+				FieldName:  utils.Cost,
+				Type:       utils.MetaMultiply,
+				Initial:    utils.META_ANY,
+				Substitute: config.NewRSRParsersMustCompile("~Cost;1.5", true, utils.INFIELD_SEP),
+			},
+			{
+				FieldName:  "UsageSeconds",
+				Initial:    utils.META_ANY,
+				Substitute: config.NewRSRParsersMustCompile("~Usage{*duration_seconds};s", true, utils.INFIELD_SEP),
+				Append:     true,
+			},
+			{
+				FieldName: "ExtraUsage",
+				Type:      utils.MetaRemove,
+				Initial:   utils.META_ANY,
+			},
+			{
+				FieldName: "Missing",
+				Type:      utils.MetaRemove,
+				Initial:   utils.META_ANY,
+				Append:    true,
+			},
+		},
+		Weight: 10,
+	}
+	if err := dmAtr.SetAttributeProfile(attrPrf1, true); err != nil {
+		t.Error(err)
+	}
+	attrArgs := &AttrArgsProcessEvent{
+		Context:     utils.StringPointer(utils.MetaSessionS),
+		ProcessRuns: utils.IntPointer(1),
+		CGREvent: utils.CGREvent{
+			Tenant: config.CgrConfig().GeneralCfg().DefaultTenant,
+			ID:     utils.GenUUID(),
+			Event: map[string]interface{}{
+				"Field1":     "Value1",
+				utils.Usage:  "1m",
+				"ExtraUsage": "30s",
+				utils.Cost:   "10",
+			},
+		},
+	}
+	eFields := []string{utils.Cost, "ExtraUsage", utils.Usage, "UsageSeconds"}
+	eEv := map[string]interface{}{
+		"Field1":       "Value1",
+		utils.Usage:    "1m30s",
+		utils.Cost:     "15",
+		"UsageSeconds": "60s",
+	}
+	var reply AttrSProcessEventReply
+	if err := attrService.V1ProcessEvent(attrArgs, &reply); err != nil {
+		t.Errorf("Error: %+v", err)
+	}
+	sort.Strings(reply.AlteredFields)
+	if !reflect.DeepEqual(eFields, reply.AlteredFields) {
+		t.Errorf("Expecting %+v, received: %+v", eFields, reply.AlteredFields)
+	}
+	if !reflect.DeepEqual(eEv, reply.CGREvent.Event) {
+		t.Errorf("Expecting %+v, received: %+v", eEv, reply.CGREvent.Event)
+	}
+}
+
+func TestAttributeCompile(t *testing.T) {
+	attr := &Attribute{
+		FieldName:  utils.Cost,
+		Type:       utils.MetaDivide,
+		Initial:    utils.META_ANY,
+		Substitute: config.NewRSRParsersMustCompile("~Cost", true, utils.INFIELD_SEP),
+	}
+	if err := attr.compile(); err == nil {
+		t.Error("expecting error on not enough operands")
+	}
+	attr.Type = "*unsupported"
+	if err := attr.compile(); err == nil {
+		t.Error("expecting error on unsupported type")
+	}
+	attr.Type = utils.META_COMPOSED
+	if err := attr.compile(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAttributeComputeOperands(t *testing.T) {
+	for _, tc := range []struct {
+		op       string
+		operands []string
+		out      string
+		hasErr   bool
+	}{
+		{utils.MetaSum, []string{"10", "2.5", "-1"}, "11.5", false},
+		{utils.MetaDifference, []string{"10", "2.5"}, "7.5", false},
+		{utils.MetaMultiply, []string{"0.5", "3"}, "1.5", false},
+		{utils.MetaDivide, []string{"10", "4"}, "2.5", false},
+		{utils.MetaDivide, []string{"10", "0"}, "", true},
+		{utils.MetaSum, []string{"1m", "30s"}, "1m30s", false},
+		{utils.MetaDifference, []string{"1m", "1000000000"}, "59s", false},
+		{utils.MetaMultiply, []string{"1m", "2"}, "", true},
+		{utils.MetaSum, []string{"1m", "Value1"}, "", true},
+	} {
+		if out, err := computeOperands(tc.op, tc.operands); (err != nil) != tc.hasErr {
+			t.Errorf("%s %v, unexpected error: %v", tc.op, tc.operands, err)
+		} else if out != tc.out {
+			t.Errorf("%s %v, expecting: <%s>, received: <%s>", tc.op, tc.operands, tc.out, out)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+var balanceExpiryNotifier *BalanceExpiryNotifier // notifies ThresholdS about the expiring balances
+
+// SetBalanceExpiryNotifier sets the BalanceExpiryNotifier to be stopped on shutdown
+func SetBalanceExpiryNotifier(ben *BalanceExpiryNotifier) {
+	balanceExpiryNotifier = ben
+}
+
+// ShutdownBalanceExpiryNotifier stops the BalanceExpiryNotifier set, if any
+func ShutdownBalanceExpiryNotifier() {
+	if balanceExpiryNotifier != nil {
+		balanceExpiryNotifier.Shutdown()
+	}
+}
+
+// isInGracePeriod checks whether the expired balance can still be restored by a top-up
+func (b *Balance) isInGracePeriod() bool {
+	grace := config.CgrConfig().RalsCfg().ExpiryGracePeriod
+	return grace > 0 && b.IsExpired() && b.ExpirationDate.Add(grace).After(time.Now())
+}
+
+// notifiedExpiry is the last expiry notification sent for a balance
+type notifiedExpiry struct {
+	expiryTime time.Time
+	expired    bool
+}
+
+// NewBalanceExpiryNotifier constructs a BalanceExpiryNotifier
+func NewBalanceExpiryNotifier(dm *DataManager, scanInterval, notifyBefore time.Duration) *BalanceExpiryNotifier {
+	return &BalanceExpiryNotifier{
+		dm:           dm,
+		scanInterval: scanInterval,
+		notifyBefore: notifyBefore,
+		notified:     make(map[string]*notifiedExpiry),
+		stopScan:     make(chan struct{}),
+		scanDone:     make(chan struct{}),
+	}
+}
+
+// BalanceExpiryNotifier scans regularly the accounts in dataDB, notifying ThresholdS
+// about the balances expiring soon and again once they are expired
+type BalanceExpiryNotifier struct {
+	dm           *DataManager
+	scanInterval time.Duration
+	notifyBefore time.Duration
+	notified     map[string]*notifiedExpiry // indexed on balance UUID
+	stopScan     chan struct{}
+	scanDone     chan struct{}
+}
+
+// ListenAndServe scans the accounts until Shutdown is called
+func (ben *BalanceExpiryNotifier) ListenAndServe() {
+	defer close(ben.scanDone)
+	if ben.scanInterval <= 0 {
+		return
+	}
+	for {
+		ben.scanAccounts()
+		select {
+		case <-ben.stopScan:
+			return
+		case <-time.After(ben.scanInterval):
+		}
+	}
+}
+
+// Shutdown stops the scanning, waiting for the scan in progress to finish
+func (ben *BalanceExpiryNotifier) Shutdown() {
+	close(ben.stopScan)
+	<-ben.scanDone
+}
+
+// scanAccounts notifies once the balances expiring within notifyBefore and once the expired ones
+// expired balances are not notified anymore after one scan interval, or after their grace period
+func (ben *BalanceExpiryNotifier) scanAccounts() {
+	keys, err := ben.dm.DataDB().GetKeysForPrefix(utils.ACCOUNT_PREFIX)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s querying the accounts for expiring balances",
+				utils.AccountService, err.Error()))
+		return
+	}
+	now := time.Now()
+	oldest := now.Add(-ben.scanInterval - config.CgrConfig().RalsCfg().ExpiryGracePeriod)
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		acc, err := ben.dm.DataDB().GetAccount(key[len(utils.ACCOUNT_PREFIX):])
+		if err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s querying account: %s for expiring balances",
+					utils.AccountService, err.Error(), key))
+			continue
+		}
+		for blncType, blncs := range acc.BalanceMap {
+			for _, b := range blncs {
+				if b.ExpirationDate.IsZero() ||
+					b.ExpirationDate.After(now.Add(ben.notifyBefore)) ||
+					b.ExpirationDate.Before(oldest) {
+					continue
+				}
+				seen[b.Uuid] = true
+				expired := b.IsExpired()
+				if ntf, has := ben.notified[b.Uuid]; has &&
+					ntf.expiryTime.Equal(b.ExpirationDate) && (ntf.expired || !expired) {
+					continue
+				}
+				if err := acc.notifyBalanceExpiry(blncType, b, expired); err != nil {
+					utils.Logger.Warning(
+						fmt.Sprintf("<%s> error: %s notifying the expiry of balance: %s of account: %s",
+							utils.AccountService, err.Error(), b.Uuid, acc.ID))
+					continue // retried on next scan
+				}
+				ben.notified[b.Uuid] = &notifiedExpiry{expiryTime: b.ExpirationDate, expired: expired}
+			}
+		}
+	}
+	for uuid := range ben.notified {
+		if !seen[uuid] {
+			delete(ben.notified, uuid)
+		}
+	}
+}
+
+// notifyBalanceExpiry sends the BalanceExpiring or BalanceExpired event of the balance to ThresholdS
+func (acc *Account) notifyBalanceExpiry(blncType string, b *Balance, expired bool) (err error) {
+	if thresholdS == nil {
+		return
+	}
+	evType := utils.BalanceExpiring
+	if expired {
+		evType = utils.BalanceExpired
+	}
+	acntTnt := utils.NewTenantID(acc.ID)
+	cgrEv := utils.CGREvent{
+		Tenant: acntTnt.Tenant,
+		ID:     utils.GenUUID(),
+		Event: map[string]interface{}{
+			utils.EventType:   evType,
+			utils.EventSource: utils.AccountService,
+			utils.Account:     acntTnt.ID,
+			utils.BalanceID:   b.ID,
+			utils.BalanceType: blncType,
+			utils.Units:       b.Value,
+			utils.ExpiryTime:  b.ExpirationDate}}
+	var tIDs []string
+	if err = thresholdS.Call(utils.ThresholdSv1ProcessEvent,
+		&ArgsProcessEvent{CGREvent: cgrEv}, &tIDs); err != nil &&
+		err.Error() == utils.ErrNotFound.Error() {
+		err = nil
+	}
+	return
+}
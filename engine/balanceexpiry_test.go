@@ -0,0 +1,178 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+type testExpiryThresholdS struct {
+	evs []*ArgsProcessEvent
+}
+
+func (tS *testExpiryThresholdS) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if serviceMethod != utils.ThresholdSv1ProcessEvent {
+		return utils.ErrNotImplemented
+	}
+	tS.evs = append(tS.evs, args.(*ArgsProcessEvent))
+	return utils.ErrNotFound
+}
+
+func TestBalanceExpiryNotifierScan(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tS := new(testExpiryThresholdS)
+	prevThdS := thresholdS
+	thresholdS = tS
+	defer func() { thresholdS = prevThdS }()
+	acc := &Account{
+		ID: "cgrates.org:expiry",
+		BalanceMap: map[string]Balances{
+			utils.VOICE: {
+				&Balance{Uuid: "uuid1", ID: "SOON", Value: 10,
+					ExpirationDate: time.Now().Add(time.Hour)},
+				&Balance{Uuid: "uuid2", ID: "LATER", Value: 20,
+					ExpirationDate: time.Now().Add(48 * time.Hour)},
+				&Balance{Uuid: "uuid3", ID: "EXPIRED", Value: 30,
+					ExpirationDate: time.Now().Add(-time.Minute)},
+				&Balance{Uuid: "uuid4", ID: "LONG_EXPIRED", Value: 40,
+					ExpirationDate: time.Now().Add(-2 * time.Hour)},
+				&Balance{Uuid: "uuid5", ID: "NEVER", Value: 50},
+			},
+		},
+	}
+	if err := dataDB.SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	ben := NewBalanceExpiryNotifier(NewDataManager(dataDB), time.Hour, 24*time.Hour)
+	ben.scanAccounts()
+	if len(tS.evs) != 2 {
+		t.Fatalf("unexpected events: %s", utils.ToJSON(tS.evs))
+	}
+	for _, ev := range tS.evs {
+		switch ev.Event[utils.BalanceID] {
+		case "SOON":
+			if ev.Event[utils.EventType] != utils.BalanceExpiring || ev.Event[utils.Units] != 10.0 ||
+				ev.Event[utils.Account] != "expiry" || ev.Tenant != "cgrates.org" {
+				t.Errorf("unexpected event: %s", utils.ToJSON(ev))
+			}
+		case "EXPIRED":
+			if ev.Event[utils.EventType] != utils.BalanceExpired ||
+				ev.Event[utils.BalanceType] != utils.VOICE {
+				t.Errorf("unexpected event: %s", utils.ToJSON(ev))
+			}
+		default:
+			t.Errorf("unexpected event: %s", utils.ToJSON(ev))
+		}
+	}
+	// notified only once
+	ben.scanAccounts()
+	if len(tS.evs) != 2 {
+		t.Fatalf("unexpected events: %s", utils.ToJSON(tS.evs))
+	}
+	acc.BalanceMap[utils.VOICE][0].ExpirationDate = time.Now().Add(-time.Second)
+	if err := dataDB.SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	ben.scanAccounts()
+	if len(tS.evs) != 3 {
+		t.Fatalf("unexpected events: %s", utils.ToJSON(tS.evs))
+	} else if tS.evs[2].Event[utils.BalanceID] != "SOON" ||
+		tS.evs[2].Event[utils.EventType] != utils.BalanceExpired {
+		t.Errorf("unexpected event: %s", utils.ToJSON(tS.evs[2]))
+	}
+}
+
+func TestBalanceExpiryNotifierShutdown(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ben := NewBalanceExpiryNotifier(NewDataManager(dataDB), time.Hour, 24*time.Hour)
+	go ben.ListenAndServe()
+	done := make(chan struct{})
+	go func() {
+		ben.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("scanning not stopped on shutdown")
+	}
+}
+
+func TestAccountRestoreInGracePeriod(t *testing.T) {
+	config.CgrConfig().RalsCfg().ExpiryGracePeriod = time.Hour
+	defer func() { config.CgrConfig().RalsCfg().ExpiryGracePeriod = 0 }()
+	acc := &Account{
+		ID: "cgrates.org:grace",
+		BalanceMap: map[string]Balances{
+			utils.VOICE: {
+				&Balance{Uuid: "uuid1", ID: "BUNDLE", Value: 5,
+					ExpirationDate: time.Now().Add(-time.Minute)},
+				&Balance{Uuid: "uuid2", ID: "OLD_BUNDLE", Value: 5,
+					ExpirationDate: time.Now().Add(-2 * time.Hour)},
+			},
+		},
+	}
+	acc.CleanExpiredStuff()
+	if len(acc.BalanceMap[utils.VOICE]) != 1 ||
+		acc.BalanceMap[utils.VOICE][0].ID != "BUNDLE" {
+		t.Fatalf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	expTime := time.Now().Add(time.Hour).Truncate(time.Second)
+	a := &Action{
+		ActionType: TOPUP,
+		Balance: &BalanceFilter{
+			ID:             utils.StringPointer("BUNDLE"),
+			Type:           utils.StringPointer(utils.VOICE),
+			Value:          &utils.ValueFormula{Static: 10},
+			ExpirationDate: &expTime,
+		},
+	}
+	if err := topupAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.BalanceMap[utils.VOICE]) != 1 {
+		t.Fatalf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	if b := acc.BalanceMap[utils.VOICE][0]; b.Value != 15 || !b.ExpirationDate.Equal(expTime) {
+		t.Errorf("balance not restored: %s", utils.ToJSON(b))
+	}
+	// no expiry on the top-up keeps the original one
+	origExpiry := time.Now().Add(-time.Minute)
+	acc.BalanceMap[utils.VOICE][0].ExpirationDate = origExpiry
+	a.Balance.ExpirationDate = nil
+	if err := topupAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.BalanceMap[utils.VOICE]) != 1 {
+		t.Fatalf("unexpected balances: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	if b := acc.BalanceMap[utils.VOICE][0]; b.Value != 25 || !b.ExpirationDate.Equal(origExpiry) {
+		t.Errorf("unexpected balance: %s", utils.ToJSON(b))
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// BalanceMovement is one change in the value of a balance, as kept in the balance history
+type BalanceMovement struct {
+	Tenant       string
+	Account      string
+	BalanceUUID  string
+	BalanceID    string
+	BalanceType  string
+	Reason       string // *debit, *refund, *refund_rounding, *expired, *capture or the type of the action
+	CGRID        string // the event charged or the reservation captured
+	ActionID     string // the actions changing the balance
+	ActionPlanID string
+	OldValue     float64
+	NewValue     float64
+	Time         time.Time
+}
+
+// balanceValue is the part of a Balance tracked by the balance history
+type balanceValue struct {
+	ID             string
+	Type           string
+	Value          float64
+	ExpirationDate time.Time
+	Disabled       bool
+}
+
+// balanceJournal collects the balance movements and the exported changes of an account until the account is saved
+type balanceJournal struct {
+	values   map[string]*balanceValue // balances as last journaled, indexed on UUID
+	reason   *BalanceMovement         // template for the movements journaled next
+	mvs      []*BalanceMovement
+	exported *accountState            // account as last journaled for the AccountExporter, nil when not exporting
+	evs      []map[string]interface{} // changes to be exported
+}
+
+// balanceValues returns the current values of the account balances, indexed on UUID
+func (acc *Account) balanceValues() (vals map[string]*balanceValue) {
+	vals = make(map[string]*balanceValue)
+	for blncType, blncs := range acc.BalanceMap {
+		for _, b := range blncs {
+			vals[b.Uuid] = &balanceValue{ID: b.ID, Type: blncType,
+				Value: b.Value, ExpirationDate: b.ExpirationDate, Disabled: b.Disabled}
+		}
+	}
+	return
+}
+
+// journalBalances attributes the balance changes from now on to rsn, returning the reason used so far
+// the changes done since the previous call are journaled with the previous reason
+// journaling starts with the first call and is a no-op when both the balance history and the AccountExporter are disabled
+func (acc *Account) journalBalances(rsn *BalanceMovement) (prev *BalanceMovement) {
+	if !balanceHistory && accountExporter == nil {
+		return
+	}
+	if acc.journal == nil {
+		acc.journal = &balanceJournal{values: acc.balanceValues(), reason: rsn}
+		if accountExporter != nil {
+			acc.journal.exported = acc.exportedState()
+		}
+		return
+	}
+	acc.journalMovements()
+	prev = acc.journal.reason
+	acc.journal.reason = rsn
+	return
+}
+
+// journalReason returns the reason the balance changes are attributed to
+func (acc *Account) journalReason() *BalanceMovement {
+	if acc.journal == nil {
+		return nil
+	}
+	return acc.journal.reason
+}
+
+// journalMovements compares the balances with their journaled values and records the differences
+func (acc *Account) journalMovements() {
+	now := time.Now()
+	tntID := utils.NewTenantID(acc.ID)
+	crrnt := acc.balanceValues()
+	uuids := make([]string, 0, len(crrnt))
+	for uuid := range crrnt {
+		uuids = append(uuids, uuid)
+	}
+	for uuid := range acc.journal.values {
+		if _, has := crrnt[uuid]; !has {
+			uuids = append(uuids, uuid)
+		}
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		mv := new(BalanceMovement)
+		if acc.journal.reason != nil {
+			*mv = *acc.journal.reason
+		}
+		old, hasOld := acc.journal.values[uuid]
+		crr, hasCrr := crrnt[uuid]
+		switch {
+		case hasOld && hasCrr:
+			if old.Value == crr.Value {
+				continue
+			}
+			mv.OldValue, mv.NewValue = old.Value, crr.Value
+		case hasCrr: // new balance
+			if crr.Value == 0 {
+				continue
+			}
+			mv.NewValue = crr.Value
+		default: // removed balance
+			if !old.ExpirationDate.IsZero() && old.ExpirationDate.Before(now) {
+				mv = &BalanceMovement{Reason: utils.MetaExpired}
+			} else if old.Value == 0 {
+				continue
+			}
+			mv.OldValue = old.Value
+			crr = old
+		}
+		mv.Tenant = tntID.Tenant
+		mv.Account = tntID.ID
+		mv.BalanceUUID = uuid
+		mv.BalanceID = crr.ID
+		mv.BalanceType = crr.Type
+		mv.Time = now
+		acc.journal.mvs = append(acc.journal.mvs, mv)
+	}
+	acc.journal.values = crrnt
+	if acc.journal.exported != nil {
+		acc.journalChanges()
+	}
+}
+
+// storeBalanceJournal writes the journaled movements into StorDB and exports the journaled changes,
+// to be called once the account is saved
+// journaling continues with the same reason for the later changes
+func (acc *Account) storeBalanceJournal() {
+	if acc.journal == nil {
+		return
+	}
+	acc.journalMovements()
+	mvs, evs := acc.journal.mvs, acc.journal.evs
+	acc.journal.mvs, acc.journal.evs = nil, nil
+	if len(evs) != 0 && accountExporter != nil {
+		accountExporter.exportChanges(acc.ID, evs)
+	}
+	if !balanceHistory || len(mvs) == 0 || cdrStorage == nil {
+		return
+	}
+	if err := cdrStorage.SetBalanceMovements(mvs); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s storing the balance history of account: %s",
+				utils.AccountService, err.Error(), acc.ID))
+	}
+}
+
+// JournalChanges attributes the account changes done from now on outside of the actions to reason
+func (acc *Account) JournalChanges(reason string) {
+	acc.journalBalances(&BalanceMovement{Reason: reason})
+}
+
+// StoreJournal stores and exports the journaled changes, to be called once the account is saved
+func (acc *Account) StoreJournal() {
+	acc.storeBalanceJournal()
+}
@@ -0,0 +1,90 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountJournalBalances(t *testing.T) {
+	balanceHistory = true
+	defer func() { balanceHistory = false }()
+	acc := &Account{
+		ID: "cgrates.org:jrnl",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: {
+				&Balance{Uuid: "uuid1", ID: "MONEY", Value: 10},
+				&Balance{Uuid: "uuid2", ID: "EXPIRING", Value: 5,
+					ExpirationDate: time.Now().Add(time.Hour)},
+			},
+		},
+	}
+	acc.journalBalances(&BalanceMovement{Reason: utils.MetaDebit, CGRID: "cgrid1"})
+	acc.BalanceMap[utils.MONETARY][0].SubstractValue(2)
+	prev := acc.journalBalances(&BalanceMovement{Reason: TOPUP, ActionID: "TOPUP_5"})
+	if prev == nil || prev.CGRID != "cgrid1" {
+		t.Errorf("unexpected previous reason: %s", utils.ToJSON(prev))
+	}
+	acc.BalanceMap[utils.MONETARY][0].AddValue(5)
+	acc.BalanceMap[utils.MONETARY] = append(acc.BalanceMap[utils.MONETARY],
+		&Balance{Uuid: "uuid3", ID: "NEW", Value: 1})
+	acc.BalanceMap[utils.MONETARY][1].ExpirationDate = time.Now().Add(-time.Minute)
+	acc.CleanExpiredStuff()
+	acc.journalMovements()
+	mvs := acc.journal.mvs
+	if len(mvs) != 4 {
+		t.Fatalf("unexpected movements: %s", utils.ToJSON(mvs))
+	}
+	if mvs[0].Reason != utils.MetaDebit || mvs[0].CGRID != "cgrid1" ||
+		mvs[0].OldValue != 10 || mvs[0].NewValue != 8 ||
+		mvs[0].Tenant != "cgrates.org" || mvs[0].Account != "jrnl" || mvs[0].BalanceID != "MONEY" {
+		t.Errorf("unexpected debit movement: %s", utils.ToJSON(mvs[0]))
+	}
+	if mvs[1].Reason != TOPUP || mvs[1].ActionID != "TOPUP_5" ||
+		mvs[1].OldValue != 8 || mvs[1].NewValue != 13 {
+		t.Errorf("unexpected topup movement: %s", utils.ToJSON(mvs[1]))
+	}
+	if mvs[2].Reason != utils.MetaExpired || mvs[2].ActionID != "" ||
+		mvs[2].BalanceID != "EXPIRING" || mvs[2].OldValue != 5 || mvs[2].NewValue != 0 {
+		t.Errorf("unexpected expiry movement: %s", utils.ToJSON(mvs[2]))
+	}
+	if mvs[3].BalanceUUID != "uuid3" || mvs[3].OldValue != 0 || mvs[3].NewValue != 1 {
+		t.Errorf("unexpected new balance movement: %s", utils.ToJSON(mvs[3]))
+	}
+	acc.storeBalanceJournal()
+	if len(acc.journal.mvs) != 0 {
+		t.Errorf("movements not flushed: %s", utils.ToJSON(acc.journal.mvs))
+	}
+}
+
+func TestAccountJournalBalancesDisabled(t *testing.T) {
+	acc := &Account{
+		ID: "cgrates.org:jrnl",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: {&Balance{Uuid: "uuid1", Value: 10}},
+		},
+	}
+	acc.journalBalances(&BalanceMovement{Reason: utils.MetaDebit})
+	if acc.journal != nil {
+		t.Errorf("journal started while the balance history is disabled")
+	}
+}
@@ -44,9 +44,11 @@ type Balance struct {
 	Disabled       bool
 	Factor         ValueFactor
 	Blocker        bool
+	RolloverOf     string // UUID of the balance the value was rolled over from
 	precision      int
 	account        *Account // used to store ub reference for shared balances
 	dirty          bool
+	reserved       float64 // value held by the account reservations
 }
 
 func (b *Balance) Equal(o *Balance) bool {
@@ -176,7 +178,9 @@ func (b *Balance) Clone() *Balance {
 		Timings:        b.Timings, // should not be a problem with aliasing
 		Blocker:        b.Blocker,
 		Disabled:       b.Disabled,
+		RolloverOf:     b.RolloverOf,
 		dirty:          b.dirty,
+		reserved:       b.reserved,
 	}
 	if b.DestinationIDs != nil {
 		n.DestinationIDs = b.DestinationIDs.Clone()
@@ -202,7 +206,7 @@ func (b *Balance) getMatchingPrefixAndDestID(dest string) (prefix, destId string
 // Returns the available number of seconds for a specified credit
 func (b *Balance) GetMinutesForCredit(origCD *CallDescriptor, initialCredit float64) (duration time.Duration, credit float64) {
 	cd := origCD.Clone()
-	availableDuration := time.Duration(b.GetValue()) * time.Second
+	availableDuration := time.Duration(b.getAvailableValue()) * time.Second
 	duration = availableDuration
 	credit = initialCredit
 	cc, err := b.GetCost(cd, false)
@@ -277,6 +281,11 @@ func (b *Balance) GetValue() float64 {
 	return b.Value
 }
 
+// getAvailableValue returns the value not held by reservations, to be used when debiting
+func (b *Balance) getAvailableValue() float64 {
+	return b.GetValue() - b.reserved
+}
+
 func (b *Balance) AddValue(amount float64) {
 	b.SetValue(b.GetValue() + amount)
 }
@@ -298,7 +307,7 @@ func (b *Balance) SetDirty() {
 // debitUnits will debit units for call descriptor.
 // returns the amount debited within cc
 func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Balances, count bool, dryRun, debitConnectFee bool) (cc *CallCost, err error) {
-	if !b.IsActiveAt(cd.TimeStart) || b.GetValue() <= 0 {
+	if !b.IsActiveAt(cd.TimeStart) || b.getAvailableValue() <= 0 {
 		return
 	}
 	if duration, err := utils.ParseZeroRatingSubject(cd.TOR, b.RatingSubject); err == nil {
@@ -345,7 +354,7 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				amount = utils.Round(amount/b.Factor.GetValue(cd.TOR),
 					globalRoundingDecimals, utils.ROUNDING_UP)
 			}
-			if b.GetValue() >= amount {
+			if b.getAvailableValue() >= amount {
 				b.SubstractValue(amount)
 				inc.BalanceInfo.Unit = &UnitInfo{
 					UUID:          b.Uuid,
@@ -470,7 +479,7 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				}
 				var moneyBal *Balance
 				for _, mb := range moneyBalances {
-					if mb.GetValue() >= cost {
+					if mb.getAvailableValue() >= cost {
 						moneyBal = mb
 						break
 					}
@@ -479,7 +488,7 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 					utils.Logger.Warning(fmt.Sprintf("<RALs> Going negative on account %s with AllowNegative: false", cd.GetAccountKey()))
 					moneyBal = ub.GetDefaultMoneyBalance()
 				}
-				if b.GetValue() >= amount && (moneyBal != nil || cost == 0) {
+				if b.getAvailableValue() >= amount && (moneyBal != nil || cost == 0) {
 					b.SubstractValue(amount)
 					inc.BalanceInfo.Unit = &UnitInfo{
 						UUID:          b.Uuid,
@@ -529,7 +538,7 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 }
 
 func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Balances, count bool, dryRun, debitConnectFee bool) (cc *CallCost, err error) {
-	if !b.IsActiveAt(cd.TimeStart) || b.GetValue() <= 0 {
+	if !b.IsActiveAt(cd.TimeStart) || b.getAvailableValue() <= 0 {
 		return
 	}
 	//log.Print("B: ", utils.ToJSON(b))
@@ -634,7 +643,7 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				continue
 			}
 
-			if b.GetValue() >= amount {
+			if b.getAvailableValue() >= amount {
 				b.SubstractValue(amount)
 				cd.MaxCostSoFar += amount
 				inc.BalanceInfo.Monetary = &MonetaryInfo{
@@ -750,7 +759,7 @@ func (bc Balances) Sort() {
 func (bc Balances) GetTotalValue() (total float64) {
 	for _, b := range bc {
 		if !b.IsExpired() && b.IsActive() {
-			total += b.GetValue()
+			total += b.getAvailableValue()
 		}
 	}
 	total = utils.Round(total, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
@@ -831,6 +840,7 @@ func (bc Balances) SaveDirtyBalances(acc *Account) {
 		}
 		if b.account != nil && b.account != acc && b.dirty && savedAccounts[b.account.ID] == nil {
 			dm.DataDB().SetAccount(b.account)
+			b.account.storeBalanceJournal()
 			savedAccounts[b.account.ID] = b.account
 		}
 	}
@@ -873,5 +883,6 @@ type BalanceSummary struct {
 	ID       string // Balance ID  if not defined
 	Type     string // *voice, *data, etc
 	Value    float64
+	Reserved float64 // part of the Value held by reservations
 	Disabled bool
 }
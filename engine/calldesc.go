@@ -60,7 +60,9 @@ var (
 	thresholdS              rpcclient.RpcClientConnection // used by RALs to communicate with ThresholdS
 	statS                   rpcclient.RpcClientConnection
 	schedCdrsConns          rpcclient.RpcClientConnection
+	schedSessionSConns      rpcclient.RpcClientConnection // used by *reauthorize_session
 	rpSubjectPrefixMatching bool
+	balanceHistory          bool // record the balance movements into StorDB
 )
 
 // Exported method to set the storage getter.
@@ -85,6 +87,10 @@ func SetRpSubjectPrefixMatching(flag bool) {
 	rpSubjectPrefixMatching = flag
 }
 
+func SetBalanceHistory(flag bool) {
+	balanceHistory = flag
+}
+
 /*
 Sets the database for CDR storing, used by *cdrlog in first place
 */
@@ -99,6 +105,14 @@ func SetSchedCdrsConns(sc rpcclient.RpcClientConnection) {
 	}
 }
 
+// SetSchedSessionSConns sets the connection towards SessionS used by the actions
+func SetSchedSessionSConns(sc rpcclient.RpcClientConnection) {
+	schedSessionSConns = sc
+	if schedSessionSConns != nil && reflect.ValueOf(schedSessionSConns).IsNil() {
+		schedSessionSConns = nil
+	}
+}
+
 // NewCallDescriptorFromCGREvent converts a CGREvent into CallDescriptor
 func NewCallDescriptorFromCGREvent(cgrEv *utils.CGREvent,
 	timezone string) (cd *CallDescriptor, err error) {
@@ -638,15 +652,26 @@ func (cd *CallDescriptor) getCost() (*CallCost, error) {
 /*
 Returns the approximate max allowed session for user balance. It will try the max amount received in the call descriptor
 If the user has no credit then it will return 0.
-If the user has postpayed plan it returns -1.
+If the user has postpayed plan it returns -1, unless limited by the credit limit or the spending caps.
 */
-func (origCD *CallDescriptor) getMaxSessionDuration(origAcc *Account) (time.Duration, error) {
+func (origCD *CallDescriptor) getMaxSessionDuration(origAcc *Account, postpaid bool) (time.Duration, error) {
 	// clone the account for discarding chenges on debit dry run
 	//log.Printf("ORIG CD: %+v", origCD)
 	account := origAcc.Clone()
-	if account.AllowNegative {
+	goNegative := account.AllowNegative || postpaid
+	creditLimit := account.creditLimit()
+	allowance, capped := account.spendingAllowance(time.Now())
+	unlimitedDebt := goNegative && creditLimit == 0
+	if unlimitedDebt && !capped {
 		return -1, nil
 	}
+	if capped && allowance == 0 {
+		return 0, utils.ErrSpendingCapExceeded
+	}
+	var debtLimit float64 // how far the default balance may go negative
+	if goNegative {
+		debtLimit = creditLimit
+	}
 	// for zero duration index
 	if origCD.DurationIndex < origCD.TimeEnd.Sub(origCD.TimeStart) {
 		origCD.DurationIndex = origCD.TimeEnd.Sub(origCD.TimeStart)
@@ -661,7 +686,7 @@ func (origCD *CallDescriptor) getMaxSessionDuration(origAcc *Account) (time.Dura
 	//use this to check what increment was payed with debt
 	initialDefaultBalanceValue := defaultBalance.GetValue()
 
-	cc, err := cd.debit(account, true, false)
+	cc, err := cd.debit(account, true, goNegative)
 	if err != nil {
 		return 0, err
 	}
@@ -671,7 +696,7 @@ func (origCD *CallDescriptor) getMaxSessionDuration(origAcc *Account) (time.Dura
 		return 0, nil
 	}
 
-	var totalCost float64
+	var totalCost, totalSpent float64
 	var totalDuration time.Duration
 	cc.Timespans.Decompress()
 	for _, ts := range cc.Timespans {
@@ -686,10 +711,16 @@ func (origCD *CallDescriptor) getMaxSessionDuration(origAcc *Account) (time.Dura
 		}
 		for _, incr := range ts.Increments {
 			totalCost += incr.Cost
-			if incr.BalanceInfo.Monetary != nil && incr.BalanceInfo.Monetary.UUID == defaultBalance.Uuid {
+			if capped && incr.BalanceInfo.Monetary != nil && incr.BalanceInfo.AccountID == account.ID {
+				if totalSpent += incr.Cost; totalSpent > allowance {
+					// this increment goes over the spending cap
+					return utils.MinDuration(initialDuration, totalDuration), nil
+				}
+			}
+			if !unlimitedDebt && incr.BalanceInfo.Monetary != nil && incr.BalanceInfo.Monetary.UUID == defaultBalance.Uuid {
 				initialDefaultBalanceValue -= incr.Cost
-				if initialDefaultBalanceValue < 0 {
-					// this increment was payed with debt
+				if initialDefaultBalanceValue < -debtLimit {
+					// this increment was payed with more debt than allowed
 					// TODO: improve this check
 					return utils.MinDuration(initialDuration, totalDuration), nil
 
@@ -707,6 +738,17 @@ func (origCD *CallDescriptor) getMaxSessionDuration(origAcc *Account) (time.Dura
 }
 
 func (cd *CallDescriptor) GetMaxSessionDuration() (duration time.Duration, err error) {
+	return cd.getMaxSessionDurationLocked(false)
+}
+
+// GetMaxPostpaidSessionDuration returns the max session duration allowed to a postpaid account by its
+// credit limit and spending caps, -1 when not limited
+func (cd *CallDescriptor) GetMaxPostpaidSessionDuration() (duration time.Duration, err error) {
+	return cd.getMaxSessionDurationLocked(true)
+}
+
+// getMaxSessionDurationLocked locks the account and its shared group members for getMaxSessionDuration
+func (cd *CallDescriptor) getMaxSessionDurationLocked(postpaid bool) (duration time.Duration, err error) {
 	cd.account = nil // make sure it's not cached
 	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
 		account, err := cd.getAccount()
@@ -724,7 +766,7 @@ func (cd *CallDescriptor) GetMaxSessionDuration() (duration time.Duration, err e
 			}
 		}
 		_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
-			duration, err = cd.getMaxSessionDuration(account)
+			duration, err = cd.getMaxSessionDuration(account, postpaid)
 			return
 		}, config.CgrConfig().GeneralCfg().LockingTimeout, lkIDs...)
 		return
@@ -752,18 +794,34 @@ func (cd *CallDescriptor) debit(account *Account, dryRun bool, goNegative bool)
 	if cd.TOR == "" {
 		cd.TOR = utils.VOICE
 	}
+	if !dryRun {
+		account.journalBalances(&BalanceMovement{Reason: utils.MetaDebit, CGRID: cd.CgrID})
+	}
 	//log.Printf("Debit CD: %+v", cd)
 	cc, err = account.debitCreditBalance(cd, !dryRun, dryRun, goNegative)
 	//log.Printf("HERE: %+v %v", cc, err)
+	capped := err == utils.ErrInsufficientCredit && cc != nil // debt capped at the credit limit, keep the debited part
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<Rater> Error getting cost for account key <%s>: %s", cd.GetAccountKey(), err.Error()))
-		return nil, err
+		if !capped {
+			return nil, err
+		}
+	}
+	var spent float64
+	if !dryRun {
+		spent = cc.monetaryDebits() // before compressing the timespans
 	}
 	cc.updateCost()
 	cc.UpdateRatedUsage()
 	cc.Timespans.Compress()
 	if !dryRun {
+		capEvs := account.addSpending(spent, time.Now())
 		dm.DataDB().SetAccount(account)
+		account.storeBalanceJournal()
+		notifySpendingCaps(capEvs)
+	}
+	if capped {
+		return nil, err
 	}
 	if cd.PerformRounding {
 		cc.Round()
@@ -831,7 +889,7 @@ func (cd *CallDescriptor) MaxDebit() (cc *CallCost, err error) {
 			}
 		}
 		_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
-			remainingDuration, err := cd.getMaxSessionDuration(account)
+			remainingDuration, err := cd.getMaxSessionDuration(account, false)
 			if err != nil && cd.GetDuration() > 0 {
 				return nil, err
 			}
@@ -887,7 +945,9 @@ func (cd *CallDescriptor) refundIncrements() (acnt *Account, err error) {
 			if acc, err := dm.DataDB().GetAccount(increment.BalanceInfo.AccountID); err == nil && acc != nil {
 				account = acc
 				accountsCache[increment.BalanceInfo.AccountID] = account
+				account.journalBalances(&BalanceMovement{Reason: utils.MetaRefund, CGRID: cd.CgrID})
 				// will save the account only once at the end of the function
+				defer account.storeBalanceJournal()
 				defer dm.DataDB().SetAccount(account)
 			}
 		}
@@ -950,7 +1010,9 @@ func (cd *CallDescriptor) refundRounding() (err error) {
 			if acc, err := dm.DataDB().GetAccount(increment.BalanceInfo.AccountID); err == nil && acc != nil {
 				account = acc
 				accountsCache[increment.BalanceInfo.AccountID] = account
+				account.journalBalances(&BalanceMovement{Reason: utils.MetaRefundRounding, CGRID: cd.CgrID})
 				// will save the account only once at the end of the function
+				defer account.storeBalanceJournal()
 				defer dm.DataDB().SetAccount(account)
 			}
 		}
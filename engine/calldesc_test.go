@@ -1320,7 +1320,7 @@ func TestMaxSesionTimeEmptyBalance(t *testing.T) {
 		Destination: "0723",
 	}
 	acc, _ := dm.DataDB().GetAccount("vdf:luna")
-	allowedTime, err := cd.getMaxSessionDuration(acc)
+	allowedTime, err := cd.getMaxSessionDuration(acc, false)
 	if err != nil || allowedTime != 0 {
 		t.Error("Error get max session for 0 acount", err)
 	}
@@ -1337,7 +1337,7 @@ func TestMaxSesionTimeEmptyBalanceAndNoCost(t *testing.T) {
 		Destination: "112",
 	}
 	acc, _ := dm.DataDB().GetAccount("vdf:luna")
-	allowedTime, err := cd.getMaxSessionDuration(acc)
+	allowedTime, err := cd.getMaxSessionDuration(acc, false)
 	if err != nil || allowedTime == 0 {
 		t.Error("Error get max session for 0 acount", err)
 	}
@@ -1353,7 +1353,7 @@ func TestMaxSesionTimeLong(t *testing.T) {
 		Destination: "0723",
 	}
 	acc, _ := dm.DataDB().GetAccount("cgrates.org:money")
-	allowedTime, err := cd.getMaxSessionDuration(acc)
+	allowedTime, err := cd.getMaxSessionDuration(acc, false)
 	if err != nil || allowedTime != cd.TimeEnd.Sub(cd.TimeStart) {
 		t.Error("Error get max session for acount:", allowedTime, err)
 	}
@@ -1369,7 +1369,7 @@ func TestMaxSesionTimeLongerThanMoney(t *testing.T) {
 		Destination: "0723",
 	}
 	acc, _ := dm.DataDB().GetAccount("cgrates.org:money")
-	allowedTime, err := cd.getMaxSessionDuration(acc)
+	allowedTime, err := cd.getMaxSessionDuration(acc, false)
 	expected, err := time.ParseDuration("9999s") // 1 is the connect fee
 	if err != nil || allowedTime != expected {
 		t.Log(utils.ToIJSON(acc))
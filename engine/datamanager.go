@@ -124,6 +124,12 @@ func (dm *DataManager) PreloadCacheForPrefix(prefix string) error {
 }
 
 func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached bool) (err error) {
+	return dm.cacheDataFromDB(prfx, ids, mustBeCached, utils.NonTransactional)
+}
+
+// cacheDataFromDB loads the items into cache as part of the cache transaction with transactionID
+func (dm *DataManager) cacheDataFromDB(prfx string, ids []string, mustBeCached bool,
+	transactionID string) (err error) {
 	if !utils.IsSliceMember([]string{
 		utils.DESTINATION_PREFIX,
 		utils.REVERSE_DESTINATION_PREFIX,
@@ -145,12 +151,17 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		utils.SupplierProfilePrefix,
 		utils.AttributeProfilePrefix,
 		utils.ChargerProfilePrefix,
-		utils.DispatcherProfilePrefix}, prfx) {
+		utils.DispatcherProfilePrefix,
+		utils.PortedNumbersPrefix}, prfx) {
 		return utils.NewCGRError(utils.DataManager,
 			utils.MandatoryIEMissingCaps,
 			utils.UnsupportedCachePrefix,
 			fmt.Sprintf("prefix <%s> is not a supported cache prefix", prfx))
 	}
+	if prfx == utils.PortedNumbersPrefix { // prefixes index rebuilt on next lookup
+		Cache.Remove(utils.CachePortedNumbers, utils.PortedPrefixesIdx,
+			cacheCommit(transactionID), transactionID)
+	}
 	if ids == nil {
 		keyIDs, err := dm.DataDB().GetKeysForPrefix(prfx)
 		if err != nil {
@@ -183,58 +194,61 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		}
 		switch prfx {
 		case utils.DESTINATION_PREFIX:
-			_, err = dm.DataDB().GetDestination(dataID, true, utils.NonTransactional)
+			_, err = dm.DataDB().GetDestination(dataID, true, transactionID)
 		case utils.REVERSE_DESTINATION_PREFIX:
-			_, err = dm.DataDB().GetReverseDestination(dataID, true, utils.NonTransactional)
+			_, err = dm.DataDB().GetReverseDestination(dataID, true, transactionID)
 		case utils.RATING_PLAN_PREFIX:
-			_, err = dm.GetRatingPlan(dataID, true, utils.NonTransactional)
+			_, err = dm.GetRatingPlan(dataID, true, transactionID)
 		case utils.RATING_PROFILE_PREFIX:
-			_, err = dm.GetRatingProfile(dataID, true, utils.NonTransactional)
+			_, err = dm.GetRatingProfile(dataID, true, transactionID)
 		case utils.ACTION_PREFIX:
-			_, err = dm.GetActions(dataID, true, utils.NonTransactional)
+			_, err = dm.GetActions(dataID, true, transactionID)
 		case utils.ACTION_PLAN_PREFIX:
-			_, err = dm.DataDB().GetActionPlan(dataID, true, utils.NonTransactional)
+			_, err = dm.DataDB().GetActionPlan(dataID, true, transactionID)
 		case utils.AccountActionPlansPrefix:
-			_, err = dm.DataDB().GetAccountActionPlans(dataID, true, utils.NonTransactional)
+			_, err = dm.DataDB().GetAccountActionPlans(dataID, true, transactionID)
 		case utils.ACTION_TRIGGER_PREFIX:
-			_, err = dm.GetActionTriggers(dataID, true, utils.NonTransactional)
+			_, err = dm.GetActionTriggers(dataID, true, transactionID)
 		case utils.SHARED_GROUP_PREFIX:
-			_, err = dm.GetSharedGroup(dataID, true, utils.NonTransactional)
+			_, err = dm.GetSharedGroup(dataID, true, transactionID)
 		case utils.ResourceProfilesPrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetResourceProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetResourceProfile(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.ResourcesPrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetResource(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetResource(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.StatQueueProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetStatQueueProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetStatQueueProfile(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.StatQueuePrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetStatQueue(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetStatQueue(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.TimingsPrefix:
-			_, err = dm.GetTiming(dataID, true, utils.NonTransactional)
+			_, err = dm.GetTiming(dataID, true, transactionID)
 		case utils.ThresholdProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetThresholdProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetThresholdProfile(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.ThresholdPrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetThreshold(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetThreshold(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.FilterPrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetFilter(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetFilter(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.SupplierProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetSupplierProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetSupplierProfile(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.AttributeProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetAttributeProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetAttributeProfile(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.ChargerProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetChargerProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetChargerProfile(tntID.Tenant, tntID.ID, false, true, transactionID)
 		case utils.DispatcherProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
-			_, err = dm.GetDispatcherProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+			_, err = dm.GetDispatcherProfile(tntID.Tenant, tntID.ID, false, true, transactionID)
+		case utils.PortedNumbersPrefix:
+			tntID := utils.NewTenantID(dataID)
+			_, err = dm.GetPortedNumbers(tntID.Tenant, tntID.ID, false, true, transactionID)
 		}
 		if err != nil {
 			return utils.NewCGRError(utils.DataManager,
@@ -1299,3 +1313,30 @@ func (dm *DataManager) RemoveDispatcherProfile(tenant, id string,
 	}
 	return
 }
+
+func (dm *DataManager) GetLoadVersion(loadID string) (lv *LoadVersion, err error) {
+	return dm.DataDB().GetLoadVersionDrv(loadID)
+}
+
+// SetLoadVersion stores the versions recorded for a load and adds the load to history,
+// the versions of the loads falling out of history are removed
+func (dm *DataManager) SetLoadVersion(lv *LoadVersion, loadHistSize int) (err error) {
+	if loadHistSize == 0 { // load history disabled
+		return
+	}
+	loadHist, err := dm.DataDB().GetLoadHistory(-1, true, utils.NonTransactional)
+	if err != nil && err != utils.ErrNotFound {
+		return
+	}
+	for i := loadHistSize - 1; i < len(loadHist); i++ {
+		if err = dm.DataDB().RemoveLoadVersionDrv(loadHist[i].LoadID); err != nil &&
+			err != utils.ErrNotFound {
+			return
+		}
+	}
+	if err = dm.DataDB().SetLoadVersionDrv(lv); err != nil {
+		return
+	}
+	return dm.DataDB().AddLoadHistory(&utils.LoadInstance{LoadID: lv.LoadID,
+		LoadTime: lv.LoadTime}, loadHistSize, utils.NonTransactional)
+}
@@ -0,0 +1,347 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// eventBusFile is the file the queued events are saved into on shutdown
+const eventBusFile = "eventbus.json"
+
+var eventBus *EventBus // used by NewRPCPool to serve the asynchronous connections
+
+// SetEventBus sets the EventBus serving the asynchronous connections
+func SetEventBus(eb *EventBus) {
+	eventBus = eb
+}
+
+// GetEventBusMetrics returns the metrics of the EventBus subscribers
+func GetEventBusMetrics() (mtrcs map[string]*EventBusMetrics, err error) {
+	if eventBus == nil {
+		return nil, utils.ErrNotFound
+	}
+	return eventBus.Metrics(), nil
+}
+
+// asyncMethods are the APIs which can be served asynchronously, their replies being ignored
+// the constructors return the types the arguments and replies are decoded into when loaded from disk
+var asyncMethods = map[string]func() (args, reply interface{}){
+	utils.ThresholdSv1ProcessEvent: func() (interface{}, interface{}) {
+		return new(ArgsProcessEvent), new([]string)
+	},
+	utils.StatSv1ProcessEvent: func() (interface{}, interface{}) {
+		return new(StatsArgsProcessEvent), new([]string)
+	},
+}
+
+// BusEvent is a request queued on the EventBus
+type BusEvent struct {
+	Method string
+	Args   interface{}
+}
+
+// EventBusMetrics are the back-pressure metrics of one subscriber
+type EventBusMetrics struct {
+	Queued    int   // events waiting in the queue
+	Published int64 // events accepted into the queue
+	Processed int64 // events consumed successfully
+	Failed    int64 // events consumed with error
+	Dropped   int64 // events dropped because the queue was full
+}
+
+// busSubscriber consumes the events queued towards one connection
+type busSubscriber struct {
+	id        string
+	conn      rpcclient.RpcClientConnection
+	queue     chan *BusEvent
+	published int64
+	processed int64
+	failed    int64
+	dropped   int64
+}
+
+// NewEventBus constructs an EventBus, loading the events persisted in persistDir
+func NewEventBus(queueSize int, overflowStrategy, persistDir string) (eb *EventBus, err error) {
+	eb = &EventBus{
+		queueSize:   queueSize,
+		blocking:    overflowStrategy != utils.MetaDrop,
+		persistDir:  persistDir,
+		subscribers: make(map[string]*busSubscriber),
+		pending:     make(map[string][]*BusEvent),
+		stopChan:    make(chan struct{}),
+	}
+	if persistDir != "" {
+		if err = eb.loadEvents(); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// EventBus decouples the publishers from the subscribed connections through bounded queues
+// so that each subscriber consumes the events at its own pace
+type EventBus struct {
+	sync.RWMutex
+	queueSize   int
+	blocking    bool // publishers wait for room in the queue instead of dropping the events
+	persistDir  string
+	subscribers map[string]*busSubscriber // indexed on subscriber ID
+	pending     map[string][]*BusEvent    // events loaded from disk, waiting for their subscriber
+	stopChan    chan struct{}
+	closed      bool // shutdown started, no more events are accepted
+	wg          sync.WaitGroup
+}
+
+// NewAsyncConn returns a connection queuing the asynchronous methods on the EventBus
+// and calling conn directly for the rest
+func (eb *EventBus) NewAsyncConn(address string, conn rpcclient.RpcClientConnection) rpcclient.RpcClientConnection {
+	return &asyncConn{eb: eb, address: address, conn: conn}
+}
+
+// subscriber returns the subscriber with id, creating it on first use
+// returns nil once the shutdown started
+func (eb *EventBus) subscriber(id string, conn rpcclient.RpcClientConnection) (sub *busSubscriber) {
+	eb.RLock()
+	sub, has := eb.subscribers[id]
+	eb.RUnlock()
+	if has {
+		return
+	}
+	eb.Lock()
+	defer eb.Unlock()
+	if sub, has = eb.subscribers[id]; has || eb.closed {
+		return
+	}
+	sub = &busSubscriber{id: id, conn: conn,
+		queue: make(chan *BusEvent, eb.queueSize)}
+	for _, ev := range eb.pending[id] {
+		select {
+		case sub.queue <- ev:
+			sub.published++
+		default:
+			sub.dropped++
+		}
+	}
+	delete(eb.pending, id)
+	eb.subscribers[id] = sub
+	eb.wg.Add(1)
+	go eb.consume(sub)
+	return
+}
+
+// Publish queues the event towards the subscriber with id
+// with a full queue it either waits for room or drops the event, based on the overflow strategy
+func (eb *EventBus) Publish(id string, conn rpcclient.RpcClientConnection, ev *BusEvent) (err error) {
+	sub := eb.subscriber(id, conn)
+	if sub == nil {
+		return utils.ErrDisconnected
+	}
+	eb.RLock() // shutdown waits for the events being queued
+	defer eb.RUnlock()
+	if eb.closed {
+		return utils.ErrDisconnected
+	}
+	if !eb.blocking {
+		select {
+		case sub.queue <- ev:
+		default:
+			atomic.AddInt64(&sub.dropped, 1)
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> queue full, dropping %s towards: <%s>",
+					utils.EventBus, ev.Method, id))
+			return
+		}
+	} else {
+		select {
+		case sub.queue <- ev:
+		case <-eb.stopChan:
+			return utils.ErrDisconnected
+		}
+	}
+	atomic.AddInt64(&sub.published, 1)
+	return
+}
+
+// consume calls the subscriber connection for the queued events until shutdown
+func (eb *EventBus) consume(sub *busSubscriber) {
+	defer eb.wg.Done()
+	for {
+		select { // stop with priority, the events left in queue are saved on shutdown
+		case <-eb.stopChan:
+			return
+		default:
+		}
+		select {
+		case <-eb.stopChan:
+			return
+		case ev := <-sub.queue:
+			_, reply := asyncMethods[ev.Method]()
+			if err := sub.conn.Call(ev.Method, ev.Args, reply); err != nil &&
+				err.Error() != utils.ErrNotFound.Error() {
+				atomic.AddInt64(&sub.failed, 1)
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> error: %s calling %s on: <%s>",
+						utils.EventBus, err.Error(), ev.Method, sub.id))
+				continue
+			}
+			atomic.AddInt64(&sub.processed, 1)
+		}
+	}
+}
+
+// Metrics returns the back-pressure metrics, indexed on subscriber ID
+func (eb *EventBus) Metrics() (mtrcs map[string]*EventBusMetrics) {
+	eb.RLock()
+	defer eb.RUnlock()
+	mtrcs = make(map[string]*EventBusMetrics, len(eb.subscribers))
+	for id, sub := range eb.subscribers {
+		mtrcs[id] = &EventBusMetrics{
+			Queued:    len(sub.queue),
+			Published: atomic.LoadInt64(&sub.published),
+			Processed: atomic.LoadInt64(&sub.processed),
+			Failed:    atomic.LoadInt64(&sub.failed),
+			Dropped:   atomic.LoadInt64(&sub.dropped),
+		}
+	}
+	return
+}
+
+// Shutdown stops the consumers and saves the events still queued, if persistence is enabled
+func (eb *EventBus) Shutdown() (err error) {
+	close(eb.stopChan) // wakes up the publishers waiting for room in the queues
+	eb.Lock()
+	eb.closed = true
+	eb.Unlock()
+	eb.wg.Wait()
+	if eb.persistDir == "" {
+		eb.dropQueued()
+		return
+	}
+	return eb.saveEvents()
+}
+
+// dropQueued counts the events left undelivered in the queues as dropped
+func (eb *EventBus) dropQueued() {
+	eb.RLock()
+	defer eb.RUnlock()
+	for id, sub := range eb.subscribers {
+		if nrEvs := len(sub.queue); nrEvs != 0 {
+			atomic.AddInt64(&sub.dropped, int64(nrEvs))
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> shutdown, dropping %d events towards: <%s>",
+					utils.EventBus, nrEvs, id))
+		}
+	}
+}
+
+// persistedEvent is the BusEvent as saved on disk
+type persistedEvent struct {
+	Method string
+	Args   json.RawMessage
+}
+
+// saveEvents writes the queued and the pending events into persistDir
+func (eb *EventBus) saveEvents() (err error) {
+	eb.Lock()
+	defer eb.Unlock()
+	evs := make(map[string][]*BusEvent)
+	for id, pending := range eb.pending {
+		evs[id] = pending
+	}
+	for id, sub := range eb.subscribers {
+		for len(sub.queue) != 0 {
+			evs[id] = append(evs[id], <-sub.queue)
+		}
+	}
+	fPath := path.Join(eb.persistDir, eventBusFile)
+	if len(evs) == 0 {
+		if err = os.Remove(fPath); os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	b, err := json.Marshal(evs)
+	if err != nil {
+		return
+	}
+	return ioutil.WriteFile(fPath, b, 0644)
+}
+
+// loadEvents reads the events saved on the previous shutdown, decoding their arguments based on method
+func (eb *EventBus) loadEvents() (err error) {
+	b, err := ioutil.ReadFile(path.Join(eb.persistDir, eventBusFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	var pEvs map[string][]*persistedEvent
+	if err = json.Unmarshal(b, &pEvs); err != nil {
+		return
+	}
+	for id, evs := range pEvs {
+		for _, pEv := range evs {
+			newArgs, has := asyncMethods[pEv.Method]
+			if !has {
+				return fmt.Errorf("unsupported method: <%s>", pEv.Method)
+			}
+			args, _ := newArgs()
+			if err = json.Unmarshal(pEv.Args, args); err != nil {
+				return
+			}
+			eb.pending[id] = append(eb.pending[id], &BusEvent{Method: pEv.Method, Args: args})
+		}
+	}
+	return
+}
+
+// asyncConn is a connection serving the asynchronous methods through the EventBus
+type asyncConn struct {
+	eb      *EventBus
+	address string
+	conn    rpcclient.RpcClientConnection
+}
+
+// Call queues the asynchronous methods, returning without waiting for their reply
+func (ac *asyncConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if _, canAsync := asyncMethods[serviceMethod]; !canAsync {
+		return ac.conn.Call(serviceMethod, args, reply)
+	}
+	// one subscriber per address and service, so *internal connections to different services do not mix
+	id := utils.ConcatenatedKey(ac.address, strings.Split(serviceMethod, utils.NestingSep)[0])
+	if err := ac.eb.Publish(id, ac.conn, &BusEvent{Method: serviceMethod, Args: args}); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s queuing %s towards: <%s>",
+				utils.EventBus, err.Error(), serviceMethod, id))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testBusConn records the calls received, blocking them until released
+type testBusConn struct {
+	release chan struct{}
+	evs     chan string
+}
+
+func (tc *testBusConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if serviceMethod != utils.ThresholdSv1ProcessEvent {
+		return utils.ErrNotImplemented
+	}
+	<-tc.release
+	tc.evs <- args.(*ArgsProcessEvent).ID
+	return nil
+}
+
+func TestEventBusAsyncConn(t *testing.T) {
+	eb, err := NewEventBus(1, utils.MetaDrop, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := &testBusConn{release: make(chan struct{}), evs: make(chan string, 3)}
+	conn := eb.NewAsyncConn(utils.MetaInternal, tc)
+	if err := conn.Call(utils.StatSv1GetQueueIDs, "cgrates.org", new([]string)); err != utils.ErrNotImplemented {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotImplemented, err)
+	}
+	for _, evID := range []string{"EV1", "EV2", "EV3"} {
+		if err := conn.Call(utils.ThresholdSv1ProcessEvent, &ArgsProcessEvent{
+			CGREvent: utils.CGREvent{Tenant: "cgrates.org", ID: evID}}, new([]string)); err != nil {
+			t.Error(err)
+		}
+		time.Sleep(10 * time.Millisecond) // let the consumer pick up EV1
+	}
+	subID := utils.ConcatenatedKey(utils.MetaInternal, utils.ThresholdSv1)
+	if mtrcs := eb.Metrics()[subID]; mtrcs == nil ||
+		mtrcs.Published != 2 || mtrcs.Dropped != 1 || mtrcs.Queued != 1 {
+		t.Errorf("unexpected metrics: %s", utils.ToJSON(mtrcs))
+	}
+	tc.release <- struct{}{}
+	tc.release <- struct{}{}
+	for _, eEvID := range []string{"EV1", "EV2"} {
+		select {
+		case evID := <-tc.evs:
+			if evID != eEvID {
+				t.Errorf("expecting: %s, received: %s", eEvID, evID)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("event not consumed")
+		}
+	}
+	if err := eb.Shutdown(); err != nil {
+		t.Error(err)
+	}
+	if mtrcs := eb.Metrics()[subID]; mtrcs.Processed != 2 {
+		t.Errorf("unexpected metrics: %s", utils.ToJSON(mtrcs))
+	}
+}
+
+func TestEventBusPersistence(t *testing.T) {
+	persistDir, err := ioutil.TempDir("", "eventbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(persistDir)
+	eb, err := NewEventBus(10, utils.MetaBlock, persistDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := &testBusConn{release: make(chan struct{}), evs: make(chan string, 2)}
+	conn := eb.NewAsyncConn("127.0.0.1:2012", tc)
+	for _, evID := range []string{"EV1", "EV2"} {
+		if err := conn.Call(utils.ThresholdSv1ProcessEvent, &ArgsProcessEvent{
+			CGREvent: utils.CGREvent{Tenant: "cgrates.org", ID: evID}}, new([]string)); err != nil {
+			t.Error(err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	errChan := make(chan error)
+	go func() { errChan <- eb.Shutdown() }()
+	time.Sleep(10 * time.Millisecond)
+	tc.release <- struct{}{} // let the consumer finish EV1 so the shutdown can complete
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	// EV2 was still queued, the new bus needs to deliver it
+	if eb, err = NewEventBus(10, utils.MetaBlock, persistDir); err != nil {
+		t.Fatal(err)
+	}
+	tc = &testBusConn{release: make(chan struct{}, 2), evs: make(chan string, 2)}
+	tc.release <- struct{}{}
+	tc.release <- struct{}{}
+	if err := eb.NewAsyncConn("127.0.0.1:2012", tc).Call(utils.ThresholdSv1ProcessEvent, &ArgsProcessEvent{
+		CGREvent: utils.CGREvent{Tenant: "cgrates.org", ID: "EV3"}}, new([]string)); err != nil {
+		t.Error(err)
+	}
+	select {
+	case evID := <-tc.evs:
+		if evID != "EV2" {
+			t.Errorf("expecting: EV2, received: %s", evID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("persisted event not consumed")
+	}
+}
+
+func TestEventBusPublishAfterShutdown(t *testing.T) {
+	eb, err := NewEventBus(10, utils.MetaBlock, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := &testBusConn{release: make(chan struct{}), evs: make(chan string, 2)}
+	conn := eb.NewAsyncConn(utils.MetaInternal, tc)
+	for _, evID := range []string{"EV1", "EV2"} {
+		if err := conn.Call(utils.ThresholdSv1ProcessEvent, &ArgsProcessEvent{
+			CGREvent: utils.CGREvent{Tenant: "cgrates.org", ID: evID}}, new([]string)); err != nil {
+			t.Error(err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond) // let the consumer pick up EV1
+	errChan := make(chan error)
+	go func() { errChan <- eb.Shutdown() }()
+	time.Sleep(10 * time.Millisecond)
+	tc.release <- struct{}{}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	subID := utils.ConcatenatedKey(utils.MetaInternal, utils.ThresholdSv1)
+	if mtrcs := eb.Metrics()[subID]; mtrcs == nil ||
+		mtrcs.Processed != 1 || mtrcs.Dropped != 1 {
+		t.Errorf("unexpected metrics: %s", utils.ToJSON(mtrcs))
+	}
+	if err := conn.Call(utils.ThresholdSv1ProcessEvent, &ArgsProcessEvent{
+		CGREvent: utils.CGREvent{Tenant: "cgrates.org", ID: "EV3"}}, new([]string)); err != utils.ErrDisconnected {
+		t.Errorf("expecting: %v, received: %v", utils.ErrDisconnected, err)
+	}
+	if err := eb.NewAsyncConn("127.0.0.1:2012", tc).Call(utils.StatSv1ProcessEvent,
+		&StatsArgsProcessEvent{}, new([]string)); err != utils.ErrDisconnected {
+		t.Errorf("expecting: %v, received: %v", utils.ErrDisconnected, err)
+	}
+	if mtrcs := eb.Metrics(); len(mtrcs) != 1 {
+		t.Errorf("subscriber created after shutdown: %s", utils.ToJSON(mtrcs))
+	}
+}
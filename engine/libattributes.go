@@ -19,19 +19,107 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
 type Attribute struct {
+	FilterIDs  []string // apply the Attribute only if the filters are passing
 	FieldName  string
+	Type       string // *composed(default), *sum, *difference, *multiply, *divide or *remove
 	Initial    interface{}
 	Substitute config.RSRParsers
 	Append     bool
 }
 
+// compile checks the Type and compiles the Substitute
+func (attr *Attribute) compile() (err error) {
+	switch attr.Type {
+	case utils.EmptyString, utils.META_COMPOSED, utils.MetaRemove:
+	case utils.MetaSum, utils.MetaDifference, utils.MetaMultiply, utils.MetaDivide:
+		if len(attr.Substitute) < 2 {
+			return fmt.Errorf("not enough operands for %s on field: <%s>",
+				attr.Type, attr.FieldName)
+		}
+	default:
+		return fmt.Errorf("unsupported type: <%s> on field: <%s>",
+			attr.Type, attr.FieldName)
+	}
+	return attr.Substitute.Compile()
+}
+
+// substitute returns the new value of the field out of the event
+// *none is returned for the fields which should be removed
+func (attr *Attribute) substitute(ev map[string]interface{}) (out string, err error) {
+	switch attr.Type {
+	case utils.MetaRemove:
+		return utils.META_NONE, nil
+	case utils.MetaSum, utils.MetaDifference, utils.MetaMultiply, utils.MetaDivide:
+		operands := make([]string, len(attr.Substitute))
+		for i, prsr := range attr.Substitute {
+			if operands[i], err = prsr.ParseEvent(ev); err != nil {
+				return
+			}
+		}
+		return computeOperands(attr.Type, operands)
+	default:
+		return attr.Substitute.ParseEvent(ev)
+	}
+}
+
+// computeOperands applies the arithmetic operation on operands
+// the operands are considered numbers and, if not, durations for *sum and *difference
+func computeOperands(op string, operands []string) (out string, err error) {
+	nums := make([]float64, len(operands))
+	for i, opr := range operands {
+		if nums[i], err = strconv.ParseFloat(opr, 64); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		res := nums[0]
+		for _, num := range nums[1:] {
+			switch op {
+			case utils.MetaSum:
+				res += num
+			case utils.MetaDifference:
+				res -= num
+			case utils.MetaMultiply:
+				res *= num
+			case utils.MetaDivide:
+				if num == 0 {
+					return "", fmt.Errorf("division by zero in operands: %v", operands)
+				}
+				res /= num
+			}
+		}
+		return strconv.FormatFloat(res, 'f', -1, 64), nil
+	}
+	if op != utils.MetaSum && op != utils.MetaDifference {
+		return "", fmt.Errorf("invalid operands for %s: %v", op, operands)
+	}
+	durs := make([]time.Duration, len(operands))
+	for i, opr := range operands {
+		if durs[i], err = utils.ParseDurationWithNanosecs(opr); err != nil {
+			return "", fmt.Errorf("invalid operands for %s: %v", op, operands)
+		}
+	}
+	res := durs[0]
+	for _, dur := range durs[1:] {
+		if op == utils.MetaSum {
+			res += dur
+		} else {
+			res -= dur
+		}
+	}
+	return res.String(), nil
+}
+
 type AttributeProfile struct {
 	Tenant             string
 	ID                 string
@@ -42,23 +130,24 @@ type AttributeProfile struct {
 	Blocker            bool // blocker flag to stop processing on multiple runs
 	Weight             float64
 
-	attributesIdx map[string]map[interface{}]*Attribute // map[FieldName][InitialValue]*Attribute, used as event match index
+	attributesIdx map[string]map[interface{}][]*Attribute // map[FieldName][InitialValue][]*Attribute, used as event match index
 }
 
 // computeAttributesIndex populates .attributes
 func (ap *AttributeProfile) computeAttributesIndex() {
-	ap.attributesIdx = make(map[string]map[interface{}]*Attribute)
+	ap.attributesIdx = make(map[string]map[interface{}][]*Attribute)
 	for _, attr := range ap.Attributes {
 		if _, has := ap.attributesIdx[attr.FieldName]; !has {
-			ap.attributesIdx[attr.FieldName] = make(map[interface{}]*Attribute)
+			ap.attributesIdx[attr.FieldName] = make(map[interface{}][]*Attribute)
 		}
-		ap.attributesIdx[attr.FieldName][attr.Initial] = attr
+		ap.attributesIdx[attr.FieldName][attr.Initial] = append(
+			ap.attributesIdx[attr.FieldName][attr.Initial], attr) // keep the order for the filters
 	}
 }
 
 func (ap *AttributeProfile) compileSubstitutes() (err error) {
 	for _, attr := range ap.Attributes {
-		if err = attr.Substitute.Compile(); err != nil {
+		if err = attr.compile(); err != nil {
 			return
 		}
 	}
@@ -59,6 +59,10 @@ func NewRPCPool(dispatchStrategy string, key_path, cert_path, ca_path string, co
 		if err == nil {
 			atLestOneConnected = true
 		}
+		if rpcConnCfg.Async && eventBus != nil {
+			rpcPool.AddClient(eventBus.NewAsyncConn(rpcConnCfg.Address, rpcClient))
+			continue
+		}
 		rpcPool.AddClient(rpcClient)
 	}
 	if atLestOneConnected {
@@ -255,9 +255,10 @@ cgrates.org,SPP_1,,,,,supplier1,FLTR_DST_DE,Account2,RPL_3,ResGroup3,Stat2,10,,,
 cgrates.org,SPP_1,,,,,supplier1,,,,ResGroup4,Stat3,10,,,
 `
 	attributeProfiles = `
-#Tenant,ID,Contexts,FilterIDs,ActivationInterval,FieldName,Initial,Substitute,Append,Blocker,Weight
+#Tenant,ID,Contexts,FilterIDs,ActivationInterval,FieldName,Initial,Substitute,Append,Blocker,Weight,AttributeFilterIDs,Type
 cgrates.org,ALS1,con1,FLTR_1,2014-07-29T15:00:00Z,Field1,Initial1,Sub1,true,true,20
-cgrates.org,ALS1,con2;con3,,,Field2,Initial2,Sub2,false,,
+cgrates.org,ALS1,con2;con3,,,Field2,Initial2,Sub2,false,,,,
+cgrates.org,ALS1,,,,Field3,*any,~Field4;10,true,,,*string:Field1:Initial1;*prefix:Field2:Init,*sum
 `
 	chargerProfiles = `
 #Tenant,ID,FilterIDs,ActivationInterval,RunID,AttributeIDs,Weight
@@ -1519,6 +1520,14 @@ func TestLoadAttributeProfiles(t *testing.T) {
 					Substitute: "Sub2",
 					Append:     false,
 				},
+				&utils.TPAttribute{
+					FilterIDs:  []string{"*string:Field1:Initial1", "*prefix:Field2:Init"},
+					FieldName:  "Field3",
+					Type:       utils.MetaSum,
+					Initial:    utils.ANY,
+					Substitute: "~Field4;10",
+					Append:     true,
+				},
 			},
 			Blocker: true,
 			Weight:  20,
@@ -0,0 +1,432 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewLoadVersion creates an empty LoadVersion for the load with loadID
+func NewLoadVersion(loadID string) *LoadVersion {
+	return &LoadVersion{LoadID: loadID, LoadTime: time.Now()}
+}
+
+// LoadVersion keeps the DataDB objects replaced by a tariff plan load so the load can be rolled back
+type LoadVersion struct {
+	LoadID   string
+	LoadTime time.Time
+	Items    []*LoadVersionItem
+	recorded utils.StringMap // prefix+ID of the items already recorded
+}
+
+// LoadVersionItem is the version of one object before the load
+type LoadVersionItem struct {
+	Prefix string // DataDB prefix of the object, eg: rpl_
+	ID     string // ID of the object, tenant:ID for the profiles
+	Value  []byte // marshaled object, empty if the object was created by the load
+}
+
+// Record stores the version of the object currently in DataDB, only the first call for an object counts
+func (lv *LoadVersion) Record(dm *DataManager, prefix, id string) (err error) {
+	if lv.recorded == nil {
+		lv.recorded = make(utils.StringMap)
+	}
+	if lv.recorded.HasKey(prefix + id) {
+		return
+	}
+	itm := &LoadVersionItem{Prefix: prefix, ID: id}
+	var stored interface{}
+	if stored, err = dm.getVersionedItem(prefix, id); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		err = nil
+	} else if itm.Value, err = dm.DataDB().Marshaler().Marshal(stored); err != nil {
+		return
+	}
+	lv.recorded[prefix+id] = true
+	lv.Items = append(lv.Items, itm)
+	return
+}
+
+// IDs returns the IDs of the recorded objects with prefix
+func (lv *LoadVersion) IDs(prefix string) (ids []string) {
+	for _, itm := range lv.Items {
+		if itm.Prefix == prefix {
+			ids = append(ids, itm.ID)
+		}
+	}
+	return
+}
+
+// getVersionedItem returns the object stored in DataDB, bypassing the cache
+func (dm *DataManager) getVersionedItem(prefix, id string) (interface{}, error) {
+	switch prefix {
+	case utils.DESTINATION_PREFIX:
+		return dm.DataDB().GetDestination(id, true, utils.NonTransactional)
+	case utils.TimingsPrefix:
+		return dm.GetTiming(id, true, utils.NonTransactional)
+	case utils.RATING_PLAN_PREFIX:
+		return dm.GetRatingPlan(id, true, utils.NonTransactional)
+	case utils.RATING_PROFILE_PREFIX:
+		return dm.GetRatingProfile(id, true, utils.NonTransactional)
+	case utils.ACTION_PREFIX:
+		return dm.GetActions(id, true, utils.NonTransactional)
+	case utils.ACTION_PLAN_PREFIX:
+		return dm.DataDB().GetActionPlan(id, true, utils.NonTransactional)
+	case utils.AccountActionPlansPrefix:
+		return dm.DataDB().GetAccountActionPlans(id, true, utils.NonTransactional)
+	case utils.ACTION_TRIGGER_PREFIX:
+		return dm.GetActionTriggers(id, true, utils.NonTransactional)
+	case utils.SHARED_GROUP_PREFIX:
+		return dm.GetSharedGroup(id, true, utils.NonTransactional)
+	}
+	tntID := utils.NewTenantID(id)
+	switch prefix {
+	case utils.FilterPrefix:
+		return dm.GetFilter(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.ResourceProfilesPrefix:
+		return dm.GetResourceProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.StatQueueProfilePrefix:
+		return dm.GetStatQueueProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.ThresholdProfilePrefix:
+		return dm.GetThresholdProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.SupplierProfilePrefix:
+		return dm.GetSupplierProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.AttributeProfilePrefix:
+		return dm.GetAttributeProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.ChargerProfilePrefix:
+		return dm.GetChargerProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.DispatcherProfilePrefix:
+		return dm.GetDispatcherProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	case utils.PortedNumbersPrefix:
+		return dm.GetPortedNumbers(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+	}
+	return nil, fmt.Errorf("unsupported load version prefix <%s>", prefix)
+}
+
+// decodeVersionedItem unmarshals the object kept by a LoadVersionItem
+func (dm *DataManager) decodeVersionedItem(itm *LoadVersionItem) (obj interface{}, err error) {
+	switch itm.Prefix {
+	case utils.DESTINATION_PREFIX:
+		obj = new(Destination)
+	case utils.TimingsPrefix:
+		obj = new(utils.TPTiming)
+	case utils.RATING_PLAN_PREFIX:
+		obj = new(RatingPlan)
+	case utils.RATING_PROFILE_PREFIX:
+		obj = new(RatingProfile)
+	case utils.ACTION_PREFIX:
+		obj = new(Actions)
+	case utils.ACTION_PLAN_PREFIX:
+		obj = new(ActionPlan)
+	case utils.AccountActionPlansPrefix:
+		obj = new([]string)
+	case utils.ACTION_TRIGGER_PREFIX:
+		obj = new(ActionTriggers)
+	case utils.SHARED_GROUP_PREFIX:
+		obj = new(SharedGroup)
+	case utils.FilterPrefix:
+		obj = new(Filter)
+	case utils.ResourceProfilesPrefix:
+		obj = new(ResourceProfile)
+	case utils.StatQueueProfilePrefix:
+		obj = new(StatQueueProfile)
+	case utils.ThresholdProfilePrefix:
+		obj = new(ThresholdProfile)
+	case utils.SupplierProfilePrefix:
+		obj = new(SupplierProfile)
+	case utils.AttributeProfilePrefix:
+		obj = new(AttributeProfile)
+	case utils.ChargerProfilePrefix:
+		obj = new(ChargerProfile)
+	case utils.DispatcherProfilePrefix:
+		obj = new(DispatcherProfile)
+	case utils.PortedNumbersPrefix:
+		obj = new(PortedNumbers)
+	default:
+		return nil, fmt.Errorf("unsupported load version prefix <%s>", itm.Prefix)
+	}
+	if err = dm.DataDB().Marshaler().Unmarshal(itm.Value, obj); err != nil {
+		return nil, err
+	}
+	switch v := obj.(type) { // slices are handled by value
+	case *Actions:
+		obj = *v
+	case *[]string:
+		obj = *v
+	case *ActionTriggers:
+		obj = *v
+	}
+	return
+}
+
+// restoreVersionedItem writes back the recorded version of the object, refreshing the caches
+func (dm *DataManager) restoreVersionedItem(itm *LoadVersionItem) (err error) {
+	var obj interface{}
+	if obj, err = dm.decodeVersionedItem(itm); err != nil {
+		return
+	}
+	switch itm.Prefix {
+	case utils.DESTINATION_PREFIX:
+		dst := obj.(*Destination)
+		var oldDst *Destination
+		if oldDst, err = dm.DataDB().GetDestination(itm.ID, true,
+			utils.NonTransactional); err != nil && err != utils.ErrNotFound {
+			return
+		}
+		if err = dm.DataDB().SetDestination(dst, utils.NonTransactional); err != nil {
+			return
+		}
+		if err = dm.CacheDataFromDB(utils.DESTINATION_PREFIX, []string{dst.Id}, true); err != nil {
+			return
+		}
+		if err = dm.DataDB().UpdateReverseDestination(oldDst, dst, utils.NonTransactional); err != nil {
+			return
+		}
+		return dm.CacheDataFromDB(utils.REVERSE_DESTINATION_PREFIX, dst.Prefixes, true)
+	case utils.TimingsPrefix:
+		return dm.SetTiming(obj.(*utils.TPTiming))
+	case utils.RATING_PLAN_PREFIX:
+		return dm.SetRatingPlan(obj.(*RatingPlan), utils.NonTransactional)
+	case utils.RATING_PROFILE_PREFIX:
+		return dm.SetRatingProfile(obj.(*RatingProfile), utils.NonTransactional)
+	case utils.ACTION_PREFIX:
+		return dm.SetActions(itm.ID, obj.(Actions), utils.NonTransactional)
+	case utils.ACTION_PLAN_PREFIX:
+		if err = dm.DataDB().SetActionPlan(itm.ID, obj.(*ActionPlan), true, utils.NonTransactional); err != nil {
+			return
+		}
+		return dm.CacheDataFromDB(utils.ACTION_PLAN_PREFIX, []string{itm.ID}, true)
+	case utils.AccountActionPlansPrefix:
+		if err = dm.DataDB().SetAccountActionPlans(itm.ID, obj.([]string), true); err != nil {
+			return
+		}
+		return dm.CacheDataFromDB(utils.AccountActionPlansPrefix, []string{itm.ID}, true)
+	case utils.ACTION_TRIGGER_PREFIX:
+		return dm.SetActionTriggers(itm.ID, obj.(ActionTriggers), utils.NonTransactional)
+	case utils.SHARED_GROUP_PREFIX:
+		return dm.SetSharedGroup(obj.(*SharedGroup), utils.NonTransactional)
+	case utils.FilterPrefix:
+		return dm.SetFilter(obj.(*Filter))
+	case utils.ResourceProfilesPrefix:
+		return dm.SetResourceProfile(obj.(*ResourceProfile), true)
+	case utils.StatQueueProfilePrefix:
+		return dm.SetStatQueueProfile(obj.(*StatQueueProfile), true)
+	case utils.ThresholdProfilePrefix:
+		return dm.SetThresholdProfile(obj.(*ThresholdProfile), true)
+	case utils.SupplierProfilePrefix:
+		return dm.SetSupplierProfile(obj.(*SupplierProfile), true)
+	case utils.AttributeProfilePrefix:
+		return dm.SetAttributeProfile(obj.(*AttributeProfile), true)
+	case utils.ChargerProfilePrefix:
+		return dm.SetChargerProfile(obj.(*ChargerProfile), true)
+	case utils.DispatcherProfilePrefix:
+		return dm.SetDispatcherProfile(obj.(*DispatcherProfile), true)
+	case utils.PortedNumbersPrefix:
+		return dm.SetPortedNumbers(obj.(*PortedNumbers))
+	}
+	return fmt.Errorf("unsupported load version prefix <%s>", itm.Prefix)
+}
+
+// removeVersionedItem removes an object which was created by the load
+func (dm *DataManager) removeVersionedItem(itm *LoadVersionItem) (err error) {
+	tntID := utils.NewTenantID(itm.ID)
+	switch itm.Prefix {
+	case utils.DESTINATION_PREFIX:
+		err = dm.DataDB().RemoveDestination(itm.ID, utils.NonTransactional)
+	case utils.TimingsPrefix:
+		err = dm.RemoveTiming(itm.ID, utils.NonTransactional)
+	case utils.RATING_PLAN_PREFIX:
+		err = dm.RemoveRatingPlan(itm.ID, utils.NonTransactional)
+	case utils.RATING_PROFILE_PREFIX:
+		err = dm.RemoveRatingProfile(itm.ID, utils.NonTransactional)
+	case utils.ACTION_PREFIX:
+		err = dm.RemoveActions(itm.ID, utils.NonTransactional)
+	case utils.ACTION_PLAN_PREFIX:
+		err = dm.DataDB().RemoveActionPlan(itm.ID, utils.NonTransactional)
+	case utils.AccountActionPlansPrefix:
+		if err = dm.DataDB().RemAccountActionPlans(itm.ID, nil); err == nil {
+			Cache.Remove(utils.CacheAccountActionPlans, itm.ID,
+				cacheCommit(utils.NonTransactional), utils.NonTransactional)
+		}
+	case utils.ACTION_TRIGGER_PREFIX:
+		err = dm.RemoveActionTriggers(itm.ID, utils.NonTransactional)
+	case utils.SHARED_GROUP_PREFIX:
+		err = dm.RemoveSharedGroup(itm.ID, utils.NonTransactional)
+	case utils.FilterPrefix:
+		err = dm.RemoveFilter(tntID.Tenant, tntID.ID, utils.NonTransactional)
+	case utils.ResourceProfilesPrefix:
+		err = dm.RemoveResourceProfile(tntID.Tenant, tntID.ID, utils.NonTransactional, true)
+	case utils.StatQueueProfilePrefix:
+		err = dm.RemoveStatQueueProfile(tntID.Tenant, tntID.ID, utils.NonTransactional, true)
+	case utils.ThresholdProfilePrefix:
+		err = dm.RemoveThresholdProfile(tntID.Tenant, tntID.ID, utils.NonTransactional, true)
+	case utils.SupplierProfilePrefix:
+		err = dm.RemoveSupplierProfile(tntID.Tenant, tntID.ID, utils.NonTransactional, true)
+	case utils.AttributeProfilePrefix:
+		err = dm.RemoveAttributeProfile(tntID.Tenant, tntID.ID, utils.NonTransactional, true)
+	case utils.ChargerProfilePrefix:
+		err = dm.RemoveChargerProfile(tntID.Tenant, tntID.ID, utils.NonTransactional, true)
+	case utils.DispatcherProfilePrefix:
+		err = dm.RemoveDispatcherProfile(tntID.Tenant, tntID.ID, utils.NonTransactional, true)
+	case utils.PortedNumbersPrefix:
+		err = dm.RemovePortedNumbers(tntID.Tenant, tntID.ID, utils.NonTransactional)
+	default:
+		err = fmt.Errorf("unsupported load version prefix <%s>", itm.Prefix)
+	}
+	if err == utils.ErrNotFound { // already gone
+		err = nil
+	}
+	return
+}
+
+// RollbackLoad restores the objects replaced by the load with loadID and removes the ones it created
+// filters are restored first and removed last so the profiles are indexed on the right version of them
+func (dm *DataManager) RollbackLoad(loadID string) (lv *LoadVersion, err error) {
+	if lv, err = dm.DataDB().GetLoadVersionDrv(loadID); err != nil {
+		return
+	}
+	if err = dm.checkNewerLoads(lv); err != nil {
+		return
+	}
+	if err = dm.restoreLoadVersion(lv); err != nil {
+		return
+	}
+	err = dm.DataDB().RemoveLoadVersionDrv(loadID)
+	return
+}
+
+// checkNewerLoads returns error if a load newer than lv, not rolled back yet, changed any of the objects of lv
+// rolling back lv would otherwise overwrite the changes of the newer load
+func (dm *DataManager) checkNewerLoads(lv *LoadVersion) (err error) {
+	loadHist, err := dm.DataDB().GetLoadHistory(-1, true, utils.NonTransactional)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	itmIDs := make(utils.StringMap)
+	for _, itm := range lv.Items {
+		itmIDs[itm.Prefix+itm.ID] = true
+	}
+	for _, ldInst := range loadHist { // newest first
+		if ldInst.LoadID == lv.LoadID {
+			break
+		}
+		var newerLv *LoadVersion
+		if newerLv, err = dm.DataDB().GetLoadVersionDrv(ldInst.LoadID); err != nil {
+			if err != utils.ErrNotFound { // rolled back or out of history
+				return
+			}
+			err = nil
+			continue
+		}
+		for _, itm := range newerLv.Items {
+			if itmIDs.HasKey(itm.Prefix + itm.ID) {
+				return fmt.Errorf("load <%s> changed <%s> after load <%s>, roll it back first",
+					newerLv.LoadID, itm.Prefix+itm.ID, lv.LoadID)
+			}
+		}
+	}
+	return
+}
+
+// restoreLoadVersion writes back the objects recorded in lv
+func (dm *DataManager) restoreLoadVersion(lv *LoadVersion) (err error) {
+	for _, fltrs := range []bool{true, false} {
+		for _, itm := range lv.Items {
+			if len(itm.Value) == 0 ||
+				(itm.Prefix == utils.FilterPrefix) != fltrs {
+				continue
+			}
+			if err = dm.restoreVersionedItem(itm); err != nil {
+				return
+			}
+		}
+	}
+	for _, fltrs := range []bool{false, true} {
+		for i := len(lv.Items) - 1; i >= 0; i-- {
+			if len(lv.Items[i].Value) != 0 ||
+				(lv.Items[i].Prefix == utils.FilterPrefix) != fltrs {
+				continue
+			}
+			if err = dm.removeVersionedItem(lv.Items[i]); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// NewLoadVersion records the versions of the DataDB objects WriteToDatabase would overwrite
+// accounts and the objects with state (resources, stat queues, thresholds) are not versioned
+func (tpr *TpReader) NewLoadVersion(loadID string) (lv *LoadVersion, err error) {
+	if tpr.dm.dataDB == nil {
+		return nil, errors.New("no database connection")
+	}
+	lv = NewLoadVersion(loadID)
+	for _, prfx := range []string{
+		utils.FilterPrefix,
+		utils.DESTINATION_PREFIX,
+		utils.TimingsPrefix,
+		utils.RATING_PLAN_PREFIX,
+		utils.RATING_PROFILE_PREFIX,
+		utils.ACTION_PREFIX,
+		utils.ACTION_PLAN_PREFIX,
+		utils.AccountActionPlansPrefix,
+		utils.ACTION_TRIGGER_PREFIX,
+		utils.SHARED_GROUP_PREFIX,
+		utils.ResourceProfilesPrefix,
+		utils.StatQueueProfilePrefix,
+		utils.ThresholdProfilePrefix,
+		utils.SupplierProfilePrefix,
+		utils.AttributeProfilePrefix,
+		utils.ChargerProfilePrefix,
+		utils.DispatcherProfilePrefix,
+	} {
+		var ids []string
+		if ids, err = tpr.GetLoadedIds(prfx); err != nil {
+			return
+		}
+		for _, id := range ids {
+			if err = lv.Record(tpr.dm, prfx, id); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// SaveLoadVersion records under loadID the objects the following WriteToDatabase will replace
+func (tpr *TpReader) SaveLoadVersion(loadID string, loadHistSize int) (err error) {
+	if loadHistSize == 0 { // load history disabled
+		return
+	}
+	var lv *LoadVersion
+	if lv, err = tpr.NewLoadVersion(loadID); err != nil {
+		return
+	}
+	return tpr.dm.SetLoadVersion(lv, loadHistSize)
+}
@@ -0,0 +1,125 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestDataManagerRollbackLoad(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := NewDataManager(dataDB)
+	for i, dsts := range []string{`
+#Tag,Prefix
+DST_1002,1002
+DST_1003,1003
+`, `
+#Tag,Prefix
+DST_1003,10031
+DST_1004,1004
+`} {
+		tpr := newDiffTestTpReader(dataDB, dsts)
+		if err := tpr.LoadDestinations(); err != nil {
+			t.Fatal(err)
+		}
+		if err := tpr.SaveLoadVersion([]string{"LOAD1", "LOAD2"}[i], 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := tpr.WriteToDatabase(false, false, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ldHist, err := dataDB.GetLoadHistory(-1, true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if len(ldHist) != 2 || ldHist[0].LoadID != "LOAD2" || ldHist[1].LoadID != "LOAD1" {
+		t.Errorf("unexpected load history: %s", utils.ToJSON(ldHist))
+	}
+	// LOAD2 changed DST_1003 after LOAD1
+	if _, err := dm.RollbackLoad("LOAD1"); err == nil {
+		t.Error("expecting error for rolling back over a newer load")
+	}
+	if dst, err := dataDB.GetDestination("DST_1003", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"10031"}, dst.Prefixes) {
+		t.Errorf("unexpected destination: %s", utils.ToJSON(dst))
+	}
+	if _, err := dm.RollbackLoad("LOAD2"); err != nil {
+		t.Fatal(err)
+	}
+	if dst, err := dataDB.GetDestination("DST_1003", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"1003"}, dst.Prefixes) {
+		t.Errorf("unexpected destination: %s", utils.ToJSON(dst))
+	}
+	if _, err := dataDB.GetDestination("DST_1002", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+	if _, err := dataDB.GetDestination("DST_1004", true,
+		utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.GetLoadVersion("LOAD2"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.RollbackLoad("LOAD2"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.RollbackLoad("LOAD1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, dstID := range []string{"DST_1002", "DST_1003"} {
+		if _, err := dataDB.GetDestination(dstID, true,
+			utils.NonTransactional); err != utils.ErrNotFound {
+			t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+		}
+	}
+}
+
+func TestDataManagerSetLoadVersionHistory(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := NewDataManager(dataDB)
+	for _, loadID := range []string{"LOAD1", "LOAD2"} {
+		if err := dm.SetLoadVersion(NewLoadVersion(loadID), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := dm.GetLoadVersion("LOAD1"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if lv, err := dm.GetLoadVersion("LOAD2"); err != nil {
+		t.Error(err)
+	} else if lv.LoadID != "LOAD2" {
+		t.Errorf("unexpected load version: %s", utils.ToJSON(lv))
+	}
+	if err := dm.SetLoadVersion(NewLoadVersion("LOAD3"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.GetLoadVersion("LOAD3"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
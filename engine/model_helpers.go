@@ -2112,8 +2112,14 @@ func (tps TPAttributes) AsTPAttributes() (result []*utils.TPAttributeProfile) {
 			}
 		}
 		if tp.FieldName != "" {
+			var attrFilterIDs []string
+			if tp.AttributeFilterIDs != "" {
+				attrFilterIDs = strings.Split(tp.AttributeFilterIDs, utils.INFIELD_SEP)
+			}
 			th.Attributes = append(th.Attributes, &utils.TPAttribute{
+				FilterIDs:  attrFilterIDs,
 				FieldName:  tp.FieldName,
+				Type:       tp.Type,
 				Initial:    tp.Initial,
 				Substitute: tp.Substitute,
 				Append:     tp.Append,
@@ -2172,7 +2178,9 @@ func APItoModelTPAttribute(th *utils.TPAttributeProfile) (mdls TPAttributes) {
 				mdl.Weight = th.Weight
 			}
 		}
+		mdl.AttributeFilterIDs = strings.Join(reqAttribute.FilterIDs, utils.INFIELD_SEP)
 		mdl.FieldName = reqAttribute.FieldName
+		mdl.Type = reqAttribute.Type
 		mdl.Initial = reqAttribute.Initial
 		mdl.Substitute = reqAttribute.Substitute
 		mdl.Append = reqAttribute.Append
@@ -2205,9 +2213,16 @@ func APItoAttributeProfile(tpAttr *utils.TPAttributeProfile, timezone string) (a
 		attrPrf.Attributes[i] = &Attribute{
 			Append:     reqAttr.Append,
 			FieldName:  reqAttr.FieldName,
+			Type:       reqAttr.Type,
 			Initial:    reqAttr.Initial,
 			Substitute: sbstPrsr,
 		}
+		if len(reqAttr.FilterIDs) != 0 {
+			attrPrf.Attributes[i].FilterIDs = make([]string, len(reqAttr.FilterIDs))
+			for j, fltrID := range reqAttr.FilterIDs {
+				attrPrf.Attributes[i].FilterIDs[j] = fltrID
+			}
+		}
 	}
 	if tpAttr.ActivationInterval != nil {
 		if attrPrf.ActivationInterval, err = tpAttr.ActivationInterval.AsActivationInterval(timezone); err != nil {
@@ -2438,7 +2453,7 @@ func (tps TPDispatchers) AsTPDispatchers() (result []*utils.TPDispatcherProfile)
 			tpDPP.Strategy = tp.Strategy
 		}
 		if tp.StrategyParameters != "" {
-			for _, param := range strings.Split(tp.FilterIDs, utils.INFIELD_SEP) {
+			for _, param := range strings.Split(tp.StrategyParameters, utils.INFIELD_SEP) {
 				tpDPP.StrategyParams = append(tpDPP.StrategyParams, param)
 			}
 		}
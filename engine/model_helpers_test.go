@@ -1292,13 +1292,15 @@ func TestAPItoAttributeProfile(t *testing.T) {
 		},
 		Weight: 20,
 	}
-	mapSubstitutes := make(map[string]map[interface{}]*Attribute)
-	mapSubstitutes["FL1"] = make(map[interface{}]*Attribute)
-	mapSubstitutes["FL1"]["In1"] = &Attribute{
-		FieldName:  "FL1",
-		Initial:    "In1",
-		Substitute: config.NewRSRParsersMustCompile("Al1", true, utils.INFIELD_SEP),
-		Append:     true,
+	mapSubstitutes := make(map[string]map[interface{}][]*Attribute)
+	mapSubstitutes["FL1"] = make(map[interface{}][]*Attribute)
+	mapSubstitutes["FL1"]["In1"] = []*Attribute{
+		{
+			FieldName:  "FL1",
+			Initial:    "In1",
+			Substitute: config.NewRSRParsersMustCompile("Al1", true, utils.INFIELD_SEP),
+			Append:     true,
+		},
 	}
 	expected := &AttributeProfile{
 		Tenant:    "cgrates.org",
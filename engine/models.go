@@ -325,6 +325,26 @@ func (t SessionCostsSQL) TableName() string {
 	return utils.SessionCostsTBL
 }
 
+type BalanceMovementSQL struct {
+	ID           int64
+	Tenant       string
+	Account      string
+	BalanceUuid  string
+	BalanceID    string
+	BalanceType  string
+	Reason       string
+	Cgrid        string
+	ActionID     string
+	ActionPlanID string
+	OldValue     float64
+	NewValue     float64
+	MovementTime time.Time
+}
+
+func (t BalanceMovementSQL) TableName() string {
+	return utils.BalanceHistoryTBL
+}
+
 type TBLVersion struct {
 	ID      uint
 	Item    string
@@ -371,6 +391,8 @@ type TPAttribute struct {
 	Append             bool    `index:"8" re:""`
 	Blocker            bool    `index:"9" re:""`
 	Weight             float64 `index:"10" re:"\d+\.?\d*"`
+	AttributeFilterIDs string  `index:"11" re:""`
+	Type               string  `index:"12" re:""`
 	CreatedAt          time.Time
 }
 
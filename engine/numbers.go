@@ -0,0 +1,383 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// PortedRange routes the numbers between First and Last, inclusive, to RoutingNumber
+// empty First and Last cover all the numbers starting with the prefix
+type PortedRange struct {
+	First         string
+	Last          string
+	RoutingNumber string
+}
+
+// PortedNumbers groups the ported ranges sharing the same prefix
+type PortedNumbers struct {
+	Tenant string
+	ID     string // prefix of the ranges, the number itself when ported alone
+	Ranges []*PortedRange
+}
+
+// TenantID returns the concatenated key between tenant and ID
+func (pn *PortedNumbers) TenantID() string {
+	return utils.ConcatenatedKey(pn.Tenant, pn.ID)
+}
+
+// Validate makes sure the ranges are within the prefix
+func (pn *PortedNumbers) Validate() (err error) {
+	if !utils.IsDigits(pn.ID) {
+		return fmt.Errorf("invalid prefix: <%s>", pn.ID)
+	}
+	for _, rng := range pn.Ranges {
+		if rng.RoutingNumber == "" {
+			return utils.NewErrMandatoryIeMissing("RoutingNumber")
+		}
+		if rng.First == "" && rng.Last == "" {
+			continue
+		}
+		if !utils.IsDigits(rng.First) || !utils.IsDigits(rng.Last) ||
+			!strings.HasPrefix(rng.First, pn.ID) || !strings.HasPrefix(rng.Last, pn.ID) ||
+			len(rng.First) != len(rng.Last) || rng.First > rng.Last {
+			return fmt.Errorf("invalid range: <%s-%s> for prefix: <%s>",
+				rng.First, rng.Last, pn.ID)
+		}
+	}
+	return
+}
+
+// routingNumber returns the routing number of num
+// the ranges are considered before the ones covering the whole prefix
+func (pn *PortedNumbers) routingNumber(num string) (rn string, has bool) {
+	for _, rng := range pn.Ranges {
+		if rng.First == "" {
+			if rn == "" {
+				rn = rng.RoutingNumber
+			}
+			continue
+		}
+		if len(num) == len(rng.First) &&
+			num >= rng.First && num <= rng.Last {
+			return rng.RoutingNumber, true
+		}
+	}
+	return rn, rn != ""
+}
+
+// TPPortedNumber is one line of the ported numbers CSV
+type TPPortedNumber struct {
+	Tenant        string `index:"0" re:""`
+	ID            string `index:"1" re:""`
+	First         string `index:"2" re:""`
+	Last          string `index:"3" re:""`
+	RoutingNumber string `index:"4" re:""`
+}
+
+type TPPortedNumbers []*TPPortedNumber
+
+// AsPortedNumbers groups the lines on tenant and prefix
+func (tps TPPortedNumbers) AsPortedNumbers() (pns []*PortedNumbers, err error) {
+	mst := make(map[string]*PortedNumbers)
+	for _, tp := range tps {
+		tntID := utils.ConcatenatedKey(tp.Tenant, tp.ID)
+		pn, has := mst[tntID]
+		if !has {
+			pn = &PortedNumbers{Tenant: tp.Tenant, ID: tp.ID}
+			mst[tntID] = pn
+			pns = append(pns, pn)
+		}
+		pn.Ranges = append(pn.Ranges, &PortedRange{
+			First:         tp.First,
+			Last:          tp.Last,
+			RoutingNumber: tp.RoutingNumber,
+		})
+	}
+	for _, pn := range pns {
+		if err = pn.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// GetPortedNumbers returns the ported numbers with tenant and prefix
+func (dm *DataManager) GetPortedNumbers(tenant, id string, cacheRead, cacheWrite bool,
+	transactionID string) (pn *PortedNumbers, err error) {
+	tntID := utils.ConcatenatedKey(tenant, id)
+	if cacheRead {
+		if x, ok := Cache.Get(utils.CachePortedNumbers, tntID); ok {
+			if x == nil {
+				return nil, utils.ErrNotFound
+			}
+			return x.(*PortedNumbers), nil
+		}
+	}
+	if pn, err = dm.DataDB().GetPortedNumbersDrv(tenant, id); err != nil {
+		if err == utils.ErrNotFound && cacheWrite {
+			Cache.Set(utils.CachePortedNumbers, tntID, nil, nil,
+				cacheCommit(transactionID), transactionID)
+		}
+		return nil, err
+	}
+	if cacheWrite {
+		Cache.Set(utils.CachePortedNumbers, tntID, pn, nil,
+			cacheCommit(transactionID), transactionID)
+	}
+	return
+}
+
+// SetPortedNumbers stores the ported numbers, replacing the ranges of the same prefix
+func (dm *DataManager) SetPortedNumbers(pn *PortedNumbers) (err error) {
+	if err = dm.DataDB().SetPortedNumbersDrv(pn); err != nil {
+		return
+	}
+	return dm.CacheDataFromDB(utils.PortedNumbersPrefix, []string{pn.TenantID()}, true)
+}
+
+// RemovePortedNumbers removes the ported numbers with tenant and prefix
+func (dm *DataManager) RemovePortedNumbers(tenant, id, transactionID string) (err error) {
+	if err = dm.DataDB().RemovePortedNumbersDrv(tenant, id); err != nil {
+		return
+	}
+	Cache.Remove(utils.CachePortedNumbers, utils.ConcatenatedKey(tenant, id),
+		cacheCommit(transactionID), transactionID)
+	Cache.Remove(utils.CachePortedNumbers, utils.PortedPrefixesIdx,
+		cacheCommit(transactionID), transactionID)
+	return
+}
+
+// GetPortedPrefixes returns the index of the prefixes with ported numbers, as tenant:prefix keys
+// the index is built out of DataDB keys and cached until the ported numbers change
+func (dm *DataManager) GetPortedPrefixes() (idx utils.StringMap, err error) {
+	if x, ok := Cache.Get(utils.CachePortedNumbers, utils.PortedPrefixesIdx); ok {
+		return x.(utils.StringMap), nil
+	}
+	keys, err := dm.DataDB().GetKeysForPrefix(utils.PortedNumbersPrefix)
+	if err != nil {
+		return nil, err
+	}
+	idx = make(utils.StringMap, len(keys))
+	for _, key := range keys {
+		idx[key[len(utils.PortedNumbersPrefix):]] = true
+	}
+	Cache.Set(utils.CachePortedNumbers, utils.PortedPrefixesIdx, idx, nil,
+		true, utils.NonTransactional)
+	return
+}
+
+// NewNumberService constructs the NumberService
+func NewNumberService(dm *DataManager, filterS *FilterS,
+	cfg *config.NumberSCfg) *NumberService {
+	return &NumberService{dm: dm, filterS: filterS, cfg: cfg}
+}
+
+// NumberService normalizes the numbers to E.164 and routes the ported ones
+type NumberService struct {
+	dm      *DataManager
+	filterS *FilterS
+	cfg     *config.NumberSCfg
+}
+
+// ListenAndServe will initialize the service
+func (nS *NumberService) ListenAndServe(exitChan chan bool) (err error) {
+	utils.Logger.Info(fmt.Sprintf("Starting <%s>", utils.NumberS))
+	e := <-exitChan
+	exitChan <- e // put back for the others listening for shutdown request
+	return
+}
+
+// Shutdown is called to shutdown the service
+func (nS *NumberService) Shutdown() (err error) {
+	utils.Logger.Info(fmt.Sprintf("<%s> shutdown initialized", utils.NumberS))
+	utils.Logger.Info(fmt.Sprintf("<%s> shutdown complete", utils.NumberS))
+	return
+}
+
+// dialPlanForEvent returns the first dial plan matching the event, nil if none
+func (nS *NumberService) dialPlanForEvent(tnt string,
+	ev map[string]interface{}) (*config.DialPlanCfg, error) {
+	for _, dp := range nS.cfg.DialPlans {
+		if pass, err := nS.filterS.Pass(tnt, dp.FilterIDs,
+			config.NewNavigableMap(ev)); err != nil {
+			return nil, err
+		} else if pass {
+			return dp, nil
+		}
+	}
+	return nil, nil
+}
+
+// dialPlan returns the dial plan with the id
+func (nS *NumberService) dialPlan(id string) (*config.DialPlanCfg, error) {
+	for _, dp := range nS.cfg.DialPlans {
+		if dp.ID == id {
+			return dp, nil
+		}
+	}
+	return nil, utils.ErrPrefixNotFound(id)
+}
+
+// normalizeNumber returns the E.164 format of num, without leading +
+// local numbers (missing both national and international prefixes) and
+// the values which are not phone numbers are not normalized
+func normalizeNumber(num string, dp *config.DialPlanCfg) (e164 string, normalized bool) {
+	if dp == nil {
+		return utils.NormalizeE164(num, "", "", "", false)
+	}
+	return utils.NormalizeE164(num, dp.InternationalPrefix, dp.NationalPrefix,
+		dp.CountryCode, false)
+}
+
+// routingNumber looks up the longest prefix of num in the ported numbers
+// only the indexed prefixes are queried so the misses do not reach DataDB or cache
+func (nS *NumberService) routingNumber(tnt, num string) (rn string, err error) {
+	idx, err := nS.dm.GetPortedPrefixes()
+	if err != nil {
+		return
+	}
+	for i := len(num); i > 0; i-- {
+		if !idx[utils.ConcatenatedKey(tnt, num[:i])] {
+			continue
+		}
+		var pn *PortedNumbers
+		if pn, err = nS.dm.GetPortedNumbers(tnt, num[:i],
+			true, true, utils.NonTransactional); err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return
+		}
+		if pnRN, has := pn.routingNumber(num); has {
+			return pnRN, nil
+		}
+	}
+	return "", utils.ErrNotFound
+}
+
+// NumberSProcessEventReply is the reply of NumberSv1.ProcessEvent
+type NumberSProcessEventReply struct {
+	AlteredFields []string
+	CGREvent      *utils.CGREvent
+}
+
+// processEvent normalizes the numbers within the event and routes the ported ones
+func (nS *NumberService) processEvent(ev *utils.CGREvent) (rply *NumberSProcessEventReply, err error) {
+	dp, err := nS.dialPlanForEvent(ev.Tenant, ev.Event)
+	if err != nil {
+		return nil, err
+	}
+	rply = &NumberSProcessEventReply{CGREvent: ev.Clone()}
+	for _, fldName := range nS.cfg.NormalizeFields {
+		num, err := ev.FieldAsString(fldName)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		if e164, normalized := normalizeNumber(num, dp); normalized && e164 != num {
+			rply.CGREvent.Event[fldName] = e164
+			rply.AlteredFields = append(rply.AlteredFields, fldName)
+		}
+	}
+	for _, fldName := range nS.cfg.PortabilityFields {
+		num, err := rply.CGREvent.FieldAsString(fldName)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		if !utils.IsDigits(num) { // already routed or not a number
+			continue
+		}
+		rn, err := nS.routingNumber(ev.Tenant, num)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		rply.CGREvent.Event[fldName] = rn + num
+		if !utils.IsSliceMember(rply.AlteredFields, fldName) {
+			rply.AlteredFields = append(rply.AlteredFields, fldName)
+		}
+	}
+	return
+}
+
+// V1ProcessEvent normalizes the numbers within the event and routes the ported ones
+func (nS *NumberService) V1ProcessEvent(args *utils.CGREvent,
+	reply *NumberSProcessEventReply) (err error) {
+	if args.Event == nil {
+		return utils.NewErrMandatoryIeMissing("Event")
+	}
+	rply, err := nS.processEvent(args)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*reply = *rply
+	return
+}
+
+// ArgsLookupNumber are the arguments of NumberSv1.LookupNumber
+type ArgsLookupNumber struct {
+	Tenant     string
+	Number     string
+	DialPlanID string // normalize the number with this dial plan, only + prefixed numbers recognized if empty
+}
+
+// NumberLookup is the reply of NumberSv1.LookupNumber
+type NumberLookup struct {
+	Number        string // E.164 format of the number
+	RoutingNumber string // empty if the number is not ported
+}
+
+// V1LookupNumber returns the E.164 format of the number and its routing number
+func (nS *NumberService) V1LookupNumber(args *ArgsLookupNumber,
+	reply *NumberLookup) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"Tenant", "Number"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	var dp *config.DialPlanCfg
+	if args.DialPlanID != "" {
+		if dp, err = nS.dialPlan(args.DialPlanID); err != nil {
+			return
+		}
+	}
+	num := args.Number
+	if e164, normalized := normalizeNumber(num, dp); normalized {
+		num = e164
+	}
+	if !utils.IsDigits(num) {
+		return fmt.Errorf("invalid number: <%s>", args.Number)
+	}
+	rn, err := nS.routingNumber(args.Tenant, num)
+	if err != nil && err != utils.ErrNotFound {
+		return utils.NewErrServerError(err)
+	}
+	*reply = NumberLookup{Number: num, RoutingNumber: rn}
+	return nil
+}
@@ -0,0 +1,308 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+var dpDE = &config.DialPlanCfg{
+	ID:                  "DE",
+	CountryCode:         "49",
+	NationalPrefix:      "0",
+	InternationalPrefix: "00",
+}
+
+func TestPortedNumbersValidate(t *testing.T) {
+	pn := &PortedNumbers{Tenant: "cgrates.org", ID: "4915112",
+		Ranges: []*PortedRange{
+			{First: "4915112000000", Last: "4915112499999", RoutingNumber: "D262"},
+			{RoutingNumber: "D263"},
+		}}
+	if err := pn.Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, rng := range []*PortedRange{
+		{First: "4915112000000", Last: "4915112499999"},                        // missing routing number
+		{First: "4915112000000", RoutingNumber: "D262"},                        // missing last
+		{First: "4915113000000", Last: "4915113499999", RoutingNumber: "D262"}, // outside prefix
+		{First: "4915112499999", Last: "4915112000000", RoutingNumber: "D262"}, // reversed
+		{First: "491511200000", Last: "4915112499999", RoutingNumber: "D262"},  // different lengths
+	} {
+		if err := (&PortedNumbers{Tenant: "cgrates.org", ID: "4915112",
+			Ranges: []*PortedRange{rng}}).Validate(); err == nil {
+			t.Errorf("expecting error for range: %s", utils.ToJSON(rng))
+		}
+	}
+	if err := (&PortedNumbers{Tenant: "cgrates.org", ID: "+49",
+		Ranges: []*PortedRange{{RoutingNumber: "D262"}}}).Validate(); err == nil {
+		t.Error("expecting error for non numeric prefix")
+	}
+}
+
+func TestPortedNumbersRoutingNumber(t *testing.T) {
+	pn := &PortedNumbers{Tenant: "cgrates.org", ID: "4915112",
+		Ranges: []*PortedRange{
+			{RoutingNumber: "D263"},
+			{First: "4915112000000", Last: "4915112499999", RoutingNumber: "D262"},
+		}}
+	if rn, has := pn.routingNumber("4915112123456"); !has || rn != "D262" {
+		t.Errorf("received: <%s>, %v", rn, has)
+	}
+	if rn, has := pn.routingNumber("4915112523456"); !has || rn != "D263" {
+		t.Errorf("received: <%s>, %v", rn, has)
+	}
+	pn.Ranges = pn.Ranges[1:]
+	if _, has := pn.routingNumber("4915112523456"); has {
+		t.Error("not expecting routing number outside the range")
+	}
+	if _, has := pn.routingNumber("491511212345"); has {
+		t.Error("not expecting routing number for shorter numbers")
+	}
+}
+
+func TestTPPortedNumbersAsPortedNumbers(t *testing.T) {
+	tps := TPPortedNumbers{
+		{Tenant: "cgrates.org", ID: "4915112", First: "4915112000000",
+			Last: "4915112499999", RoutingNumber: "D262"},
+		{Tenant: "cgrates.org", ID: "49151123456", RoutingNumber: "D263"},
+		{Tenant: "cgrates.org", ID: "4915112", RoutingNumber: "D264"},
+	}
+	ePns := []*PortedNumbers{
+		{Tenant: "cgrates.org", ID: "4915112",
+			Ranges: []*PortedRange{
+				{First: "4915112000000", Last: "4915112499999", RoutingNumber: "D262"},
+				{RoutingNumber: "D264"},
+			}},
+		{Tenant: "cgrates.org", ID: "49151123456",
+			Ranges: []*PortedRange{{RoutingNumber: "D263"}}},
+	}
+	if pns, err := tps.AsPortedNumbers(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(ePns, pns) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(ePns), utils.ToJSON(pns))
+	}
+	tps = append(tps, &TPPortedNumber{Tenant: "cgrates.org", ID: "4916"})
+	if _, err := tps.AsPortedNumbers(); err == nil {
+		t.Error("expecting error on missing routing number")
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	testCases := []struct {
+		num        string
+		dp         *config.DialPlanCfg
+		e164       string
+		normalized bool
+	}{
+		{"+49 (151) 123-456", nil, "49151123456", true},
+		{"0049151123456", dpDE, "49151123456", true},
+		{"0151 123456", dpDE, "49151123456", true},
+		{"+49151123456", dpDE, "49151123456", true},
+		{"0151123456", nil, "", false}, // no dial plan for national numbers
+		{"1001", dpDE, "", false},      // local number
+		{"sip:1001@cgrates.org", dpDE, "", false},
+		{"+4915112345678901", dpDE, "", false}, // too long
+	}
+	for _, tc := range testCases {
+		if e164, normalized := normalizeNumber(tc.num, tc.dp); e164 != tc.e164 ||
+			normalized != tc.normalized {
+			t.Errorf("number: <%s>, expecting: <%s>, %v, received: <%s>, %v",
+				tc.num, tc.e164, tc.normalized, e164, normalized)
+		}
+	}
+}
+
+func testNumberService(t *testing.T) *NumberService {
+	data, _ := NewMapStorage()
+	dm := NewDataManager(data)
+	defaultCfg, err := config.NewDefaultCGRConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pn := range []*PortedNumbers{
+		{Tenant: "cgrates.org", ID: "4915112",
+			Ranges: []*PortedRange{
+				{First: "4915112000000", Last: "4915112499999", RoutingNumber: "D262"},
+			}},
+		{Tenant: "cgrates.org", ID: "49171", Ranges: []*PortedRange{{RoutingNumber: "D263"}}},
+	} {
+		if err := dm.SetPortedNumbers(pn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewNumberService(dm, &FilterS{dm: dm, cfg: defaultCfg},
+		&config.NumberSCfg{
+			Enabled:           true,
+			NormalizeFields:   []string{utils.Account, utils.Destination},
+			PortabilityFields: []string{utils.Destination},
+			DialPlans: []*config.DialPlanCfg{
+				{ID: "AT", FilterIDs: []string{"*string:Tenant:cgrates.at"},
+					CountryCode: "43", NationalPrefix: "0", InternationalPrefix: "00"},
+				dpDE,
+			},
+		})
+}
+
+func TestNumberSProcessEvent(t *testing.T) {
+	nS := testNumberService(t)
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "TestNumberSProcessEvent",
+		Event: map[string]interface{}{
+			utils.Tenant:      "cgrates.org",
+			utils.Account:     "1001",
+			utils.Destination: "0151 12 123456",
+		},
+	}
+	eRply := NumberSProcessEventReply{
+		AlteredFields: []string{utils.Destination},
+		CGREvent: &utils.CGREvent{
+			Tenant: "cgrates.org",
+			ID:     "TestNumberSProcessEvent",
+			Event: map[string]interface{}{
+				utils.Tenant:      "cgrates.org",
+				utils.Account:     "1001",
+				utils.Destination: "D2624915112123456",
+			},
+		},
+	}
+	var rply NumberSProcessEventReply
+	if err := nS.V1ProcessEvent(ev, &rply); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eRply, rply) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eRply), utils.ToJSON(rply))
+	}
+	// processing the reply again should not route it twice
+	if err := nS.V1ProcessEvent(rply.CGREvent, &rply); err != nil {
+		t.Error(err)
+	} else if len(rply.AlteredFields) != 0 {
+		t.Errorf("received: %s", utils.ToJSON(rply))
+	}
+	ev.Event[utils.Account] = "+49 171 1234567"
+	ev.Event[utils.Destination] = "0049 30 123456" // not ported
+	eRply.AlteredFields = []string{utils.Account, utils.Destination}
+	eRply.CGREvent.Event[utils.Account] = "491711234567"
+	eRply.CGREvent.Event[utils.Destination] = "4930123456"
+	if err := nS.V1ProcessEvent(ev, &rply); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eRply, rply) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eRply), utils.ToJSON(rply))
+	}
+	ev.Event[utils.Tenant] = "cgrates.at" // selects the AT dial plan
+	ev.Event[utils.Destination] = "0171 1234567"
+	if err := nS.V1ProcessEvent(ev, &rply); err != nil {
+		t.Error(err)
+	} else if rply.CGREvent.Event[utils.Destination] != "431711234567" {
+		t.Errorf("received: %s", utils.ToJSON(rply))
+	}
+}
+
+func TestNumberSLookupNumber(t *testing.T) {
+	nS := testNumberService(t)
+	var rply NumberLookup
+	if err := nS.V1LookupNumber(&ArgsLookupNumber{Tenant: "cgrates.org",
+		Number: "0171 7654321", DialPlanID: "DE"}, &rply); err != nil {
+		t.Error(err)
+	} else if eRply := (NumberLookup{Number: "491717654321",
+		RoutingNumber: "D263"}); rply != eRply {
+		t.Errorf("expecting: %+v, received: %+v", eRply, rply)
+	}
+	if err := nS.V1LookupNumber(&ArgsLookupNumber{Tenant: "cgrates.org",
+		Number: "+49 30 123456"}, &rply); err != nil {
+		t.Error(err)
+	} else if eRply := (NumberLookup{Number: "4930123456"}); rply != eRply {
+		t.Errorf("expecting: %+v, received: %+v", eRply, rply)
+	}
+	if err := nS.V1LookupNumber(&ArgsLookupNumber{Tenant: "cgrates.org",
+		Number: "0171 7654321", DialPlanID: "FR"}, &rply); err == nil {
+		t.Error("expecting error on unknown dial plan")
+	}
+	if err := nS.V1LookupNumber(&ArgsLookupNumber{Tenant: "cgrates.org",
+		Number: "0171 7654321"}, &rply); err == nil {
+		t.Error("expecting error on number which cannot be normalized")
+	}
+}
+
+func TestNumberSRoutingNumberIndex(t *testing.T) {
+	nS := testNumberService(t)
+	if _, err := nS.routingNumber("cgrates.org", "4930123456"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	// the misses are not cached
+	for i := len("4930123456"); i > 0; i-- {
+		if _, has := Cache.Get(utils.CachePortedNumbers,
+			utils.ConcatenatedKey("cgrates.org", "4930123456"[:i])); has {
+			t.Errorf("prefix %s cached", "4930123456"[:i])
+		}
+	}
+	// new prefixes are indexed after being stored
+	if err := nS.dm.SetPortedNumbers(&PortedNumbers{Tenant: "cgrates.org", ID: "4930",
+		Ranges: []*PortedRange{{RoutingNumber: "D264"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if rn, err := nS.routingNumber("cgrates.org", "4930123456"); err != nil {
+		t.Error(err)
+	} else if rn != "D264" {
+		t.Errorf("expecting: D264, received: %s", rn)
+	}
+	if err := nS.dm.RemovePortedNumbers("cgrates.org", "4930",
+		utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nS.routingNumber("cgrates.org", "4930123456"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+// numberSConn calls the NumberService directly from AttributeS
+type numberSConn struct {
+	nS *NumberService
+}
+
+func (nC *numberSConn) Call(serviceMethod string, args, reply interface{}) error {
+	return nC.nS.V1ProcessEvent(args.(*utils.CGREvent), reply.(*NumberSProcessEventReply))
+}
+
+func TestAttributeSProcessEventWithNumberS(t *testing.T) {
+	nS := testNumberService(t)
+	alS, _ := NewAttributeService(nS.dm, nS.filterS, nil, nil, 1, &numberSConn{nS: nS})
+	ev := &AttrArgsProcessEvent{
+		CGREvent: utils.CGREvent{
+			Tenant: "cgrates.org",
+			ID:     "TestAttributeSProcessEventWithNumberS",
+			Event: map[string]interface{}{
+				utils.Account:     "1001",
+				utils.Destination: "0151 12 123456",
+			},
+		},
+	}
+	var rply AttrSProcessEventReply
+	if err := alS.V1ProcessEvent(ev, &rply); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{utils.Destination}, rply.AlteredFields) {
+		t.Errorf("received: %s", utils.ToJSON(rply))
+	} else if rply.CGREvent.Event[utils.Destination] != "D2624915112123456" {
+		t.Errorf("received: %s", utils.ToJSON(rply))
+	}
+}
@@ -64,6 +64,8 @@ var sTestsOnStorIT = []func(t *testing.T){
 	testOnStorITResourceProfile,
 	testOnStorITTiming,
 	testOnStorITCRUDHistory,
+	testOnStorITCRUDLoadVersion,
+	testOnStorITCRUDStagedLoad,
 	testOnStorITCRUDStructVersion,
 	testOnStorITStatQueueProfile,
 	testOnStorITStatQueue,
@@ -1367,6 +1369,63 @@ func testOnStorITCRUDHistory(t *testing.T) {
 	}
 }
 
+func testOnStorITCRUDLoadVersion(t *testing.T) {
+	lv := &LoadVersion{
+		LoadID:   "LOAD_VERSION",
+		LoadTime: time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC),
+		Items: []*LoadVersionItem{
+			{Prefix: utils.RATING_PLAN_PREFIX, ID: "RP_1", Value: []byte("rp")},
+			{Prefix: utils.FilterPrefix, ID: "cgrates.org:FLTR_1"},
+		},
+	}
+	if _, err := onStor.GetLoadVersion(lv.LoadID); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+	if err := onStor.DataDB().SetLoadVersionDrv(lv); err != nil {
+		t.Error(err)
+	}
+	if rcv, err := onStor.GetLoadVersion(lv.LoadID); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(lv.Items, rcv.Items) ||
+		!lv.LoadTime.Equal(rcv.LoadTime) {
+		t.Errorf("Expecting: %v, received: %v", utils.ToJSON(lv), utils.ToJSON(rcv))
+	}
+	if err := onStor.DataDB().RemoveLoadVersionDrv(lv.LoadID); err != nil {
+		t.Error(err)
+	}
+	if _, err := onStor.GetLoadVersion(lv.LoadID); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+}
+
+func testOnStorITCRUDStagedLoad(t *testing.T) {
+	sl := &StagedLoad{
+		LoadID:         "STAGED_LOAD",
+		ActivationTime: time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC),
+		Items: []*LoadVersionItem{
+			{Prefix: utils.RATING_PLAN_PREFIX, ID: "RP_1", Value: []byte("rp")},
+		},
+	}
+	if _, err := onStor.GetStagedLoad(sl.LoadID); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+	if err := onStor.DataDB().SetStagedLoadDrv(sl); err != nil {
+		t.Error(err)
+	}
+	if rcv, err := onStor.GetStagedLoad(sl.LoadID); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(sl.Items, rcv.Items) ||
+		!sl.ActivationTime.Equal(rcv.ActivationTime) {
+		t.Errorf("Expecting: %v, received: %v", utils.ToJSON(sl), utils.ToJSON(rcv))
+	}
+	if err := onStor.DataDB().RemoveStagedLoadDrv(sl.LoadID); err != nil {
+		t.Error(err)
+	}
+	if _, err := onStor.GetStagedLoad(sl.LoadID); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+}
+
 func testOnStorITCRUDStructVersion(t *testing.T) {
 	if _, err := onStor.DataDB().GetVersions(utils.Accounts); err != utils.ErrNotFound {
 		t.Error(err)
@@ -1965,13 +2024,15 @@ func testOnStorITSupplierProfile(t *testing.T) {
 }
 
 func testOnStorITAttributeProfile(t *testing.T) {
-	mapSubstitutes := make(map[string]map[interface{}]*Attribute)
-	mapSubstitutes["FN1"] = make(map[interface{}]*Attribute)
-	mapSubstitutes["FN1"]["Init1"] = &Attribute{
-		FieldName:  "FN1",
-		Initial:    "Init1",
-		Substitute: config.NewRSRParsersMustCompile("Al1", true, utils.INFIELD_SEP),
-		Append:     true,
+	mapSubstitutes := make(map[string]map[interface{}][]*Attribute)
+	mapSubstitutes["FN1"] = make(map[interface{}][]*Attribute)
+	mapSubstitutes["FN1"]["Init1"] = []*Attribute{
+		{
+			FieldName:  "FN1",
+			Initial:    "Init1",
+			Substitute: config.NewRSRParsersMustCompile("Al1", true, utils.INFIELD_SEP),
+			Append:     true,
+		},
 	}
 	attrProfile := &AttributeProfile{
 		Tenant:    "cgrates.org",
@@ -2057,13 +2118,15 @@ func testOnStorITAttributeProfile(t *testing.T) {
 
 func testOnStorITTestAttributeSubstituteIface(t *testing.T) {
 	//set Substitue with type string
-	mapSubstitutes := make(map[string]map[interface{}]*Attribute)
-	mapSubstitutes["FN1"] = make(map[interface{}]*Attribute)
-	mapSubstitutes["FN1"]["Init1"] = &Attribute{
-		FieldName:  "FN1",
-		Initial:    "Init1",
-		Substitute: config.NewRSRParsersMustCompile("Val1", true, utils.INFIELD_SEP),
-		Append:     true,
+	mapSubstitutes := make(map[string]map[interface{}][]*Attribute)
+	mapSubstitutes["FN1"] = make(map[interface{}][]*Attribute)
+	mapSubstitutes["FN1"]["Init1"] = []*Attribute{
+		{
+			FieldName:  "FN1",
+			Initial:    "Init1",
+			Substitute: config.NewRSRParsersMustCompile("Val1", true, utils.INFIELD_SEP),
+			Append:     true,
+		},
 	}
 	attrProfile := &AttributeProfile{
 		Tenant:    "cgrates.org",
@@ -2106,11 +2169,13 @@ func testOnStorITTestAttributeSubstituteIface(t *testing.T) {
 		t.Errorf("Expecting: %v, received: %v", attrProfile, rcv)
 	}
 	//set Substitue with type float
-	mapSubstitutes["FN1"]["Init1"] = &Attribute{
-		FieldName:  "FN1",
-		Initial:    "Init1",
-		Substitute: config.NewRSRParsersMustCompile("123.123", true, utils.INFIELD_SEP),
-		Append:     true,
+	mapSubstitutes["FN1"]["Init1"] = []*Attribute{
+		{
+			FieldName:  "FN1",
+			Initial:    "Init1",
+			Substitute: config.NewRSRParsersMustCompile("123.123", true, utils.INFIELD_SEP),
+			Append:     true,
+		},
 	}
 	attrProfile.Attributes = []*Attribute{
 		{
@@ -2139,11 +2204,13 @@ func testOnStorITTestAttributeSubstituteIface(t *testing.T) {
 		t.Errorf("Expecting: %v, received: %v", utils.ToJSON(attrProfile), utils.ToJSON(rcv))
 	}
 	//set Substitue with type bool
-	mapSubstitutes["FN1"]["Init1"] = &Attribute{
-		FieldName:  "FN1",
-		Initial:    "Init1",
-		Substitute: config.NewRSRParsersMustCompile("true", true, utils.INFIELD_SEP),
-		Append:     true,
+	mapSubstitutes["FN1"]["Init1"] = []*Attribute{
+		{
+			FieldName:  "FN1",
+			Initial:    "Init1",
+			Substitute: config.NewRSRParsersMustCompile("true", true, utils.INFIELD_SEP),
+			Append:     true,
+		},
 	}
 	attrProfile.Attributes = []*Attribute{
 		{
@@ -0,0 +1,208 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// BalanceReservation holds value of the account balances until captured, released or expired
+type BalanceReservation struct {
+	ID          string
+	BalanceType string
+	Amounts     map[string]float64 // reserved value, indexed on balance UUID
+	ExpiryTime  time.Time          // the reservation is released automatically afterwards
+}
+
+// IsExpired checks whether the reservation was released because of its TTL
+func (rsv *BalanceReservation) IsExpired() bool {
+	return !rsv.ExpiryTime.IsZero() && !rsv.ExpiryTime.After(time.Now())
+}
+
+// TotalValue returns the value reserved out of all balances
+func (rsv *BalanceReservation) TotalValue() (total float64) {
+	for _, amount := range rsv.Amounts {
+		total += amount
+	}
+	return utils.Round(total, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+}
+
+// reservedValue returns the value of the balance held by the active reservations
+func (acc *Account) reservedValue(blncUUID string) (rsvd float64) {
+	for _, rsv := range acc.Reservations {
+		if rsv.IsExpired() {
+			continue
+		}
+		rsvd += rsv.Amounts[blncUUID]
+	}
+	return
+}
+
+// cleanExpiredReservations removes the reservations released because of their TTL
+func (acc *Account) cleanExpiredReservations() {
+	for rsvID, rsv := range acc.Reservations {
+		if rsv.IsExpired() {
+			delete(acc.Reservations, rsvID)
+		}
+	}
+}
+
+// reservableBalances returns the balances of blncType in the order they are reserved and captured
+func (acc *Account) reservableBalances(blncType string) (blncs Balances) {
+	blncs = make(Balances, len(acc.BalanceMap[blncType]))
+	copy(blncs, acc.BalanceMap[blncType])
+	sort.SliceStable(blncs, func(i, j int) bool { return blncs[i].Weight > blncs[j].Weight })
+	return
+}
+
+// reserveBalances holds value out of the balances matching fltr, failing if they cannot cover it entirely
+func (acc *Account) reserveBalances(rsvID string, fltr *BalanceFilter, value float64,
+	expiryTime time.Time) (rsv *BalanceReservation, err error) {
+	if value <= 0 {
+		return nil, utils.ErrInvalidValue
+	}
+	acc.cleanExpiredReservations()
+	if _, has := acc.Reservations[rsvID]; has {
+		return nil, utils.ErrExists
+	}
+	rsv = &BalanceReservation{ID: rsvID, BalanceType: fltr.GetType(),
+		Amounts: make(map[string]float64), ExpiryTime: expiryTime}
+	rest := value
+	for _, b := range acc.reservableBalances(rsv.BalanceType) {
+		if rest <= 0 {
+			break
+		}
+		if b.IsExpired() || !b.IsActive() || !b.MatchFilter(fltr, false, false) {
+			continue
+		}
+		avail := b.GetValue() - acc.reservedValue(b.Uuid)
+		if avail <= 0 {
+			continue
+		}
+		rsv.Amounts[b.Uuid] = math.Min(avail, rest)
+		rest = utils.Round(rest-rsv.Amounts[b.Uuid], globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	}
+	if rest > 0 {
+		return nil, utils.ErrInsufficientCredit
+	}
+	if acc.Reservations == nil {
+		acc.Reservations = make(map[string]*BalanceReservation)
+	}
+	acc.Reservations[rsvID] = rsv
+	return
+}
+
+// captureReservation debits the reserved balances with value (all of the reservation when nil) and releases the rest
+func (acc *Account) captureReservation(rsvID string, value *float64) (captured float64, err error) {
+	rsv, has := acc.Reservations[rsvID]
+	if !has || rsv.IsExpired() {
+		return 0, utils.ErrNotFound
+	}
+	rest := rsv.TotalValue()
+	if value != nil {
+		if *value <= 0 {
+			return 0, utils.ErrInvalidValue
+		}
+		if *value > rest {
+			return 0, utils.ErrInsufficientCredit
+		}
+		rest = *value
+	}
+	delete(acc.Reservations, rsvID)
+	for _, b := range acc.reservableBalances(rsv.BalanceType) {
+		if rest <= 0 {
+			break
+		}
+		amount, has := rsv.Amounts[b.Uuid]
+		if !has {
+			continue
+		}
+		amount = math.Min(amount, rest)
+		b.SubstractValue(amount)
+		captured += amount
+		rest = utils.Round(rest-amount, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	}
+	captured = utils.Round(captured, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	return
+}
+
+// ReserveBalance holds value out of the account balances matching fltr for ttl (no expiry when 0)
+func ReserveBalance(acntID, rsvID string, fltr *BalanceFilter, value float64,
+	ttl time.Duration) (rsv *BalanceReservation, err error) {
+	var expiryTime time.Time
+	if ttl > 0 {
+		expiryTime = time.Now().Add(ttl)
+	}
+	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		acc, err := dm.DataDB().GetAccount(acntID)
+		if err != nil {
+			return
+		}
+		if rsv, err = acc.reserveBalances(rsvID, fltr, value, expiryTime); err != nil {
+			return
+		}
+		err = dm.DataDB().SetAccount(acc)
+		return
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACCOUNT_PREFIX+acntID)
+	return
+}
+
+// CaptureReservation debits the account with value out of the reservation, the rest of it being released
+func CaptureReservation(acntID, rsvID string, value *float64) (captured float64, err error) {
+	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		acc, err := dm.DataDB().GetAccount(acntID)
+		if err != nil {
+			return
+		}
+		acc.journalBalances(&BalanceMovement{Reason: utils.MetaCapture, CGRID: rsvID})
+		if captured, err = acc.captureReservation(rsvID, value); err != nil {
+			return
+		}
+		acc.ExecuteActionTriggers(nil)
+		if err = dm.DataDB().SetAccount(acc); err != nil {
+			return
+		}
+		acc.storeBalanceJournal()
+		return
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACCOUNT_PREFIX+acntID)
+	return
+}
+
+// ReleaseReservation gives back the reserved value to the account balances
+func ReleaseReservation(acntID, rsvID string) (err error) {
+	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		acc, err := dm.DataDB().GetAccount(acntID)
+		if err != nil {
+			return
+		}
+		if rsv, has := acc.Reservations[rsvID]; !has || rsv.IsExpired() {
+			return nil, utils.ErrNotFound
+		}
+		delete(acc.Reservations, rsvID)
+		err = dm.DataDB().SetAccount(acc)
+		return
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACCOUNT_PREFIX+acntID)
+	return
+}
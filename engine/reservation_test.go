@@ -0,0 +1,111 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountReserveBalances(t *testing.T) {
+	acc := &Account{
+		ID: "cgrates.org:rsv",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: {
+				&Balance{Uuid: "uuid2", ID: "LOW", Value: 5, Weight: 10},
+				&Balance{Uuid: "uuid1", ID: "HIGH", Value: 10, Weight: 20},
+			},
+		},
+	}
+	fltr := &BalanceFilter{Type: utils.StringPointer(utils.MONETARY)}
+	rsv, err := acc.reserveBalances("RSV1", fltr, 12, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rsv.Amounts) != 2 || rsv.Amounts["uuid1"] != 10 || rsv.Amounts["uuid2"] != 2 {
+		t.Errorf("unexpected reservation: %s", utils.ToJSON(rsv))
+	}
+	if _, err := acc.reserveBalances("RSV2", fltr, 4, time.Time{}); err != utils.ErrInsufficientCredit {
+		t.Errorf("expecting: %v, received: %v", utils.ErrInsufficientCredit, err)
+	}
+	if _, err := acc.reserveBalances("RSV1", fltr, 1, time.Time{}); err != utils.ErrExists {
+		t.Errorf("expecting: %v, received: %v", utils.ErrExists, err)
+	}
+	for _, value := range []float64{0, -1} {
+		if _, err := acc.reserveBalances("RSV3", fltr, value, time.Time{}); err != utils.ErrInvalidValue {
+			t.Errorf("expecting: %v, received: %v", utils.ErrInvalidValue, err)
+		}
+	}
+	if _, has := acc.Reservations["RSV3"]; has {
+		t.Errorf("unexpected reservations: %s", utils.ToJSON(acc.Reservations))
+	}
+	if _, err := acc.reserveBalances("RSV2", fltr, 3, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// everything is reserved, nothing left for debits
+	if blncs := acc.getBalancesForPrefix("1001", "call", utils.MONETARY, ""); len(blncs) != 0 {
+		t.Errorf("unexpected balances: %s", utils.ToJSON(blncs))
+	}
+	for _, bs := range acc.AsAccountSummary().BalanceSummaries {
+		if bs.UUID == "uuid2" && bs.Reserved != 5 ||
+			bs.UUID == "uuid1" && bs.Reserved != 10 {
+			t.Errorf("unexpected balance summary: %s", utils.ToJSON(bs))
+		}
+	}
+	for _, value := range []float64{0, -1} {
+		if captured, err := acc.captureReservation("RSV1", utils.Float64Pointer(value)); err != utils.ErrInvalidValue {
+			t.Errorf("expecting: %v, received: %v, captured: %v", utils.ErrInvalidValue, err, captured)
+		}
+	}
+	if captured, err := acc.captureReservation("RSV1", utils.Float64Pointer(13)); err != utils.ErrInsufficientCredit {
+		t.Errorf("expecting: %v, received: %v, captured: %v", utils.ErrInsufficientCredit, err, captured)
+	}
+	if captured, err := acc.captureReservation("RSV1", utils.Float64Pointer(11)); err != nil {
+		t.Fatal(err)
+	} else if captured != 11 {
+		t.Errorf("expecting: 11, received: %v", captured)
+	}
+	if _, has := acc.Reservations["RSV1"]; has {
+		t.Error("reservation not removed after capture")
+	}
+	if b := acc.BalanceMap[utils.MONETARY].GetBalance("uuid1"); b.Value != 0 {
+		t.Errorf("unexpected balance: %s", utils.ToJSON(b))
+	}
+	if b := acc.BalanceMap[utils.MONETARY].GetBalance("uuid2"); b.Value != 4 {
+		t.Errorf("unexpected balance: %s", utils.ToJSON(b))
+	}
+	if blncs := acc.getBalancesForPrefix("1001", "call", utils.MONETARY, ""); len(blncs) != 1 ||
+		blncs[0].getAvailableValue() != 1 {
+		t.Errorf("unexpected balances: %s", utils.ToJSON(blncs))
+	}
+	// expired reservations are not honored anymore
+	acc.Reservations["RSV2"].ExpiryTime = time.Now().Add(-time.Second)
+	if rsvd := acc.reservedValue("uuid2"); rsvd != 0 {
+		t.Errorf("expecting: 0, received: %v", rsvd)
+	}
+	if _, err := acc.captureReservation("RSV2", nil); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	acc.CleanExpiredStuff()
+	if len(acc.Reservations) != 0 {
+		t.Errorf("unexpected reservations: %s", utils.ToJSON(acc.Reservations))
+	}
+}
@@ -205,6 +205,23 @@ func (rs *Responder) GetMaxSessionTime(arg *CallDescriptor, reply *time.Duration
 	return
 }
 
+// GetMaxPostpaidSessionTime returns the usage allowed to a postpaid account by its credit limit and spending caps,
+// -1 when not limited
+func (rs *Responder) GetMaxPostpaidSessionTime(arg *CallDescriptor, reply *time.Duration) (err error) {
+	if arg.Subject == "" {
+		arg.Subject = arg.Account
+	}
+	if !rs.usageAllowed(arg.TOR, arg.GetDuration()) {
+		return utils.ErrMaxUsageExceeded
+	}
+	r, e := arg.GetMaxPostpaidSessionDuration()
+	if e == utils.ErrAccountNotFound { // nothing limits the debt of unknown accounts
+		r, e = -1, nil
+	}
+	*reply, err = r, e
+	return
+}
+
 func (rs *Responder) Status(arg string, reply *map[string]interface{}) (err error) {
 	if arg != "" { // Introduce  delay in answer, used in some automated tests
 		if delay, err := utils.ParseDurationWithNanosecs(arg); err == nil {
@@ -41,6 +41,7 @@ type SharedGroup struct {
 	Id                string
 	AccountParameters map[string]*SharingParameters
 	MemberIds         utils.StringMap
+	CreditLimit       float64 // how far the default balances sharing the group may go negative, 0 for no limit
 	//members           []*Account // accounts caching
 }
 
@@ -101,6 +102,9 @@ func (sg *SharedGroup) GetBalances(destination, category, balanceType string, ub
 			if nUb == nil || nUb.Disabled {
 				continue
 			}
+			if rsn := ub.journalReason(); rsn != nil {
+				nUb.journalBalances(rsn) // journal the debits of shared balances too
+			}
 		}
 		//sg.members = append(sg.members, nUb)
 		sb := nUb.getBalancesForPrefix(destination, category, balanceType, sg.Id)
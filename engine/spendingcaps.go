@@ -0,0 +1,235 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SpendingCap limits the monetary value an account spends within a daily or monthly period
+type SpendingCap struct {
+	SoftLimit   float64   // ThresholdS is notified once reached, 0 for no limit
+	HardLimit   float64   // no more usage is authorized once reached, 0 for no limit
+	Spent       float64   // monetary value spent since PeriodStart
+	PeriodStart time.Time // the spent value is reset when a new period starts
+	SoftReached bool      // the soft limit notification was sent within this period
+	HardReached bool      // the hard limit notification was sent within this period
+}
+
+// spendingPeriodStart returns the start of the period containing t
+func spendingPeriodStart(period string, t time.Time) (time.Time, error) {
+	switch period {
+	case utils.MetaDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), nil
+	case utils.MetaMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("unsupported spending cap period: %s", period)
+}
+
+// reset starts over the counting when t is within a new period
+func (sc *SpendingCap) reset(period string, t time.Time) {
+	start, err := spendingPeriodStart(period, t)
+	if err != nil || start.Equal(sc.PeriodStart) {
+		return
+	}
+	sc.PeriodStart = start
+	sc.Spent = 0
+	sc.SoftReached, sc.HardReached = false, false
+}
+
+// creditLimit returns how far the default monetary balance may go negative, 0 when not limited
+// the limits of the shared groups of the default balance apply on top of the account one
+func (acc *Account) creditLimit() (limit float64) {
+	limit = acc.CreditLimit
+	for sgID := range acc.GetDefaultMoneyBalance().SharedGroups {
+		sg, err := dm.GetSharedGroup(sgID, false, utils.NonTransactional)
+		if err != nil || sg.CreditLimit <= 0 {
+			continue
+		}
+		if limit <= 0 || sg.CreditLimit < limit {
+			limit = sg.CreditLimit
+		}
+	}
+	return
+}
+
+// spendingAllowance returns the monetary value which can still be spent before reaching a hard limit
+// capped is false when none of the spending caps has a hard limit
+func (acc *Account) spendingAllowance(t time.Time) (allowance float64, capped bool) {
+	for period, sc := range acc.SpendingCaps {
+		sc.reset(period, t)
+		if sc.HardLimit <= 0 {
+			continue
+		}
+		if left := sc.HardLimit - sc.Spent; !capped || left < allowance {
+			allowance = left
+		}
+		capped = true
+	}
+	allowance = math.Max(allowance, 0)
+	return
+}
+
+// monetaryDebits returns the monetary value debited for the call cost, including the shared group members balances
+func (cc *CallCost) monetaryDebits() (value float64) {
+	for _, ts := range cc.Timespans {
+		for _, incr := range ts.Increments {
+			if incr.BalanceInfo != nil && incr.BalanceInfo.Monetary != nil {
+				value += incr.GetCost()
+			}
+		}
+	}
+	return
+}
+
+// addSpending counts the spent value against the spending caps
+// returns the notifications of the limits reached with this spending
+func (acc *Account) addSpending(spent float64, t time.Time) (evs []*utils.CGREvent) {
+	if len(acc.SpendingCaps) == 0 {
+		return
+	}
+	if spent = utils.Round(spent, globalRoundingDecimals, utils.ROUNDING_MIDDLE); spent <= 0 {
+		return
+	}
+	acntTnt := utils.NewTenantID(acc.ID)
+	for period, sc := range acc.SpendingCaps {
+		sc.reset(period, t)
+		sc.Spent = utils.Round(sc.Spent+spent, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+		var lmtType string
+		switch {
+		case sc.HardLimit > 0 && !sc.HardReached && sc.Spent >= sc.HardLimit:
+			sc.HardReached, sc.SoftReached = true, true
+			lmtType = utils.HardLimit
+		case sc.SoftLimit > 0 && !sc.SoftReached && sc.Spent >= sc.SoftLimit:
+			sc.SoftReached = true
+			lmtType = utils.SoftLimit
+		default:
+			continue
+		}
+		evs = append(evs, &utils.CGREvent{
+			Tenant: acntTnt.Tenant,
+			ID:     utils.GenUUID(),
+			Event: map[string]interface{}{
+				utils.EventType:   utils.SpendingCapExceeded,
+				utils.EventSource: utils.AccountService,
+				utils.Account:     acntTnt.ID,
+				utils.Period:      period,
+				utils.Spent:       sc.Spent,
+				utils.SoftLimit:   sc.SoftLimit,
+				utils.HardLimit:   sc.HardLimit,
+				utils.LimitType:   lmtType}})
+	}
+	return
+}
+
+// notifySpendingCaps sends the spending cap notifications to ThresholdS
+// the calls are asynchronous since the threshold actions might need the account locked by the caller
+func notifySpendingCaps(evs []*utils.CGREvent) {
+	if thresholdS == nil {
+		return
+	}
+	for _, ev := range evs {
+		go func(ev *utils.CGREvent) {
+			var tIDs []string
+			if err := thresholdS.Call(utils.ThresholdSv1ProcessEvent,
+				&ArgsProcessEvent{CGREvent: *ev}, &tIDs); err != nil &&
+				err.Error() != utils.ErrNotFound.Error() {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> error: %s processing spending cap event %+v with ThresholdS.",
+						utils.AccountService, err.Error(), ev))
+			}
+		}(ev)
+	}
+}
+
+// setCreditLimitAction sets the credit limit of the account out of the ExtraParameters, 0 removing it
+func setCreditLimitAction(acc *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if acc == nil {
+		return errors.New("nil account")
+	}
+	var limit float64
+	if limit, err = strconv.ParseFloat(a.ExtraParameters, 64); err != nil {
+		return
+	}
+	return acc.SetCreditLimit(limit)
+}
+
+// SetCreditLimit limits how far the default monetary balance may go negative, 0 removing the limit
+func (acc *Account) SetCreditLimit(limit float64) error {
+	if limit < 0 {
+		return fmt.Errorf("negative credit limit: %v", limit)
+	}
+	acc.CreditLimit = limit
+	return nil
+}
+
+// spendingCapParams are the ExtraParameters of the *set_spending_cap action
+type spendingCapParams struct {
+	Period    string
+	SoftLimit float64
+	HardLimit float64
+}
+
+// setSpendingCapAction sets the limits of a spending cap out of the ExtraParameters, removing the cap when both are 0
+// the value spent within the current period is kept
+func setSpendingCapAction(acc *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if acc == nil {
+		return errors.New("nil account")
+	}
+	var params spendingCapParams
+	if err = json.Unmarshal([]byte(a.ExtraParameters), &params); err != nil {
+		return
+	}
+	return acc.setSpendingCap(params.Period, params.SoftLimit, params.HardLimit)
+}
+
+// setSpendingCap sets the limits of the spending cap for period, removing it when both are 0
+func (acc *Account) setSpendingCap(period string, softLimit, hardLimit float64) (err error) {
+	if _, err = spendingPeriodStart(period, time.Now()); err != nil {
+		return
+	}
+	if softLimit < 0 || hardLimit < 0 {
+		return fmt.Errorf("negative spending cap limit for period: %s", period)
+	}
+	if softLimit == 0 && hardLimit == 0 {
+		delete(acc.SpendingCaps, period)
+		return
+	}
+	if acc.SpendingCaps == nil {
+		acc.SpendingCaps = make(map[string]*SpendingCap)
+	}
+	sc, has := acc.SpendingCaps[period]
+	if !has {
+		sc = new(SpendingCap)
+		acc.SpendingCaps[period] = sc
+	}
+	sc.reset(period, time.Now())
+	sc.SoftLimit, sc.HardLimit = softLimit, hardLimit
+	sc.SoftReached = softLimit != 0 && sc.Spent >= softLimit
+	sc.HardReached = hardLimit != 0 && sc.Spent >= hardLimit
+	return
+}
@@ -0,0 +1,230 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSpendingPeriodStart(t *testing.T) {
+	tm := time.Date(2019, 3, 15, 13, 20, 0, 0, time.UTC)
+	if start, err := spendingPeriodStart(utils.MetaDaily, tm); err != nil {
+		t.Error(err)
+	} else if eStart := time.Date(2019, 3, 15, 0, 0, 0, 0, time.UTC); !start.Equal(eStart) {
+		t.Errorf("expecting: %v, received: %v", eStart, start)
+	}
+	if start, err := spendingPeriodStart(utils.MetaMonthly, tm); err != nil {
+		t.Error(err)
+	} else if eStart := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC); !start.Equal(eStart) {
+		t.Errorf("expecting: %v, received: %v", eStart, start)
+	}
+	if _, err := spendingPeriodStart(utils.MetaHourly, tm); err == nil {
+		t.Error("expecting error for unsupported period")
+	}
+}
+
+func TestAccountSpendingCaps(t *testing.T) {
+	tm := time.Date(2019, 3, 15, 13, 20, 0, 0, time.UTC)
+	acc := &Account{
+		ID: "cgrates.org:caps",
+		SpendingCaps: map[string]*SpendingCap{
+			utils.MetaDaily:   {SoftLimit: 5, HardLimit: 10},
+			utils.MetaMonthly: {HardLimit: 100},
+		},
+	}
+	if allowance, capped := acc.spendingAllowance(tm); !capped || allowance != 10 {
+		t.Errorf("unexpected allowance: %v, capped: %v", allowance, capped)
+	}
+	evs := acc.addSpending(6, tm)
+	if len(evs) != 1 || evs[0].Event[utils.EventType] != utils.SpendingCapExceeded ||
+		evs[0].Event[utils.Period] != utils.MetaDaily ||
+		evs[0].Event[utils.LimitType] != utils.SoftLimit ||
+		evs[0].Event[utils.Spent] != 6.0 || evs[0].Event[utils.Account] != "caps" {
+		t.Errorf("unexpected notifications: %s", utils.ToJSON(evs))
+	}
+	if evs := acc.addSpending(2, tm); len(evs) != 0 {
+		t.Errorf("unexpected notifications: %s", utils.ToJSON(evs))
+	}
+	evs = acc.addSpending(3, tm)
+	if len(evs) != 1 || evs[0].Event[utils.LimitType] != utils.HardLimit {
+		t.Errorf("unexpected notifications: %s", utils.ToJSON(evs))
+	}
+	if allowance, capped := acc.spendingAllowance(tm); !capped || allowance != 0 {
+		t.Errorf("unexpected allowance: %v, capped: %v", allowance, capped)
+	}
+	// the daily cap starts over the next day
+	if allowance, capped := acc.spendingAllowance(tm.AddDate(0, 0, 1)); !capped || allowance != 10 {
+		t.Errorf("unexpected allowance: %v, capped: %v", allowance, capped)
+	}
+	if acc.SpendingCaps[utils.MetaDaily].Spent != 0 || acc.SpendingCaps[utils.MetaDaily].SoftReached ||
+		acc.SpendingCaps[utils.MetaMonthly].Spent != 11 {
+		t.Errorf("unexpected spending caps: %s", utils.ToJSON(acc.SpendingCaps))
+	}
+	if evs := acc.addSpending(0, tm); len(evs) != 0 {
+		t.Errorf("unexpected notifications: %s", utils.ToJSON(evs))
+	}
+}
+
+func TestSetSpendingCapAction(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:caps"}
+	if err := setSpendingCapAction(acc, &Action{
+		ExtraParameters: `{"Period":"*monthly","SoftLimit":50,"HardLimit":80}`}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sc, has := acc.SpendingCaps[utils.MetaMonthly]; !has || sc.SoftLimit != 50 || sc.HardLimit != 80 {
+		t.Errorf("unexpected spending caps: %s", utils.ToJSON(acc.SpendingCaps))
+	}
+	if err := setSpendingCapAction(acc, &Action{
+		ExtraParameters: `{"Period":"*weekly","HardLimit":80}`}, nil, nil); err == nil {
+		t.Error("expecting error for unsupported period")
+	}
+	if err := setSpendingCapAction(acc, &Action{
+		ExtraParameters: `{"Period":"*monthly"}`}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.SpendingCaps) != 0 {
+		t.Errorf("unexpected spending caps: %s", utils.ToJSON(acc.SpendingCaps))
+	}
+	if err := setCreditLimitAction(acc, &Action{ExtraParameters: "25.5"}, nil, nil); err != nil {
+		t.Fatal(err)
+	} else if acc.CreditLimit != 25.5 {
+		t.Errorf("unexpected credit limit: %v", acc.CreditLimit)
+	}
+	if err := setCreditLimitAction(acc, &Action{ExtraParameters: "-1"}, nil, nil); err == nil {
+		t.Error("expecting error for negative credit limit")
+	}
+}
+
+func TestAccountCreditLimit(t *testing.T) {
+	if err := dm.SetSharedGroup(&SharedGroup{Id: "SG_CREDIT_LIMIT", CreditLimit: 5},
+		utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	acc := &Account{
+		ID:          "cgrates.org:credit",
+		CreditLimit: 10,
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: {&Balance{Uuid: "uuid1", ID: utils.META_DEFAULT,
+				SharedGroups: utils.NewStringMap("SG_CREDIT_LIMIT")}},
+		},
+	}
+	if limit := acc.creditLimit(); limit != 5 {
+		t.Errorf("expecting: 5, received: %v", limit)
+	}
+	acc.BalanceMap[utils.MONETARY][0].SharedGroups = nil
+	if limit := acc.creditLimit(); limit != 10 {
+		t.Errorf("expecting: 10, received: %v", limit)
+	}
+}
+
+func TestMaxSessionDurationPostpaid(t *testing.T) {
+	cd := &CallDescriptor{
+		TimeStart:   time.Date(2015, 07, 24, 13, 37, 0, 0, time.UTC),
+		TimeEnd:     time.Date(2015, 07, 24, 13, 38, 0, 0, time.UTC),
+		Category:    "call",
+		Tenant:      "cgrates.org",
+		Subject:     "money",
+		Destination: "0723",
+	}
+	acc := &Account{ID: "cgrates.org:postpaid"}
+	if maxDur, err := cd.getMaxSessionDuration(acc, true); err != nil || maxDur != -1 {
+		t.Errorf("unexpected max duration: %v, err: %v", maxDur, err)
+	}
+	acc.SpendingCaps = map[string]*SpendingCap{
+		utils.MetaDaily: {HardLimit: 10, Spent: 10, HardReached: true}}
+	acc.SpendingCaps[utils.MetaDaily].PeriodStart, _ = spendingPeriodStart(utils.MetaDaily, time.Now())
+	if maxDur, err := cd.getMaxSessionDuration(acc, true); err != utils.ErrSpendingCapExceeded || maxDur != 0 {
+		t.Errorf("unexpected max duration: %v, err: %v", maxDur, err)
+	}
+	if acc.SpendingCaps[utils.MetaDaily].Spent != 10 {
+		t.Errorf("original account modified: %s", utils.ToJSON(acc.SpendingCaps))
+	}
+}
+
+func TestDebitCreditLimit(t *testing.T) {
+	cd := &CallDescriptor{
+		TimeStart:   time.Date(2015, 07, 24, 13, 37, 0, 0, time.UTC),
+		TimeEnd:     time.Date(2015, 07, 24, 13, 38, 0, 0, time.UTC),
+		Category:    "call",
+		Tenant:      "cgrates.org",
+		Subject:     "money",
+		Account:     "credit_debit",
+		Destination: "0723",
+	}
+	acc := &Account{
+		ID:          "cgrates.org:credit_debit",
+		CreditLimit: 10,
+		SpendingCaps: map[string]*SpendingCap{
+			utils.MetaDaily: {SoftLimit: 100}},
+	}
+	// 1 connect fee and 1 per second, the debt stops at 10
+	if cc, err := cd.debit(acc, false, true); err != utils.ErrInsufficientCredit || cc != nil {
+		t.Errorf("unexpected cost: %s, err: %v", utils.ToJSON(cc), err)
+	}
+	if val := acc.GetDefaultMoneyBalance().GetValue(); val != -10 {
+		t.Errorf("expecting: -10, received: %v", val)
+	}
+	if spent := acc.SpendingCaps[utils.MetaDaily].Spent; spent != 10 {
+		t.Errorf("expecting: 10, received: %v", spent)
+	}
+	if rcv, err := dm.DataDB().GetAccount(acc.ID); err != nil {
+		t.Error(err)
+	} else if val := rcv.GetDefaultMoneyBalance().GetValue(); val != -10 {
+		t.Errorf("expecting: -10 stored, received: %v", val)
+	}
+}
+
+func TestCallCostMonetaryDebits(t *testing.T) {
+	cc := &CallCost{Timespans: TimeSpans{
+		&TimeSpan{Increments: Increments{
+			&Increment{Cost: 1, CompressFactor: 3, BalanceInfo: &DebitInfo{
+				Monetary: &MonetaryInfo{UUID: "uuid1"}, AccountID: "cgrates.org:member"}},
+			&Increment{Cost: 2, BalanceInfo: &DebitInfo{
+				Unit: &UnitInfo{UUID: "uuid2"}}},
+		}},
+		&TimeSpan{Increments: Increments{
+			&Increment{Cost: 0.5, BalanceInfo: &DebitInfo{
+				Monetary: &MonetaryInfo{UUID: "uuid3"}, AccountID: "cgrates.org:acc"}},
+		}},
+	}}
+	if spent := cc.monetaryDebits(); spent != 3.5 {
+		t.Errorf("expecting: 3.5, received: %v", spent)
+	}
+}
+
+func TestTpReaderSharedGroupCreditLimit(t *testing.T) {
+	if err := dm.SetSharedGroup(&SharedGroup{Id: "SG_KEEP_LIMIT", CreditLimit: 7},
+		utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	tpr := &TpReader{dm: dm}
+	if err := tpr.setSharedGroup(&SharedGroup{Id: "SG_KEEP_LIMIT",
+		AccountParameters: map[string]*SharingParameters{
+			utils.ANY: {Strategy: STRATEGY_LOWEST}}}); err != nil {
+		t.Fatal(err)
+	}
+	if sg, err := dm.GetSharedGroup("SG_KEEP_LIMIT", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if sg.CreditLimit != 7 || sg.AccountParameters[utils.ANY] == nil {
+		t.Errorf("unexpected shared group: %s", utils.ToJSON(sg))
+	}
+}
@@ -0,0 +1,510 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewStagedLoad creates an empty StagedLoad to be activated at actTime
+func NewStagedLoad(loadID string, actTime time.Time) *StagedLoad {
+	return &StagedLoad{LoadID: loadID, ActivationTime: actTime}
+}
+
+// StagedLoad is a tariff plan load kept aside in DataDB until the Scheduler activates it
+type StagedLoad struct {
+	LoadID         string
+	ActivationTime time.Time
+	Items          []*LoadVersionItem // objects to be written on activation
+}
+
+// Add marshals obj as the version of the object to be activated
+func (sl *StagedLoad) Add(dm *DataManager, prefix, id string, obj interface{}) (err error) {
+	itm := &LoadVersionItem{Prefix: prefix, ID: id}
+	if itm.Value, err = dm.DataDB().Marshaler().Marshal(obj); err != nil {
+		return
+	}
+	sl.Items = append(sl.Items, itm)
+	return
+}
+
+// stagedLoadActionsID is the ID of both the Actions and the ActionPlan activating a staged load
+func stagedLoadActionsID(loadID string) string {
+	return utils.ConcatenatedKey(MetaActivateStagedLoad, loadID)
+}
+
+// GetStagedLoad returns the staged load with loadID
+func (dm *DataManager) GetStagedLoad(loadID string) (*StagedLoad, error) {
+	return dm.DataDB().GetStagedLoadDrv(loadID)
+}
+
+// StageLoad stores the staged load together with the ActionPlan activating it
+// the Scheduler needs to be reloaded in order to queue the activation
+func (dm *DataManager) StageLoad(sl *StagedLoad) (err error) {
+	if !sl.ActivationTime.After(time.Now()) {
+		return fmt.Errorf("activation time <%s> is not in the future", sl.ActivationTime)
+	}
+	if err = dm.DataDB().SetStagedLoadDrv(sl); err != nil {
+		return
+	}
+	actsID := stagedLoadActionsID(sl.LoadID)
+	if err = dm.SetActions(actsID, Actions{&Action{Id: actsID,
+		ActionType: MetaActivateStagedLoad, ExtraParameters: sl.LoadID}},
+		utils.NonTransactional); err != nil {
+		return
+	}
+	actTime := sl.ActivationTime.Local() // the Scheduler works with local time
+	ap := &ActionPlan{
+		Id: actsID,
+		ActionTimings: []*ActionTiming{{
+			Uuid: utils.GenUUID(),
+			Timing: &RateInterval{Timing: &RITiming{
+				Years:     utils.Years{actTime.Year()},
+				Months:    utils.Months{actTime.Month()},
+				MonthDays: utils.MonthDays{actTime.Day()},
+				StartTime: actTime.Format("15:04:05"),
+			}},
+			ActionsID: actsID,
+		}},
+	}
+	if err = dm.DataDB().SetActionPlan(actsID, ap, true, utils.NonTransactional); err != nil {
+		return
+	}
+	return dm.CacheDataFromDB(utils.ACTION_PLAN_PREFIX, []string{actsID}, true)
+}
+
+// RemoveStagedLoad removes the staged load together with the ActionPlan activating it
+func (dm *DataManager) RemoveStagedLoad(loadID string) (err error) {
+	if _, err = dm.DataDB().GetStagedLoadDrv(loadID); err != nil {
+		return
+	}
+	actsID := stagedLoadActionsID(loadID)
+	if err = dm.DataDB().RemoveActionPlan(actsID, utils.NonTransactional); err != nil &&
+		err != utils.ErrNotFound {
+		return
+	}
+	if err = dm.RemoveActions(actsID, utils.NonTransactional); err != nil &&
+		err != utils.ErrNotFound {
+		return
+	}
+	return dm.DataDB().RemoveStagedLoadDrv(loadID)
+}
+
+// ActivateStagedLoad writes the staged objects into DataDB and swaps the cached ones in one cache transaction
+// the filter indexes are updated before the swap, any failure rolling back the activation
+// the replaced objects are kept in the load history so the activation can be rolled back with RollbackLoad
+func (dm *DataManager) ActivateStagedLoad(loadID string, loadHistSize int) (err error) {
+	var sl *StagedLoad
+	if sl, err = dm.DataDB().GetStagedLoadDrv(loadID); err != nil {
+		return
+	}
+	objs := make([]interface{}, len(sl.Items))
+	for i, itm := range sl.Items { // decode everything before writing anything
+		if objs[i], err = dm.decodeVersionedItem(itm); err != nil {
+			return
+		}
+	}
+	lv := NewLoadVersion(loadID)
+	for _, itm := range sl.Items {
+		if err = lv.Record(dm, itm.Prefix, itm.ID); err != nil {
+			return
+		}
+	}
+	transID := Cache.BeginTransaction()
+	refresh := make(map[string][]string) // IDs to be reloaded in cache, per prefix
+	for i, itm := range sl.Items {
+		if err = dm.setStagedItem(itm, objs[i], refresh, transID); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		for prfx, ids := range refresh {
+			if err = dm.cacheDataFromDB(prfx, ids, true, transID); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = dm.reindexStagedLoad(sl, lv, objs)
+	}
+	if err != nil {
+		Cache.RollbackTransaction(transID)
+		if errRb := dm.restoreLoadVersion(lv); errRb != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> failed restoring DataDB after activating staged load <%s>, err: %s",
+				utils.DataManager, loadID, errRb.Error()))
+		}
+		return
+	}
+	Cache.CommitTransaction(transID)
+	if loadHistSize != 0 {
+		if err = dm.SetLoadVersion(lv, loadHistSize); err != nil {
+			return
+		}
+	}
+	return dm.RemoveStagedLoad(loadID)
+}
+
+// setStagedItem writes one staged object into DataDB, leaving the caches to the transaction with transID
+// refresh collects the IDs which need to be reloaded in cache
+func (dm *DataManager) setStagedItem(itm *LoadVersionItem, obj interface{},
+	refresh map[string][]string, transID string) (err error) {
+	refresh[itm.Prefix] = append(refresh[itm.Prefix], itm.ID)
+	switch itm.Prefix {
+	case utils.DESTINATION_PREFIX:
+		dst := obj.(*Destination)
+		var oldDst *Destination
+		if oldDst, err = dm.DataDB().GetDestination(itm.ID, true,
+			utils.NonTransactional); err != nil && err != utils.ErrNotFound {
+			return
+		}
+		if err = dm.DataDB().SetDestination(dst, transID); err != nil {
+			return
+		}
+		refresh[utils.REVERSE_DESTINATION_PREFIX] = append(
+			refresh[utils.REVERSE_DESTINATION_PREFIX], dst.Prefixes...)
+		return dm.DataDB().UpdateReverseDestination(oldDst, dst, transID)
+	case utils.TimingsPrefix:
+		return dm.DataDB().SetTimingDrv(obj.(*utils.TPTiming))
+	case utils.RATING_PLAN_PREFIX:
+		return dm.DataDB().SetRatingPlanDrv(obj.(*RatingPlan))
+	case utils.RATING_PROFILE_PREFIX:
+		return dm.DataDB().SetRatingProfileDrv(obj.(*RatingProfile))
+	case utils.ACTION_PREFIX:
+		return dm.DataDB().SetActionsDrv(itm.ID, obj.(Actions))
+	case utils.ACTION_PLAN_PREFIX:
+		ap := obj.(*ActionPlan)
+		var oldAP *ActionPlan
+		if oldAP, err = dm.DataDB().GetActionPlan(itm.ID, true,
+			utils.NonTransactional); err != nil && err != utils.ErrNotFound {
+			return
+		}
+		if oldAP != nil && len(oldAP.AccountIDs) != 0 { // keep the accounts, same as WriteToDatabase
+			if ap.AccountIDs == nil {
+				ap.AccountIDs = make(utils.StringMap)
+			}
+			for acntID := range oldAP.AccountIDs {
+				ap.AccountIDs[acntID] = true
+			}
+		}
+		for _, at := range ap.ActionTimings {
+			if !at.IsASAP() {
+				continue
+			}
+			if err = dm.DataDB().PushTask(&Task{Uuid: utils.GenUUID(),
+				ActionsID: at.ActionsID}); err != nil {
+				return
+			}
+		}
+		return dm.DataDB().SetActionPlan(itm.ID, ap, true, transID)
+	case utils.ACTION_TRIGGER_PREFIX:
+		return dm.DataDB().SetActionTriggersDrv(itm.ID, obj.(ActionTriggers))
+	case utils.SHARED_GROUP_PREFIX:
+		return dm.DataDB().SetSharedGroupDrv(obj.(*SharedGroup))
+	case utils.FilterPrefix:
+		return dm.DataDB().SetFilterDrv(obj.(*Filter))
+	case utils.ResourceProfilesPrefix:
+		rsp := obj.(*ResourceProfile)
+		if err = dm.DataDB().SetResourceProfileDrv(rsp); err != nil {
+			return
+		}
+		if _, err = dm.DataDB().GetResourceDrv(rsp.Tenant, rsp.ID); err != utils.ErrNotFound {
+			return
+		}
+		refresh[utils.ResourcesPrefix] = append(refresh[utils.ResourcesPrefix], rsp.TenantID())
+		return dm.DataDB().SetResourceDrv(&Resource{Tenant: rsp.Tenant, ID: rsp.ID,
+			Usages: make(map[string]*ResourceUsage)})
+	case utils.StatQueueProfilePrefix:
+		sqp := obj.(*StatQueueProfile)
+		if err = dm.DataDB().SetStatQueueProfileDrv(sqp); err != nil {
+			return
+		}
+		if _, err = dm.DataDB().GetStoredStatQueueDrv(sqp.Tenant, sqp.ID); err != utils.ErrNotFound {
+			return
+		}
+		sq := &StatQueue{Tenant: sqp.Tenant, ID: sqp.ID, SQMetrics: make(map[string]StatMetric)}
+		for _, metricWithParam := range sqp.Metrics {
+			if sq.SQMetrics[metricWithParam.MetricID], err = NewStatMetric(metricWithParam.MetricID,
+				sqp.MinItems, metricWithParam.Parameters); err != nil {
+				return
+			}
+		}
+		var ssq *StoredStatQueue
+		if ssq, err = NewStoredStatQueue(sq, dm.DataDB().Marshaler()); err != nil {
+			return
+		}
+		refresh[utils.StatQueuePrefix] = append(refresh[utils.StatQueuePrefix], sqp.TenantID())
+		return dm.DataDB().SetStoredStatQueueDrv(ssq)
+	case utils.ThresholdProfilePrefix:
+		thp := obj.(*ThresholdProfile)
+		if err = dm.DataDB().SetThresholdProfileDrv(thp); err != nil {
+			return
+		}
+		if _, err = dm.DataDB().GetThresholdDrv(thp.Tenant, thp.ID); err != utils.ErrNotFound {
+			return
+		}
+		refresh[utils.ThresholdPrefix] = append(refresh[utils.ThresholdPrefix], thp.TenantID())
+		return dm.DataDB().SetThresholdDrv(&Threshold{Tenant: thp.Tenant, ID: thp.ID})
+	case utils.SupplierProfilePrefix:
+		return dm.DataDB().SetSupplierProfileDrv(obj.(*SupplierProfile))
+	case utils.AttributeProfilePrefix:
+		return dm.DataDB().SetAttributeProfileDrv(obj.(*AttributeProfile))
+	case utils.ChargerProfilePrefix:
+		return dm.DataDB().SetChargerProfileDrv(obj.(*ChargerProfile))
+	case utils.DispatcherProfilePrefix:
+		return dm.DataDB().SetDispatcherProfileDrv(obj.(*DispatcherProfile))
+	case utils.PortedNumbersPrefix:
+		return dm.DataDB().SetPortedNumbersDrv(obj.(*PortedNumbers))
+	}
+	return fmt.Errorf("unsupported staged load prefix <%s>", itm.Prefix)
+}
+
+// filterIndexContexts returns the contexts and the filters a profile is indexed on
+// indexed is false for the objects without filter indexes
+func filterIndexContexts(prf interface{}) (tenant, id string, ctxs, fltrIDs []string, indexed bool) {
+	ctxs = []string{utils.EmptyString}
+	switch p := prf.(type) {
+	case *ResourceProfile:
+		return p.Tenant, p.ID, ctxs, p.FilterIDs, true
+	case *StatQueueProfile:
+		return p.Tenant, p.ID, ctxs, p.FilterIDs, true
+	case *ThresholdProfile:
+		return p.Tenant, p.ID, ctxs, p.FilterIDs, true
+	case *SupplierProfile:
+		return p.Tenant, p.ID, ctxs, p.FilterIDs, true
+	case *ChargerProfile:
+		return p.Tenant, p.ID, ctxs, p.FilterIDs, true
+	case *AttributeProfile:
+		return p.Tenant, p.ID, p.Contexts, p.FilterIDs, true
+	case *DispatcherProfile:
+		return p.Tenant, p.ID, p.Subsystems, p.FilterIDs, true
+	}
+	return
+}
+
+// reindexStagedItem moves the filter indexes of a profile from its old version to the new one
+func (dm *DataManager) reindexStagedItem(prefix string, oldPrf, newPrf interface{}) (err error) {
+	tenant, id, ctxs, fltrIDs, indexed := filterIndexContexts(newPrf)
+	if !indexed {
+		return
+	}
+	if oldPrf != nil {
+		if err = dm.unindexStagedItem(prefix, oldPrf); err != nil {
+			return
+		}
+	}
+	for _, ctx := range ctxs {
+		if err = createAndIndex(prefix, tenant, ctx, id, fltrIDs, dm); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// unindexStagedItem removes the profile out of its filter indexes
+func (dm *DataManager) unindexStagedItem(prefix string, prf interface{}) (err error) {
+	tenant, id, ctxs, fltrIDs, indexed := filterIndexContexts(prf)
+	if !indexed {
+		return
+	}
+	for _, ctx := range ctxs {
+		idxKey := tenant
+		if ctx != utils.EmptyString {
+			idxKey = utils.ConcatenatedKey(tenant, ctx)
+		}
+		if err = NewFilterIndexer(dm, prefix, idxKey).RemoveItemFromIndex(
+			tenant, id, fltrIDs); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// reindexStagedLoad updates the filter indexes of the staged objects, putting back the old ones on error
+func (dm *DataManager) reindexStagedLoad(sl *StagedLoad, lv *LoadVersion, objs []interface{}) (err error) {
+	oldItms := make(map[string]*LoadVersionItem)
+	for _, itm := range lv.Items {
+		oldItms[itm.Prefix+itm.ID] = itm
+	}
+	oldPrfs := make([]interface{}, len(sl.Items))
+	for i, itm := range sl.Items {
+		if oldItm := oldItms[itm.Prefix+itm.ID]; len(oldItm.Value) != 0 {
+			if oldPrfs[i], err = dm.decodeVersionedItem(oldItm); err != nil {
+				return
+			}
+		}
+		if itm.Prefix == utils.FilterPrefix { // index based on the staged filter, not the cached one
+			Cache.Remove(utils.CacheFilters, itm.ID, true, utils.NonTransactional)
+		}
+	}
+	for i, itm := range sl.Items {
+		if err = dm.reindexStagedItem(itm.Prefix, oldPrfs[i], objs[i]); err == nil {
+			continue
+		}
+		for j := i; j >= 0; j-- {
+			errRv := dm.unindexStagedItem(sl.Items[j].Prefix, objs[j])
+			if errRv == nil && oldPrfs[j] != nil {
+				errRv = dm.reindexStagedItem(sl.Items[j].Prefix, nil, oldPrfs[j])
+			}
+			if errRv != nil {
+				utils.Logger.Warning(fmt.Sprintf("<%s> failed restoring the filter indexes of <%s>, err: %s",
+					utils.DataManager, sl.Items[j].Prefix+sl.Items[j].ID, errRv.Error()))
+			}
+		}
+		return
+	}
+	return
+}
+
+// NewStagedLoad builds out of the loaded data the objects WriteToDatabase would write
+// account actions are not accepted since accounts carry state and cannot wait for the activation
+func (tpr *TpReader) NewStagedLoad(loadID string, actTime time.Time) (sl *StagedLoad, err error) {
+	if tpr.dm.dataDB == nil {
+		return nil, errors.New("no database connection")
+	}
+	if len(tpr.accountActions) != 0 {
+		return nil, errors.New("account actions cannot be staged")
+	}
+	sl = NewStagedLoad(loadID, actTime)
+	for tntID, tpFltr := range tpr.filters {
+		var fltr *Filter
+		if fltr, err = APItoFilter(tpFltr, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.FilterPrefix, tntID.TenantID(), fltr); err != nil {
+			return
+		}
+	}
+	for id, dst := range tpr.destinations {
+		if err = sl.Add(tpr.dm, utils.DESTINATION_PREFIX, id, dst); err != nil {
+			return
+		}
+	}
+	for id, tm := range tpr.timings {
+		if err = sl.Add(tpr.dm, utils.TimingsPrefix, id, tm); err != nil {
+			return
+		}
+	}
+	for id, rp := range tpr.ratingPlans {
+		if err = sl.Add(tpr.dm, utils.RATING_PLAN_PREFIX, id, rp); err != nil {
+			return
+		}
+	}
+	for id, rpf := range tpr.ratingProfiles {
+		if err = sl.Add(tpr.dm, utils.RATING_PROFILE_PREFIX, id, rpf); err != nil {
+			return
+		}
+	}
+	for id, as := range tpr.actions {
+		if err = sl.Add(tpr.dm, utils.ACTION_PREFIX, id, Actions(as)); err != nil {
+			return
+		}
+	}
+	for id, ap := range tpr.actionPlans {
+		if err = sl.Add(tpr.dm, utils.ACTION_PLAN_PREFIX, id, ap); err != nil {
+			return
+		}
+	}
+	for id, atrs := range tpr.actionsTriggers {
+		if err = sl.Add(tpr.dm, utils.ACTION_TRIGGER_PREFIX, id, atrs); err != nil {
+			return
+		}
+	}
+	for id, sg := range tpr.sharedGroups {
+		if err = sl.Add(tpr.dm, utils.SHARED_GROUP_PREFIX, id, sg); err != nil {
+			return
+		}
+	}
+	for tntID, tpRsp := range tpr.resProfiles {
+		var rsp *ResourceProfile
+		if rsp, err = APItoResource(tpRsp, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.ResourceProfilesPrefix, tntID.TenantID(), rsp); err != nil {
+			return
+		}
+	}
+	for tntID, tpSQP := range tpr.sqProfiles {
+		var sqp *StatQueueProfile
+		if sqp, err = APItoStats(tpSQP, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.StatQueueProfilePrefix, tntID.TenantID(), sqp); err != nil {
+			return
+		}
+	}
+	for tntID, tpTHP := range tpr.thProfiles {
+		var thp *ThresholdProfile
+		if thp, err = APItoThresholdProfile(tpTHP, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.ThresholdProfilePrefix, tntID.TenantID(), thp); err != nil {
+			return
+		}
+	}
+	for tntID, tpSPP := range tpr.sppProfiles {
+		var spp *SupplierProfile
+		if spp, err = APItoSupplierProfile(tpSPP, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.SupplierProfilePrefix, tntID.TenantID(), spp); err != nil {
+			return
+		}
+	}
+	for tntID, tpAttr := range tpr.attributeProfiles {
+		var attrPrf *AttributeProfile
+		if attrPrf, err = APItoAttributeProfile(tpAttr, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.AttributeProfilePrefix, tntID.TenantID(), attrPrf); err != nil {
+			return
+		}
+	}
+	for tntID, tpCPP := range tpr.chargerProfiles {
+		var cpp *ChargerProfile
+		if cpp, err = APItoChargerProfile(tpCPP, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.ChargerProfilePrefix, tntID.TenantID(), cpp); err != nil {
+			return
+		}
+	}
+	for tntID, tpDPP := range tpr.dispatcherProfiles {
+		var dpp *DispatcherProfile
+		if dpp, err = APItoDispatcherProfile(tpDPP, tpr.timezone); err != nil {
+			return
+		}
+		if err = sl.Add(tpr.dm, utils.DispatcherProfilePrefix, tntID.TenantID(), dpp); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// StageLoad keeps the loaded data aside in DataDB, to be written by the Scheduler at actTime
+func (tpr *TpReader) StageLoad(loadID string, actTime time.Time) (err error) {
+	var sl *StagedLoad
+	if sl, err = tpr.NewStagedLoad(loadID, actTime); err != nil {
+		return
+	}
+	return tpr.dm.StageLoad(sl)
+}
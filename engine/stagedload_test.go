@@ -0,0 +1,176 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestDataManagerActivateStagedLoad(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := NewDataManager(dataDB)
+	tpr := newDiffTestTpReader(dataDB, `
+#Tag,Prefix
+DST_STG_1002,1002
+`)
+	if err := tpr.LoadDestinations(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tpr.WriteToDatabase(false, false, false); err != nil {
+		t.Fatal(err)
+	}
+	tpr = newDiffTestTpReader(dataDB, `
+#Tag,Prefix
+DST_STG_1002,10021
+DST_STG_1003,1003
+`)
+	if err := tpr.LoadDestinations(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tpr.StageLoad("STAGED1", time.Now().Add(-time.Minute)); err == nil {
+		t.Error("expecting error for activation time in the past")
+	}
+	if err := tpr.StageLoad("STAGED1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if sl, err := dm.GetStagedLoad("STAGED1"); err != nil {
+		t.Error(err)
+	} else if len(sl.Items) != 2 {
+		t.Errorf("unexpected staged load: %s", utils.ToJSON(sl))
+	}
+	actsID := stagedLoadActionsID("STAGED1")
+	if _, err := dataDB.GetActionPlan(actsID, true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+	// nothing changes before the activation
+	if dst, err := dataDB.GetDestination("DST_STG_1002", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"1002"}, dst.Prefixes) {
+		t.Errorf("unexpected destination: %s", utils.ToJSON(dst))
+	}
+	if err := dm.ActivateStagedLoad("STAGED1", 10); err != nil {
+		t.Fatal(err)
+	}
+	if dst, err := dm.DataDB().GetDestination("DST_STG_1002", false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"10021"}, dst.Prefixes) {
+		t.Errorf("unexpected destination: %s", utils.ToJSON(dst))
+	}
+	if rcv, err := dataDB.GetReverseDestination("1003", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"DST_STG_1003"}, rcv) {
+		t.Errorf("unexpected reverse destination: %+v", rcv)
+	}
+	if _, err := dm.GetStagedLoad("STAGED1"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dataDB.GetActionPlan(actsID, true, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.RollbackLoad("STAGED1"); err != nil {
+		t.Fatal(err)
+	}
+	if dst, err := dataDB.GetDestination("DST_STG_1002", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"1002"}, dst.Prefixes) {
+		t.Errorf("unexpected destination: %s", utils.ToJSON(dst))
+	}
+	if _, err := dataDB.GetDestination("DST_STG_1003", true,
+		utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestDataManagerRemoveStagedLoad(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := NewDataManager(dataDB)
+	sl := NewStagedLoad("STAGED2", time.Now().Add(time.Hour))
+	if err := sl.Add(dm, utils.DESTINATION_PREFIX, "DST_STG_1004",
+		&Destination{Id: "DST_STG_1004", Prefixes: []string{"1004"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.StageLoad(sl); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.RemoveStagedLoad("STAGED2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.RemoveStagedLoad("STAGED2"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := dm.ActivateStagedLoad("STAGED2", 10); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dataDB.GetActionPlan(stagedLoadActionsID("STAGED2"), true,
+		utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dataDB.GetDestination("DST_STG_1004", true,
+		utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestDataManagerActivateStagedLoadIndexError(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := NewDataManager(dataDB)
+	sl := NewStagedLoad("STAGED3", time.Now().Add(time.Hour))
+	if err := sl.Add(dm, utils.DESTINATION_PREFIX, "DST_STG_1005",
+		&Destination{Id: "DST_STG_1005", Prefixes: []string{"1005"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sl.Add(dm, utils.ResourceProfilesPrefix, "cgrates.org:RES_STG",
+		&ResourceProfile{Tenant: "cgrates.org", ID: "RES_STG",
+			FilterIDs: []string{"FLTR_STG_MISSING"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.StageLoad(sl); err != nil {
+		t.Fatal(err)
+	}
+	// the broken filter reference fails the indexing, nothing is activated
+	if err := dm.ActivateStagedLoad("STAGED3", 10); err == nil {
+		t.Error("expecting error for broken filter reference")
+	}
+	if _, err := dataDB.GetDestination("DST_STG_1005", true,
+		utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dataDB.GetResourceProfileDrv("cgrates.org", "RES_STG"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, ok := Cache.Get(utils.CacheDestinations, "DST_STG_1005"); ok {
+		t.Error("destination cached after the failed activation")
+	}
+	if _, err := dm.GetStagedLoad("STAGED3"); err != nil {
+		t.Error(err)
+	}
+}
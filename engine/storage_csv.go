@@ -602,7 +602,9 @@ func (csvs *CSVStorage) GetTPSuppliers(tpid, tenant, id string) ([]*utils.TPSupp
 }
 
 func (csvs *CSVStorage) GetTPAttributes(tpid, tenant, id string) ([]*utils.TPAttributeProfile, error) {
-	csvReader, fp, err := csvs.readerFunc(csvs.attributeProfilesFn, csvs.sep, getColumnCount(TPAttribute{}))
+	nrFields := getColumnCount(TPAttribute{})
+	// files without the AttributeFilterIDs and Type columns are still accepted
+	csvReader, fp, err := csvs.readerFunc(csvs.attributeProfilesFn, csvs.sep, -1)
 	if err != nil {
 		//log.Print("Could not load AttributeProfile file: ", err)
 		// allow writing of the other values
@@ -613,6 +615,11 @@ func (csvs *CSVStorage) GetTPAttributes(tpid, tenant, id string) ([]*utils.TPAtt
 	}
 	var tpAls TPAttributes
 	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err == nil && len(record) == nrFields-2 {
+			record = append(record, "", "")
+		} else if err == nil && len(record) != nrFields {
+			err = csv.ErrFieldCount
+		}
 		if err != nil {
 			log.Printf("bad line in %s, %s\n", csvs.attributeProfilesFn, err.Error())
 			return nil, err
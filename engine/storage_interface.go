@@ -94,6 +94,18 @@ type DataDB interface {
 	RemoveTimingDrv(string) error
 	GetLoadHistory(int, bool, string) ([]*utils.LoadInstance, error)
 	AddLoadHistory(*utils.LoadInstance, int, string) error
+	GetLoadVersionDrv(string) (*LoadVersion, error)
+	SetLoadVersionDrv(*LoadVersion) error
+	RemoveLoadVersionDrv(string) error
+	GetStagedLoadDrv(string) (*StagedLoad, error)
+	SetStagedLoadDrv(*StagedLoad) error
+	RemoveStagedLoadDrv(string) error
+	GetSubscriptionProductDrv(string, string) (*SubscriptionProduct, error)
+	SetSubscriptionProductDrv(*SubscriptionProduct) error
+	RemoveSubscriptionProductDrv(string, string) error
+	GetPortedNumbersDrv(string, string) (*PortedNumbers, error)
+	SetPortedNumbersDrv(*PortedNumbers) error
+	RemovePortedNumbersDrv(string, string) error
 	GetFilterIndexesDrv(cacheID, itemIDPrefix, filterType string,
 		fldNameVal map[string]string) (indexes map[string]utils.StringMap, err error)
 	SetFilterIndexesDrv(cacheID, itemIDPrefix string,
@@ -143,6 +155,8 @@ type CdrStorage interface {
 	GetSMCosts(cgrid, runid, originHost, originIDPrfx string) ([]*SMCost, error)
 	RemoveSMCost(*SMCost) error
 	GetCDRs(*utils.CDRsFilter, bool) ([]*CDR, int64, error)
+	SetBalanceMovements([]*BalanceMovement) error
+	GetBalanceMovements(*utils.BalanceHistoryFilter) ([]*BalanceMovement, error)
 }
 
 type LoadStorage interface {
@@ -393,6 +393,9 @@ func (ms *MapStorage) UpdateReverseDestination(oldDest, newDest *Destination,
 	var obsoletePrefixes []string
 	var addedPrefixes []string
 	var found bool
+	if oldDest == nil {
+		oldDest = new(Destination) // so we can process prefixes
+	}
 	for _, oldPrefix := range oldDest.Prefixes {
 		found = false
 		for _, newPrefix := range newDest.Prefixes {
@@ -537,17 +540,164 @@ func (ms *MapStorage) RemoveAccount(key string) (err error) {
 	return
 }
 
+// Limit will only retrieve the last n items out of history, newest first
 func (ms *MapStorage) GetLoadHistory(limitItems int,
-	skipCache bool, transactionID string) ([]*utils.LoadInstance, error) {
+	skipCache bool, transactionID string) (loadInsts []*utils.LoadInstance, err error) {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
-	return nil, nil
+	values, ok := ms.dict[utils.LOADINST_KEY]
+	if !ok || limitItems == 0 {
+		return nil, nil
+	}
+	if err = ms.ms.Unmarshal(values, &loadInsts); err != nil {
+		return nil, err
+	}
+	if limitItems != -1 && limitItems < len(loadInsts) {
+		loadInsts = loadInsts[:limitItems]
+	}
+	return
 }
 
-func (ms *MapStorage) AddLoadHistory(*utils.LoadInstance, int, string) error {
+// Adds a single load instance to load history
+func (ms *MapStorage) AddLoadHistory(ldInst *utils.LoadInstance,
+	loadHistSize int, transactionID string) (err error) {
+	if loadHistSize == 0 { // Load history disabled
+		return
+	}
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
-	return nil
+	var loadInsts []*utils.LoadInstance
+	if values, ok := ms.dict[utils.LOADINST_KEY]; ok {
+		if err = ms.ms.Unmarshal(values, &loadInsts); err != nil {
+			return
+		}
+	}
+	loadInsts = append([]*utils.LoadInstance{ldInst}, loadInsts...)
+	if len(loadInsts) > loadHistSize {
+		loadInsts = loadInsts[:loadHistSize]
+	}
+	var result []byte
+	if result, err = ms.ms.Marshal(loadInsts); err != nil {
+		return
+	}
+	ms.dict[utils.LOADINST_KEY] = result
+	return
+}
+
+func (ms *MapStorage) GetLoadVersionDrv(loadID string) (lv *LoadVersion, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.LoadVersionPrefix+loadID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &lv)
+	return
+}
+
+func (ms *MapStorage) SetLoadVersionDrv(lv *LoadVersion) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var result []byte
+	if result, err = ms.ms.Marshal(lv); err != nil {
+		return
+	}
+	ms.dict[utils.LoadVersionPrefix+lv.LoadID] = result
+	return
+}
+
+func (ms *MapStorage) RemoveLoadVersionDrv(loadID string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.LoadVersionPrefix+loadID)
+	return
+}
+
+func (ms *MapStorage) GetStagedLoadDrv(loadID string) (sl *StagedLoad, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.StagedLoadPrefix+loadID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &sl)
+	return
+}
+
+func (ms *MapStorage) SetStagedLoadDrv(sl *StagedLoad) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var result []byte
+	if result, err = ms.ms.Marshal(sl); err != nil {
+		return
+	}
+	ms.dict[utils.StagedLoadPrefix+sl.LoadID] = result
+	return
+}
+
+func (ms *MapStorage) RemoveStagedLoadDrv(loadID string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.StagedLoadPrefix+loadID)
+	return
+}
+
+func (ms *MapStorage) GetSubscriptionProductDrv(tenant, id string) (prdct *SubscriptionProduct, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.SubscriptionProductPrefix+utils.ConcatenatedKey(tenant, id)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &prdct)
+	return
+}
+
+func (ms *MapStorage) SetSubscriptionProductDrv(prdct *SubscriptionProduct) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var result []byte
+	if result, err = ms.ms.Marshal(prdct); err != nil {
+		return
+	}
+	ms.dict[utils.SubscriptionProductPrefix+prdct.TenantID()] = result
+	return
+}
+
+func (ms *MapStorage) RemoveSubscriptionProductDrv(tenant, id string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.SubscriptionProductPrefix+utils.ConcatenatedKey(tenant, id))
+	return
+}
+
+func (ms *MapStorage) GetPortedNumbersDrv(tenant, id string) (pn *PortedNumbers, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.PortedNumbersPrefix+utils.ConcatenatedKey(tenant, id)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &pn)
+	return
+}
+
+func (ms *MapStorage) SetPortedNumbersDrv(pn *PortedNumbers) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var result []byte
+	if result, err = ms.ms.Marshal(pn); err != nil {
+		return
+	}
+	ms.dict[utils.PortedNumbersPrefix+pn.TenantID()] = result
+	return
+}
+
+func (ms *MapStorage) RemovePortedNumbersDrv(tenant, id string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.PortedNumbersPrefix+utils.ConcatenatedKey(tenant, id))
+	return
 }
 
 func (ms *MapStorage) GetActionTriggersDrv(key string) (atrs ActionTriggers, err error) {
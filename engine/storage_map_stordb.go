@@ -167,3 +167,11 @@ func (ms *MapStorage) GetCDRs(filter *utils.CDRsFilter, remove bool) (cdrs []*CD
 func (ms *MapStorage) GetSMCosts(cgrid, runid, originHost, originIDPrfx string) (smCosts []*SMCost, err error) {
 	return nil, utils.ErrNotImplemented
 }
+
+func (ms *MapStorage) SetBalanceMovements(mvs []*BalanceMovement) (err error) {
+	return utils.ErrNotImplemented
+}
+
+func (ms *MapStorage) GetBalanceMovements(fltr *utils.BalanceHistoryFilter) (mvs []*BalanceMovement, err error) {
+	return nil, utils.ErrNotImplemented
+}
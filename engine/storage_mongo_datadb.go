@@ -56,6 +56,8 @@ const (
 	colAcc  = "accounts"
 	colShg  = "shared_groups"
 	colLht  = "load_history"
+	colLdv  = "load_versions"
+	colStg  = "staged_loads"
 	colVer  = "versions"
 	colRsP  = "resource_profiles"
 	colRFI  = "request_filter_indexes"
@@ -71,6 +73,8 @@ const (
 	ColCDRs = "cdrs"
 	colCpp  = "charger_profiles"
 	colDpp  = "dispatcher_profiles"
+	colSbp  = "subscription_products"
+	colPtn  = "ported_numbers"
 )
 
 var (
@@ -242,7 +246,7 @@ func (ms *MongoStorage) EnsureIndexes() (err error) {
 			}
 		}
 		for _, col := range []string{colRsP, colRes, colSqs, colSqp,
-			colTps, colThs, colSpp, colAttr, colFlt, colCpp} {
+			colTps, colThs, colSpp, colAttr, colFlt, colCpp, colSbp, colPtn} {
 			if err = ms.EnusureIndex(col, true, "tenant", "id"); err != nil {
 				return
 			}
@@ -252,6 +256,12 @@ func (ms *MongoStorage) EnsureIndexes() (err error) {
 				return
 			}
 		}
+		if err = ms.EnusureIndex(colLdv, true, "loadid"); err != nil {
+			return
+		}
+		if err = ms.EnusureIndex(colStg, true, "loadid"); err != nil {
+			return
+		}
 	}
 	if ms.storageType == utils.StorDB {
 		for _, col := range []string{utils.TBLTPTimings, utils.TBLTPDestinations,
@@ -294,6 +304,11 @@ func (ms *MongoStorage) EnsureIndexes() (err error) {
 			OriginIDLow); err != nil {
 			return
 		}
+
+		if err = ms.EnusureIndex(utils.BalanceHistoryTBL, false, "tenant",
+			"account", "time"); err != nil {
+			return
+		}
 	}
 	return
 }
@@ -312,6 +327,10 @@ func (ms *MongoStorage) getColNameForPrefix(prefix string) (string, bool) {
 		utils.ACCOUNT_PREFIX:             colAcc,
 		utils.SHARED_GROUP_PREFIX:        colShg,
 		utils.LOADINST_KEY:               colLht,
+		utils.LoadVersionPrefix:          colLdv,
+		utils.StagedLoadPrefix:           colStg,
+		utils.SubscriptionProductPrefix:  colSbp,
+		utils.PortedNumbersPrefix:        colPtn,
 		utils.VERSION_PREFIX:             colVer,
 		utils.TimingsPrefix:              colTmg,
 		utils.ResourcesPrefix:            colRes,
@@ -582,6 +601,8 @@ func (ms *MongoStorage) GetKeysForPrefix(prefix string) (result []string, err er
 			result, err = ms.getField2(sctx, colCpp, utils.ChargerProfilePrefix, subject, tntID)
 		case utils.DispatcherProfilePrefix:
 			result, err = ms.getField2(sctx, colDpp, utils.DispatcherProfilePrefix, subject, tntID)
+		case utils.PortedNumbersPrefix:
+			result, err = ms.getField2(sctx, colPtn, utils.PortedNumbersPrefix, subject, tntID)
 		default:
 			err = fmt.Errorf("unsupported prefix in GetKeysForPrefix: %s", prefix)
 		}
@@ -1058,6 +1079,7 @@ func (ms *MongoStorage) SetAccount(acc *Account) error {
 			ac.UnitCounters = acc.UnitCounters
 			ac.AllowNegative = acc.AllowNegative
 			ac.Disabled = acc.Disabled
+			ac.Reservations = acc.Reservations
 			acc = ac
 		}
 	}
@@ -1181,6 +1203,150 @@ func (ms *MongoStorage) AddLoadHistory(ldInst *utils.LoadInstance,
 	return err
 }
 
+func (ms *MongoStorage) GetLoadVersionDrv(loadID string) (lv *LoadVersion, err error) {
+	lv = new(LoadVersion)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colLdv).FindOne(sctx, bson.M{"loadid": loadID})
+		if err := cur.Decode(lv); err != nil {
+			lv = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetLoadVersionDrv(lv *LoadVersion) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colLdv).UpdateOne(sctx, bson.M{"loadid": lv.LoadID},
+			bson.M{"$set": lv},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveLoadVersionDrv(loadID string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colLdv).DeleteOne(sctx, bson.M{"loadid": loadID})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetStagedLoadDrv(loadID string) (sl *StagedLoad, err error) {
+	sl = new(StagedLoad)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colStg).FindOne(sctx, bson.M{"loadid": loadID})
+		if err := cur.Decode(sl); err != nil {
+			sl = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetStagedLoadDrv(sl *StagedLoad) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colStg).UpdateOne(sctx, bson.M{"loadid": sl.LoadID},
+			bson.M{"$set": sl},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveStagedLoadDrv(loadID string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colStg).DeleteOne(sctx, bson.M{"loadid": loadID})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetSubscriptionProductDrv(tenant, id string) (prdct *SubscriptionProduct, err error) {
+	prdct = new(SubscriptionProduct)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colSbp).FindOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if err := cur.Decode(prdct); err != nil {
+			prdct = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetSubscriptionProductDrv(prdct *SubscriptionProduct) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colSbp).UpdateOne(sctx, bson.M{"tenant": prdct.Tenant, "id": prdct.ID},
+			bson.M{"$set": prdct},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveSubscriptionProductDrv(tenant, id string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colSbp).DeleteOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetPortedNumbersDrv(tenant, id string) (pn *PortedNumbers, err error) {
+	pn = new(PortedNumbers)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colPtn).FindOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if err := cur.Decode(pn); err != nil {
+			pn = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetPortedNumbersDrv(pn *PortedNumbers) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colPtn).UpdateOne(sctx, bson.M{"tenant": pn.Tenant, "id": pn.ID},
+			bson.M{"$set": pn},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemovePortedNumbersDrv(tenant, id string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colPtn).DeleteOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
 func (ms *MongoStorage) GetActionTriggersDrv(key string) (atrs ActionTriggers, err error) {
 	var kv struct {
 		Key   string
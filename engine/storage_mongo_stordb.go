@@ -912,6 +912,81 @@ func (ms *MongoStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix stri
 	return smcs, err
 }
 
+func (ms *MongoStorage) SetBalanceMovements(mvs []*BalanceMovement) error {
+	if len(mvs) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(mvs))
+	for i, mv := range mvs {
+		docs[i] = mv
+	}
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(utils.BalanceHistoryTBL).InsertMany(sctx, docs)
+		return err
+	})
+}
+
+// GetBalanceMovements returns the balance history matching the filter, oldest first
+func (ms *MongoStorage) GetBalanceMovements(fltr *utils.BalanceHistoryFilter) (mvs []*BalanceMovement, err error) {
+	filter := bson.M{}
+	if fltr.Tenant != "" {
+		filter["tenant"] = fltr.Tenant
+	}
+	if fltr.Account != "" {
+		filter["account"] = fltr.Account
+	}
+	if len(fltr.BalanceIDs) != 0 {
+		filter["$or"] = []bson.M{
+			{"balanceid": bson.M{"$in": fltr.BalanceIDs}},
+			{"balanceuuid": bson.M{"$in": fltr.BalanceIDs}},
+		}
+	}
+	if fltr.BalanceType != "" {
+		filter["balancetype"] = fltr.BalanceType
+	}
+	if len(fltr.Reasons) != 0 {
+		filter["reason"] = bson.M{"$in": fltr.Reasons}
+	}
+	if len(fltr.CGRIDs) != 0 {
+		filter[CGRIDLow] = bson.M{"$in": fltr.CGRIDs}
+	}
+	if fltr.TimeStart != nil || fltr.TimeEnd != nil {
+		timeFltr := bson.M{}
+		if fltr.TimeStart != nil {
+			timeFltr["$gte"] = fltr.TimeStart
+		}
+		if fltr.TimeEnd != nil {
+			timeFltr["$lt"] = fltr.TimeEnd
+		}
+		filter["time"] = timeFltr
+	}
+	fop := options.Find().SetSort(bson.M{"time": 1})
+	if fltr.Paginator.Limit != nil {
+		fop = fop.SetLimit(int64(*fltr.Paginator.Limit))
+	}
+	if fltr.Paginator.Offset != nil {
+		fop = fop.SetSkip(int64(*fltr.Paginator.Offset))
+	}
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(utils.BalanceHistoryTBL).Find(sctx, filter, fop)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var mv BalanceMovement
+			if err := cur.Decode(&mv); err != nil {
+				return err
+			}
+			mvs = append(mvs, &mv)
+		}
+		if len(mvs) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
 func (ms *MongoStorage) SetCDR(cdr *CDR, allowUpdate bool) (err error) {
 	if cdr.OrderID == 0 {
 		cdr.OrderID = ms.cnter.Next()
@@ -783,6 +783,104 @@ func (rs *RedisStorage) AddLoadHistory(ldInst *utils.LoadInstance, loadHistSize
 	return err
 }
 
+func (rs *RedisStorage) GetLoadVersionDrv(loadID string) (lv *LoadVersion, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.LoadVersionPrefix+loadID).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &lv)
+	return
+}
+
+func (rs *RedisStorage) SetLoadVersionDrv(lv *LoadVersion) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(lv); err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.LoadVersionPrefix+lv.LoadID, result).Err
+}
+
+func (rs *RedisStorage) RemoveLoadVersionDrv(loadID string) (err error) {
+	return rs.Cmd("DEL", utils.LoadVersionPrefix+loadID).Err
+}
+
+func (rs *RedisStorage) GetStagedLoadDrv(loadID string) (sl *StagedLoad, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.StagedLoadPrefix+loadID).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &sl)
+	return
+}
+
+func (rs *RedisStorage) SetStagedLoadDrv(sl *StagedLoad) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(sl); err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.StagedLoadPrefix+sl.LoadID, result).Err
+}
+
+func (rs *RedisStorage) RemoveStagedLoadDrv(loadID string) (err error) {
+	return rs.Cmd("DEL", utils.StagedLoadPrefix+loadID).Err
+}
+
+func (rs *RedisStorage) GetSubscriptionProductDrv(tenant, id string) (prdct *SubscriptionProduct, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.SubscriptionProductPrefix+
+		utils.ConcatenatedKey(tenant, id)).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &prdct)
+	return
+}
+
+func (rs *RedisStorage) SetSubscriptionProductDrv(prdct *SubscriptionProduct) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(prdct); err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.SubscriptionProductPrefix+prdct.TenantID(), result).Err
+}
+
+func (rs *RedisStorage) RemoveSubscriptionProductDrv(tenant, id string) (err error) {
+	return rs.Cmd("DEL", utils.SubscriptionProductPrefix+utils.ConcatenatedKey(tenant, id)).Err
+}
+
+func (rs *RedisStorage) GetPortedNumbersDrv(tenant, id string) (pn *PortedNumbers, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.PortedNumbersPrefix+
+		utils.ConcatenatedKey(tenant, id)).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &pn)
+	return
+}
+
+func (rs *RedisStorage) SetPortedNumbersDrv(pn *PortedNumbers) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(pn); err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.PortedNumbersPrefix+pn.TenantID(), result).Err
+}
+
+func (rs *RedisStorage) RemovePortedNumbersDrv(tenant, id string) (err error) {
+	return rs.Cmd("DEL", utils.PortedNumbersPrefix+utils.ConcatenatedKey(tenant, id)).Err
+}
+
 func (rs *RedisStorage) GetActionTriggersDrv(key string) (atrs ActionTriggers, err error) {
 	key = utils.ACTION_TRIGGER_PREFIX + key
 	var values []byte
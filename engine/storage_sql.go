@@ -123,10 +123,10 @@ func (self *SQLStorage) IsDBEmpty() (resp bool, err error) {
 func (self *SQLStorage) GetTpIds(colName string) ([]string, error) {
 	var rows *sql.Rows
 	var err error
-	qryStr := fmt.Sprintf(" (SELECT tpid FROM %s)", colName)
+	qryStr := fmt.Sprintf("SELECT tpid FROM %s", colName)
 	if colName == "" {
 		qryStr = fmt.Sprintf(
-			"(SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s)",
+			"SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s",
 			utils.TBLTPTimings,
 			utils.TBLTPDestinations,
 			utils.TBLTPRates,
@@ -787,6 +787,93 @@ func (self *SQLStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix stri
 	return smCosts, nil
 }
 
+func (self *SQLStorage) SetBalanceMovements(mvs []*BalanceMovement) error {
+	tx := self.db.Begin()
+	for _, mv := range mvs {
+		mvSQL := &BalanceMovementSQL{
+			Tenant:       mv.Tenant,
+			Account:      mv.Account,
+			BalanceUuid:  mv.BalanceUUID,
+			BalanceID:    mv.BalanceID,
+			BalanceType:  mv.BalanceType,
+			Reason:       mv.Reason,
+			Cgrid:        mv.CGRID,
+			ActionID:     mv.ActionID,
+			ActionPlanID: mv.ActionPlanID,
+			OldValue:     mv.OldValue,
+			NewValue:     mv.NewValue,
+			MovementTime: mv.Time,
+		}
+		if err := tx.Save(mvSQL).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+// GetBalanceMovements returns the balance history matching the filter, oldest first
+func (self *SQLStorage) GetBalanceMovements(fltr *utils.BalanceHistoryFilter) ([]*BalanceMovement, error) {
+	q := self.db.Table(utils.BalanceHistoryTBL).Select("*")
+	if fltr.Tenant != "" {
+		q = q.Where("tenant = ?", fltr.Tenant)
+	}
+	if fltr.Account != "" {
+		q = q.Where("account = ?", fltr.Account)
+	}
+	if len(fltr.BalanceIDs) != 0 {
+		q = q.Where("balance_id in (?) OR balance_uuid in (?)", fltr.BalanceIDs, fltr.BalanceIDs)
+	}
+	if fltr.BalanceType != "" {
+		q = q.Where("balance_type = ?", fltr.BalanceType)
+	}
+	if len(fltr.Reasons) != 0 {
+		q = q.Where("reason in (?)", fltr.Reasons)
+	}
+	if len(fltr.CGRIDs) != 0 {
+		q = q.Where("cgrid in (?)", fltr.CGRIDs)
+	}
+	if fltr.TimeStart != nil {
+		q = q.Where("movement_time >= ?", fltr.TimeStart)
+	}
+	if fltr.TimeEnd != nil {
+		q = q.Where("movement_time < ?", fltr.TimeEnd)
+	}
+	q = q.Order("movement_time, id")
+	if fltr.Paginator.Limit != nil {
+		q = q.Limit(*fltr.Paginator.Limit)
+	}
+	if fltr.Paginator.Offset != nil {
+		q = q.Offset(*fltr.Paginator.Offset)
+	}
+	results := make([]*BalanceMovementSQL, 0)
+	if err := q.Find(&results).Error; err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	mvs := make([]*BalanceMovement, len(results))
+	for i, result := range results {
+		mvs[i] = &BalanceMovement{
+			Tenant:       result.Tenant,
+			Account:      result.Account,
+			BalanceUUID:  result.BalanceUuid,
+			BalanceID:    result.BalanceID,
+			BalanceType:  result.BalanceType,
+			Reason:       result.Reason,
+			CGRID:        result.Cgrid,
+			ActionID:     result.ActionID,
+			ActionPlanID: result.ActionPlanID,
+			OldValue:     result.OldValue,
+			NewValue:     result.NewValue,
+			Time:         result.MovementTime,
+		}
+	}
+	return mvs, nil
+}
+
 func (self *SQLStorage) LogActionTrigger(ubId, source string, at *ActionTrigger, as Actions) (err error) {
 	return
 }
@@ -77,11 +77,13 @@ func ConfigureStorStorage(db_type, host, port, name, user, pass, marshaler strin
 		d, err = NewPostgresStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MYSQL:
 		d, err = NewMySQLStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.SQLITE:
+		d, err = NewSQLiteStorage(name, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.INTERNAL:
 		d, err = NewMapStorage()
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.INTERNAL))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.INTERNAL))
 	}
 	if err != nil {
 		return nil, err
@@ -97,13 +99,15 @@ func ConfigureLoadStorage(db_type, host, port, name, user, pass, marshaler strin
 		d, err = NewPostgresStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MYSQL:
 		d, err = NewMySQLStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.SQLITE:
+		d, err = NewSQLiteStorage(name, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MONGO:
 		d, err = NewMongoStorage(host, port, name, user, pass, utils.StorDB, cdrsIndexes, nil, false)
 	case utils.INTERNAL:
 		d, err = NewMapStorage()
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.INTERNAL))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.INTERNAL))
 	}
 	if err != nil {
 		return nil, err
@@ -119,13 +123,15 @@ func ConfigureCdrStorage(db_type, host, port, name, user, pass string,
 		d, err = NewPostgresStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MYSQL:
 		d, err = NewMySQLStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.SQLITE:
+		d, err = NewSQLiteStorage(name, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MONGO:
 		d, err = NewMongoStorage(host, port, name, user, pass, utils.StorDB, cdrsIndexes, nil, false)
 	case utils.INTERNAL:
 		d, err = NewMapStorage()
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.INTERNAL))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.INTERNAL))
 	}
 	if err != nil {
 		return nil, err
@@ -141,13 +147,15 @@ func ConfigureStorDB(db_type, host, port, name, user, pass string,
 		d, err = NewPostgresStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MYSQL:
 		d, err = NewMySQLStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.SQLITE:
+		d, err = NewSQLiteStorage(name, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MONGO:
 		d, err = NewMongoStorage(host, port, name, user, pass, utils.StorDB, cdrsIndexes, nil, false)
 	case utils.INTERNAL:
 		d, err = NewMapStorage()
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.INTERNAL))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.INTERNAL))
 	}
 	if err != nil {
 		return nil, err
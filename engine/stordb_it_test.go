@@ -98,6 +98,24 @@ func TestStorDBitPostgresSQL(t *testing.T) {
 	}
 }
 
+func TestStorDBitSQLite(t *testing.T) {
+	if cfg, err = config.NewCGRConfigFromFolder(path.Join(*dataDir, "conf", "samples", "storage", "sqlite")); err != nil {
+		t.Fatal(err)
+	}
+	if storDB, err = NewSQLiteStorage(cfg.StorDbCfg().StorDBName,
+		cfg.StorDbCfg().StorDBMaxOpenConns, cfg.StorDbCfg().StorDBMaxIdleConns,
+		cfg.StorDbCfg().StorDBConnMaxLifetime); err != nil {
+		t.Fatal(err)
+	}
+	storDB2ndDBname = "sqlite"
+	for _, stest := range sTestsStorDBit {
+		stestFullName := runtime.FuncForPC(reflect.ValueOf(stest).Pointer()).Name()
+		split := strings.Split(stestFullName, ".")
+		stestName := split[len(split)-1]
+		t.Run(stestName, stest)
+	}
+}
+
 func TestStorDBitMongo(t *testing.T) {
 	if cfg, err = config.NewCGRConfigFromFolder(path.Join(*dataDir, "conf", "samples", "storage", "mongo")); err != nil {
 		t.Fatal(err)
@@ -130,7 +148,7 @@ func testStorDBitIsDBEmpty(t *testing.T) {
 		} else if test != true {
 			t.Errorf("\nExpecting: true got :%+v", test)
 		}
-	case utils.POSTGRES, utils.MYSQL:
+	case utils.POSTGRES, utils.MYSQL, utils.SQLITE:
 		test, err := storDB.IsDBEmpty()
 		if err != nil {
 			t.Error(err)
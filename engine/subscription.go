@@ -0,0 +1,370 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SubscriptionProduct is a recurring offer the accounts subscribe to, billed monthly
+type SubscriptionProduct struct {
+	Tenant          string
+	ID              string
+	Fee             float64 // monetary value charged for one billing cycle
+	BundleActionsID string  // actions topping up the balances included for one billing cycle
+}
+
+// TenantID returns the concatenated key between tenant and ID
+func (prdct *SubscriptionProduct) TenantID() string {
+	return utils.ConcatenatedKey(prdct.Tenant, prdct.ID)
+}
+
+// Subscription ties an account to a SubscriptionProduct
+type Subscription struct {
+	ProductID string
+	AnchorDay int // day of the month the billing cycles start on
+	StartTime time.Time
+	PaidUntil time.Time // end of the last billing cycle charged
+}
+
+// GetSubscriptionProduct returns the product with tenant and id
+func (dm *DataManager) GetSubscriptionProduct(tenant, id string) (*SubscriptionProduct, error) {
+	return dm.DataDB().GetSubscriptionProductDrv(tenant, id)
+}
+
+// SetSubscriptionProduct stores the product, the changes applying to the subscribers from their next billing cycle
+func (dm *DataManager) SetSubscriptionProduct(prdct *SubscriptionProduct) error {
+	return dm.DataDB().SetSubscriptionProductDrv(prdct)
+}
+
+// RemoveSubscriptionProduct removes the product with tenant and id
+func (dm *DataManager) RemoveSubscriptionProduct(tenant, id string) error {
+	return dm.DataDB().RemoveSubscriptionProductDrv(tenant, id)
+}
+
+// anchorDate returns the anchorDay of the month, the last day of the shorter months
+func anchorDate(year int, month time.Month, anchorDay int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	if lastDay := first.AddDate(0, 1, -1).Day(); anchorDay > lastDay {
+		anchorDay = lastDay
+	}
+	return first.AddDate(0, 0, anchorDay-1)
+}
+
+// billingCycle returns the limits of the monthly billing cycle containing t
+func billingCycle(t time.Time, anchorDay int) (start, end time.Time) {
+	if start = anchorDate(t.Year(), t.Month(), anchorDay, t.Location()); start.After(t) {
+		start = anchorDate(t.Year(), t.Month()-1, anchorDay, t.Location())
+	}
+	end = anchorDate(start.Year(), start.Month()+1, anchorDay, t.Location())
+	return
+}
+
+// cycleFactor returns the part of the billing cycle between t and its end, counted in days
+// the day of t is charged but not credited back
+func cycleFactor(t, start, end time.Time, credit bool) float64 {
+	days := end.Sub(t).Hours() / 24
+	if credit {
+		days = math.Floor(days)
+	} else {
+		days = math.Ceil(days)
+	}
+	return days / math.Round(end.Sub(start).Hours()/24)
+}
+
+// cycleActions returns the actions charging the factor out of the billing cycle ending at cycleEnd
+// negative factors credit back the fee and remove the bundles of the cycle
+func (prdct *SubscriptionProduct) cycleActions(factor float64, cycleEnd time.Time) (acs Actions, err error) {
+	if fee := utils.Round(prdct.Fee*math.Abs(factor), globalRoundingDecimals,
+		utils.ROUNDING_MIDDLE); fee != 0 {
+		actType := DEBIT
+		if factor < 0 {
+			actType = TOPUP
+		}
+		acs = append(acs, &Action{Id: prdct.ID, ActionType: actType,
+			Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+				ID:    utils.StringPointer(utils.META_DEFAULT),
+				Value: &utils.ValueFormula{Static: fee}}})
+	}
+	if prdct.BundleActionsID != "" {
+		var bundles Actions
+		if bundles, err = dm.GetActions(prdct.BundleActionsID, false, utils.NonTransactional); err != nil {
+			return
+		}
+		for _, a := range bundles {
+			if !utils.IsSliceMember([]string{TOPUP, TOPUP_RESET}, a.ActionType) || a.Balance == nil {
+				continue // only the bundles are prorated
+			}
+			bundle := a.Clone()
+			bundle.Balance.ExpirationDate = utils.TimePointer(cycleEnd)
+			if factor < 0 {
+				if bundle.Balance.ID == nil {
+					continue // cannot identify the bundle balance
+				}
+				acs = append(acs, &Action{Id: bundle.Id, ActionType: REMOVE_BALANCE,
+					Balance: &BalanceFilter{Type: bundle.Balance.Type, ID: bundle.Balance.ID,
+						ExpirationDate: bundle.Balance.ExpirationDate}})
+				continue
+			}
+			bundle.Balance.SetValue(utils.Round(bundle.Balance.GetValue()*factor,
+				globalRoundingDecimals, utils.ROUNDING_MIDDLE))
+			acs = append(acs, bundle)
+		}
+	}
+	if len(acs) != 0 && schedCdrsConns != nil {
+		acs = append(acs, &Action{Id: prdct.ID, ActionType: CDRLOG})
+	}
+	return
+}
+
+// postponedCdrLog is a CDRLOG action together with the actions it logs
+type postponedCdrLog struct {
+	a   *Action
+	acs Actions
+}
+
+// executeActions runs acs on the account the way the ActionTiming does, without saving it
+// the CDRLOG actions are postponed until the account is saved, see postCdrLogs
+func (acc *Account) executeActions(acs Actions) (err error) {
+	for _, a := range acs {
+		if a.ActionType == CDRLOG {
+			acc.cdrLogs = append(acc.cdrLogs, &postponedCdrLog{a: a, acs: acs})
+			continue
+		}
+		actionFunction, exists := getActionFunc(a.ActionType)
+		if !exists {
+			return fmt.Errorf("unsupported action type: <%s>", a.ActionType)
+		}
+		acc.journalBalances(&BalanceMovement{Reason: a.ActionType, ActionID: a.Id})
+		if err = actionFunction(acc, a, acs, nil); err != nil {
+			if a.ActionType == REMOVE_BALANCE && err == utils.ErrNotFound {
+				err = nil // bundle removed already
+				continue
+			}
+			return
+		}
+	}
+	return
+}
+
+// postCdrLogs executes the CDRLOG actions postponed by executeActions, to be called once the account was saved
+func (acc *Account) postCdrLogs() {
+	cdrLogs := acc.cdrLogs
+	acc.cdrLogs = nil
+	for _, cl := range cdrLogs {
+		if err := cdrLogAction(acc, cl.a, cl.acs, nil); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s logging the CDRs of action: %s on account: %s",
+					utils.AccountService, err.Error(), cl.a.Id, acc.ID))
+		}
+	}
+}
+
+// subscribe charges the account with the rest of the billing cycle containing sTime
+func (acc *Account) subscribe(prdct *SubscriptionProduct, anchorDay int, sTime time.Time) (err error) {
+	if _, has := acc.Subscriptions[prdct.ID]; has {
+		return utils.ErrExists
+	}
+	acs, end, err := prdct.subscribeActions(anchorDay, sTime)
+	if err != nil {
+		return
+	}
+	return acc.addSubscription(prdct, acs, anchorDay, sTime, end)
+}
+
+// subscribeActions returns the actions charging the rest of the billing cycle containing sTime, together with the cycle end
+func (prdct *SubscriptionProduct) subscribeActions(anchorDay int,
+	sTime time.Time) (acs Actions, end time.Time, err error) {
+	start, end := billingCycle(sTime, anchorDay)
+	acs, err = prdct.cycleActions(cycleFactor(sTime, start, end, false), end)
+	return
+}
+
+// addSubscription executes the subscribe actions and ties the account to the product
+func (acc *Account) addSubscription(prdct *SubscriptionProduct, acs Actions,
+	anchorDay int, sTime, end time.Time) (err error) {
+	if err = acc.executeActions(acs); err != nil {
+		return
+	}
+	if acc.Subscriptions == nil {
+		acc.Subscriptions = make(map[string]*Subscription)
+	}
+	acc.Subscriptions[prdct.ID] = &Subscription{ProductID: prdct.ID,
+		AnchorDay: anchorDay, StartTime: sTime, PaidUntil: end}
+	return
+}
+
+// unsubscribe credits the account with the part of the billing cycle already charged but unused at cTime
+// the day of cTime is credited as well with withCurrentDay, the subscription continuing with another product
+func (acc *Account) unsubscribe(prdct *SubscriptionProduct, cTime time.Time,
+	withCurrentDay bool) (sub *Subscription, err error) {
+	sub, has := acc.Subscriptions[prdct.ID]
+	if !has {
+		return nil, utils.ErrNotFound
+	}
+	if start, end := billingCycle(cTime, sub.AnchorDay); sub.PaidUntil.Equal(end) {
+		var acs Actions
+		if acs, err = prdct.cycleActions(-cycleFactor(cTime, start, end, !withCurrentDay), end); err != nil {
+			return
+		}
+		if err = acc.executeActions(acs); err != nil {
+			return
+		}
+	}
+	delete(acc.Subscriptions, prdct.ID)
+	return
+}
+
+// changeSubscription moves the account from prdct to newPrdct at cTime
+// the new product is checked before running any of the unsubscribe actions
+func (acc *Account) changeSubscription(prdct, newPrdct *SubscriptionProduct,
+	cTime time.Time) (err error) {
+	sub, has := acc.Subscriptions[prdct.ID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	if _, has := acc.Subscriptions[newPrdct.ID]; has && newPrdct.ID != prdct.ID {
+		return utils.ErrExists
+	}
+	anchorDay := sub.AnchorDay
+	acs, end, err := newPrdct.subscribeActions(anchorDay, cTime)
+	if err != nil {
+		return
+	}
+	if _, err = acc.unsubscribe(prdct, cTime, true); err != nil {
+		return
+	}
+	return acc.addSubscription(newPrdct, acs, anchorDay, cTime, end)
+}
+
+// renewSubscriptions charges the billing cycles started since the subscriptions were last paid
+func (acc *Account) renewSubscriptions(now time.Time) (err error) {
+	tnt := utils.NewTenantID(acc.ID).Tenant
+	prdctIDs := make([]string, 0, len(acc.Subscriptions))
+	for prdctID := range acc.Subscriptions {
+		prdctIDs = append(prdctIDs, prdctID)
+	}
+	sort.Strings(prdctIDs)
+	for _, prdctID := range prdctIDs {
+		sub := acc.Subscriptions[prdctID]
+		if sub.PaidUntil.After(now) {
+			continue
+		}
+		var prdct *SubscriptionProduct
+		if prdct, err = dm.GetSubscriptionProduct(tnt, prdctID); err != nil {
+			return
+		}
+		for !sub.PaidUntil.After(now) {
+			_, end := billingCycle(sub.PaidUntil, sub.AnchorDay)
+			var acs Actions
+			if acs, err = prdct.cycleActions(1, end); err != nil {
+				return
+			}
+			if err = acc.executeActions(acs); err != nil {
+				return
+			}
+			sub.PaidUntil = end
+		}
+	}
+	return
+}
+
+// changeSubscriptions runs f on the account, saving it afterwards
+func changeSubscriptions(acntID string, f func(acc *Account) error) (err error) {
+	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		acc, err := dm.DataDB().GetAccount(acntID)
+		if err != nil {
+			return
+		}
+		if err = f(acc); err != nil {
+			return
+		}
+		if err = dm.DataDB().SetAccount(acc); err != nil {
+			return
+		}
+		acc.storeBalanceJournal()
+		acc.postCdrLogs()
+		return
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACCOUNT_PREFIX+acntID)
+	return
+}
+
+// Subscribe ties the account to the product, charging the rest of the billing cycle containing sTime
+// anchorDay defaults to the day of sTime
+func Subscribe(acntID, prdctID string, anchorDay int, sTime time.Time) (err error) {
+	if anchorDay == 0 {
+		anchorDay = sTime.Day()
+	}
+	if anchorDay < 1 || anchorDay > 31 {
+		return fmt.Errorf("invalid anchor day: %d", anchorDay)
+	}
+	prdct, err := dm.GetSubscriptionProduct(utils.NewTenantID(acntID).Tenant, prdctID)
+	if err != nil {
+		return
+	}
+	return changeSubscriptions(acntID, func(acc *Account) error {
+		return acc.subscribe(prdct, anchorDay, sTime)
+	})
+}
+
+// CancelSubscription removes the subscription of the account to the product,
+// crediting back the unused part of the billing cycle containing cTime
+func CancelSubscription(acntID, prdctID string, cTime time.Time) (err error) {
+	prdct, err := dm.GetSubscriptionProduct(utils.NewTenantID(acntID).Tenant, prdctID)
+	if err != nil {
+		return
+	}
+	return changeSubscriptions(acntID, func(acc *Account) (err error) {
+		_, err = acc.unsubscribe(prdct, cTime, false)
+		return
+	})
+}
+
+// ChangeSubscription moves the account from one product to another, keeping the billing anchor day
+// the unused part of the billing cycle is credited for the old product and charged for the new one
+func ChangeSubscription(acntID, prdctID, newPrdctID string, cTime time.Time) (err error) {
+	tnt := utils.NewTenantID(acntID).Tenant
+	prdct, err := dm.GetSubscriptionProduct(tnt, prdctID)
+	if err != nil {
+		return
+	}
+	newPrdct, err := dm.GetSubscriptionProduct(tnt, newPrdctID)
+	if err != nil {
+		return
+	}
+	return changeSubscriptions(acntID, func(acc *Account) error {
+		return acc.changeSubscription(prdct, newPrdct, cTime)
+	})
+}
+
+// renewSubscriptionsAction charges the subscriptions of the account for the billing cycles started meanwhile
+func renewSubscriptionsAction(acc *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if acc == nil {
+		return errors.New("nil account")
+	}
+	return acc.renewSubscriptions(time.Now())
+}
@@ -0,0 +1,188 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSubscriptionBillingCycle(t *testing.T) {
+	for _, tc := range []struct {
+		t          time.Time
+		anchorDay  int
+		start, end time.Time
+	}{
+		{time.Date(2018, 1, 17, 10, 0, 0, 0, time.UTC), 1,
+			time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2018, 2, 17, 0, 0, 0, 0, time.UTC), 31,
+			time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2018, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC), 31,
+			time.Date(2018, 2, 28, 0, 0, 0, 0, time.UTC), time.Date(2018, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2018, 12, 20, 0, 0, 0, 0, time.UTC), 15,
+			time.Date(2018, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC)},
+	} {
+		if start, end := billingCycle(tc.t, tc.anchorDay); !start.Equal(tc.start) || !end.Equal(tc.end) {
+			t.Errorf("cycle of %v on day %d: %v - %v", tc.t, tc.anchorDay, start, end)
+		}
+	}
+	start, end := billingCycle(time.Date(2018, 1, 17, 10, 0, 0, 0, time.UTC), 1)
+	if f := cycleFactor(time.Date(2018, 1, 17, 10, 0, 0, 0, time.UTC), start, end, false); f != 15.0/31 {
+		t.Errorf("unexpected charged factor: %v", f)
+	}
+	if f := cycleFactor(time.Date(2018, 1, 17, 10, 0, 0, 0, time.UTC), start, end, true); f != 14.0/31 {
+		t.Errorf("unexpected credited factor: %v", f)
+	}
+}
+
+func TestSubscriptionProration(t *testing.T) {
+	if err := dm.SetActions("ACT_SUB_BUNDLE", Actions{
+		&Action{Id: "ACT_SUB_BUNDLE", ActionType: TOPUP,
+			Balance: &BalanceFilter{Type: utils.StringPointer(utils.SMS),
+				ID: utils.StringPointer("SUB_SMS"), Value: &utils.ValueFormula{Static: 310}}},
+	}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	basic := &SubscriptionProduct{Tenant: "cgrates.org", ID: "PRD_BASIC",
+		Fee: 31, BundleActionsID: "ACT_SUB_BUNDLE"}
+	premium := &SubscriptionProduct{Tenant: "cgrates.org", ID: "PRD_PREMIUM", Fee: 62}
+	for _, prdct := range []*SubscriptionProduct{basic, premium} {
+		if err := dm.SetSubscriptionProduct(prdct); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if prdct, err := dm.GetSubscriptionProduct("cgrates.org", "PRD_BASIC"); err != nil {
+		t.Error(err)
+	} else if *prdct != *basic {
+		t.Errorf("unexpected product: %s", utils.ToJSON(prdct))
+	}
+	acntID := "cgrates.org:subscriber"
+	if err := dm.DataDB().SetAccount(&Account{ID: acntID,
+		BalanceMap: map[string]Balances{utils.MONETARY: {
+			&Balance{Uuid: "uuid1", ID: utils.META_DEFAULT, Value: 100}}}}); err != nil {
+		t.Fatal(err)
+	}
+	defaultValue := func(acc *Account) float64 {
+		for _, b := range acc.BalanceMap[utils.MONETARY] {
+			if b.ID == utils.META_DEFAULT {
+				return b.GetValue()
+			}
+		}
+		return 0
+	}
+	sTime := time.Date(2018, 1, 17, 10, 0, 0, 0, time.UTC)
+	if err := Subscribe(acntID, "PRD_BASIC", 1, sTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := Subscribe(acntID, "PRD_BASIC", 1, sTime); err != utils.ErrExists {
+		t.Errorf("expecting: %v, received: %v", utils.ErrExists, err)
+	}
+	acc, err := dm.DataDB().GetAccount(acntID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaultValue(acc) != 85 {
+		t.Errorf("unexpected balances after subscribe: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	cycleEnd := time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)
+	if len(acc.BalanceMap[utils.SMS]) != 1 || acc.BalanceMap[utils.SMS][0].GetValue() != 150 ||
+		!acc.BalanceMap[utils.SMS][0].ExpirationDate.Equal(cycleEnd) {
+		t.Errorf("unexpected bundle: %s", utils.ToJSON(acc.BalanceMap[utils.SMS]))
+	}
+	if sub, has := acc.Subscriptions["PRD_BASIC"]; !has || !sub.PaidUntil.Equal(cycleEnd) {
+		t.Errorf("unexpected subscriptions: %s", utils.ToJSON(acc.Subscriptions))
+	}
+	// upgrade, 12 days left: 12 credited out of the basic fee, 24 charged out of the premium one
+	if err := ChangeSubscription(acntID, "PRD_BASIC", "PRD_PREMIUM",
+		time.Date(2018, 1, 20, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if acc, err = dm.DataDB().GetAccount(acntID); err != nil {
+		t.Fatal(err)
+	}
+	if defaultValue(acc) != 73 || len(acc.BalanceMap[utils.SMS]) != 0 {
+		t.Errorf("unexpected balances after change: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	if sub, has := acc.Subscriptions["PRD_PREMIUM"]; !has || sub.AnchorDay != 1 ||
+		len(acc.Subscriptions) != 1 {
+		t.Errorf("unexpected subscriptions: %s", utils.ToJSON(acc.Subscriptions))
+	}
+	// changing to a product already subscribed runs none of the unsubscribe actions
+	dblSub := &Account{ID: "cgrates.org:dblsubscriber",
+		BalanceMap: map[string]Balances{utils.MONETARY: {
+			&Balance{Uuid: "uuid2", ID: utils.META_DEFAULT, Value: 100}}},
+		Subscriptions: map[string]*Subscription{
+			"PRD_BASIC":   {ProductID: "PRD_BASIC", AnchorDay: 1, PaidUntil: cycleEnd},
+			"PRD_PREMIUM": {ProductID: "PRD_PREMIUM", AnchorDay: 1, PaidUntil: cycleEnd},
+		}}
+	if err := dblSub.changeSubscription(basic, premium,
+		time.Date(2018, 1, 20, 12, 0, 0, 0, time.UTC)); err != utils.ErrExists {
+		t.Errorf("expecting: %v, received: %v", utils.ErrExists, err)
+	} else if defaultValue(dblSub) != 100 || len(dblSub.Subscriptions) != 2 {
+		t.Errorf("unexpected account after failed change: %s", utils.ToJSON(dblSub))
+	}
+	// renewal of the February and March cycles
+	if err = acc.renewSubscriptions(time.Date(2018, 3, 2, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if defaultValue(acc) != -51 ||
+		!acc.Subscriptions["PRD_PREMIUM"].PaidUntil.Equal(time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected account after renewal: %s", utils.ToJSON(acc))
+	}
+	// 7 days left out of March
+	if _, err = acc.unsubscribe(premium, time.Date(2018, 3, 25, 0, 0, 0, 0, time.UTC), false); err != nil {
+		t.Fatal(err)
+	}
+	if defaultValue(acc) != -37 || len(acc.Subscriptions) != 0 {
+		t.Errorf("unexpected account after cancel: %s", utils.ToJSON(acc))
+	}
+	if err := CancelSubscription(acntID, "PRD_BASIC", time.Now()); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestSubscriptionPostponedCdrLog(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:cdrlogged",
+		BalanceMap: map[string]Balances{utils.MONETARY: {
+			&Balance{Uuid: "uuid1", ID: utils.META_DEFAULT, Value: 10}}},
+		Subscriptions: map[string]*Subscription{"PRD_BASIC": {ProductID: "PRD_BASIC", AnchorDay: 1}}}
+	acs := Actions{
+		&Action{Id: "PRD_BASIC", ActionType: DEBIT,
+			Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+				Value: &utils.ValueFormula{Static: 5}}},
+		&Action{Id: "PRD_BASIC", ActionType: CDRLOG},
+	}
+	if err := acc.executeActions(acs); err != nil {
+		t.Fatal(err)
+	}
+	if len(acc.cdrLogs) != 1 || acc.cdrLogs[0].a != acs[1] {
+		t.Errorf("expecting the CDRLOG postponed, received: %+v", acc.cdrLogs)
+	}
+	acc.postCdrLogs()
+	if acc.cdrLogs != nil {
+		t.Errorf("expecting the postponed CDRLOGs consumed, received: %+v", acc.cdrLogs)
+	}
+	cln := acc.Clone()
+	cln.Subscriptions["PRD_BASIC"].AnchorDay = 15
+	if acc.Subscriptions["PRD_BASIC"].AnchorDay != 1 {
+		t.Error("the clone shares the subscriptions of the account")
+	}
+}
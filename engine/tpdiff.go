@@ -0,0 +1,385 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// LoadDiffItems groups the IDs of one data category based on the change a load would produce
+type LoadDiffItems struct {
+	Added     []string
+	Modified  []string
+	Removed   []string
+	Unchanged int
+}
+
+// LoadDiff is the preview of a tariff plan load, indexed on DataDB prefix
+type LoadDiff map[string]*LoadDiffItems
+
+// items returns the category items, creating them if not already there
+func (ld LoadDiff) items(categ string) (itms *LoadDiffItems) {
+	var has bool
+	if itms, has = ld[categ]; !has {
+		itms = new(LoadDiffItems)
+		ld[categ] = itms
+	}
+	return
+}
+
+// HasChanges returns true if the load would change at least one object
+func (ld LoadDiff) HasChanges() bool {
+	for _, itms := range ld {
+		if len(itms.Added) != 0 ||
+			len(itms.Modified) != 0 ||
+			len(itms.Removed) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AsText returns the human readable version of the diff, sorted on categories and IDs
+func (ld LoadDiff) AsText() string {
+	categs := make([]string, 0, len(ld))
+	for categ := range ld {
+		categs = append(categs, categ)
+	}
+	sort.Strings(categs)
+	var out []string
+	for _, categ := range categs {
+		itms := ld[categ]
+		out = append(out, fmt.Sprintf("%s added: %d, modified: %d, removed: %d, unchanged: %d",
+			categ, len(itms.Added), len(itms.Modified), len(itms.Removed), itms.Unchanged))
+		for _, chng := range []struct {
+			sign string
+			ids  []string
+		}{
+			{"+", itms.Added},
+			{"~", itms.Modified},
+			{"-", itms.Removed},
+		} {
+			ids := make([]string, len(chng.ids))
+			copy(ids, chng.ids)
+			sort.Strings(ids)
+			for _, id := range ids {
+				out = append(out, fmt.Sprintf("\t%s %s", chng.sign, id))
+			}
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// compare classifies one loaded object against the version stored in DataDB
+// scrub will clear fields which are generated on each load (eg: UUIDs) so they do not count as changes
+func (ld LoadDiff) compare(categ, id string, loaded, stored interface{},
+	errGet error, remove bool, scrub func(interface{})) (err error) {
+	if errGet != nil && errGet != utils.ErrNotFound {
+		return errGet
+	}
+	itms := ld.items(categ)
+	if errGet == utils.ErrNotFound {
+		if !remove {
+			itms.Added = append(itms.Added, id)
+		}
+		return
+	}
+	if remove {
+		itms.Removed = append(itms.Removed, id)
+		return
+	}
+	var nLoaded, nStored interface{}
+	if nLoaded, err = normalizeForDiff(loaded); err != nil {
+		return
+	}
+	if nStored, err = normalizeForDiff(stored); err != nil {
+		return
+	}
+	if scrub != nil {
+		scrub(nLoaded)
+		scrub(nStored)
+	}
+	if utils.ToJSON(nLoaded) != utils.ToJSON(nStored) {
+		itms.Modified = append(itms.Modified, id)
+	} else {
+		itms.Unchanged++
+	}
+	return
+}
+
+// compareState is used for the objects with state (eg: Resources) which are reset by a load
+func (ld LoadDiff) compareState(categ, id string, hasState bool,
+	errGet error, remove bool) (err error) {
+	if errGet != nil && errGet != utils.ErrNotFound {
+		return errGet
+	}
+	itms := ld.items(categ)
+	switch {
+	case errGet == utils.ErrNotFound:
+		if !remove {
+			itms.Added = append(itms.Added, id)
+		}
+	case remove:
+		itms.Removed = append(itms.Removed, id)
+	case hasState:
+		itms.Modified = append(itms.Modified, id)
+	default:
+		itms.Unchanged++
+	}
+	return
+}
+
+// normalizeForDiff returns a copy of v (pointer) built out of a marshaling round trip
+// so both sides of a comparison share the same representation of times and empty containers
+func normalizeForDiff(v interface{}) (nV interface{}, err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return nil, errors.New("pointer expected")
+	}
+	if rv.IsNil() {
+		return v, nil
+	}
+	ms := NewCodecMsgpackMarshaler()
+	var b []byte
+	if b, err = ms.Marshal(v); err != nil {
+		return
+	}
+	nV = reflect.New(rv.Type().Elem()).Interface()
+	err = ms.Unmarshal(b, nV)
+	return
+}
+
+func scrubActionPlanForDiff(v interface{}) {
+	if ap, canCast := v.(*ActionPlan); canCast && ap != nil {
+		for _, at := range ap.ActionTimings {
+			at.Uuid = ""
+		}
+	}
+}
+
+func scrubActionTriggersForDiff(v interface{}) {
+	if atrs, canCast := v.(*ActionTriggers); canCast && atrs != nil {
+		for _, at := range *atrs {
+			at.UniqueID = ""
+		}
+	}
+}
+
+func scrubAccountForDiff(v interface{}) {
+	if acc, canCast := v.(*Account); canCast && acc != nil {
+		for _, at := range acc.ActionTriggers {
+			at.UniqueID = ""
+		}
+	}
+}
+
+// DiffWithDatabase compares the loaded data with the one in DataDB without writing anything
+// with remove the report will list the objects which RemoveFromDatabase would delete
+func (tpr *TpReader) DiffWithDatabase(remove bool) (ld LoadDiff, err error) {
+	if tpr.dm.dataDB == nil {
+		return nil, errors.New("no database connection")
+	}
+	ld = make(LoadDiff)
+	for id, d := range tpr.destinations {
+		stored, errGet := tpr.dm.DataDB().GetDestination(id, true, utils.NonTransactional)
+		if err = ld.compare(utils.DESTINATION_PREFIX, id, d, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for id, rp := range tpr.ratingPlans {
+		stored, errGet := tpr.dm.GetRatingPlan(id, true, utils.NonTransactional)
+		if err = ld.compare(utils.RATING_PLAN_PREFIX, id, rp, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for id, rpf := range tpr.ratingProfiles {
+		stored, errGet := tpr.dm.GetRatingProfile(id, true, utils.NonTransactional)
+		if err = ld.compare(utils.RATING_PROFILE_PREFIX, id, rpf, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for id, ap := range tpr.actionPlans {
+		stored, errGet := tpr.dm.DataDB().GetActionPlan(id, true, utils.NonTransactional)
+		loaded := ap
+		if errGet == nil && stored != nil { // account ids are merged on write
+			loaded = &ActionPlan{Id: ap.Id, ActionTimings: ap.ActionTimings,
+				AccountIDs: make(utils.StringMap)}
+			for _, acntIDs := range []utils.StringMap{ap.AccountIDs, stored.AccountIDs} {
+				for acntID := range acntIDs {
+					loaded.AccountIDs[acntID] = true
+				}
+			}
+		}
+		if err = ld.compare(utils.ACTION_PLAN_PREFIX, id, loaded, stored, errGet,
+			remove, scrubActionPlanForDiff); err != nil {
+			return
+		}
+	}
+	for id, atrs := range tpr.actionsTriggers {
+		stored, errGet := tpr.dm.GetActionTriggers(id, true, utils.NonTransactional)
+		if err = ld.compare(utils.ACTION_TRIGGER_PREFIX, id, &atrs, &stored, errGet,
+			remove, scrubActionTriggersForDiff); err != nil {
+			return
+		}
+	}
+	for id, sg := range tpr.sharedGroups {
+		stored, errGet := tpr.dm.GetSharedGroup(id, true, utils.NonTransactional)
+		if err = ld.compare(utils.SHARED_GROUP_PREFIX, id, sg, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for id, as := range tpr.actions {
+		loaded := Actions(as)
+		stored, errGet := tpr.dm.GetActions(id, true, utils.NonTransactional)
+		if err = ld.compare(utils.ACTION_PREFIX, id, &loaded, &stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for id, acc := range tpr.accountActions {
+		stored, errGet := tpr.dm.DataDB().GetAccount(id)
+		loaded := acc
+		if errGet == nil && stored != nil &&
+			len(acc.BalanceMap) == 0 && !stored.allBalancesExpired() { // balances are kept on write
+			wouldBe := *stored
+			wouldBe.ActionTriggers = acc.ActionTriggers
+			wouldBe.UnitCounters = acc.UnitCounters
+			wouldBe.AllowNegative = acc.AllowNegative
+			wouldBe.Disabled = acc.Disabled
+			loaded = &wouldBe
+		}
+		if err = ld.compare(utils.ACCOUNT_PREFIX, id, loaded, stored, errGet,
+			remove, scrubAccountForDiff); err != nil {
+			return
+		}
+	}
+	for tntID, tpFltr := range tpr.filters {
+		var fltr *Filter
+		if fltr, err = APItoFilter(tpFltr, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetFilter(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.FilterPrefix, tntID.TenantID(), fltr, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for tntID, tpRsp := range tpr.resProfiles {
+		var rsp *ResourceProfile
+		if rsp, err = APItoResource(tpRsp, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetResourceProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.ResourceProfilesPrefix, tntID.TenantID(), rsp, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for _, tntID := range tpr.resources {
+		stored, errGet := tpr.dm.GetResource(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compareState(utils.ResourcesPrefix, tntID.TenantID(),
+			errGet == nil && len(stored.Usages) != 0, errGet, remove); err != nil {
+			return
+		}
+	}
+	for tntID, tpSQP := range tpr.sqProfiles {
+		var sqp *StatQueueProfile
+		if sqp, err = APItoStats(tpSQP, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetStatQueueProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.StatQueueProfilePrefix, tntID.TenantID(), sqp, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for _, tntID := range tpr.statQueues {
+		stored, errGet := tpr.dm.GetStatQueue(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compareState(utils.StatQueuePrefix, tntID.TenantID(),
+			errGet == nil && len(stored.SQItems) != 0, errGet, remove); err != nil {
+			return
+		}
+	}
+	for tntID, tpTHP := range tpr.thProfiles {
+		var thp *ThresholdProfile
+		if thp, err = APItoThresholdProfile(tpTHP, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetThresholdProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.ThresholdProfilePrefix, tntID.TenantID(), thp, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for _, tntID := range tpr.thresholds {
+		stored, errGet := tpr.dm.GetThreshold(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compareState(utils.ThresholdPrefix, tntID.TenantID(),
+			errGet == nil && (stored.Hits != 0 || !stored.Snooze.IsZero()), errGet, remove); err != nil {
+			return
+		}
+	}
+	for tntID, tpSPP := range tpr.sppProfiles {
+		var spp *SupplierProfile
+		if spp, err = APItoSupplierProfile(tpSPP, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetSupplierProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.SupplierProfilePrefix, tntID.TenantID(), spp, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for tntID, tpAttr := range tpr.attributeProfiles {
+		var attrPrf *AttributeProfile
+		if attrPrf, err = APItoAttributeProfile(tpAttr, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetAttributeProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.AttributeProfilePrefix, tntID.TenantID(), attrPrf, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for tntID, tpCPP := range tpr.chargerProfiles {
+		var cpp *ChargerProfile
+		if cpp, err = APItoChargerProfile(tpCPP, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetChargerProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.ChargerProfilePrefix, tntID.TenantID(), cpp, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for tntID, tpDPP := range tpr.dispatcherProfiles {
+		var dpp *DispatcherProfile
+		if dpp, err = APItoDispatcherProfile(tpDPP, tpr.timezone); err != nil {
+			return
+		}
+		stored, errGet := tpr.dm.GetDispatcherProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional)
+		if err = ld.compare(utils.DispatcherProfilePrefix, tntID.TenantID(), dpp, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	for id, tm := range tpr.timings {
+		stored, errGet := tpr.dm.GetTiming(id, true, utils.NonTransactional)
+		if err = ld.compare(utils.TimingsPrefix, id, tm, stored, errGet, remove, nil); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,115 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func newDiffTestTpReader(dataDB DataDB, dsts string) *TpReader {
+	return NewTpReader(dataDB, NewStringCSVStorage(',', dsts,
+		"", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", ""), "", "")
+}
+
+func TestTpReaderDiffWithDatabase(t *testing.T) {
+	dataDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpr := newDiffTestTpReader(dataDB, `
+#Tag,Prefix
+DST_1002,1002
+DST_1003,1003
+`)
+	if err := tpr.LoadDestinations(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tpr.WriteToDatabase(false, false, false); err != nil {
+		t.Fatal(err)
+	}
+	tpr = newDiffTestTpReader(dataDB, `
+#Tag,Prefix
+DST_1002,1002
+DST_1003,10031
+DST_1004,1004
+`)
+	if err := tpr.LoadDestinations(); err != nil {
+		t.Fatal(err)
+	}
+	ld, err := tpr.DiffWithDatabase(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eDsts := &LoadDiffItems{
+		Added:     []string{"DST_1004"},
+		Modified:  []string{"DST_1003"},
+		Unchanged: 1,
+	}
+	if !reflect.DeepEqual(eDsts, ld[utils.DESTINATION_PREFIX]) {
+		t.Errorf("expecting: %s, received: %s",
+			utils.ToJSON(eDsts), utils.ToJSON(ld[utils.DESTINATION_PREFIX]))
+	}
+	if ld[utils.TimingsPrefix] == nil ||
+		ld[utils.TimingsPrefix].Unchanged != len(tpr.timings) {
+		t.Errorf("unexpected timings diff: %s", utils.ToJSON(ld[utils.TimingsPrefix]))
+	}
+	if !ld.HasChanges() {
+		t.Error("expecting changes")
+	}
+	if ld, err = tpr.DiffWithDatabase(true); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(ld[utils.DESTINATION_PREFIX].Removed)
+	eDsts = &LoadDiffItems{
+		Removed: []string{"DST_1002", "DST_1003"},
+	}
+	if !reflect.DeepEqual(eDsts, ld[utils.DESTINATION_PREFIX]) {
+		t.Errorf("expecting: %s, received: %s",
+			utils.ToJSON(eDsts), utils.ToJSON(ld[utils.DESTINATION_PREFIX]))
+	}
+}
+
+func TestLoadDiffAsText(t *testing.T) {
+	ld := LoadDiff{
+		utils.RATING_PLAN_PREFIX: &LoadDiffItems{
+			Added:     []string{"RP_2", "RP_1"},
+			Removed:   []string{"RP_3"},
+			Unchanged: 2,
+		},
+		utils.DESTINATION_PREFIX: &LoadDiffItems{
+			Modified: []string{"DST_1"},
+		},
+	}
+	eOut := "dst_ added: 0, modified: 1, removed: 0, unchanged: 0\n" +
+		"\t~ DST_1\n" +
+		"rpl_ added: 2, modified: 0, removed: 1, unchanged: 2\n" +
+		"\t+ RP_1\n" +
+		"\t+ RP_2\n" +
+		"\t- RP_3"
+	if out := ld.AsText(); out != eOut {
+		t.Errorf("expecting: %q, received: %q", eOut, out)
+	}
+	if ld := (LoadDiff{utils.DESTINATION_PREFIX: &LoadDiffItems{Unchanged: 1}}); ld.HasChanges() {
+		t.Error("not expecting changes")
+	}
+}
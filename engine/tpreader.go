@@ -463,7 +463,7 @@ func (tpr *TpReader) LoadSharedGroupsFiltered(tag string, save bool) (err error)
 	}
 	if save {
 		for _, sg := range tpr.sharedGroups {
-			if err := tpr.dm.SetSharedGroup(sg, utils.NonTransactional); err != nil {
+			if err := tpr.setSharedGroup(sg); err != nil {
 				return err
 			}
 		}
@@ -471,6 +471,16 @@ func (tpr *TpReader) LoadSharedGroupsFiltered(tag string, save bool) (err error)
 	return nil
 }
 
+// setSharedGroup stores the shared group keeping the CreditLimit, which is set over the API only
+func (tpr *TpReader) setSharedGroup(sg *SharedGroup) error {
+	if oldSg, err := tpr.dm.GetSharedGroup(sg.Id, true, utils.NonTransactional); err == nil {
+		sg.CreditLimit = oldSg.CreditLimit
+	} else if err != utils.ErrNotFound {
+		return err
+	}
+	return tpr.dm.SetSharedGroup(sg, utils.NonTransactional)
+}
+
 func (tpr *TpReader) LoadSharedGroups() error {
 	return tpr.LoadSharedGroupsFiltered(tpr.tpid, false)
 }
@@ -1478,7 +1488,7 @@ func (tpr *TpReader) WriteToDatabase(flush, verbose, disable_reverse bool) (err
 		log.Print("Shared Groups:")
 	}
 	for k, sg := range tpr.sharedGroups {
-		err = tpr.dm.SetSharedGroup(sg, utils.NonTransactional)
+		err = tpr.setSharedGroup(sg)
 		if err != nil {
 			return err
 		}
@@ -1920,6 +1930,14 @@ func (tpr *TpReader) GetLoadedIds(categ string) ([]string, error) {
 			i++
 		}
 		return keys, nil
+	case utils.TimingsPrefix:
+		keys := make([]string, len(tpr.timings))
+		i := 0
+		for k := range tpr.timings {
+			keys[i] = k
+			i++
+		}
+		return keys, nil
 	}
 	return nil, errors.New("Unsupported load category")
 }
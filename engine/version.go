@@ -37,6 +37,7 @@ var (
 	storDBVers = map[string]string{
 		utils.CostDetails:   "cgr-migrator -migrate=*cost_details",
 		utils.SessionSCosts: "cgr-migrator -migrate=*sessions_costs",
+		utils.TpAttributes:  "cgr-migrator -migrate=*tp_attributes",
 	}
 	allVers map[string]string // init will fill this with a merge of data+stor
 )
@@ -121,7 +122,7 @@ func (vers Versions) Compare(curent Versions, storType string, isDataDB bool) st
 		}
 	case utils.MAPSTOR:
 		message = allVers
-	case utils.POSTGRES, utils.MYSQL:
+	case utils.POSTGRES, utils.MYSQL, utils.SQLITE:
 		message = storDBVers
 	case utils.REDIS:
 		message = dataDBVers
@@ -184,6 +185,7 @@ func CurrentStorDBVersions() Versions {
 		utils.TpRatingProfile:    1,
 		utils.TpChargers:         1,
 		utils.TpDispatchers:      1,
+		utils.TpAttributes:       2,
 	}
 }
 
@@ -209,7 +211,7 @@ func CurrentDBVersions(storType string, isDataDB bool) Versions {
 		return CurrentStorDBVersions()
 	case utils.MAPSTOR:
 		return CurrentAllDBVersions()
-	case utils.POSTGRES, utils.MYSQL:
+	case utils.POSTGRES, utils.MYSQL, utils.SQLITE:
 		return CurrentStorDBVersions()
 	case utils.REDIS:
 		return CurrentDataDBVersions()
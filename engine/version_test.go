@@ -69,5 +69,9 @@ func TestVersionCompare(t *testing.T) {
 	if message6 != "cgr-migrator -migrate=*sessions_costs" {
 		t.Errorf("Error failed to compare to curent version expected: %s received: %s", "cgr-migrator -migrate=*sessions_costs", message6)
 	}
+	message7 := a.Compare(b, utils.SQLITE, false)
+	if message7 != "cgr-migrator -migrate=*sessions_costs" {
+		t.Errorf("Error failed to compare to curent version expected: %s received: %s", "cgr-migrator -migrate=*sessions_costs", message7)
+	}
 
 }
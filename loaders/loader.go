@@ -26,9 +26,11 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/servmanager"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -39,11 +41,13 @@ type openedCSVFile struct {
 }
 
 func NewLoader(dm *engine.DataManager, cfg *config.LoaderSCfg,
-	timezone string, filterS *engine.FilterS) (ldr *Loader) {
+	timezone string, filterS *engine.FilterS,
+	srvMngr *servmanager.ServiceManager) (ldr *Loader) {
 	ldr = &Loader{
 		enabled:       cfg.Enabled,
 		tenant:        cfg.Tenant,
 		dryRun:        cfg.DryRun,
+		actTime:       cfg.ActivationTime,
 		ldrID:         cfg.Id,
 		tpInDir:       cfg.TpInDir,
 		tpOutDir:      cfg.TpOutDir,
@@ -55,6 +59,7 @@ func NewLoader(dm *engine.DataManager, cfg *config.LoaderSCfg,
 		dm:            dm,
 		timezone:      timezone,
 		filterS:       filterS,
+		srvMngr:       srvMngr,
 	}
 	for _, ldrData := range cfg.Data {
 		ldr.dataTpls[ldrData.Type] = ldrData.Fields
@@ -78,6 +83,7 @@ type Loader struct {
 	enabled       bool
 	tenant        config.RSRParsers
 	dryRun        bool
+	actTime       string // stage the load and switch it live at this time
 	ldrID         string
 	tpInDir       string
 	tpOutDir      string
@@ -91,6 +97,9 @@ type Loader struct {
 	dm            *engine.DataManager
 	timezone      string
 	filterS       *engine.FilterS
+	lv            *engine.LoadVersion         // versions of the objects replaced by the current load
+	sl            *engine.StagedLoad          // objects kept aside when the load is staged
+	srvMngr       *servmanager.ServiceManager // reload the scheduler after staging a load
 }
 
 func (ldr *Loader) ListenAndServe(exitChan chan struct{}) (err error) {
@@ -106,6 +115,19 @@ func (ldr *Loader) ProcessFolder() (err error) {
 		return
 	}
 	defer ldr.unlockFolder()
+	if !ldr.dryRun {
+		if ldr.actTime == utils.EmptyString {
+			ldr.lv = engine.NewLoadVersion(utils.GenUUID())
+			defer func() { ldr.lv = nil }()
+		} else {
+			var actTime time.Time
+			if actTime, err = utils.ParseTimeDetectLayout(ldr.actTime, ldr.timezone); err != nil {
+				return
+			}
+			ldr.sl = engine.NewStagedLoad(utils.GenUUID(), actTime)
+			defer func() { ldr.sl = nil }()
+		}
+	}
 	for ldrType := range ldr.rdrs {
 		if err = ldr.processFiles(ldrType); err != nil {
 			utils.Logger.Warning(fmt.Sprintf("<%s-%s> loaderType: <%s> cannot open files, err: %s",
@@ -113,9 +135,43 @@ func (ldr *Loader) ProcessFolder() (err error) {
 			continue
 		}
 	}
+	if ldr.lv != nil && len(ldr.lv.Items) != 0 {
+		if err = ldr.dm.SetLoadVersion(ldr.lv,
+			config.CgrConfig().GeneralCfg().LoadHistorySize); err != nil {
+			return
+		}
+	}
+	if ldr.sl != nil && len(ldr.sl.Items) != 0 {
+		if err = ldr.dm.StageLoad(ldr.sl); err != nil {
+			return
+		}
+		utils.Logger.Info(fmt.Sprintf("<%s-%s> staged load <%s> to be activated at: %s",
+			utils.LoaderS, ldr.ldrID, ldr.sl.LoadID, ldr.sl.ActivationTime))
+		if ldr.srvMngr != nil {
+			if sched := ldr.srvMngr.GetScheduler(); sched != nil {
+				sched.Reload()
+			}
+		}
+	}
 	return ldr.moveFiles()
 }
 
+// recordVersion keeps the stored version of the object before the load overwrites it
+func (ldr *Loader) recordVersion(prefix, tntID string) (err error) {
+	if ldr.lv == nil {
+		return
+	}
+	return ldr.lv.Record(ldr.dm, prefix, tntID)
+}
+
+// stage keeps the object aside instead of writing it when the load is staged
+func (ldr *Loader) stage(prefix, tntID string, obj interface{}) (staged bool, err error) {
+	if ldr.sl == nil {
+		return
+	}
+	return true, ldr.sl.Add(ldr.dm, prefix, tntID, obj)
+}
+
 // lockFolder will attempt to lock the folder by creating the lock file
 func (ldr *Loader) lockFolder() (err error) {
 	_, err = os.OpenFile(path.Join(ldr.tpInDir, ldr.lockFilename),
@@ -268,6 +324,14 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(apf)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.AttributeProfilePrefix, apf.TenantID(), apf); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.AttributeProfilePrefix, apf.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetAttributeProfile(apf, true); err != nil {
 					return err
 				}
@@ -294,6 +358,14 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(res)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.ResourceProfilesPrefix, res.TenantID(), res); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.ResourceProfilesPrefix, res.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetResourceProfile(res, true); err != nil {
 					return err
 				}
@@ -326,6 +398,14 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(fltrPrf)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.FilterPrefix, fltrPrf.TenantID(), fltrPrf); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.FilterPrefix, fltrPrf.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetFilter(fltrPrf); err != nil {
 					return err
 				}
@@ -351,6 +431,14 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(stsPrf)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.StatQueueProfilePrefix, stsPrf.TenantID(), stsPrf); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.StatQueueProfilePrefix, stsPrf.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetStatQueueProfile(stsPrf, true); err != nil {
 					return err
 				}
@@ -388,6 +476,14 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(thPrf)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.ThresholdProfilePrefix, thPrf.TenantID(), thPrf); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.ThresholdProfilePrefix, thPrf.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetThresholdProfile(thPrf, true); err != nil {
 					return err
 				}
@@ -417,6 +513,14 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(spPrf)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.SupplierProfilePrefix, spPrf.TenantID(), spPrf); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.SupplierProfilePrefix, spPrf.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetSupplierProfile(spPrf, true); err != nil {
 					return err
 				}
@@ -443,6 +547,14 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(cpp)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.ChargerProfilePrefix, cpp.TenantID(), cpp); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.ChargerProfilePrefix, cpp.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetChargerProfile(cpp, true); err != nil {
 					return err
 				}
@@ -468,11 +580,52 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 							utils.LoaderS, ldr.ldrID, utils.ToJSON(dsp)))
 					continue
 				}
+				if staged, err := ldr.stage(utils.DispatcherProfilePrefix, dsp.TenantID(), dsp); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.DispatcherProfilePrefix, dsp.TenantID()); err != nil {
+					return err
+				}
 				if err := ldr.dm.SetDispatcherProfile(dsp, true); err != nil {
 					return err
 				}
 			}
 		}
+	case utils.MetaPortedNumbers:
+		for _, lDataSet := range lds {
+			pnModels := make(engine.TPPortedNumbers, len(lDataSet))
+			for i, ld := range lDataSet {
+				pnModels[i] = new(engine.TPPortedNumber)
+				if err = utils.UpdateStructWithIfaceMap(pnModels[i], ld); err != nil {
+					return
+				}
+			}
+			pns, err := pnModels.AsPortedNumbers()
+			if err != nil {
+				return err
+			}
+			for _, pn := range pns {
+				if ldr.dryRun {
+					utils.Logger.Info(
+						fmt.Sprintf("<%s-%s> DRY_RUN: PortedNumbers: %s",
+							utils.LoaderS, ldr.ldrID, utils.ToJSON(pn)))
+					continue
+				}
+				if staged, err := ldr.stage(utils.PortedNumbersPrefix, pn.TenantID(), pn); err != nil {
+					return err
+				} else if staged {
+					continue
+				}
+				if err := ldr.recordVersion(utils.PortedNumbersPrefix, pn.TenantID()); err != nil {
+					return err
+				}
+				if err := ldr.dm.SetPortedNumbers(pn); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	return
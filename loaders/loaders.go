@@ -24,17 +24,19 @@ import (
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/servmanager"
 	"github.com/cgrates/cgrates/utils"
 )
 
 func NewLoaderService(dm *engine.DataManager, ldrsCfg []*config.LoaderSCfg,
-	timezone string, filterS *engine.FilterS) (ldrS *LoaderService) {
+	timezone string, filterS *engine.FilterS,
+	srvMngr *servmanager.ServiceManager) (ldrS *LoaderService) {
 	ldrS = &LoaderService{ldrs: make(map[string]*Loader)}
 	for _, ldrCfg := range ldrsCfg {
 		if !ldrCfg.Enabled {
 			continue
 		}
-		ldrS.ldrs[ldrCfg.Id] = NewLoader(dm, ldrCfg, timezone, filterS)
+		ldrS.ldrs[ldrCfg.Id] = NewLoader(dm, ldrCfg, timezone, filterS, srvMngr)
 	}
 	return
 }
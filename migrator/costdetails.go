@@ -59,6 +59,8 @@ func (m *Migrator) migrateCostDetails() (err error) {
 		storSQL = m.storDBOut.(*engine.SQLStorage).Db
 	case utils.POSTGRES:
 		storSQL = m.storDBOut.(*engine.SQLStorage).Db
+	case utils.SQLITE:
+		storSQL = m.storDBOut.(*engine.SQLStorage).Db
 	default:
 		return utils.NewCGRError(utils.Migrator,
 			utils.MandatoryIEMissingCaps,
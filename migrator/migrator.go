@@ -176,6 +176,8 @@ func (m *Migrator) Migrate(taskIDs []string) (err error, stats map[string]int) {
 			err = m.migrateTPChargers()
 		case utils.MetaTpDispatchers:
 			err = m.migrateTPDispatchers()
+		case utils.MetaTpAttributes:
+			err = m.migrateTPAttributes()
 			//DATADB ALL
 		case utils.MetaDataDB:
 			if err := m.migrateAccounts(); err != nil {
@@ -289,6 +291,9 @@ func (m *Migrator) Migrate(taskIDs []string) (err error, stats map[string]int) {
 			if err := m.migrateTPDestinations(); err != nil {
 				log.Print("ERROR: ", utils.MetaTpDestinations, " ", err)
 			}
+			if err := m.migrateTPAttributes(); err != nil {
+				log.Print("ERROR: ", utils.MetaTpAttributes, " ", err)
+			}
 			err = nil
 		}
 	}
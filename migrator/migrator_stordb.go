@@ -30,5 +30,6 @@ type MigratorStorDB interface {
 	getV2SMCost() (v2Cost *v2SessionsCost, err error)
 	setV2SMCost(v2Cost *v2SessionsCost) (err error)
 	remV2SMCost(v2Cost *v2SessionsCost) (err error)
+	addV1TPAttributesColumns() (err error)
 	StorDB() engine.StorDB
 }
@@ -70,12 +70,15 @@ func NewMigratorStorDB(db_type, host, port, name, user, pass string,
 	case utils.POSTGRES:
 		d = newMigratorSQL(storDb)
 		db = d.(MigratorStorDB)
+	case utils.SQLITE:
+		d = newMigratorSQL(storDb)
+		db = d.(MigratorStorDB)
 	case utils.INTERNAL:
 		d = newMapStorDBMigrator(storDb)
 		db = d.(MigratorStorDB)
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.INTERNAL))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.INTERNAL))
 	}
 	return d, nil
 }
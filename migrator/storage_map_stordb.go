@@ -76,3 +76,8 @@ func (mpMig *mapStorDBMigrator) setV2SMCost(v2Cost *v2SessionsCost) (err error)
 func (mpMig *mapStorDBMigrator) remV2SMCost(v2Cost *v2SessionsCost) (err error) {
 	return utils.ErrNotImplemented
 }
+
+//TPAttributes methods
+func (mpMig *mapStorDBMigrator) addV1TPAttributesColumns() (err error) {
+	return utils.ErrNotImplemented
+}
@@ -122,3 +122,9 @@ func (v1ms *mongoStorDBMigrator) remV2SMCost(v2Cost *v2SessionsCost) (err error)
 	_, err = v1ms.mgoDB.DB().Collection(utils.SessionCostsTBL).DeleteMany(v1ms.mgoDB.GetContext(), bson.D{})
 	return
 }
+
+//TPAttributes methods
+// documents without the new fields are decoded with their zero values
+func (v1ms *mongoStorDBMigrator) addV1TPAttributesColumns() (err error) {
+	return
+}
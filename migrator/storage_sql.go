@@ -78,7 +78,8 @@ func (mgSQL *migratorSQL) setV1CDR(v1Cdr *v1Cdrs) (err error) {
 
 func (mgSQL *migratorSQL) renameV1SMCosts() (err error) {
 	qry := "RENAME TABLE sm_costs TO session_costs;"
-	if mgSQL.StorDB().GetStorageType() == utils.POSTGRES {
+	if utils.IsSliceMember([]string{utils.POSTGRES, utils.SQLITE},
+		mgSQL.StorDB().GetStorageType()) {
 		qry = "ALTER TABLE sm_costs RENAME TO session_costs"
 	}
 	if _, err := mgSQL.sqlStorage.Db.Exec(qry); err != nil {
@@ -105,6 +106,22 @@ func (mgSQL *migratorSQL) createV1SMCosts() (err error) {
 	  UNIQUE (cgrid, run_id)
 	);
 		`
+	} else if mgSQL.StorDB().GetStorageType() == utils.SQLITE {
+		qry = `
+	CREATE TABLE sm_costs (
+	  id INTEGER PRIMARY KEY AUTOINCREMENT,
+	  cgrid VARCHAR(40) NOT NULL,
+	  run_id  VARCHAR(64) NOT NULL,
+	  origin_host VARCHAR(64) NOT NULL,
+	  origin_id VARCHAR(128) NOT NULL,
+	  cost_source VARCHAR(64) NOT NULL,
+	  usage BIGINT NOT NULL,
+	  cost_details TEXT,
+	  created_at DATETIME,
+	  deleted_at DATETIME NULL,
+	  UNIQUE (cgrid, run_id)
+	);
+		`
 	}
 	if _, err := mgSQL.sqlStorage.Db.Exec("DROP TABLE IF EXISTS session_costs;"); err != nil {
 		return err
@@ -164,3 +181,29 @@ func (mgSQL *migratorSQL) remV2SMCost(v2Cost *v2SessionsCost) (err error) {
 	return nil
 
 }
+
+//TPAttributes methods
+func (mgSQL *migratorSQL) addV1TPAttributesColumns() (err error) {
+	qrys := []string{"ALTER TABLE tp_attributes ADD COLUMN `attribute_filter_ids` varchar(64) NOT NULL DEFAULT '', ADD COLUMN `type` varchar(64) NOT NULL DEFAULT '', DROP INDEX `unique_tp_attributes`, ADD UNIQUE KEY `unique_tp_attributes` (`tpid`,`tenant`,`id`,`filter_ids`,`attribute_filter_ids`,`field_name`,`initial`,`substitute`);"}
+	switch mgSQL.StorDB().GetStorageType() {
+	case utils.POSTGRES:
+		qrys = []string{
+			`ALTER TABLE tp_attributes ADD COLUMN "attribute_filter_ids" varchar(64) NOT NULL DEFAULT '', ADD COLUMN "type" varchar(64) NOT NULL DEFAULT ''`,
+			`DROP INDEX IF EXISTS tp_attributes_unique`,
+			`CREATE INDEX tp_attributes_unique ON tp_attributes ("tpid", "tenant", "id", "filter_ids", "attribute_filter_ids", "field_name", "initial", "substitute")`,
+		}
+	case utils.SQLITE: // SQLite adds one column per statement
+		qrys = []string{
+			`ALTER TABLE tp_attributes ADD COLUMN "attribute_filter_ids" varchar(64) NOT NULL DEFAULT ''`,
+			`ALTER TABLE tp_attributes ADD COLUMN "type" varchar(64) NOT NULL DEFAULT ''`,
+			`DROP INDEX IF EXISTS tp_attributes_unique`,
+			`CREATE INDEX tp_attributes_unique ON tp_attributes ("tpid", "tenant", "id", "filter_ids", "attribute_filter_ids", "field_name", "initial", "substitute")`,
+		}
+	}
+	for _, qry := range qrys {
+		if _, err = mgSQL.sqlStorage.Db.Exec(qry); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,112 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (m *Migrator) migrateCurrentTPAttributes() (err error) {
+	tpids, err := m.storDBIn.StorDB().GetTpIds(utils.TBLTPAttributes)
+	if err != nil {
+		return err
+	}
+
+	for _, tpid := range tpids {
+		ids, err := m.storDBIn.StorDB().GetTpTableIds(tpid, utils.TBLTPAttributes,
+			utils.TPDistinctIds{"id"}, map[string]string{}, nil)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			attrs, err := m.storDBIn.StorDB().GetTPAttributes(tpid, "", id)
+			if err != nil {
+				return err
+			}
+			if attrs != nil {
+				if m.dryRun != true {
+					if err := m.storDBOut.StorDB().SetTPAttributes(attrs); err != nil {
+						return err
+					}
+					for _, attr := range attrs {
+						if err := m.storDBIn.StorDB().RemTpData(utils.TBLTPAttributes, attr.TPid,
+							map[string]string{"id": attr.ID}); err != nil {
+							return err
+						}
+					}
+					m.stats[utils.TpAttributes] += 1
+				}
+			}
+		}
+	}
+	return
+}
+
+// migrateV1TPAttributes adds the attribute_filter_ids and type columns
+// to the tp_attributes table, existing rows get them empty
+func (m *Migrator) migrateV1TPAttributes() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBIn.addV1TPAttributesColumns(); err != nil {
+		return err
+	}
+	vrs := engine.Versions{utils.TpAttributes: engine.CurrentStorDBVersions()[utils.TpAttributes]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating TpAttributes version into StorDB", err.Error()))
+	}
+	return
+}
+
+func (m *Migrator) migrateTPAttributes() (err error) {
+	var vrs engine.Versions
+	current := engine.CurrentStorDBVersions()
+	vrs, err = m.storDBOut.StorDB().GetVersions("")
+	if err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when querying OutStorDB for versions", err.Error()))
+	} else if len(vrs) == 0 {
+		return utils.NewCGRError(utils.Migrator,
+			utils.MandatoryIEMissingCaps,
+			utils.UndefinedVersion,
+			"version number is not defined for TPAttributes model")
+	}
+	switch vrs[utils.TpAttributes] {
+	case 0, 1:
+		if err := m.migrateV1TPAttributes(); err != nil {
+			return err
+		}
+	case current[utils.TpAttributes]:
+		if m.sameStorDB {
+			return
+		}
+		if err := m.migrateCurrentTPAttributes(); err != nil {
+			return err
+		}
+	}
+	return
+}
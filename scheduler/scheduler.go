@@ -96,7 +96,14 @@ func (s *Scheduler) Loop() {
 		now := time.Now()
 		start := a0.GetNextStartTime(now)
 		if start.Equal(now) || start.Before(now) {
-			go a0.Execute(s.actSucessChan, s.actFailedChan)
+			if strings.HasPrefix(a0.GetActionPlanID(), engine.MetaActivateStagedLoad) {
+				go func() { // the staged load brings its own action plans
+					a0.Execute(s.actSucessChan, s.actFailedChan)
+					s.Reload()
+				}()
+			} else {
+				go a0.Execute(s.actSucessChan, s.actFailedChan)
+			}
 			// if after execute the next start time is in the past then
 			// do not add it to the queue
 			a0.ResetStartTimeCache()
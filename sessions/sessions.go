@@ -658,6 +658,20 @@ func (sS *SessionS) disconnectSession(s *Session, rsn string) (err error) {
 	return
 }
 
+// reAuthorizeSession will ask the client to re-authorize the session (ie: Diameter RAR)
+func (sS *SessionS) reAuthorizeSession(s *Session) (err error) {
+	clnt := sS.biJClnt(s.ClientConnID)
+	if clnt == nil {
+		return fmt.Errorf("calling %s requires bidirectional JSON connection", utils.SessionSv1ReAuthorize)
+	}
+	s.RLock()
+	sEv := s.EventStart.AsMapInterface()
+	s.RUnlock()
+	var rply string
+	return clnt.conn.Call(utils.SessionSv1ReAuthorize,
+		utils.AttrReAuthorizeSession{EventStart: sEv}, &rply)
+}
+
 // replicateSessions will replicate sessions with or without cgrID specified
 func (sS *SessionS) replicateSessions(cgrID string, psv bool, rplConns []*SReplConn) (err error) {
 	if len(rplConns) == 0 {
@@ -1129,12 +1143,19 @@ func (sS *SessionS) authSession(tnt string, evStart *engine.SafEvent) (maxUsage
 	prepaidReqs := []string{utils.META_PREPAID, utils.META_PSEUDOPREPAID}
 	for _, sr := range s.SRuns {
 		var rplyMaxUsage time.Duration
-		if !utils.IsSliceMember(prepaidReqs,
-			sr.Event.GetStringIgnoreErrors(utils.RequestType)) {
+		reqType := sr.Event.GetStringIgnoreErrors(utils.RequestType)
+		if utils.IsSliceMember(prepaidReqs, reqType) {
+			if err = sS.ralS.Call(utils.ResponderGetMaxSessionTime,
+				sr.CD, &rplyMaxUsage); err != nil {
+				return
+			}
+		} else if reqType == utils.META_POSTPAID { // limited by the credit limit and spending caps
+			if err = sS.ralS.Call(utils.ResponderGetMaxPostpaidSessionTime,
+				sr.CD, &rplyMaxUsage); err != nil {
+				return
+			}
+		} else {
 			rplyMaxUsage = time.Duration(-1)
-		} else if err = sS.ralS.Call(utils.ResponderGetMaxSessionTime,
-			sr.CD, &rplyMaxUsage); err != nil {
-			return
 		}
 		if !maxUsageSet ||
 			maxUsage == time.Duration(-1) ||
@@ -2399,6 +2420,41 @@ func (sS *SessionS) BiRPCv1ForceDisconnect(clnt rpcclient.RpcClientConnection,
 	return nil
 }
 
+// BiRPCv1ReAuthorize will ask the clients of the sessions matching the filters to re-authorize them
+func (sS *SessionS) BiRPCv1ReAuthorize(clnt rpcclient.RpcClientConnection,
+	fltr map[string]string, reply *string) error {
+	for fldName, fldVal := range fltr {
+		if fldVal == "" {
+			fltr[fldName] = utils.META_NONE
+		}
+	}
+	aSs, _, err := sS.asActiveSessions(fltr, false, false)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	} else if len(aSs) == 0 {
+		return utils.ErrNotFound
+	}
+	for _, as := range aSs {
+		ss := sS.getSessions(as.CGRID, false)
+		if len(ss) == 0 {
+			continue
+		}
+		if errReAuth := sS.reAuthorizeSession(ss[0]); errReAuth != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf(
+					"<%s> failed re-authorizing session with id: <%s>, err: <%s>",
+					utils.SessionS, ss[0].CGRid(), errReAuth.Error()))
+			err = utils.ErrPartiallyExecuted
+		}
+	}
+	if err == nil {
+		*reply = utils.OK
+	} else {
+		*reply = err.Error()
+	}
+	return nil
+}
+
 func (sS *SessionS) BiRPCv1RegisterInternalBiJSONConn(clnt rpcclient.RpcClientConnection,
 	ign string, reply *string) error {
 	sS.RegisterIntBiJConn(clnt)
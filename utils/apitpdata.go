@@ -673,6 +673,8 @@ type AttrLoadTpFromFolder struct {
 	DryRun     bool   // Do not write to database but parse only
 	FlushDb    bool   // Flush previous data before loading new one
 	Validate   bool   // Run structural checks on data
+	// ActivationTime stages the load, to be switched live by the Scheduler at this time <*midnight|$time>
+	ActivationTime string
 }
 
 type AttrImportTPFromFolder struct {
@@ -925,6 +927,112 @@ func (self *RPCCDRsFilter) AsCDRsFilter(timezone string) (*CDRsFilter, error) {
 	return cdrFltr, nil
 }
 
+// BalanceHistoryFilter is used to query the balance history out of storDB
+type BalanceHistoryFilter struct {
+	Tenant      string
+	Account     string
+	BalanceIDs  []string   // If provided, it will filter on balance ID or UUID
+	BalanceType string     // If provided, it will filter on balance type
+	Reasons     []string   // If provided, it will filter on the reason of the movement
+	CGRIDs      []string   // If provided, it will filter on the charged event
+	TimeStart   *time.Time // Start of interval, bigger or equal than configured
+	TimeEnd     *time.Time // End interval, smaller than
+	Paginator
+}
+
+// AttrGetBalanceHistory is the BalanceHistoryFilter used in Rpc calls, with string instead of Time filters
+type AttrGetBalanceHistory struct {
+	Tenant      string
+	Account     string
+	BalanceIDs  []string
+	BalanceType string
+	Reasons     []string
+	CGRIDs      []string
+	TimeStart   string
+	TimeEnd     string
+	Paginator
+}
+
+func (attr *AttrGetBalanceHistory) AsBalanceHistoryFilter(timezone string) (*BalanceHistoryFilter, error) {
+	fltr := &BalanceHistoryFilter{
+		Tenant:      attr.Tenant,
+		Account:     attr.Account,
+		BalanceIDs:  attr.BalanceIDs,
+		BalanceType: attr.BalanceType,
+		Reasons:     attr.Reasons,
+		CGRIDs:      attr.CGRIDs,
+		Paginator:   attr.Paginator,
+	}
+	if len(attr.TimeStart) != 0 {
+		tStart, err := ParseTimeDetectLayout(attr.TimeStart, timezone)
+		if err != nil {
+			return nil, err
+		}
+		fltr.TimeStart = &tStart
+	}
+	if len(attr.TimeEnd) != 0 {
+		tEnd, err := ParseTimeDetectLayout(attr.TimeEnd, timezone)
+		if err != nil {
+			return nil, err
+		}
+		fltr.TimeEnd = &tEnd
+	}
+	return fltr, nil
+}
+
+// AttrAddBalance is used to top-up or debit an account balance
+type AttrAddBalance struct {
+	Tenant         string
+	Account        string
+	BalanceUuid    *string
+	BalanceId      *string
+	BalanceType    string
+	Directions     *string
+	Value          float64
+	ExpiryTime     *string
+	RatingSubject  *string
+	Categories     *string
+	DestinationIds *string
+	TimingIds      *string
+	Weight         *float64
+	SharedGroups   *string
+	Overwrite      bool // When true it will reset if the balance is already there
+	Blocker        *bool
+	Disabled       *bool
+	Cdrlog         *bool
+}
+
+// AttrReserveBalance holds value out of the account balances matching the filters
+type AttrReserveBalance struct {
+	Tenant         string
+	Account        string
+	ReservationID  string // generated if not provided
+	BalanceType    string
+	BalanceID      *string
+	DestinationIDs *string
+	Categories     *string
+	Value          float64
+	TTL            string // the reservation is released automatically afterwards, kept until captured or released if empty
+}
+
+// AttrSubscription identifies the subscription of an account to a product
+type AttrSubscription struct {
+	Tenant       string
+	Account      string
+	ProductID    string
+	NewProductID string // replacing ProductID on change
+	AnchorDay    int    // day of the month the billing cycles start on, defaults to the day of the subscription
+	Time         string // time of the change, defaults to *now
+}
+
+// AttrReservation identifies a balance reservation to be captured or released
+type AttrReservation struct {
+	Tenant        string
+	Account       string
+	ReservationID string
+	Value         *float64 // the value captured, all of the reservation if nil
+}
+
 type AttrSetActions struct {
 	ActionsId string      // Actions id
 	Overwrite bool        // If previously defined, will be overwritten
@@ -943,6 +1051,7 @@ type AttrSetAccount struct {
 	ActionPlanId     string
 	ActionTriggersId string
 	AllowNegative    *bool
+	CreditLimit      *float64
 	Disabled         *bool
 	ReloadScheduler  bool
 }
@@ -1057,6 +1166,11 @@ type AttrDisconnectSession struct {
 	Reason     string
 }
 
+// Attributes to send on ReAuthorize by SessionS
+type AttrReAuthorizeSession struct {
+	EventStart map[string]interface{}
+}
+
 // TPStats is used in APIs to manage remotely offline Stats config
 type TPStats struct {
 	TPid               string
@@ -1133,7 +1247,9 @@ type TPSupplierProfile struct {
 }
 
 type TPAttribute struct {
+	FilterIDs  []string
 	FieldName  string
+	Type       string
 	Initial    string
 	Substitute string
 	Append     bool
@@ -73,6 +73,7 @@ var (
 		CacheAttributeProfiles:       AttributeProfilePrefix,
 		CacheChargerProfiles:         ChargerProfilePrefix,
 		CacheDispatcherProfiles:      DispatcherProfilePrefix,
+		CachePortedNumbers:           PortedNumbersPrefix,
 		CacheResourceFilterIndexes:   ResourceFilterIndexes,
 		CacheStatFilterIndexes:       StatFilterIndexes,
 		CacheThresholdFilterIndexes:  ThresholdFilterIndexes,
@@ -103,6 +104,9 @@ const (
 	CGRATES                       = "cgrates"
 	POSTGRES                      = "postgres"
 	MYSQL                         = "mysql"
+	SQLITE                        = "sqlite"
+	SQLITE3                       = "sqlite3"
+	SQLiteMemory                  = ":memory:"
 	MONGO                         = "mongo"
 	INTERNAL                      = "internal"
 	DataManager                   = "DataManager"
@@ -254,6 +258,11 @@ const (
 	ThresholdProfilePrefix        = "thp_"
 	StatQueuePrefix               = "stq_"
 	LOADINST_KEY                  = "load_history"
+	LoadVersionPrefix             = "ldv_"
+	StagedLoadPrefix              = "stg_"
+	SubscriptionProductPrefix     = "sbp_"
+	PortedNumbersPrefix           = "pnb_"
+	PortedPrefixesIdx             = "*ported_prefixes"
 	SESSION_MANAGER_SOURCE        = "SMR"
 	MEDIATOR_SOURCE               = "MED"
 	CDRS_SOURCE                   = "CDRS"
@@ -388,9 +397,19 @@ const (
 	MetaAttributes               = "*attributes"
 	MetaChargers                 = "*chargers"
 	MetaDispatchers              = "*dispatchers"
+	MetaPortedNumbers            = "*ported_numbers"
+	MetaCaches                   = "*caches"
+	MetaApier                    = "*apier"
 	MetaResources                = "*resources"
 	MetaFilters                  = "*filters"
 	MetaCDRs                     = "*cdrs"
+	MetaDebit                    = "*debit"
+	MetaRefund                   = "*refund"
+	MetaRefundRounding           = "*refund_rounding"
+	MetaExpired                  = "*expired"
+	MetaCapture                  = "*capture"
+	MetaDaily                    = "*daily"
+	MetaMonthly                  = "*monthly"
 	Migrator                     = "migrator"
 	UnsupportedMigrationTask     = "unsupported migration task"
 	NoStorDBConnection           = "not connected to StorDB"
@@ -408,6 +427,7 @@ const (
 	FileLockPrefix               = "file_"
 	ActionsPoster                = "act"
 	CDRPoster                    = "cdr"
+	AccountsPoster               = "acnt"
 	MetaFileCSV                  = "*file_csv"
 	MetaFileFWV                  = "*file_fwv"
 	Accounts                     = "Accounts"
@@ -444,6 +464,22 @@ const (
 	Units                        = "Units"
 	AccountUpdate                = "AccountUpdate"
 	BalanceUpdate                = "BalanceUpdate"
+	BalanceExpiring              = "BalanceExpiring"
+	BalanceExpired               = "BalanceExpired"
+	APILimitExceeded             = "APILimitExceeded"
+	BalanceRemoved               = "BalanceRemoved"
+	BalanceUUID                  = "BalanceUUID"
+	ActionTriggerIDs             = "ActionTriggerIDs"
+	Reason                       = "Reason"
+	ActionID                     = "ActionID"
+	EventTime                    = "EventTime"
+	AccountExporter              = "AccountExporter"
+	SpendingCapExceeded          = "SpendingCapExceeded"
+	Period                       = "Period"
+	Spent                        = "Spent"
+	SoftLimit                    = "SoftLimit"
+	HardLimit                    = "HardLimit"
+	LimitType                    = "LimitType"
 	StatUpdate                   = "StatUpdate"
 	ResourceUpdate               = "ResourceUpdate"
 	CDR                          = "CDR"
@@ -512,12 +548,18 @@ const (
 	SchedulerS                   = "SchedulerS"
 	MetaMultiply                 = "*multiply"
 	MetaDivide                   = "*divide"
+	MetaDifference               = "*difference"
+	MetaRemove                   = "*remove"
 	MetaUrl                      = "*url"
 	MetaXml                      = "*xml"
+	MetaJSON                     = "*json"
+	MetaForm                     = "*form"
+	MetaHdr                      = "*hdr"
 	ApiKey                       = "apikey"
 	MetaReq                      = "*req"
 	MetaVars                     = "*vars"
 	MetaRep                      = "*rep"
+	MetaAns                      = "*ans"
 	CGROriginHost                = "cgr_originhost"
 	MetaInitiate                 = "*initiate"
 	MetaUpdate                   = "*update"
@@ -547,8 +589,12 @@ const (
 	MetaRemoteHost               = "*remote_host"
 	Local                        = "local"
 	TCP                          = "tcp"
+	UDP                          = "udp"
+	SCTP                         = "sctp"
 	CGRDebitInterval             = "CGRDebitInterval"
 	MetaAsr                      = "*asr"
+	MetaRAR                      = "*rar"
+	MetaProxy                    = "*proxy"
 	Version                      = "Version"
 )
 
@@ -581,8 +627,10 @@ const (
 	FilterS     = "FilterS"
 	ThresholdS  = "ThresholdS"
 	DispatcherS = "DispatcherS"
+	EventBus    = "EventBus"
 	LoaderS     = "LoaderS"
 	ChargerS    = "ChargerS"
+	NumberS     = "NumberS"
 	CacheS      = "CacheS"
 	AnalyzerS   = "AnalyzerS"
 )
@@ -592,6 +640,7 @@ const (
 	SessionsLow    = "sessions"
 	AttributesLow  = "attributes"
 	ChargerSLow    = "chargers"
+	NumberSLow     = "numbers"
 	SuppliersLow   = "suppliers"
 	ResourcesLow   = "resources"
 	StatServiceLow = "stats"
@@ -626,8 +675,26 @@ const (
 	MetaTpRatingProfile     = "*tp_rating_profiles"
 	MetaTpChargers          = "*tp_chargers"
 	MetaTpDispatchers       = "*tp_dispatchers"
+	MetaTpAttributes        = "*tp_attributes"
 	MetaDurationSeconds     = "*duration_seconds"
 	MetaDurationNanoseconds = "*duration_nanoseconds"
+	MetaDuration            = "*duration"
+	MetaDurationHMS         = "*duration_hms"
+	MetaTimeString          = "*time_string"
+	MetaTimeZone            = "*time_zone"
+	MetaUnixTime            = "*unix_time"
+	MetaUpper               = "*upper"
+	MetaLower               = "*lower"
+	MetaPadLeft             = "*pad_left"
+	MetaPadRight            = "*pad_right"
+	MetaBase64Encode        = "*base64_encode"
+	MetaBase64Decode        = "*base64_decode"
+	MetaHexEncode           = "*hex_encode"
+	MetaHexDecode           = "*hex_decode"
+	MetaSHA256              = "*sha256"
+	MetaE164                = "*e164"
+	MetaJSONField           = "*json_field"
+	MetaIP2Int              = "*ip2int"
 	CapAttributes           = "Attributes"
 	CapResourceAllocation   = "ResourceAllocation"
 	CapMaxUsage             = "MaxUsage"
@@ -660,6 +727,7 @@ const (
 	TpRatingProfile    = "TpRatingProfile"
 	TpChargers         = "TpChargers"
 	TpDispatchers      = "TpDispatchers"
+	TpAttributes       = "TpAttributes"
 )
 
 // Dispatcher Const
@@ -669,6 +737,9 @@ const (
 	MetaBroadcast  = "*broadcast"
 	MetaNext       = "*next"
 	MetaRoundRobin = "*round_robin"
+	MetaHash       = "*hash"
+	MetaBlock      = "*block"
+	MetaDrop       = "*drop"
 	ThresholdSv1   = "ThresholdSv1"
 	StatSv1        = "StatSv1"
 	ResourceSv1    = "ResourceSv1"
@@ -676,22 +747,52 @@ const (
 	AttributeSv1   = "AttributeSv1"
 	SessionSv1     = "SessionSv1"
 	ChargerSv1     = "ChargerSv1"
+	NumberSv1      = "NumberSv1"
+	DispatcherSv1  = "DispatcherSv1"
+	CDRsV1         = "CDRsV1"
+	CDRsV2         = "CDRsV2"
+	Responder      = "Responder"
+	CacheSv1       = "CacheSv1"
+	ApierV1        = "ApierV1"
 	MetaAuth       = "*auth"
 	APIKey         = "APIKey"
 	APIMethods     = "APIMethods"
 	APIMethod      = "APIMethod"
+	APIRateLimits  = "APIRateLimits"
+	APIConcurrency = "APIConcurrency"
+	APILimitType   = "APILimitType"
+	MetaRateLimit  = "*rate_limit"
+	MetaConcurrent = "*concurrent"
 	NestingSep     = "."
 )
 
 // ApierV1 APIs
 const (
-	ApierV1ComputeFilterIndexes    = "ApierV1.ComputeFilterIndexes"
-	ApierV1ReloadCache             = "ApierV1.ReloadCache"
-	ApierV1ReloadScheduler         = "ApierV1.ReloadScheduler"
-	ApierV1Ping                    = "ApierV1.Ping"
-	ApierV1SetDispatcherProfile    = "ApierV1.SetDispatcherProfile"
-	ApierV1GetDispatcherProfile    = "ApierV1.GetDispatcherProfile"
-	ApierV1RemoveDispatcherProfile = "ApierV1.RemoveDispatcherProfile"
+	ApierV1ComputeFilterIndexes     = "ApierV1.ComputeFilterIndexes"
+	ApierV1ReloadCache              = "ApierV1.ReloadCache"
+	ApierV1ReloadScheduler          = "ApierV1.ReloadScheduler"
+	ApierV1DiffTariffPlanFromFolder = "ApierV1.DiffTariffPlanFromFolder"
+	ApierV1RollbackLoad             = "ApierV1.RollbackLoad"
+	ApierV1ActivateStagedLoad       = "ApierV1.ActivateStagedLoad"
+	ApierV1RemoveStagedLoad         = "ApierV1.RemoveStagedLoad"
+	ApierV1GetBalanceHistory        = "ApierV1.GetBalanceHistory"
+	ApierV1ReserveBalance           = "ApierV1.ReserveBalance"
+	ApierV1CaptureReservation       = "ApierV1.CaptureReservation"
+	ApierV1ReleaseReservation       = "ApierV1.ReleaseReservation"
+	ApierV1GetAccount               = "ApierV1.GetAccount"
+	ApierV1SetAccount               = "ApierV1.SetAccount"
+	ApierV1RemoveAccount            = "ApierV1.RemoveAccount"
+	ApierV1AddBalance               = "ApierV1.AddBalance"
+	ApierV1DebitBalance             = "ApierV1.DebitBalance"
+	ApierV1SetBalance               = "ApierV1.SetBalance"
+	ApierV1RemoveBalances           = "ApierV1.RemoveBalances"
+	ApierV1AddAccountActionTriggers = "ApierV1.AddAccountActionTriggers"
+	ApierV1AddTriggeredAction       = "ApierV1.AddTriggeredAction"
+	ApierV1GetEventBusMetrics       = "ApierV1.GetEventBusMetrics"
+	ApierV1Ping                     = "ApierV1.Ping"
+	ApierV1SetDispatcherProfile     = "ApierV1.SetDispatcherProfile"
+	ApierV1GetDispatcherProfile     = "ApierV1.GetDispatcherProfile"
+	ApierV1RemoveDispatcherProfile  = "ApierV1.RemoveDispatcherProfile"
 )
 
 const (
@@ -719,6 +820,13 @@ const (
 	ChargerSv1ProcessEvent        = "ChargerSv1.ProcessEvent"
 )
 
+// NumberS APIs
+const (
+	NumberSv1Ping         = "NumberSv1.Ping"
+	NumberSv1ProcessEvent = "NumberSv1.ProcessEvent"
+	NumberSv1LookupNumber = "NumberSv1.LookupNumber"
+)
+
 // ThresholdS APIs
 const (
 	ThresholdSv1ProcessEvent          = "ThresholdSv1.ProcessEvent"
@@ -762,6 +870,7 @@ const (
 	SessionSv1GetActiveSessions          = "SessionSv1.GetActiveSessions"
 	SessionSv1GetActiveSessionsCount     = "SessionSv1.GetActiveSessionsCount"
 	SessionSv1ForceDisconnect            = "SessionSv1.ForceDisconnect"
+	SessionSv1ReAuthorize                = "SessionSv1.ReAuthorize"
 	SessionSv1GetPassiveSessions         = "SessionSv1.GetPassiveSessions"
 	SessionSv1GetPassiveSessionsCount    = "SessionSv1.GetPassiveSessionsCount"
 	SessionSv1SetPassiveSession          = "SessionSv1.SetPassiveSession"
@@ -776,14 +885,18 @@ const (
 
 // Responder APIs
 const (
-	ResponderDebit             = "Responder.Debit"
-	ResponderRefundIncrements  = "Responder.RefundIncrements"
-	ResponderGetMaxSessionTime = "Responder.GetMaxSessionTime"
+	ResponderGetCost                   = "Responder.GetCost"
+	ResponderDebit                     = "Responder.Debit"
+	ResponderMaxDebit                  = "Responder.MaxDebit"
+	ResponderRefundIncrements          = "Responder.RefundIncrements"
+	ResponderGetMaxSessionTime         = "Responder.GetMaxSessionTime"
+	ResponderGetMaxPostpaidSessionTime = "Responder.GetMaxPostpaidSessionTime"
 )
 
 // DispatcherS APIs
 const (
-	DispatcherSv1Ping = "DispatcherSv1.Ping"
+	DispatcherSv1Ping              = "DispatcherSv1.Ping"
+	DispatcherSv1GetAPIKeyCounters = "DispatcherSv1.GetAPIKeyCounters"
 )
 
 // AnalyzerS APIs
@@ -797,6 +910,12 @@ const (
 	LoaderSv1Ping = "LoaderSv1.Ping"
 )
 
+// DiameterAgent APIs
+const (
+	DiameterAgentV1GetPeerStats = "DiameterAgentV1.GetPeerStats"
+	DiameterAgentV1Ping         = "DiameterAgentV1.Ping"
+)
+
 // CacheS APIs
 const (
 	CacheSv1GetCacheStats     = "CacheSv1.GetCacheStats"
@@ -809,10 +928,12 @@ const (
 	CacheSv1GetGroupItemIDs   = "CacheSv1.GetGroupItemIDs"
 	CacheSv1RemoveGroup       = "CacheSv1.RemoveGroup"
 	CacheSv1Clear             = "CacheSv1.Clear"
+	CacheSv1Ping              = "CacheSv1.Ping"
 )
 
 // Cdrs APIs
 const (
+	CDRsV1ProcessCDR       = "CDRsV1.ProcessCDR"
 	CDRsV1CountCDRs        = "CDRsV1.CountCDRs"
 	CDRsV1RateCDRs         = "CDRsV1.RateCDRs"
 	CDRsV1GetCDRs          = "CDRsV1.GetCDRs"
@@ -870,6 +991,7 @@ const (
 	AttributesCsv         = "Attributes.csv"
 	ChargersCsv           = "Chargers.csv"
 	DispatchersCsv        = "Dispatchers.csv"
+	PortedNumbersCsv      = "PortedNumbers.csv"
 )
 
 // Table Name
@@ -891,6 +1013,7 @@ const (
 	TBLTPFilters          = "tp_filters"
 	SessionCostsTBL       = "session_costs"
 	CDRsTBL               = "cdrs"
+	BalanceHistoryTBL     = "balance_history"
 	TBLTPSuppliers        = "tp_suppliers"
 	TBLTPAttributes       = "tp_attributes"
 	TBLTPChargers         = "tp_chargers"
@@ -925,6 +1048,7 @@ const (
 	CacheDispatcherProfiles      = "dispatcher_profiles"
 	CacheDispatchers             = "dispatchers"
 	CacheDispatcherRoutes        = "dispatcher_routes"
+	CachePortedNumbers           = "ported_numbers"
 	CacheResourceFilterIndexes   = "resource_filter_indexes"
 	CacheStatFilterIndexes       = "stat_filter_indexes"
 	CacheThresholdFilterIndexes  = "threshold_filter_indexes"
@@ -956,6 +1080,8 @@ const (
 	FreeSWITCHAgent = "FreeSWITCHAgent"
 	AsteriskAgent   = "AsteriskAgent"
 	HTTPAgent       = "HTTPAgent"
+	SIPAgent        = "SIPAgent"
+	SMPPAgent       = "SMPPAgent"
 )
 
 func buildCacheInstRevPrefixes() {
@@ -185,6 +185,8 @@ func ParseTimeDetectLayout(tmStr string, timezone string) (time.Time, error) {
 		return time.Now().AddDate(0, 1, 0), nil // add one month
 	case tmStr == "*yearly":
 		return time.Now().AddDate(1, 0, 0), nil // add one year
+	case tmStr == "*midnight":
+		return NextMidnight(time.Now(), loc), nil
 	case strings.HasPrefix(tmStr, "*month_end"):
 		expDate := GetEndOfMonth(time.Now())
 		if eDurIdx := strings.Index(tmStr, "+"); eDurIdx != -1 {
@@ -609,6 +611,12 @@ func GetEndOfMonth(ref time.Time) time.Time {
 	return eom.Add(-time.Second)
 }
 
+// NextMidnight returns the first midnight after ref, in location loc
+func NextMidnight(ref time.Time, loc *time.Location) time.Time {
+	ref = ref.In(loc)
+	return time.Date(ref.Year(), ref.Month(), ref.Day()+1, 0, 0, 0, 0, loc)
+}
+
 // formats number in K,M,G, etc.
 func SizeFmt(num float64, suffix string) string {
 	if suffix == "" {
@@ -675,6 +683,48 @@ func MaskSuffix(dest string, maskLen int) string {
 	return dest
 }
 
+// IsDigits checks that s is made of decimal digits only
+func IsDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeE164 returns the E.164 digits of num, without leading +
+// the formatting characters and the international prefix (+ or intlPrefix) are removed,
+// natPrefix is replaced with the countryCode and the numbers without any of the prefixes
+// are considered E.164 only if unprefixed is true
+func NormalizeE164(num, intlPrefix, natPrefix, countryCode string,
+	unprefixed bool) (e164 string, normalized bool) {
+	e164 = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '/':
+			return -1 // formatting characters
+		}
+		return r
+	}, num)
+	switch {
+	case strings.HasPrefix(e164, "+"):
+		e164 = e164[1:]
+	case intlPrefix != "" && strings.HasPrefix(e164, intlPrefix):
+		e164 = e164[len(intlPrefix):]
+	case natPrefix != "" && strings.HasPrefix(e164, natPrefix):
+		e164 = countryCode + e164[len(natPrefix):]
+	case !unprefixed:
+		return "", false
+	}
+	if len(e164) > 15 || !IsDigits(e164) {
+		return "", false
+	}
+	return e164, true
+}
+
 // Sortable Int64Slice
 type Int64Slice []int64
 
@@ -848,7 +898,7 @@ func NewFallbackFileNameFronString(fileName string) (ffn *FallbackFileName, err
 	moduleIdx := strings.Index(fileName, HandlerArgSep)
 	ffn.Module = fileName[:moduleIdx]
 	var supportedModule bool
-	for _, prfx := range []string{ActionsPoster, CDRPoster} {
+	for _, prfx := range []string{ActionsPoster, CDRPoster, AccountsPoster} {
 		if strings.HasPrefix(ffn.Module, prfx) {
 			supportedModule = true
 			break
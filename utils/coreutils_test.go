@@ -299,6 +299,13 @@ func TestParseTimeDetectLayout(t *testing.T) {
 	} else if !date.Equal(expected) {
 		t.Errorf("expecting: %+v, received: %+v", expected, date)
 	}
+	before := time.Now()
+	if date, err := ParseTimeDetectLayout("*midnight", ""); err != nil {
+		t.Error(err)
+	} else if after := time.Now(); !date.After(before) || date.After(after.Add(24*time.Hour)) ||
+		date.UTC().Hour() != 0 || date.UTC().Minute() != 0 || date.UTC().Second() != 0 {
+		t.Errorf("received: %+v", date)
+	}
 
 	date, err = ParseTimeDetectLayout("2013-07-30T19:33:10Z", "")
 	expected = time.Date(2013, 7, 30, 19, 33, 10, 0, time.UTC)
@@ -632,6 +639,74 @@ func TestMaskSuffix(t *testing.T) {
 	}
 }
 
+func TestIsDigits(t *testing.T) {
+	for s, exp := range map[string]bool{
+		"0123456789": true,
+		"":           false,
+		"+49151":     false,
+		"1001a":      false,
+	} {
+		if rcv := IsDigits(s); rcv != exp {
+			t.Errorf("<%s> expecting: %v, received: %v", s, exp, rcv)
+		}
+	}
+}
+
+func TestNormalizeE164(t *testing.T) {
+	testCases := []struct {
+		num         string
+		intlPrefix  string
+		natPrefix   string
+		countryCode string
+		unprefixed  bool
+		e164        string
+		normalized  bool
+	}{
+		{"+49 (151) 123-456", "", "", "", false, "49151123456", true},
+		{"0049151123456", "00", "0", "49", false, "49151123456", true},
+		{"0151 123456", "00", "0", "49", false, "49151123456", true},
+		{"011 49151123456", "011", "1", "1", false, "49151123456", true},
+		{"0151123456", "00", "", "", true, "0151123456", true},
+		{"49151123456", "00", "0", "49", true, "49151123456", true},
+		{"1001", "00", "0", "49", false, "", false},
+		{"+49 151 abc", "", "", "", true, "", false},
+		{"+4915112345678901", "", "", "", false, "", false},
+		{"+", "", "", "", true, "", false},
+	}
+	for _, tc := range testCases {
+		if e164, normalized := NormalizeE164(tc.num, tc.intlPrefix, tc.natPrefix,
+			tc.countryCode, tc.unprefixed); e164 != tc.e164 || normalized != tc.normalized {
+			t.Errorf("number: <%s>, expecting: <%s>, %v, received: <%s>, %v",
+				tc.num, tc.e164, tc.normalized, e164, normalized)
+		}
+	}
+}
+
+func TestNextMidnight(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		ref      time.Time
+		loc      *time.Location
+		expected time.Time
+	}{
+		{time.Date(2018, 5, 10, 14, 30, 0, 0, time.UTC), time.UTC,
+			time.Date(2018, 5, 11, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2018, 5, 10, 0, 0, 0, 0, time.UTC), time.UTC,
+			time.Date(2018, 5, 11, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2018, 12, 31, 23, 59, 59, 0, time.UTC), time.UTC,
+			time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2018, 5, 10, 23, 30, 0, 0, time.UTC), berlin, // already 11th in Berlin
+			time.Date(2018, 5, 12, 0, 0, 0, 0, berlin)},
+	} {
+		if rcv := NextMidnight(tc.ref, tc.loc); !rcv.Equal(tc.expected) {
+			t.Errorf("ref: %+v, expecting: %+v, received: %+v", tc.ref, tc.expected, rcv)
+		}
+	}
+}
+
 func TestTimeIs0h(t *testing.T) {
 	t1, err := time.Parse(time.RFC3339, "2012-11-01T22:08:41+00:00")
 	if err != nil {
@@ -19,7 +19,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -52,6 +58,10 @@ type DataConverter interface {
 // NewDataConverter is a factory of converters
 func NewDataConverter(params string) (
 	conv DataConverter, err error) {
+	convName, convParams := params, ""
+	if idx := strings.Index(params, InInFieldSep); idx != -1 {
+		convName, convParams = params[:idx], params[idx+1:]
+	}
 	switch {
 	case params == MetaDurationSeconds:
 		return NewDurationSecondsConverter("")
@@ -72,6 +82,40 @@ func NewDataConverter(params string) (
 			return NewDivideConverter("")
 		}
 		return NewDivideConverter(params[len(MetaDivide)+1:])
+	case convName == MetaDuration:
+		return NewDurationConverter(convParams)
+	case convName == MetaDurationHMS:
+		return NewDurationHMSConverter(convParams)
+	case convName == MetaTimeString:
+		return NewTimeStringConverter(convParams)
+	case convName == MetaTimeZone:
+		return NewTimeZoneConverter(convParams)
+	case convName == MetaUnixTime:
+		return NewUnixTimeConverter(convParams)
+	case convName == MetaUpper:
+		return NewUpperConverter(convParams)
+	case convName == MetaLower:
+		return NewLowerConverter(convParams)
+	case convName == MetaPadLeft:
+		return NewPaddingConverter(convParams, true)
+	case convName == MetaPadRight:
+		return NewPaddingConverter(convParams, false)
+	case convName == MetaBase64Encode:
+		return NewBase64EncodeConverter(convParams)
+	case convName == MetaBase64Decode:
+		return NewBase64DecodeConverter(convParams)
+	case convName == MetaHexEncode:
+		return NewHexEncodeConverter(convParams)
+	case convName == MetaHexDecode:
+		return NewHexDecodeConverter(convParams)
+	case convName == MetaSHA256:
+		return NewSHA256Converter(convParams)
+	case convName == MetaE164:
+		return NewE164Converter(convParams)
+	case convName == MetaJSONField:
+		return NewJSONFieldConverter(convParams)
+	case convName == MetaIP2Int:
+		return NewIP2IntConverter(convParams)
 	default:
 		return nil,
 			fmt.Errorf("unsupported converter definition: <%s>",
@@ -219,3 +263,400 @@ func (m *DivideConverter) Convert(in interface{}) (
 	out = inFloat64 / m.Value
 	return
 }
+
+func NewDurationConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(DurationConverter), nil
+}
+
+// DurationConverter converts the input into time.Duration, represented as string ie: 1m30s
+type DurationConverter struct{}
+
+func (dc *DurationConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	return IfaceAsDuration(in)
+}
+
+func NewDurationHMSConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(DurationHMSConverter), nil
+}
+
+// DurationHMSConverter formats the duration as hours:minutes:seconds, ie: 01:30:05
+type DurationHMSConverter struct{}
+
+func (dc *DurationHMSConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inDur time.Duration
+	if inDur, err = IfaceAsDuration(in); err != nil {
+		return nil, err
+	}
+	var sign string
+	if inDur < 0 {
+		sign = "-"
+		inDur = -inDur
+	}
+	secs := int64(inDur.Seconds())
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign,
+		secs/3600, (secs%3600)/60, secs%60), nil
+}
+
+// NewTimeStringConverter constructs a TimeStringConverter
+// the parameter is the Go time layout, RFC3339 if missing
+func NewTimeStringConverter(params string) (
+	hdlr DataConverter, err error) {
+	if params == "" {
+		params = time.RFC3339
+	}
+	return &TimeStringConverter{Layout: params}, nil
+}
+
+// TimeStringConverter formats the time using the layout
+// strings without timezone information are considered UTC
+type TimeStringConverter struct {
+	Layout string
+}
+
+func (tS *TimeStringConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var tm time.Time
+	if tm, err = IfaceAsTime(in, ""); err != nil {
+		return nil, err
+	}
+	return tm.Format(tS.Layout), nil
+}
+
+// NewTimeZoneConverter constructs a TimeZoneConverter out of IANA timezone name or Local
+func NewTimeZoneConverter(params string) (
+	hdlr DataConverter, err error) {
+	if params == "" {
+		return nil, ErrMandatoryIeMissingNoCaps
+	}
+	tzC := new(TimeZoneConverter)
+	if tzC.Location, err = time.LoadLocation(params); err != nil {
+		return nil, err
+	}
+	return tzC, nil
+}
+
+// TimeZoneConverter moves the time into the location, keeping the instant
+// strings without timezone information are considered UTC
+type TimeZoneConverter struct {
+	Location *time.Location
+}
+
+func (tzC *TimeZoneConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var tm time.Time
+	if tm, err = IfaceAsTime(in, ""); err != nil {
+		return nil, err
+	}
+	return tm.In(tzC.Location), nil
+}
+
+func NewUnixTimeConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(UnixTimeConverter), nil
+}
+
+// UnixTimeConverter converts the time into unix timestamp, encapsulated in int64
+type UnixTimeConverter struct{}
+
+func (uC *UnixTimeConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var tm time.Time
+	if tm, err = IfaceAsTime(in, ""); err != nil {
+		return nil, err
+	}
+	return tm.Unix(), nil
+}
+
+func NewUpperConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(UpperConverter), nil
+}
+
+// UpperConverter converts the string to upper case
+type UpperConverter struct{}
+
+func (uC *UpperConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	return strings.ToUpper(inStr), nil
+}
+
+func NewLowerConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(LowerConverter), nil
+}
+
+// LowerConverter converts the string to lower case
+type LowerConverter struct{}
+
+func (lC *LowerConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	return strings.ToLower(inStr), nil
+}
+
+// NewPaddingConverter constructs a PaddingConverter out of length[:padding_character]
+// padding with spaces if the character is missing
+func NewPaddingConverter(params string, left bool) (
+	hdlr DataConverter, err error) {
+	if params == "" {
+		return nil, ErrMandatoryIeMissingNoCaps
+	}
+	pC := &PaddingConverter{PadChar: " ", Left: left}
+	paramsSplt := strings.SplitN(params, InInFieldSep, 2)
+	if pC.Length, err = strconv.Atoi(paramsSplt[0]); err != nil {
+		return nil, fmt.Errorf("padding converter needs integer as length, have: <%s>",
+			paramsSplt[0])
+	}
+	if len(paramsSplt) == 2 {
+		if len([]rune(paramsSplt[1])) != 1 {
+			return nil, fmt.Errorf("padding converter needs one character, have: <%s>",
+				paramsSplt[1])
+		}
+		pC.PadChar = paramsSplt[1]
+	}
+	return pC, nil
+}
+
+// PaddingConverter pads the string up to length, on left or right side
+// longer strings are not truncated
+type PaddingConverter struct {
+	Length  int
+	PadChar string
+	Left    bool
+}
+
+func (pC *PaddingConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	padLen := pC.Length - len([]rune(inStr))
+	if padLen <= 0 {
+		return inStr, nil
+	}
+	if pC.Left {
+		return strings.Repeat(pC.PadChar, padLen) + inStr, nil
+	}
+	return inStr + strings.Repeat(pC.PadChar, padLen), nil
+}
+
+func NewBase64EncodeConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(Base64EncodeConverter), nil
+}
+
+// Base64EncodeConverter encodes the string using standard base64 encoding
+type Base64EncodeConverter struct{}
+
+func (bC *Base64EncodeConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString([]byte(inStr)), nil
+}
+
+func NewBase64DecodeConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(Base64DecodeConverter), nil
+}
+
+// Base64DecodeConverter decodes the standard base64 encoded string
+type Base64DecodeConverter struct{}
+
+func (bC *Base64DecodeConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	var b []byte
+	if b, err = base64.StdEncoding.DecodeString(inStr); err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func NewHexEncodeConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(HexEncodeConverter), nil
+}
+
+// HexEncodeConverter encodes the string as lower case hexadecimal
+type HexEncodeConverter struct{}
+
+func (hC *HexEncodeConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString([]byte(inStr)), nil
+}
+
+func NewHexDecodeConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(HexDecodeConverter), nil
+}
+
+// HexDecodeConverter decodes the hexadecimal string, accepting the 0x prefix
+type HexDecodeConverter struct{}
+
+func (hC *HexDecodeConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	var b []byte
+	if b, err = hex.DecodeString(strings.TrimPrefix(inStr, "0x")); err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// NewSHA256Converter constructs a SHA256Converter with optional salt as parameter
+func NewSHA256Converter(params string) (
+	hdlr DataConverter, err error) {
+	return &SHA256Converter{Salt: params}, nil
+}
+
+// SHA256Converter hashes the salted string, returning the hexadecimal digest
+// used to pseudonymize data like phone numbers
+type SHA256Converter struct {
+	Salt string
+}
+
+func (sC *SHA256Converter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(sC.Salt + inStr))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// NewE164Converter constructs an E164Converter with optional country code as parameter
+func NewE164Converter(params string) (
+	hdlr DataConverter, err error) {
+	if params != "" {
+		if _, err = strconv.ParseUint(params, 10, 16); err != nil {
+			return nil, fmt.Errorf("%s converter needs numeric country code, have: <%s>",
+				MetaE164, params)
+		}
+	}
+	return &E164Converter{CountryCode: params}, nil
+}
+
+// E164Converter normalizes the phone number to E.164 digits, without leading +
+// the international prefixes (+ or 00) are removed and the national ones (single 0)
+// replaced with the country code, if defined
+type E164Converter struct {
+	CountryCode string
+}
+
+func (eC *E164Converter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	var natPrefix string
+	if eC.CountryCode != "" {
+		natPrefix = "0"
+	}
+	num, normalized := NormalizeE164(inStr, "00", natPrefix, eC.CountryCode, true)
+	if !normalized {
+		return nil, fmt.Errorf("invalid E.164 number: <%s>", inStr)
+	}
+	return num, nil
+}
+
+// NewJSONFieldConverter constructs a JSONFieldConverter out of the path, with . as separator
+func NewJSONFieldConverter(params string) (
+	hdlr DataConverter, err error) {
+	if params == "" {
+		return nil, ErrMandatoryIeMissingNoCaps
+	}
+	return &JSONFieldConverter{Path: strings.Split(params, NestingSep)}, nil
+}
+
+// JSONFieldConverter extracts the field out of the JSON string
+// the array elements are selected by index within the path
+type JSONFieldConverter struct {
+	Path []string
+}
+
+func (jC *JSONFieldConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(inStr), &out); err != nil {
+		return nil, err
+	}
+	for _, fld := range jC.Path {
+		switch val := out.(type) {
+		case map[string]interface{}:
+			var has bool
+			if out, has = val[fld]; !has {
+				return nil, ErrNotFound
+			}
+		case []interface{}:
+			idx, errIdx := strconv.Atoi(fld)
+			if errIdx != nil || idx < 0 || idx >= len(val) {
+				return nil, ErrNotFound
+			}
+			out = val[idx]
+		default:
+			return nil, ErrNotFound
+		}
+	}
+	if _, isMap := out.(map[string]interface{}); isMap {
+		return ToJSON(out), nil
+	}
+	if _, isSlice := out.([]interface{}); isSlice {
+		return ToJSON(out), nil
+	}
+	return
+}
+
+func NewIP2IntConverter(params string) (
+	hdlr DataConverter, err error) {
+	return new(IP2IntConverter), nil
+}
+
+// IP2IntConverter converts the IP address into integer
+// encapsulated in int64 for IPv4 and decimal string for IPv6
+type IP2IntConverter struct{}
+
+func (iC *IP2IntConverter) Convert(in interface{}) (
+	out interface{}, err error) {
+	var inStr string
+	if inStr, err = IfaceAsString(in); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(inStr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address: <%s>", inStr)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return int64(ip4[0])<<24 | int64(ip4[1])<<16 | int64(ip4[2])<<8 | int64(ip4[3]), nil
+	}
+	return new(big.Int).SetBytes(ip.To16()).String(), nil
+}
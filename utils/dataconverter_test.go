@@ -198,3 +198,138 @@ func TestDivideConverter(t *testing.T) {
 		t.Errorf("expecting: %+v, received: %+v", expOut, out)
 	}
 }
+
+func TestNewDataConverterParams(t *testing.T) {
+	eCnv := &PaddingConverter{Length: 10, PadChar: "0", Left: true}
+	if cnv, err := NewDataConverter("*pad_left:10:0"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCnv, cnv) {
+		t.Errorf("expecting: %+v, received: %+v", eCnv, cnv)
+	}
+	eTmCnv := &TimeStringConverter{Layout: "2006-01-02 15:04:05"}
+	if cnv, err := NewDataConverter("*time_string:2006-01-02 15:04:05"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eTmCnv, cnv) {
+		t.Errorf("expecting: %+v, received: %+v", eTmCnv, cnv)
+	}
+	for _, params := range []string{
+		"*pad_right", "*pad_right:a", "*pad_left:10:ab",
+		"*time_zone", "*time_zone:Mars/Olympus",
+		"*e164:+49", "*json_field", "*unknown"} {
+		if _, err := NewDataConverter(params); err == nil {
+			t.Errorf("expecting error for: <%s>", params)
+		}
+	}
+}
+
+func TestDurationConverters(t *testing.T) {
+	if out, err := NewDataConverterMustCompile(MetaDuration).Convert("90"); err != nil {
+		t.Error(err)
+	} else if out != time.Duration(90) {
+		t.Errorf("received: %+v", out)
+	}
+	dcs := DataConverters{NewDataConverterMustCompile(MetaDuration)}
+	if out, err := dcs.ConvertString("90s"); err != nil {
+		t.Error(err)
+	} else if out != "1m30s" {
+		t.Errorf("received: <%s>", out)
+	}
+	hms := NewDataConverterMustCompile(MetaDurationHMS)
+	for in, eOut := range map[interface{}]string{
+		"1h30m5s":                   "01:30:05",
+		time.Duration(0):            "00:00:00",
+		"-90s":                      "-00:01:30",
+		100*time.Hour + time.Second: "100:00:01",
+	} {
+		if out, err := hms.Convert(in); err != nil {
+			t.Error(err)
+		} else if out != eOut {
+			t.Errorf("in: %v, expecting: <%s>, received: <%v>", in, eOut, out)
+		}
+	}
+}
+
+func TestTimeConverters(t *testing.T) {
+	dcs := DataConverters{
+		NewDataConverterMustCompile("*time_zone:Europe/Berlin"),
+		NewDataConverterMustCompile("*time_string:2006-01-02 15:04:05 MST")}
+	if out, err := dcs.ConvertString("2018-10-15T12:30:00Z"); err != nil {
+		t.Error(err)
+	} else if out != "2018-10-15 14:30:00 CEST" {
+		t.Errorf("received: <%s>", out)
+	}
+	dcs = DataConverters{NewDataConverterMustCompile(MetaTimeString)}
+	if out, err := dcs.ConvertString("2018-10-15 12:30:00"); err != nil { // no timezone, considered UTC
+		t.Error(err)
+	} else if out != "2018-10-15T12:30:00Z" {
+		t.Errorf("received: <%s>", out)
+	}
+	dcs = DataConverters{NewDataConverterMustCompile(MetaUnixTime)}
+	if out, err := dcs.ConvertString("2018-10-15T12:30:00+02:00"); err != nil {
+		t.Error(err)
+	} else if out != "1539599400" {
+		t.Errorf("received: <%s>", out)
+	}
+	if _, err := dcs.ConvertString("not a time"); err == nil {
+		t.Error("expecting error")
+	}
+}
+
+func TestStringConverters(t *testing.T) {
+	testCases := []struct {
+		convs string
+		in    string
+		out   string
+	}{
+		{"*upper", "cgrates.org", "CGRATES.ORG"},
+		{"*lower", "CGRateS", "cgrates"},
+		{"*pad_left:5:0", "42", "00042"},
+		{"*pad_right:5", "ab", "ab   "},
+		{"*pad_left:2:0", "12345", "12345"},
+		{"*base64_encode", "CGRateS", "Q0dSYXRlUw=="},
+		{"*base64_decode", "Q0dSYXRlUw==", "CGRateS"},
+		{"*hex_encode", "1001", "31303031"},
+		{"*hex_decode", "0x31303031", "1001"},
+		{"*sha256", "1001", "fe675fe7aaee830b6fed09b64e034f84dcbdaeb429d9cccd4ebb90e15af8dd71"},
+		{"*e164", "+49 (151) 123-456", "49151123456"},
+		{"*e164", "0049151123456", "49151123456"},
+		{"*e164:49", "0151 123456", "49151123456"},
+		{"*e164", "0151123456", "0151123456"},
+		{"*json_field:account.balances.1.value", `{"account": {"balances": [{"value": 1}, {"value": 2.5}]}}`, "2.5"},
+		{"*json_field:account.id", `{"account": {"id": "1001"}}`, "1001"},
+		{"*json_field:account", `{"account": {"id": "1001"}}`, `{"id":"1001"}`},
+		{"*ip2int", "192.168.0.1", "3232235521"},
+		{"*ip2int", "::1", "1"},
+	}
+	for _, tc := range testCases {
+		cnv, err := NewDataConverter(tc.convs)
+		if err != nil {
+			t.Errorf("converter: <%s>, error: %s", tc.convs, err)
+			continue
+		}
+		if out, err := (DataConverters{cnv}).ConvertString(tc.in); err != nil {
+			t.Errorf("converter: <%s>, input: <%s>, error: %s", tc.convs, tc.in, err)
+		} else if out != tc.out {
+			t.Errorf("converter: <%s>, input: <%s>, expecting: <%s>, received: <%s>",
+				tc.convs, tc.in, tc.out, out)
+		}
+	}
+	salted := DataConverters{NewDataConverterMustCompile("*sha256:s3cr3t")}
+	if out, err := salted.ConvertString("1001"); err != nil {
+		t.Error(err)
+	} else if out == "fe675fe7aaee830b6fed09b64e034f84dcbdaeb429d9cccd4ebb90e15af8dd71" {
+		t.Error("salt not considered")
+	}
+	for convs, in := range map[string]string{
+		"*base64_decode":  "not base64!",
+		"*hex_decode":     "0xZZ",
+		"*e164":           "+49 151 abc",
+		"*json_field:a.b": `{"a": {"c": 1}}`,
+		"*json_field:a.5": `{"a": [1]}`,
+		"*ip2int":         "192.168.0.256",
+	} {
+		if _, err := (DataConverters{NewDataConverterMustCompile(convs)}).ConvertString(in); err == nil {
+			t.Errorf("converter: <%s>, input: <%s>, expecting error", convs, in)
+		}
+	}
+}
@@ -40,6 +40,7 @@ var (
 	ErrParserError              = errors.New("PARSER_ERROR")
 	ErrInvalidPath              = errors.New("INVALID_PATH")
 	ErrInvalidKey               = errors.New("INVALID_KEY")
+	ErrInvalidValue             = errors.New("INVALID_VALUE")
 	ErrUnauthorizedDestination  = errors.New("UNAUTHORIZED_DESTINATION")
 	ErrRatingPlanNotFound       = errors.New("RATING_PLAN_NOT_FOUND")
 	ErrAccountNotFound          = errors.New("ACCOUNT_NOT_FOUND")
@@ -52,6 +53,7 @@ var (
 	ErrNoActiveSession          = errors.New("NO_ACTIVE_SESSION")
 	ErrPartiallyExecuted        = errors.New("PARTIALLY_EXECUTED")
 	ErrMaxUsageExceeded         = errors.New("MAX_USAGE_EXCEEDED")
+	ErrSpendingCapExceeded      = errors.New("SPENDING_CAP_EXCEEDED")
 	ErrUnallocatedResource      = errors.New("UNALLOCATED_RESOURCE")
 	ErrNotFoundNoCaps           = errors.New("not found")
 	ErrFilterNotPassingNoCaps   = errors.New("filter not passing")
@@ -59,6 +61,8 @@ var (
 	ErrMandatoryIeMissingNoCaps = errors.New("mandatory information missing")
 	ErrUnauthorizedApi          = errors.New("UNAUTHORIZED_API")
 	ErrUnknownApiKey            = errors.New("UNKNOWN_API_KEY")
+	ErrMaxRateExceeded          = errors.New("MAX_RATE_EXCEEDED")
+	ErrMaxConcurrentExceeded    = errors.New("MAX_CONCURRENT_EXCEEDED")
 	ErrIncompatible             = errors.New("INCOMPATIBLE")
 	ErrReqUnsynchronized        = errors.New("REQ_UNSYNCHRONIZED")
 	ErrUnsupporteServiceMethod  = errors.New("UNSUPPORTED_SERVICE_METHOD")
@@ -298,7 +298,8 @@ func (r *rpcRequest) Call() io.Reader {
 	return r.rw
 }
 
-func loadTLSConfig(serverCrt, serverKey, caCert string, serverPolicy int,
+// LoadTLSConfig builds the server TLS configuration out of certificate files
+func LoadTLSConfig(serverCrt, serverKey, caCert string, serverPolicy int,
 	serverName string) (config tls.Config, err error) {
 	cert, err := tls.LoadX509KeyPair(serverCrt, serverKey)
 	if err != nil {
@@ -345,7 +346,7 @@ func (s *Server) ServeGOBTLS(addr, serverCrt, serverKey, caCert string,
 	if !enabled {
 		return
 	}
-	config, err := loadTLSConfig(serverCrt, serverKey, caCert, serverPolicy, serverName)
+	config, err := LoadTLSConfig(serverCrt, serverKey, caCert, serverPolicy, serverName)
 	if err != nil {
 		return
 	}
@@ -386,7 +387,7 @@ func (s *Server) ServeJSONTLS(addr, serverCrt, serverKey, caCert string,
 	if !enabled {
 		return
 	}
-	config, err := loadTLSConfig(serverCrt, serverKey, caCert, serverPolicy, serverName)
+	config, err := LoadTLSConfig(serverCrt, serverKey, caCert, serverPolicy, serverName)
 	if err != nil {
 		return
 	}
@@ -460,7 +461,7 @@ func (s *Server) ServeHTTPTLS(addr, serverCrt, serverKey, caCert string, serverP
 	if useBasicAuth {
 		Logger.Info("<HTTPTLS> enabling basic auth")
 	}
-	config, err := loadTLSConfig(serverCrt, serverKey, caCert, serverPolicy, serverName)
+	config, err := LoadTLSConfig(serverCrt, serverKey, caCert, serverPolicy, serverName)
 	if err != nil {
 		return
 	}